@@ -0,0 +1,89 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.18
+// +build go1.18
+
+package check
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+)
+
+// AsFills returns the empty string if errors.As finds an error of type T in
+// got, verify, if not nil, returns the empty string when passed it, and
+// errors.As finds no error in got for each of the targets in notAs, otherwise
+// it returns a string indicating each failure.  Each target in notAs is a
+// non-nil pointer, as passed to errors.As:
+//
+//	s := check.AsFills(err, func(e *fs.PathError) string {
+//		if e.Path != "/tmp/x" {
+//			return fmt.Sprintf("got path %q, want /tmp/x", e.Path)
+//		}
+//		return ""
+//	}, new(*os.LinkError), new(*net.OpError))
+func AsFills[T error](got error, verify func(T) string, notAs ...interface{}) string {
+	return asFills(defaults(), got, verify, notAs)
+}
+
+// asFills implements AsFills using the settings in c.
+func asFills[T error](c *config, got error, verify func(T) string, notAs []interface{}) string {
+	if c.outer() {
+		return c.run("AsFills", got, func() string { return sprintf("an error of type %v", reflect.TypeOf((*T)(nil)).Elem()) },
+			func(c *config) string { return asFills(c, got, verify, notAs) })
+	}
+	if got == nil {
+		return c.failf(expectedType, reflect.TypeOf((*T)(nil)).Elem())
+	}
+	var failures []string
+	var target T
+	if !errors.As(got, &target) {
+		failures = append(failures, c.failf(wrongType, got, reflect.TypeOf((*T)(nil)).Elem()))
+	} else if verify != nil {
+		if s := verify(target); s != "" {
+			failures = append(failures, s)
+		}
+	}
+	for _, want := range notAs {
+		t := asTarget(want)
+		switch {
+		case t == nil:
+			failures = append(failures, c.code(CodeUnsupported)+sprintf(unsupported, want))
+		case errors.As(got, want):
+			failures = append(failures, c.failf(unwantedType, got, t))
+		}
+	}
+	return strings.Join(failures, "\n")
+}
+
+// AsError returns the first error in the chain of got that is assignable to
+// T, as found by errors.As, and the empty string, otherwise it returns the
+// zero T and a string indicating the failure, naming T.  T must be an
+// interface type or implement error:
+//
+//	perr, s := check.AsError[*fs.PathError](err)
+//	if s != "" {
+//		t.Fatal(s)
+//	}
+//	if perr.Path != "/tmp/x" {
+//		...
+//
+// AsError is the same as Error(got, &target) for a target of type T.
+func AsError[T any](got error) (T, string) {
+	var target T
+	s := defaults().checkError(got, &target)
+	return target, s
+}
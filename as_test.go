@@ -0,0 +1,98 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.18
+// +build go1.18
+
+package check
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"testing"
+)
+
+func TestAsFills(t *testing.T) {
+	setDefaults(t)
+	got := fmt.Errorf("load: %w", &os.PathError{Op: "open", Path: "/tmp/x", Err: os.ErrNotExist})
+	path := func(want string) func(*os.PathError) string {
+		return func(e *os.PathError) string {
+			if e.Path != want {
+				return fmt.Sprintf("got path %q, want %q", e.Path, want)
+			}
+			return ""
+		}
+	}
+	if s := AsFills(got, path("/tmp/x"), new(*os.LinkError), new(*net.OpError)); s != "" {
+		t.Errorf("pass: %s", s)
+	}
+	if s := AsFills[*os.PathError](got, nil); s != "" {
+		t.Errorf("nil verify: %s", s)
+	}
+	if s := AsFills(got, path("/tmp/y")); s != `got path "/tmp/x", want "/tmp/y"` {
+		t.Errorf("verify: got %q", s)
+	}
+	if s, want := AsFills(io.EOF, path("/tmp/x")), sprintf(wrongType, "EOF", "*fs.PathError"); s != want {
+		t.Errorf("wrong type: got %q, want %q", s, want)
+	}
+	if s, want := AsFills(nil, path("/tmp/x")), sprintf(expectedType, "*fs.PathError"); s != want {
+		t.Errorf("nil: got %q, want %q", s, want)
+	}
+	want := sprintf(unwantedType, "load: open /tmp/x: file does not exist", "*fs.PathError") + "\n" + sprintf(unsupported, "x")
+	if s := AsFills(got, path("/tmp/x"), new(*os.PathError), "x"); s != want {
+		t.Errorf("notAs: got:\n%s\nwant:\n%s", s, want)
+	}
+	// *fs.PathError has a Timeout method.
+	if s := AsFills(got, func(e interface {
+		error
+		Timeout() bool
+	}) string {
+		if e.Timeout() {
+			return "timed out"
+		}
+		return ""
+	}); s != "" {
+		t.Errorf("interface: %s", s)
+	}
+	var col collector
+	setDefaults(t, Report(&col))
+	AsFills(io.EOF, path("/tmp/x"))
+	if len(col.failures) != 1 || col.failures[0].Check != "AsFills" {
+		t.Errorf("reported %+v", col.failures)
+	}
+}
+
+func TestAsError(t *testing.T) {
+	setDefaults(t)
+	perr := &os.PathError{Op: "open", Path: "/tmp/x", Err: os.ErrNotExist}
+	got := fmt.Errorf("load: %w", perr)
+
+	if e, s := AsError[*os.PathError](got); s != "" || e != perr {
+		t.Errorf("*os.PathError: got %v, %q", e, s)
+	}
+	if e, s := AsError[interface{ Timeout() bool }](got); s != "" || e != perr {
+		t.Errorf("Timeout interface: got %v, %q", e, s)
+	}
+	if e, s := AsError[*net.OpError](got); e != nil || s != sprintf(wrongType, got, "*net.OpError") {
+		t.Errorf("*net.OpError: got %v, %q", e, s)
+	}
+	if e, s := AsError[*os.PathError](nil); e != nil || s != sprintf(expectedType, "*fs.PathError") {
+		t.Errorf("nil: got %v, %q", e, s)
+	}
+	if _, s := AsError[int](io.EOF); s != sprintf(unsupported, new(int)) {
+		t.Errorf("int: got %q", s)
+	}
+}
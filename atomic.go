@@ -0,0 +1,101 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.19
+// +build go1.19
+
+package check
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// AtomicPointer checks the error stored in p, as by Error.  A p storing nil,
+// or a pointer to nil, is no error.  The error is read with p.Load, so
+// AtomicPointer may be called while other goroutines store errors in p.
+func AtomicPointer(p *atomic.Pointer[error], want interface{}) string {
+	return defaults().atomicPointer(p, want)
+}
+
+// AtomicPointer is the same as the AtomicPointer function but uses the options
+// of ck.
+func (ck *Checker) AtomicPointer(p *atomic.Pointer[error], want interface{}) Result {
+	ck.count()
+	return Result(ck.c.atomicPointer(p, want))
+}
+
+func (c *config) atomicPointer(p *atomic.Pointer[error], want interface{}) string {
+	var err error
+	if ep := p.Load(); ep != nil {
+		err = *ep
+	}
+	if c.outer() {
+		return c.run("AtomicPointer", err, func() string { return Describe(want) },
+			func(c *config) string { return c.checkError(err, want) })
+	}
+	return c.checkError(err, want)
+}
+
+// AtomicValue checks the error stored in v, as by Error.  A v storing nothing
+// is no error.  AtomicValue returns a failure if v stores a value that is not
+// an error.
+func AtomicValue(v *atomic.Value, want interface{}) string {
+	return defaults().atomicValue(v.Load(), want)
+}
+
+// AtomicValue is the same as the AtomicValue function but uses the options of
+// ck.
+func (ck *Checker) AtomicValue(v *atomic.Value, want interface{}) Result {
+	ck.count()
+	return Result(ck.c.atomicValue(v.Load(), want))
+}
+
+// atomicValue checks x, the value loaded from an atomic.Value.
+func (c *config) atomicValue(x interface{}, want interface{}) string {
+	err, ok := x.(error)
+	if c.outer() {
+		return c.run("AtomicValue", err, func() string { return Describe(want) },
+			func(c *config) string { return c.atomicValue(x, want) })
+	}
+	if x != nil && !ok {
+		return c.failc(CodeUnsupported, sprintf("atomic.Value holds %T, not an error", x))
+	}
+	return c.checkError(err, want)
+}
+
+// OnceError checks *err, an error set by a function passed to once.Do, as by
+// Error.  OnceError calls once.Do itself, with a function that does nothing,
+// before reading *err: once.Do does not return until the first function
+// passed to it returns, so the read is ordered after the write.  If once has
+// not been done OnceError marks it done, so OnceError should only be called
+// once the code under test has had the chance to do once.
+func OnceError(once *sync.Once, err *error, want interface{}) string {
+	return defaults().onceError(once, err, want)
+}
+
+// OnceError is the same as the OnceError function but uses the options of ck.
+func (ck *Checker) OnceError(once *sync.Once, err *error, want interface{}) Result {
+	ck.count()
+	return Result(ck.c.onceError(once, err, want))
+}
+
+func (c *config) onceError(once *sync.Once, err *error, want interface{}) string {
+	once.Do(func() {})
+	if c.outer() {
+		return c.run("OnceError", *err, func() string { return Describe(want) },
+			func(c *config) string { return c.checkError(*err, want) })
+	}
+	return c.checkError(*err, want)
+}
@@ -0,0 +1,116 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.19
+// +build go1.19
+
+package check
+
+import (
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestAtomicPointer(t *testing.T) {
+	setDefaults(t)
+	var p atomic.Pointer[error]
+	if s := AtomicPointer(&p, nil); s != "" {
+		t.Errorf("empty: %s", s)
+	}
+	var nilErr error
+	p.Store(&nilErr)
+	if s := AtomicPointer(&p, nil); s != "" {
+		t.Errorf("nil: %s", s)
+	}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		err := io.EOF
+		p.Store(&err)
+	}()
+	wg.Wait()
+	if s := AtomicPointer(&p, io.EOF); s != "" {
+		t.Errorf("EOF: %s", s)
+	}
+	if s, want := AtomicPointer(&p, nil), sprintf(unexpected, "EOF"); s != want {
+		t.Errorf("got %q, want %q", s, want)
+	}
+}
+
+func TestAtomicValue(t *testing.T) {
+	setDefaults(t)
+	var v atomic.Value
+	if s := AtomicValue(&v, nil); s != "" {
+		t.Errorf("empty: %s", s)
+	}
+	v.Store(io.EOF)
+	if s := AtomicValue(&v, "EOF"); s != "" {
+		t.Errorf("EOF: %s", s)
+	}
+	var w atomic.Value
+	w.Store(42)
+	if s, want := AtomicValue(&w, nil), "atomic.Value holds int, not an error"; s != want {
+		t.Errorf("got %q, want %q", s, want)
+	}
+}
+
+func TestOnceError(t *testing.T) {
+	setDefaults(t)
+	var once sync.Once
+	var err error
+	done := make(chan struct{})
+	go func() {
+		once.Do(func() {
+			<-done
+			err = io.ErrUnexpectedEOF
+		})
+	}()
+	close(done)
+	// OnceError may run before the goroutine calls once.Do, in which case
+	// err is never set.
+	s := OnceError(&once, &err, true)
+	if s != "" && s != sprintf(missing) {
+		t.Errorf("got %q", s)
+	}
+
+	var once2 sync.Once
+	var err2 error
+	once2.Do(func() { err2 = io.EOF })
+	if s := OnceError(&once2, &err2, io.EOF); s != "" {
+		t.Errorf("EOF: %s", s)
+	}
+}
+
+func TestAtomicReport(t *testing.T) {
+	var col collector
+	setDefaults(t, Report(&col))
+	var p atomic.Pointer[error]
+	AtomicPointer(&p, "a")
+	var v atomic.Value
+	AtomicValue(&v, "a")
+	var once sync.Once
+	var err error
+	OnceError(&once, &err, "a")
+	var checks []string
+	for _, f := range col.failures {
+		checks = append(checks, f.Check)
+	}
+	if got, want := strings.Join(checks, ","), "AtomicPointer,AtomicValue,OnceError"; got != want {
+		t.Errorf("got checks %s, want %s", got, want)
+	}
+}
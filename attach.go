@@ -0,0 +1,81 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import "strings"
+
+// An Attachment is an artifact, such as a request dump or a configuration
+// snapshot, attached to a failure to help debug it.
+type Attachment struct {
+	Name    string
+	Content string
+}
+
+// attachments is an immutable list of attachments, most recent first.  A
+// list, rather than a slice, keeps config comparable.
+type attachments struct {
+	Attachment
+	next *attachments
+}
+
+// list returns the attachments in a, in the order they were attached.
+func (a *attachments) list() []Attachment {
+	var list []Attachment
+	for ; a != nil; a = a.next {
+		list = append(list, a.Attachment)
+	}
+	for i, j := 0, len(list)-1; i < j; i, j = i+1, j-1 {
+		list[i], list[j] = list[j], list[i]
+	}
+	return list
+}
+
+// Attach returns an Option that attaches an artifact named name to failures.
+// Each attachment is appended to the failure under a separator:
+//
+//	got error "status 500", want "status 404"
+//	--- request ---
+//	GET /v1/users/42 HTTP/1.1
+//	...
+//
+// Attach may be used more than once to attach several artifacts.  Passing Attach
+// to a Checker's With method attaches artifacts to just the checks of the
+// returned Checker:
+//
+//	ck.With(check.Attach("request", dump)).Error(err, "not found")
+func Attach(name, content string) Option {
+	return func(c *config) {
+		c.attachments = &attachments{
+			Attachment: Attachment{Name: name, Content: content},
+			next:       c.attachments,
+		}
+	}
+}
+
+// attach returns the failure s with the attachments of c appended.
+func (c *config) attach(s string) string {
+	if c.attachments == nil {
+		return s
+	}
+	var b strings.Builder
+	b.WriteString(s)
+	for _, a := range c.attachments.list() {
+		b.WriteString("\n--- ")
+		b.WriteString(a.Name)
+		b.WriteString(" ---\n")
+		b.WriteString(strings.TrimSuffix(a.Content, "\n"))
+	}
+	return b.String()
+}
@@ -0,0 +1,69 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"io"
+	"testing"
+)
+
+func TestAttach(t *testing.T) {
+	setDefaults(t)
+	ck := NewChecker(Attach("request", "GET / HTTP/1.1\nHost: example.com\n"))
+	both := ck.With(Attach("config", "retries: 3"))
+	fail := sprintf(wrong, "EOF", "other")
+	request := "\n--- request ---\nGET / HTTP/1.1\nHost: example.com"
+	for _, tt := range []struct {
+		name string
+		out  Result
+		want string
+	}{
+		{
+			name: "pass",
+			out:  ck.Error(io.EOF, "EOF"),
+		}, {
+			name: "fail",
+			out:  ck.Error(io.EOF, "other"),
+			want: fail + request,
+		}, {
+			name: "both",
+			out:  both.Error(io.EOF, "other"),
+			want: fail + request + "\n--- config ---\nretries: 3",
+		}, {
+			name: "Is",
+			out:  ck.Is(io.EOF, io.ErrUnexpectedEOF),
+			want: sprintf(wrong, "EOF", "unexpected EOF") + request,
+		}, {
+			name: "HasError",
+			out:  ck.HasError(io.EOF, "other", SingleLine()),
+			want: fail + request,
+		}, {
+			name: "NoError",
+			out:  ck.NoError(nil),
+		}, {
+			name: "matcher",
+			out:  ck.Error(io.EOF, Scrub("other")),
+			want: fail + request,
+		}, {
+			name: "without",
+			out:  NewChecker().Error(io.EOF, "other"),
+			want: fail,
+		},
+	} {
+		if string(tt.out) != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, tt.out, tt.want)
+		}
+	}
+}
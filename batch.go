@@ -0,0 +1,91 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import "strings"
+
+// A BatchPolicy declares how a batch API reports the failures of several of
+// its inputs.
+type BatchPolicy int
+
+const (
+	// FirstFailure is the policy of returning an error for only the first
+	// failing input.
+	FirstFailure BatchPolicy = iota
+
+	// AllFailures is the policy of returning an error that aggregates the
+	// failures of all failing inputs, such as with errors.Join.
+	AllFailures
+)
+
+func (p BatchPolicy) String() string {
+	switch p {
+	case FirstFailure:
+		return "FirstFailure"
+	case AllFailures:
+		return "AllFailures"
+	}
+	return sprintf("BatchPolicy(%d)", int(p))
+}
+
+// Batch returns the empty string if got, the error returned by a batch API
+// passed inputs engineered to fail at several positions, reflects those
+// failures as declared by policy, otherwise it returns a string indicating
+// each discrepancy.  wants holds the want, as by Error, for the failure of
+// each failing input, in input order.  With FirstFailure got must match the
+// first of wants and none of the others; with AllFailures got must match each
+// of wants:
+//
+//	err := store.PutAll(ctx, []Item{good, noKey, good, tooBig})
+//	if s := check.Batch(err, check.AllFailures, "item 1: missing key", "item 3: too big"); s != "" {
+//		t.Error(s)
+//	}
+//
+// Failing inputs are numbered from 0 in failures.
+func Batch(got error, policy BatchPolicy, wants ...interface{}) string {
+	return defaults().batch(got, policy, wants)
+}
+
+// Batch is the same as the Batch function but uses the options of ck.
+func (ck *Checker) Batch(got error, policy BatchPolicy, wants ...interface{}) Result {
+	ck.count()
+	return Result(ck.c.batch(got, policy, wants))
+}
+
+func (c *config) batch(got error, policy BatchPolicy, wants []interface{}) string {
+	if c.outer() {
+		return c.run("Batch", got, func() string { return describeAll(wants) },
+			func(c *config) string { return c.batch(got, policy, wants) })
+	}
+	if len(wants) == 0 {
+		return c.checkError(got, nil)
+	}
+	if got == nil {
+		return c.failf(missing)
+	}
+	var failures []string
+	for i, want := range wants {
+		matched := quiet.checkError(got, want) == ""
+		switch {
+		case policy == FirstFailure && i == 0 && !matched:
+			failures = append(failures, c.failc(CodeWrong, "got error %q, want the first failure: %q", got, description(Describe(want))))
+		case policy == FirstFailure && i > 0 && matched:
+			failures = append(failures, c.failc(CodeWrong, sprintf("got error %%q, want only the first failure, not failure %d: %%q", i), got, description(Describe(want))))
+		case policy == AllFailures && !matched:
+			failures = append(failures, c.failc(CodeWrong, sprintf("got error %%q, which does not reflect failure %d: want %%q", i), got, description(Describe(want))))
+		}
+	}
+	return strings.Join(failures, "\n")
+}
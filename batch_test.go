@@ -0,0 +1,77 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// putAll fails for each empty item, stopping at the first if first is set.
+func putAll(items []string, first bool) error {
+	var errs []string
+	for i, item := range items {
+		if item == "" {
+			errs = append(errs, fmt.Sprintf("item %d: empty", i))
+			if first {
+				break
+			}
+		}
+	}
+	if errs == nil {
+		return nil
+	}
+	return errors.New(strings.Join(errs, "; "))
+}
+
+func TestBatch(t *testing.T) {
+	setDefaults(t)
+	items := []string{"a", "", "b", ""}
+	first := putAll(items, true)
+	all := putAll(items, false)
+	for _, tt := range []struct {
+		name   string
+		got    error
+		policy BatchPolicy
+		wants  []interface{}
+		out    string
+	}{
+		{name: "first", got: first, policy: FirstFailure, wants: []interface{}{"item 1", "item 3"}},
+		{name: "all", got: all, policy: AllFailures, wants: []interface{}{"item 1", "item 3"}},
+		{name: "no failures", got: nil, policy: AllFailures},
+		{name: "unexpected", got: first, policy: AllFailures, out: sprintf(unexpected, "item 1: empty")},
+		{name: "missing", got: nil, policy: FirstFailure, wants: []interface{}{"item 1"}, out: sprintf(missing)},
+		{
+			name: "first but aggregated", got: all, policy: FirstFailure, wants: []interface{}{"item 1", "item 3"},
+			out: `got error "item 1: empty; item 3: empty", want only the first failure, not failure 1: contains "item 3"`,
+		}, {
+			name: "all but first", got: first, policy: AllFailures, wants: []interface{}{"item 1", "item 3"},
+			out: `got error "item 1: empty", which does not reflect failure 1: want contains "item 3"`,
+		}, {
+			name: "wrong first", got: first, policy: FirstFailure, wants: []interface{}{"item 0", "item 1"},
+			out: `got error "item 1: empty", want the first failure: contains "item 0"
+got error "item 1: empty", want only the first failure, not failure 1: contains "item 1"`,
+		},
+	} {
+		if s := Batch(tt.got, tt.policy, tt.wants...); s != tt.out {
+			t.Errorf("%s: got:\n%s\nwant:\n%s", tt.name, s, tt.out)
+		}
+	}
+	if s := fmt.Sprint(FirstFailure, AllFailures, BatchPolicy(7)); s != "FirstFailure AllFailures BatchPolicy(7)" {
+		t.Errorf("got %s", s)
+	}
+}
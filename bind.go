@@ -0,0 +1,79 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+)
+
+type bind struct {
+	want interface{}
+	args []interface{}
+}
+
+// Bind returns a Matcher that checks got against want, as by Error, with the
+// fmt verbs in want filled in from args when the check is made rather than
+// when Bind is called.  An arg that is a pointer is replaced by the value it
+// points to, so a table row may refer to variables set by the test:
+//
+//	var path string
+//	rows := []struct {
+//		want interface{}
+//	}{
+//		{check.Bind("open %s: no such file", &path)},
+//	}
+//	for _, row := range rows {
+//		path = filepath.Join(t.TempDir(), "missing")
+//		...
+//
+// want is a string, Equal, Case, CaseEqual, or Regexp.  The args of a Regexp
+// are quoted, as by regexp.QuoteMeta, so they match literally.
+func Bind(want interface{}, args ...interface{}) Matcher {
+	return bind{want: want, args: args}
+}
+
+func (b bind) match(c *config, got error) string {
+	return c.checkError(got, b.bound())
+}
+
+// bound returns the want of b with its verbs filled in.
+func (b bind) bound() interface{} {
+	args := make([]interface{}, len(b.args))
+	_, isRegexp := b.want.(Regexp)
+	for i, arg := range b.args {
+		if v := reflect.ValueOf(arg); v.Kind() == reflect.Ptr && !v.IsNil() {
+			arg = v.Elem().Interface()
+		}
+		if isRegexp {
+			arg = regexp.QuoteMeta(fmt.Sprint(arg))
+		}
+		args[i] = arg
+	}
+	switch w := b.want.(type) {
+	case string:
+		return sprintf(w, args...)
+	case Equal:
+		return Equal(sprintf(string(w), args...))
+	case Case:
+		return Case(sprintf(string(w), args...))
+	case CaseEqual:
+		return CaseEqual(sprintf(string(w), args...))
+	case Regexp:
+		return Regexp(sprintf(string(w), args...))
+	}
+	return b.want
+}
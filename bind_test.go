@@ -0,0 +1,67 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestBind(t *testing.T) {
+	setDefaults(t)
+	var path string
+	var n int
+	rows := []struct {
+		want Matcher
+		out  string
+	}{
+		{Bind("open %s: no such file", &path), ""},
+		{Bind(Equal("open %s: no such file or directory"), &path), ""},
+		{Bind(Case("OPEN %s"), &path), ""},
+		{Bind(CaseEqual("OPEN %s: NO SUCH FILE OR DIRECTORY"), &path), ""},
+		{Bind(Regexp("^open %s: "), &path), ""},
+		{Bind("read %s", &path), `got error "open /tmp/a.b: no such file or directory", want "read /tmp/a.b"`},
+		{Bind("%s line %d", "/tmp/a.b", &n), `got error "open /tmp/a.b: no such file or directory", want "/tmp/a.b line 2"`},
+	}
+	path, n = "/tmp/a.b", 2
+	got := errors.New("open /tmp/a.b: no such file or directory")
+	for _, row := range rows {
+		if s := Error(got, row.want); s != row.out {
+			t.Errorf("%s: got %q, want %q", Describe(row.want), s, row.out)
+		}
+	}
+	// The args of a Regexp match literally.
+	path = "/tmp/a+b"
+	if s := Error(got, Bind(Regexp("^open %s: "), &path)); s == "" {
+		t.Errorf("Regexp arg %q matched %q", path, got)
+	}
+	if d := Describe(Bind(Equal("open %s"), &path)); d != `is "open /tmp/a+b"` {
+		t.Errorf("got description %q", d)
+	}
+	for _, tt := range []struct {
+		want Matcher
+		err  string
+	}{
+		{Bind("%s", &path), ""},
+		{Bind(1, &path), "want 0: Bind: unsupported type int"},
+		{Bind("%d", (*int)(nil)), "want 0: Bind arg 0 is a nil *int"},
+		{Bind(Regexp("(%s"), &path), "want 0: Regexp: error parsing regexp: missing closing ): `(/tmp/a\\+b`"},
+	} {
+		if err := Validate(tt.want); fmt.Sprint(err) != tt.err && (err != nil || tt.err != "") {
+			t.Errorf("Validate: got %v, want %s", err, tt.err)
+		}
+	}
+}
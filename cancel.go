@@ -0,0 +1,161 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// DefaultLatency is the time Propagates permits f to take to return once its
+// context is canceled.
+const DefaultLatency = 250 * time.Millisecond
+
+// startDelay is how long f is given to start before its context is canceled.
+const startDelay = 10 * time.Millisecond
+
+const (
+	returnedEarly = "returned error %q before the context was canceled"
+	returnedNil   = "returned no error before the context was canceled"
+	notCanceled   = "got error %q, want an error that is context.Canceled"
+)
+
+// Propagates returns the empty string if f, called with a context derived
+// from ctx, returns an error that is context.Canceled, as determined by
+// errors.Is, within DefaultLatency of the context being canceled, otherwise
+// it returns a string indicating the error.  The context is canceled shortly
+// after f is called; f failing before then is an error.  If f does not return
+// in time it is abandoned, still running.
+//
+// Propagates waits using timers from the time package, so within a
+// testing/synctest bubble it runs in virtual time and returns without delay.
+// An abandoned f keeps the bubble from completing.
+//
+//	if s := check.Propagates(ctx, func(ctx context.Context) error {
+//		_, err := client.Watch(ctx, "key")
+//		return err
+//	}); s != "" {
+//		t.Error(s)
+//	}
+func Propagates(ctx context.Context, f func(context.Context) error) string {
+	return PropagatesWithin(ctx, f, DefaultLatency)
+}
+
+// Propagates is the same as the Propagates function but uses the options of ck.
+func (ck *Checker) Propagates(ctx context.Context, f func(context.Context) error) Result {
+	return ck.PropagatesWithin(ctx, f, DefaultLatency)
+}
+
+// PropagatesWithin is Propagates with a latency of latency rather than
+// DefaultLatency.
+func PropagatesWithin(ctx context.Context, f func(context.Context) error, latency time.Duration) string {
+	return defaults().propagates(ctx, f, latency)
+}
+
+// PropagatesWithin is the same as the PropagatesWithin function but uses the
+// options of ck.
+func (ck *Checker) PropagatesWithin(ctx context.Context, f func(context.Context) error, latency time.Duration) Result {
+	ck.count()
+	return Result(ck.c.propagates(ctx, f, latency))
+}
+
+func (c *config) propagates(ctx context.Context, f func(context.Context) error, latency time.Duration) string {
+	if c.outer() {
+		return c.run("Propagates", nil, func() string { return sprintf("cancellation propagated within %v", latency) },
+			func(c *config) string { return c.propagates(ctx, f, latency) })
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- f(ctx) }()
+
+	start, stop := after(startDelay)
+	select {
+	case err := <-done:
+		stop()
+		if err == nil {
+			return c.failf(returnedNil)
+		}
+		return c.failf(returnedEarly, err)
+	case <-start:
+	}
+	cancel()
+	timeout, stop := after(latency)
+	defer stop()
+	select {
+	case err := <-done:
+		switch {
+		case err == nil:
+			return c.failf(expected, context.Canceled)
+		case !errors.Is(err, context.Canceled):
+			return c.failf(notCanceled, err)
+		}
+		return ""
+	case <-timeout:
+		return c.failc(CodeTimeout, sprintf("did not return within %v of the context being canceled", latency))
+	}
+}
+
+// Expires returns the empty string if f, called with a context derived from
+// ctx that expires after deadline, returns an error that is
+// context.DeadlineExceeded, as determined by errors.Is, within tolerance of
+// the deadline, otherwise it returns a string indicating the error and the
+// time f took.  Returning well before the deadline is an error.  If f does
+// not return in time it is abandoned, still running.  Within a
+// testing/synctest bubble the time f takes is virtual and exact, so a
+// tolerance of 0 may be used.
+//
+//	if s := check.Expires(ctx, fetch, 50*time.Millisecond, 25*time.Millisecond); s != "" {
+//		t.Error(s)
+//	}
+func Expires(ctx context.Context, f func(context.Context) error, deadline, tolerance time.Duration) string {
+	return defaults().expires(ctx, f, deadline, tolerance)
+}
+
+// Expires is the same as the Expires function but uses the options of ck.
+func (ck *Checker) Expires(ctx context.Context, f func(context.Context) error, deadline, tolerance time.Duration) Result {
+	ck.count()
+	return Result(ck.c.expires(ctx, f, deadline, tolerance))
+}
+
+func (c *config) expires(ctx context.Context, f func(context.Context) error, deadline, tolerance time.Duration) string {
+	if c.outer() {
+		return c.run("Expires", nil, func() string { return sprintf("expires after %v", deadline) },
+			func(c *config) string { return c.expires(ctx, f, deadline, tolerance) })
+	}
+	ctx, cancel := context.WithTimeout(ctx, deadline)
+	defer cancel()
+	done := make(chan error, 1)
+	begin := time.Now()
+	go func() { done <- f(ctx) }()
+	timeout, stop := after(deadline + tolerance)
+	defer stop()
+	select {
+	case err := <-done:
+		elapsed := time.Since(begin).Round(time.Millisecond)
+		switch {
+		case err == nil:
+			return c.failc(CodeMissing, sprintf("returned no error after %v, want context.DeadlineExceeded", elapsed))
+		case !errors.Is(err, context.DeadlineExceeded):
+			return c.failc(CodeWrong, sprintf("got error %%q after %v, want an error that is context.DeadlineExceeded", elapsed), err)
+		case elapsed < deadline-tolerance:
+			return c.failc(CodeWrong, sprintf("got error %%q after %v, before the %v deadline", elapsed, deadline), err)
+		}
+		return ""
+	case <-timeout:
+		return c.failc(CodeTimeout, sprintf("did not return within %v of the %v deadline", tolerance, deadline))
+	}
+}
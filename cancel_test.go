@@ -0,0 +1,151 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPropagates(t *testing.T) {
+	setDefaults(t)
+	stuck := make(chan struct{})
+	defer close(stuck)
+	for _, tt := range []struct {
+		name string
+		f    func(context.Context) error
+		out  string
+	}{
+		{
+			name: "canceled",
+			f: func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			},
+		}, {
+			name: "wrapped",
+			f: func(ctx context.Context) error {
+				<-ctx.Done()
+				return fmt.Errorf("watch: %w", ctx.Err())
+			},
+		}, {
+			name: "early",
+			f:    func(context.Context) error { return io.EOF },
+			out:  sprintf(returnedEarly, "EOF"),
+		}, {
+			name: "early nil",
+			f:    func(context.Context) error { return nil },
+			out:  returnedNil,
+		}, {
+			name: "nil",
+			f: func(ctx context.Context) error {
+				<-ctx.Done()
+				return nil
+			},
+			out: sprintf(expected, "context canceled"),
+		}, {
+			name: "wrong",
+			f: func(ctx context.Context) error {
+				<-ctx.Done()
+				return errors.New("interrupted")
+			},
+			out: sprintf(notCanceled, "interrupted"),
+		}, {
+			name: "stuck",
+			f: func(context.Context) error {
+				<-stuck
+				return nil
+			},
+			out: "did not return within 20ms of the context being canceled",
+		},
+	} {
+		if s := PropagatesWithin(context.Background(), tt.f, 20*time.Millisecond); s != tt.out {
+			t.Errorf("%s: got %q, want %q", tt.name, s, tt.out)
+		}
+	}
+	if s := Propagates(context.Background(), func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}); s != "" {
+		t.Errorf("Propagates: %s", s)
+	}
+}
+
+func TestExpires(t *testing.T) {
+	setDefaults(t)
+	stuck := make(chan struct{})
+	defer close(stuck)
+	for _, tt := range []struct {
+		name   string
+		f      func(context.Context) error
+		prefix string
+		suffix string
+	}{
+		{
+			name: "expired",
+			f: func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			},
+		}, {
+			name: "wrapped",
+			f: func(ctx context.Context) error {
+				<-ctx.Done()
+				return fmt.Errorf("fetch: %w", ctx.Err())
+			},
+		}, {
+			name: "nil",
+			f: func(ctx context.Context) error {
+				<-ctx.Done()
+				return nil
+			},
+			prefix: "returned no error after ",
+			suffix: ", want context.DeadlineExceeded",
+		}, {
+			name: "wrong",
+			f: func(ctx context.Context) error {
+				<-ctx.Done()
+				return io.EOF
+			},
+			prefix: `got error "EOF" after `,
+			suffix: ", want an error that is context.DeadlineExceeded",
+		}, {
+			name:   "early",
+			f:      func(context.Context) error { return context.DeadlineExceeded },
+			prefix: `got error "context deadline exceeded" after `,
+			suffix: ", before the 50ms deadline",
+		}, {
+			name: "stuck",
+			f: func(context.Context) error {
+				<-stuck
+				return nil
+			},
+			prefix: "did not return within 30ms of the 50ms deadline",
+		},
+	} {
+		s := Expires(context.Background(), tt.f, 50*time.Millisecond, 30*time.Millisecond)
+		switch {
+		case tt.prefix == "" && s != "":
+			t.Errorf("%s: %s", tt.name, s)
+		case !strings.HasPrefix(s, tt.prefix) || !strings.HasSuffix(s, tt.suffix):
+			t.Errorf("%s: got %q, want %q...%q", tt.name, s, tt.prefix, tt.suffix)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"sync"
+)
+
+// A Category is a broad class of errors, such as NotFound, that tests often
+// care about more than the exact error.  A Category is also a Matcher that
+// matches errors of its category, as determined by Classify:
+//
+//	if s := check.Error(err, check.NotFound); s != "" {
+//
+// Categories other than those defined by this package may be used, such as
+// Category("RateLimited").
+type Category string
+
+// The built in categories.
+const (
+	Unknown    = Category("Unknown")    // an error that is not classified
+	NotFound   = Category("NotFound")   // a thing does not exist
+	Permission = Category("Permission") // not permitted or not authenticated
+	Timeout    = Category("Timeout")    // a deadline was exceeded
+	Canceled   = Category("Canceled")   // the operation was canceled
+	Conflict   = Category("Conflict")   // a thing already exists or was changed
+	Invalid    = Category("Invalid")    // an argument or request is invalid
+)
+
+// categorySentinels map sentinel errors to their categories.
+var categorySentinels = []struct {
+	err error
+	cat Category
+}{
+	{os.ErrNotExist, NotFound},
+	{os.ErrPermission, Permission},
+	{os.ErrExist, Conflict},
+	{os.ErrInvalid, Invalid},
+	{os.ErrDeadlineExceeded, Timeout},
+	{context.DeadlineExceeded, Timeout},
+	{context.Canceled, Canceled},
+}
+
+var grpcCategories = map[uint32]Category{
+	grpcCanceled:           Canceled,
+	grpcInvalidArgument:    Invalid,
+	grpcDeadlineExceeded:   Timeout,
+	grpcNotFound:           NotFound,
+	grpcAlreadyExists:      Conflict,
+	grpcPermissionDenied:   Permission,
+	grpcFailedPrecondition: Invalid,
+	grpcAborted:            Conflict,
+	grpcOutOfRange:         Invalid,
+	grpcUnauthenticated:    Permission,
+}
+
+var httpCategories = map[int]Category{
+	400: Invalid,
+	401: Permission,
+	403: Permission,
+	404: NotFound,
+	408: Timeout,
+	409: Conflict,
+	410: NotFound,
+	412: Conflict,
+	422: Invalid,
+	499: Canceled,
+	504: Timeout,
+}
+
+// Classify returns the Category of err.  The first error in the chain of err,
+// as walked by errors.Is, that is classified determines the category.  An
+// error is classified by, in order:
+//
+//	Timeout() bool        Timeout if it returns true, e.g., a net.Error
+//	GRPCStatus()          by the code of the status
+//	StatusCode() int      by the HTTP status code
+//	HTTPStatusCode() int  by the HTTP status code
+//	being a sentinel      such as os.ErrNotExist or context.Canceled
+//
+// A sentinel also matches an error whose Is method reports it is the
+// sentinel, e.g., syscall.ENOENT is os.ErrNotExist.  Rules registered with
+// RegisterSentinel, RegisterType, and RegisterPredicate are applied before
+// these.  Classify returns Unknown if no error in the chain is classified, and
+// "" if err is nil.
+func Classify(err error) Category {
+	if err == nil {
+		return ""
+	}
+	cat := Unknown
+	walk(err, func(err error) bool {
+		if c := classify(err); c != "" {
+			cat = c
+			return false
+		}
+		return true
+	})
+	return cat
+}
+
+// A categoryRule classifies errors that match it as cat.
+type categoryRule struct {
+	match func(error) bool
+	cat   Category
+}
+
+var categoryRules struct {
+	mu    sync.RWMutex
+	rules []categoryRule
+}
+
+// register adds a rule classifying the errors that match as cat.
+func register(cat Category, match func(error) bool) {
+	categoryRules.mu.Lock()
+	categoryRules.rules = append(categoryRules.rules, categoryRule{match: match, cat: cat})
+	categoryRules.mu.Unlock()
+}
+
+// RegisterSentinel registers sentinels as errors of category cat.  An error is
+// a sentinel if it is identical to the sentinel or its Is method reports it is
+// the sentinel.  Registrations are normally made by the init function of a
+// package, so that an organization's errors may be classified by importing
+// it in tests:
+//
+//	func init() {
+//		check.RegisterSentinel(check.NotFound, store.ErrNoSuchKey, users.ErrUnknown)
+//		check.RegisterType(check.Conflict, (*store.VersionError)(nil))
+//	}
+//
+// Registered rules are applied, in the order they were registered, before the
+// built in rules of Classify.
+func RegisterSentinel(cat Category, sentinels ...error) {
+	for _, sentinel := range sentinels {
+		sentinel := sentinel
+		register(cat, func(err error) bool { return isSentinel(err, sentinel) })
+	}
+}
+
+// RegisterType registers errors with the same dynamic type as any of examples
+// as errors of category cat.  An example is typically a nil pointer, e.g.,
+// (*MyError)(nil).
+func RegisterType(cat Category, examples ...error) {
+	for _, example := range examples {
+		t := reflect.TypeOf(example)
+		register(cat, func(err error) bool { return reflect.TypeOf(err) == t })
+	}
+}
+
+// RegisterPredicate registers the errors for which match returns true as
+// errors of category cat.  Match is only passed errors in the chain of the
+// error being classified, never nil.
+func RegisterPredicate(cat Category, match func(error) bool) {
+	register(cat, match)
+}
+
+// classify returns the Category of err, not considering the errors it wraps,
+// or "" if it is not classified.
+func classify(err error) Category {
+	categoryRules.mu.RLock()
+	rules := categoryRules.rules
+	categoryRules.mu.RUnlock()
+	for _, r := range rules {
+		if r.match(err) {
+			return r.cat
+		}
+	}
+	if e, ok := err.(interface{ Timeout() bool }); ok && e.Timeout() {
+		return Timeout
+	}
+	if code, ok := grpcCode(err); ok {
+		if c, ok := grpcCategories[code]; ok {
+			return c
+		}
+	}
+	if status, ok := httpStatus(err); ok {
+		if c, ok := httpCategories[status]; ok {
+			return c
+		}
+	}
+	for _, s := range categorySentinels {
+		if isSentinel(err, s.err) {
+			return s.cat
+		}
+	}
+	return ""
+}
+
+// isSentinel reports whether err, not considering the errors it wraps, is
+// sentinel.
+func isSentinel(err, sentinel error) bool {
+	if sameError(err, sentinel) {
+		return true
+	}
+	is, ok := err.(interface{ Is(error) bool })
+	return ok && is.Is(sentinel)
+}
+
+// sameError reports whether err is sentinel without panicking if the dynamic
+// type of err is not comparable.
+func sameError(err, sentinel error) (same bool) {
+	defer func() { recover() }()
+	return err == sentinel
+}
+
+const (
+	expectedCategory = "did not get expected error of category %q"
+	wrongCategory    = "got error %q of category %q, want category %q"
+)
+
+func (cat Category) match(c *config, got error) string {
+	s := cat.check(c, got)
+	if c.categories != nil && !c.quiet {
+		c.categories.add(cat, s != "")
+	}
+	return s
+}
+
+func (cat Category) check(c *config, got error) string {
+	if got == nil {
+		return c.failf(expectedCategory, cat)
+	}
+	if gc := Classify(got); gc != cat {
+		return c.failf(wrongCategory, got, gc, cat)
+	}
+	return ""
+}
+
+// Describe returns a description of the errors matched by cat.
+func (cat Category) Describe() string {
+	return sprintf("an error of category %s", string(cat))
+}
@@ -0,0 +1,166 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"syscall"
+	"testing"
+)
+
+type timeoutErr struct{}
+
+func (timeoutErr) Error() string   { return "i/o timeout" }
+func (timeoutErr) Timeout() bool   { return true }
+func (timeoutErr) Temporary() bool { return true }
+
+// uncomparable is an error whose dynamic type is not comparable.
+type uncomparable []string
+
+func (e uncomparable) Error() string { return "uncomparable" }
+
+func TestClassify(t *testing.T) {
+	for _, tt := range []struct {
+		err error
+		cat Category
+	}{
+		{nil, ""},
+		{io.EOF, Unknown},
+		{uncomparable{"x"}, Unknown},
+		{os.ErrNotExist, NotFound},
+		{&os.PathError{Op: "open", Path: "/x", Err: syscall.ENOENT}, NotFound},
+		{fmt.Errorf("open: %w", os.ErrPermission), Permission},
+		{syscall.EACCES, Permission},
+		{os.ErrExist, Conflict},
+		{os.ErrInvalid, Invalid},
+		{context.Canceled, Canceled},
+		{fmt.Errorf("call: %w", context.DeadlineExceeded), Timeout},
+		{timeoutErr{}, Timeout},
+		{httpErr(404), NotFound},
+		{httpErr(403), Permission},
+		{httpErr(409), Conflict},
+		{httpErr(500), Unknown},
+		{grpcErr{&grpcStatus{code: grpcNotFound}}, NotFound},
+		{grpcErr{&grpcStatus{code: grpcUnauthenticated}}, Permission},
+		{grpcErr{&grpcStatus{code: grpcUnavailable}}, Unknown},
+		{grpcErr{}, Unknown},
+		// The outermost classified error wins.
+		{multi{io.EOF, httpErr(400), os.ErrNotExist}, Invalid},
+		{fmt.Errorf("%w: %v", httpErr(404), context.Canceled), NotFound},
+	} {
+		if got := Classify(tt.err); got != tt.cat {
+			t.Errorf("Classify(%v): got %q, want %q", tt.err, got, tt.cat)
+		}
+	}
+}
+
+func TestCategory(t *testing.T) {
+	setDefaults(t)
+	notFound := fmt.Errorf("lookup: %w", os.ErrNotExist)
+	for _, tt := range []struct {
+		name string
+		got  error
+		want interface{}
+		out  string
+	}{
+		{
+			name: "match",
+			got:  notFound,
+			want: NotFound,
+		}, {
+			name: "conversion",
+			got:  notFound,
+			want: Category(NotFound),
+		}, {
+			name: "wrong",
+			got:  notFound,
+			want: Permission,
+			out:  sprintf(wrongCategory, "lookup: file does not exist", "NotFound", "Permission"),
+		}, {
+			name: "unknown",
+			got:  errors.New("oops"),
+			want: Timeout,
+			out:  sprintf(wrongCategory, "oops", "Unknown", "Timeout"),
+		}, {
+			name: "missing",
+			want: Timeout,
+			out:  sprintf(expectedCategory, "Timeout"),
+		}, {
+			name: "custom",
+			got:  notFound,
+			want: Category("RateLimited"),
+			out:  sprintf(wrongCategory, "lookup: file does not exist", "NotFound", "RateLimited"),
+		},
+	} {
+		if s := Error(tt.got, tt.want); s != tt.out {
+			t.Errorf("%s: got %q, want %q", tt.name, s, tt.out)
+		}
+	}
+	if got, want := Describe(NotFound), "an error of category NotFound"; got != want {
+		t.Errorf("Describe: got %q, want %q", got, want)
+	}
+	if got, want := wantName(NotFound), "category_NotFound"; got != want {
+		t.Errorf("wantName: got %q, want %q", got, want)
+	}
+}
+
+// saveRules restores the registered category rules when t completes.
+func saveRules(t *testing.T) {
+	categoryRules.mu.Lock()
+	saved := categoryRules.rules
+	categoryRules.mu.Unlock()
+	t.Cleanup(func() {
+		categoryRules.mu.Lock()
+		categoryRules.rules = saved
+		categoryRules.mu.Unlock()
+	})
+}
+
+type versionErr struct{ v int }
+
+func (e *versionErr) Error() string { return fmt.Sprintf("version %d is stale", e.v) }
+
+func TestRegister(t *testing.T) {
+	saveRules(t)
+	errNoKey := errors.New("no such key")
+	errQuota := errors.New("quota exceeded")
+	RegisterSentinel(NotFound, errNoKey)
+	RegisterType(Conflict, (*versionErr)(nil))
+	RegisterPredicate(Category("RateLimited"), func(err error) bool { return err == errQuota })
+	RegisterSentinel(Invalid, os.ErrNotExist) // overrides the built in rule
+
+	for _, tt := range []struct {
+		err error
+		cat Category
+	}{
+		{errNoKey, NotFound},
+		{fmt.Errorf("get: %w", errNoKey), NotFound},
+		{&versionErr{3}, Conflict},
+		{fmt.Errorf("%w", errQuota), Category("RateLimited")},
+		{os.ErrNotExist, Invalid},
+		{io.EOF, Unknown},
+	} {
+		if got := Classify(tt.err); got != tt.cat {
+			t.Errorf("Classify(%v): got %q, want %q", tt.err, got, tt.cat)
+		}
+	}
+	if s := Error(fmt.Errorf("put: %w", &versionErr{1}), Conflict); s != "" {
+		t.Error(s)
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.20
+// +build go1.20
+
+package check
+
+import "context"
+
+// noCause is appended to failures of ContextCause when ctx was canceled, or
+// expired, without a cause.
+const noCause = "\n(the context ended without a cause)"
+
+// ContextCause checks context.Cause(ctx) against want, as by Error.  A ctx
+// that is not done has no cause and so matches a nil want.  A ctx canceled
+// with a cause, such as by the cancel function of context.WithCancelCause or
+// by an errgroup.Group created with WithContext, has that cause, while a ctx
+// canceled without one has the cause context.Canceled; a failure in the
+// latter case says so.
+//
+//	g, ctx := errgroup.WithContext(ctx)
+//	...
+//	g.Wait()
+//	if s := check.ContextCause(ctx, ErrQuotaExceeded); s != "" {
+//		t.Error(s)
+//	}
+func ContextCause(ctx context.Context, want interface{}) string {
+	return defaults().contextCause(ctx, want)
+}
+
+// ContextCause is the same as the ContextCause function but uses the options of
+// ck.
+func (ck *Checker) ContextCause(ctx context.Context, want interface{}) Result {
+	ck.count()
+	return Result(ck.c.contextCause(ctx, want))
+}
+
+func (c *config) contextCause(ctx context.Context, want interface{}) string {
+	cause := context.Cause(ctx)
+	if c.outer() {
+		return c.run("ContextCause", cause, func() string { return Describe(want) },
+			func(c *config) string { return c.contextCause(ctx, want) })
+	}
+	s := c.checkError(cause, want)
+	if s != "" && cause != nil && cause == ctx.Err() {
+		s += noCause
+	}
+	return s
+}
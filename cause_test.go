@@ -0,0 +1,57 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.20
+// +build go1.20
+
+package check
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestContextCause(t *testing.T) {
+	setDefaults(t)
+	quota := errors.New("quota exceeded")
+
+	live := context.Background()
+	plain, cancel := context.WithCancel(live)
+	cancel()
+	caused, cancelCause := context.WithCancelCause(live)
+	cancelCause(quota)
+	expired, cancel := context.WithTimeout(live, -1)
+	defer cancel()
+
+	for _, tt := range []struct {
+		name string
+		ctx  context.Context
+		want interface{}
+		out  string
+	}{
+		{name: "live", ctx: live},
+		{name: "live want", ctx: live, want: quota, out: sprintf(expected, quota)},
+		{name: "caused", ctx: caused, want: quota},
+		{name: "caused is canceled", ctx: caused, want: context.Canceled, out: sprintf(wrong, quota, context.Canceled)},
+		{name: "plain", ctx: plain, want: context.Canceled},
+		{name: "plain want cause", ctx: plain, want: quota, out: sprintf(wrong, context.Canceled, quota) + noCause},
+		{name: "expired", ctx: expired, want: context.DeadlineExceeded},
+		{name: "expired want nil", ctx: expired, out: sprintf(unexpected, context.DeadlineExceeded) + noCause},
+	} {
+		if s := ContextCause(tt.ctx, tt.want); s != tt.out {
+			t.Errorf("%s: got %q, want %q", tt.name, s, tt.out)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Walk calls f with err and then, depth first, with each error err wraps,
+// either through an Unwrap() error or an Unwrap() []error method, until f
+// returns false.  It reports whether the walk completed.  Walk visits the
+// errors the checks of this package do, so packages that extend them, such as
+// checkproto, search a chain as they do:
+//
+//	check.Walk(err, func(err error) bool {
+//		_, ok := err.(*QuotaError)
+//		return !ok
+//	})
+func Walk(err error, f func(error) bool) bool {
+	return walk(err, f)
+}
+
+// walk calls f with err and then, depth first, with each error err wraps,
+// either through an Unwrap() error or an Unwrap() []error method.  Walking
+// stops when f returns false, in which case walk returns false.  Nil errors
+// joined by an Unwrap() []error method are skipped, and an Unwrap method that
+// panics is treated as wrapping nothing.
+func walk(err error, f func(error) bool) bool {
+	if err == nil {
+		return true
+	}
+	if !f(err) {
+		return false
+	}
+	for _, err := range unwrapAll(err) {
+		if !walk(err, f) {
+			return false
+		}
+	}
+	return true
+}
+
+// unwrapAll returns the non-nil errors err wraps through either form of
+// Unwrap, if any, recovering from a panic by Unwrap.
+func unwrapAll(err error) []error {
+	errs, _ := unwrapChecked(err)
+	var out []error
+	for _, e := range errs {
+		if e != nil {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// unwrapChecked returns the errors err wraps through either form of Unwrap,
+// which may include nils.  If Unwrap panics the value it panicked with is
+// returned.
+func unwrapChecked(err error) (errs []error, p interface{}) {
+	defer func() {
+		if r := recover(); r != nil {
+			errs, p = nil, r
+		}
+	}()
+	switch e := err.(type) {
+	case interface{ Unwrap() error }:
+		return []error{e.Unwrap()}, nil
+	case interface{ Unwrap() []error }:
+		return e.Unwrap(), nil
+	}
+	return nil, nil
+}
+
+// malformed returns a description of each malformed Unwrap() []error method
+// in the tree of err: one that returns no errors or a nil error, or that
+// panics, as does an Unwrap() error method that panics.
+func malformed(err error) []string {
+	var problems []string
+	walk(err, func(err error) bool {
+		errs, p := unwrapChecked(err)
+		if p != nil {
+			problems = append(problems, sprintf("(%T).Unwrap panicked: %v", err, p))
+			return true
+		}
+		if _, ok := err.(interface{ Unwrap() []error }); !ok {
+			return true
+		}
+		if len(errs) == 0 {
+			problems = append(problems, sprintf("(%T).Unwrap returned no errors", err))
+		}
+		for i, e := range errs {
+			if e == nil {
+				problems = append(problems, sprintf("(%T).Unwrap returned nil at index %d", err, i))
+			}
+		}
+		return true
+	})
+	return problems
+}
+
+// message returns err.Error(), recovering from a panic by Error, as can
+// happen with a typed nil error.
+func message(err error) (msg string) {
+	defer func() {
+		if p := recover(); p != nil {
+			msg = sprintf("<Error panicked: %v>", p)
+		}
+	}()
+	return err.Error()
+}
+
+// typedNil reports whether err is a non-nil error holding a nil pointer,
+// map, slice, func, chan, or interface.
+func typedNil(err error) bool {
+	if err == nil {
+		return false
+	}
+	v := reflect.ValueOf(err)
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan, reflect.Interface:
+		return v.IsNil()
+	}
+	return false
+}
+
+// chainLines returns a line for err and each error it wraps, giving its
+// type and quoted message.  Errors wrapped by an error with an
+// Unwrap() []error method are indented beneath it.
+func chainLines(err error) []string {
+	var lines []string
+	var add func(err error, depth int)
+	add = func(err error, depth int) {
+		for err != nil {
+			prefix := ""
+			for i := 0; i < depth; i++ {
+				prefix += "  "
+			}
+			lines = append(lines, sprintf("%s%T: %s", prefix, err, strconv.Quote(message(err))))
+			if _, ok := err.(interface{ Unwrap() []error }); ok {
+				for _, err := range unwrapAll(err) {
+					add(err, depth+1)
+				}
+				return
+			}
+			errs := unwrapAll(err)
+			if len(errs) == 0 {
+				return
+			}
+			err = errs[0]
+		}
+	}
+	add(err, 0)
+	return lines
+}
+
+// chain returns the lines of chainLines(err) indented beneath a "chain:"
+// label, to be appended to a failure.
+func chain(err error) string {
+	var b strings.Builder
+	b.WriteString("\n\tchain:")
+	for _, line := range chainLines(err) {
+		b.WriteString("\n\t\t")
+		b.WriteString(line)
+	}
+	return b.String()
+}
+
+// Verbose returns an Option that appends the chain of the error checked, the
+// type and message of each error it wraps, to each failure, as NoError does.
+// This shows why, for example, Is did not find the error wanted.
+func Verbose() Option {
+	return func(c *config) { c.verbose = true }
+}
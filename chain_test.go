@@ -0,0 +1,94 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+// multi is an error that wraps several errors, as returned by errors.Join.
+type multi []error
+
+func (m multi) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "\n")
+}
+
+func (m multi) Unwrap() []error { return m }
+
+func TestWalk(t *testing.T) {
+	a := errors.New("a")
+	b := errors.New("b")
+	c := fmt.Errorf("c: %w", b)
+	err := fmt.Errorf("top: %w", multi{a, c})
+
+	var seen []string
+	Walk(err, func(err error) bool {
+		seen = append(seen, strings.SplitN(err.Error(), ":", 2)[0])
+		return true
+	})
+	if got, want := strings.Join(seen, ","), "top,a\nc,a,c,b"; got != want {
+		t.Errorf("walked %q, want %q", got, want)
+	}
+
+	seen = nil
+	if Walk(err, func(err error) bool {
+		seen = append(seen, err.Error())
+		return err != a
+	}) {
+		t.Errorf("Walk did not return false when stopped")
+	}
+	if len(seen) != 3 {
+		t.Errorf("walked %d errors, want 3", len(seen))
+	}
+	if !Walk(nil, func(error) bool { return false }) {
+		t.Errorf("Walk of nil returned false")
+	}
+}
+
+func TestVerbose(t *testing.T) {
+	setDefaults(t)
+	perr := &os.PathError{Op: "open", Path: "x", Err: os.ErrPermission}
+	got := fmt.Errorf("load: %w", perr)
+	want := sprintf(wrong, got, os.ErrNotExist) +
+		"\n\tchain:" +
+		"\n\t\t*fmt.wrapError: \"load: open x: permission denied\"" +
+		"\n\t\t*fs.PathError: \"open x: permission denied\"" +
+		"\n\t\t*errors.errorString: \"permission denied\""
+	if s := Is(got, os.ErrNotExist, Verbose()); s != want {
+		t.Errorf("Is: got %q, want %q", s, want)
+	}
+	if s := Is(got, os.ErrPermission, Verbose()); s != "" {
+		t.Errorf("passing Is: got %q", s)
+	}
+	if s := Is(nil, os.ErrNotExist, Verbose()); s != sprintf(expected, os.ErrNotExist) {
+		t.Errorf("nil got: got %q", s)
+	}
+	ck := NewChecker(Verbose(), Codes())
+	if s := ck.Error(io.EOF, "x"); s != Result("CHK-WRONG: "+sprintf(wrong, io.EOF, "x")+"\n\tchain:\n\t\t*errors.errorString: \"EOF\"") {
+		t.Errorf("Error: got %q", s)
+	}
+	if s, want := ck.NoError(io.EOF), NewChecker(Codes()).NoError(io.EOF); s != want {
+		t.Errorf("NoError: got %q, want %q", s, want)
+	}
+}
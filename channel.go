@@ -0,0 +1,108 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import "time"
+
+// ErrorFrom returns the empty string if an error, or nil, matched by want,
+// as by Error, is received from ch within timeout, otherwise it returns a
+// string indicating the failure.  Timing out and ch being closed are failures
+// of their own:
+//
+//	errc := make(chan error, 1)
+//	go func() { errc <- srv.Serve(l) }()
+//	l.Close()
+//	if s := check.ErrorFrom(errc, time.Second, "use of closed network connection"); s != "" {
+//		t.Error(s)
+//	}
+//
+// Within a testing/synctest bubble timeout is measured in the bubble's
+// virtual time.
+func ErrorFrom(ch <-chan error, timeout time.Duration, want interface{}) string {
+	return defaults().errorFrom(ch, timeout, want)
+}
+
+// ErrorFrom is the same as the ErrorFrom function but uses the options of ck.
+func (ck *Checker) ErrorFrom(ch <-chan error, timeout time.Duration, want interface{}) Result {
+	ck.count()
+	return Result(ck.c.errorFrom(ch, timeout, want))
+}
+
+// Silent returns the empty string if nothing, neither an error nor nil, is
+// received from ch within d, and ch is not closed, otherwise it returns a
+// string indicating what was received.  Silent asserts that asynchronous code
+// has not failed, or finished, early.
+func Silent(ch <-chan error, d time.Duration) string {
+	return defaults().silent(ch, d)
+}
+
+// Silent is the same as the Silent function but uses the options of ck.
+func (ck *Checker) Silent(ch <-chan error, d time.Duration) Result {
+	ck.count()
+	return Result(ck.c.silent(ch, d))
+}
+
+// receive returns the value received from ch within d, whether one was
+// received, and whether ch is closed.  A value already sent is received even
+// if d is 0.
+func receive(ch <-chan error, d time.Duration) (err error, received, closed bool) {
+	select {
+	case err, ok := <-ch:
+		return err, ok, !ok
+	default:
+	}
+	timeout, stop := after(d)
+	defer stop()
+	select {
+	case err, ok := <-ch:
+		return err, ok, !ok
+	case <-timeout:
+		return nil, false, false
+	}
+}
+
+// errorFrom implements ErrorFrom using the settings in c.
+func (c *config) errorFrom(ch <-chan error, timeout time.Duration, want interface{}) string {
+	if c.outer() {
+		return c.run("ErrorFrom", nil, func() string { return Describe(want) },
+			func(c *config) string { return c.errorFrom(ch, timeout, want) })
+	}
+	err, received, closed := receive(ch, timeout)
+	switch {
+	case closed:
+		return c.failc(CodeMissing, "channel closed, want %q", description(Describe(want)))
+	case !received:
+		return c.failc(CodeTimeout, sprintf("timed out after %v waiting for error, want %%q", timeout), description(Describe(want)))
+	}
+	return c.checkError(err, want)
+}
+
+// silent implements Silent using the settings in c.
+func (c *config) silent(ch <-chan error, d time.Duration) string {
+	if c.outer() {
+		return c.run("Silent", nil, func() string { return Describe(nil) },
+			func(c *config) string { return c.silent(ch, d) })
+	}
+	err, received, closed := receive(ch, d)
+	switch {
+	case closed:
+		return c.failc(CodeUnexpected, sprintf("channel closed, want silence for %v", d))
+	case !received:
+		return ""
+	case err == nil:
+		return c.failc(CodeUnexpected, sprintf("received no error, want silence for %v", d))
+	}
+	return c.failc(CodeUnexpected, sprintf("received error %%q, want silence for %v", d), err)
+}
@@ -0,0 +1,78 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"io"
+	"testing"
+	"time"
+)
+
+// sent returns a channel holding errs that is closed if closed is true.
+func sent(closed bool, errs ...error) <-chan error {
+	ch := make(chan error, len(errs))
+	for _, err := range errs {
+		ch <- err
+	}
+	if closed {
+		close(ch)
+	}
+	return ch
+}
+
+func TestErrorFrom(t *testing.T) {
+	setDefaults(t)
+	for _, tt := range []struct {
+		name    string
+		ch      <-chan error
+		timeout time.Duration
+		want    interface{}
+		out     string
+	}{
+		{"error", sent(false, io.EOF), time.Millisecond, "EOF", ""},
+		{"no wait", sent(false, io.EOF), 0, io.EOF, ""},
+		{"nil", sent(false, nil), time.Millisecond, nil, ""},
+		{"wrong", sent(false, io.EOF), time.Millisecond, "timeout", sprintf(wrong, io.EOF, "timeout")},
+		{"unexpected", sent(false, io.EOF), time.Millisecond, nil, sprintf(unexpected, io.EOF)},
+		{"timeout", sent(false), 10 * time.Millisecond, "EOF", `timed out after 10ms waiting for error, want contains "EOF"`},
+		{"closed", sent(true), time.Millisecond, "EOF", `channel closed, want contains "EOF"`},
+		{"value before close", sent(true, io.EOF), time.Millisecond, "EOF", ""},
+	} {
+		if s := ErrorFrom(tt.ch, tt.timeout, tt.want); s != tt.out {
+			t.Errorf("%s: got %q, want %q", tt.name, s, tt.out)
+		}
+	}
+	if s := defaults().with(Codes()).errorFrom(sent(false), 0, nil); s != "CHK-TIMEOUT: timed out after 0s waiting for error, want no error" {
+		t.Errorf("Codes: got %q", s)
+	}
+}
+
+func TestSilent(t *testing.T) {
+	setDefaults(t)
+	for _, tt := range []struct {
+		name string
+		ch   <-chan error
+		out  string
+	}{
+		{"silent", sent(false), ""},
+		{"error", sent(false, io.EOF), `received error "EOF", want silence for 10ms`},
+		{"nil", sent(false, nil), "received no error, want silence for 10ms"},
+		{"closed", sent(true), "channel closed, want silence for 10ms"},
+	} {
+		if s := Silent(tt.ch, 10*time.Millisecond); s != tt.out {
+			t.Errorf("%s: got %q, want %q", tt.name, s, tt.out)
+		}
+	}
+}
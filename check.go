@@ -44,12 +44,22 @@
 // message contains the string, case sensitive.  Strings cast to Case are
 // checked case insensitive while Equal and CaseEqual require the entire error
 // message to be matched either case sensitive or insensitive respectively.
+// Strings cast to Regexp are regular expressions the message must match.
+//
+// The rendering of failures may be changed by passing Options, such as Quote,
+// to SetDefaults.
+//
+// If the environment variable CHECK_REPORT_FILE is set, each failure is also
+// appended, as a line of JSON, to the file it names (see NDJSON).
 package check
 
 import (
 	"errors"
 	"fmt"
+	"reflect"
+	"regexp"
 	"strings"
+	"sync"
 )
 
 // Type Equal is a string that an error must match exactly.
@@ -61,112 +71,325 @@ type Case string
 // Type CaseEqual is a string that error must case insensitive match exactly.
 type CaseEqual string
 
+// Type Regexp is a regular expression, as accepted by the regexp package, that
+// the error must match.  The pattern is not anchored; use ^ and $ to match
+// the entire message.
+type Regexp string
+
 // error formats
 
 const (
-	unexpected = "got unexpected error %q"
-	expected   = "did not get expected error %q"
-	wrong      = "got error %q, want %q"
+	unexpected  = "got unexpected error %q"
+	expected    = "did not get expected error %q"
+	missing     = "did not get expected error"
+	wrong       = "got error %q, want %q"
+	noMatch     = "got error %q, want a match of pattern %q"
+	badPattern  = "pattern %q did not compile: %q"
+	unsupported = "Check does not support type %T"
+
+	expectedType = "did not get expected error of type %q"
+	wrongType    = "got error %q, want error of type %q"
+	unwantedType = "got error %q, want no error of type %q"
 )
 
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
 var sprintf = fmt.Sprintf
 
 // Error compares error got to interface want returning an empty string if they
 // match or an error string if they are different.  The type of want determines
 // how the check is made.
 //
-//	error:     got must be exactly want
-//	bool:      check for existance of error
-//	string:    check if got.Error() contains want
-//	Case:      check if got.Error() contains want, case insensitive
-//	Equal:     check if got.Error() is want
-//	CaseEqual: check if got.Error() is want, case insensitive
+//	error:              got must be exactly want
+//	bool:               check for existance of error
+//	string:             check if got.Error() contains want
+//	Case:               check if got.Error() contains want, case insensitive
+//	Equal:              check if got.Error() is want
+//	CaseEqual:          check if got.Error() is want, case insensitive
+//	Regexp:             check if got.Error() matches the regular expression want
+//	Matcher:            check as described by the Matcher (e.g., Similar)
+//	CustomMatcher:      check by calling its Match method
+//	func(error) bool:   check that want(got) returns true
+//	func(error) string: check that want(got) returns the empty string
+//	*T:                 check errors.As(got, want), setting *want to the error found
+//
+// A want of type *T is a non-nil pointer where T is an interface type or
+// implements error, e.g., *error, **fs.PathError, or *MyError.  This is
+// useful for capturing the error for further checks:
+//
+//	var perr *fs.PathError
+//	if s := check.Error(err, &perr); s != "" {
+//		t.Fatalf("Calling myFunc: %s", s)
+//	}
+//	if perr.Path != "/tmp/x" {
+//		...
 func Error(got error, want interface{}) string {
+	return defaults().checkError(got, want)
+}
+
+// checkError implements Error using the settings in c.
+func (c *config) checkError(got error, want interface{}) string {
+	if c.outer() {
+		return c.run("Error", got, func() string { return Describe(want) },
+			func(c *config) string { return c.checkError(got, want) })
+	}
+	if c.formatter != nil {
+		return c.format("Error", matchKind(want), got, want,
+			func(c *config) string { return c.checkError(got, want) })
+	}
+	if c.transforms != nil {
+		// The transformed want is checked without the transforms so
+		// Matchers that check their own want, such as FirstLine, do not
+		// transform it again.
+		nc := *c
+		nc.transforms = nil
+		return nc.checkError(got, c.transforms.apply(want))
+	}
+	if _, ok := want.(error); !ok {
+		// An error want is checked by identity, not by message.
+		got = c.normalized(c.formatted(got))
+	}
+	if c.strict {
+		if err := validate(want); err != nil {
+			panic("check: " + err.Error())
+		}
+	}
+	if c.noEmpty {
+		if s := emptyWant(want); s != "" {
+			if c.strict {
+				panic("check: " + s)
+			}
+			return c.failc(CodeUnsupported, s)
+		}
+	}
+	if c.cache != nil {
+		if s, ok := c.memoized(got, want); ok {
+			return s
+		}
+	}
+	if s, ok := c.matchMessage(got, want); ok {
+		return s
+	}
 	switch want := want.(type) {
+	case Matcher:
+		return want.match(c, got)
+	case CustomMatcher:
+		return c.matchCustom(want, got)
+	case func(error) bool:
+		return c.matchPredicate(want, got)
+	case func(error) string:
+		if want == nil {
+			return c.matchPredicate(nil, got)
+		}
+		return c.matchCustom(matchFunc(want), got)
 	case bool:
 		switch want {
 		case (got != nil):
 			return ""
 		case true:
-			return sprintf("did not get expected error")
+			return c.failf(missing)
 		default:
-			return sprintf(unexpected, got)
+			return c.failf(unexpected, got)
+		}
+	case nil:
+		// A nil interface appears to the type switch as type nil.
+		// This means want can be any interface
+		// type rather than only the error interface.  It also means
+		// in the error case below we know want != nil.
+		switch {
+		case got == nil:
+			return ""
+		default:
+			return c.failf(unexpected, got)
+		}
+	case error:
+		switch {
+		case got == nil:
+			return c.failf(expected, wantArg(want))
+		case want != got:
+			return c.failf(wrong, got, wantArg(want))
+		default:
+			return ""
 		}
+	default:
+		if t := asTarget(want); t != nil {
+			switch {
+			case got == nil:
+				return c.failf(expectedType, t)
+			case !errors.As(got, want):
+				return c.failf(wrongType, got, t)
+			default:
+				return ""
+			}
+		}
+		if c.quiet {
+			return quietFailure
+		}
+		return c.code(CodeUnsupported) + sprintf(unsupported, want)
+	}
+}
+
+// matchMessage returns the result of checking the message of got against
+// want and true if want is a string, Case, Equal, CaseEqual, or Regexp,
+// otherwise it returns false.
+func (c *config) matchMessage(got error, want interface{}) (string, bool) {
+	switch want := want.(type) {
 	case Equal:
 		switch {
 		case got == nil && want == "":
-			return ""
+			return "", true
 		case got == nil:
-			return sprintf(expected, want)
+			return c.failf(expected, want), true
 		case want == "":
-			return sprintf(unexpected, got)
+			return c.failf(unexpected, got), true
 		case got.Error() != string(want):
-			return sprintf(wrong, got, want)
+			return c.failf(wrong, got, want) + c.caret(got.Error(), string(want), false) + c.hint(got.Error(), string(want), false, false), true
 		default:
-			return ""
+			return "", true
 		}
 	case CaseEqual:
 		switch {
 		case got == nil && want == "":
-			return ""
+			return "", true
 		case got == nil:
-			return sprintf(expected, want)
+			return c.failf(expected, want), true
 		case want == "":
-			return sprintf(unexpected, got)
-		case strings.ToLower(got.Error()) != strings.ToLower(string(want)):
-			return sprintf(wrong, got, want)
+			return c.failf(unexpected, got), true
+		case c.toLower(got.Error()) != c.toLower(string(want)):
+			return c.failf(wrong, got, want) + c.caret(got.Error(), string(want), true) + c.hint(got.Error(), string(want), true, false), true
 		default:
-			return ""
+			return "", true
 		}
 	case Case:
 		switch {
 		case got == nil && want == "":
-			return ""
+			return "", true
 		case got == nil:
-			return sprintf(expected, want)
+			return c.failf(expected, want), true
 		case want == "":
-			return sprintf(unexpected, got)
-		case !strings.Contains(strings.ToLower(got.Error()), strings.ToLower(string(want))):
-			return sprintf(wrong, got, want)
+			return c.failf(unexpected, got), true
+		case !strings.Contains(c.toLower(got.Error()), c.toLower(string(want))):
+			return c.failf(wrong, got, want) + c.nearMatch(got.Error(), string(want)) + c.hint(got.Error(), string(want), true, true), true
 		default:
-			return ""
+			return "", true
 		}
-	case string:
+	case Regexp:
+		re, err := compileRegexp(string(want))
 		switch {
+		case err != nil:
+			return c.failf(badPattern, want, err), true
 		case got == nil && want == "":
-			return ""
+			return "", true
 		case got == nil:
-			return sprintf(expected, want)
+			return c.failf(expected, want), true
 		case want == "":
-			return sprintf(unexpected, got)
-		case !strings.Contains(got.Error(), want):
-			return sprintf(wrong, got, want)
+			return c.failf(unexpected, got), true
+		case !re.MatchString(got.Error()):
+			return c.failf(noMatch, got, want), true
 		default:
-			return ""
+			return "", true
 		}
-	case nil:
-		// A nil interface appears to the type switch as type nil.
-		// This means want can be any interface
-		// type rather than only the error interface.  It also means
-		// in the error case below we know want != nil.
+	case string:
 		switch {
+		case got == nil && want == "":
+			return "", true
 		case got == nil:
-			return ""
+			return c.failf(expected, want), true
+		case want == "":
+			return c.failf(unexpected, got), true
+		case !strings.Contains(got.Error(), want):
+			return c.failf(wrong, got, want) + c.nearMatch(got.Error(), want) + c.hint(got.Error(), want, false, true), true
 		default:
-			return sprintf(unexpected, got)
+			return "", true
 		}
-	case error:
-		switch {
-		case got == nil:
-			return sprintf(expected, want)
-		case want != got:
-			return sprintf(wrong, got, want)
-		default:
-			return ""
+	}
+	return "", false
+}
+
+// asTarget returns the type T if want is a non-nil *T that may be passed as
+// the target of errors.As, otherwise it returns nil.
+func asTarget(want interface{}) reflect.Type {
+	v := reflect.ValueOf(want)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return nil
+	}
+	if t := v.Type().Elem(); t.Kind() == reflect.Interface || t.Implements(errorType) {
+		return t
+	}
+	return nil
+}
+
+// quiet is the configuration used by Matches.
+var quiet = config{quiet: true}
+
+// Matches reports whether got matches want, as described by Error.  Unlike
+// Error, Matches does no formatting of messages, making it suitable for use
+// outside of tests, such as in retry predicates and health checks, that want
+// the same semantics.  An unsupported want never matches.
+func Matches(got error, want interface{}) bool {
+	return quiet.checkError(got, want) == ""
+}
+
+// NoError returns the empty string if got is nil, otherwise it returns a
+// string describing got in detail: its message, its dynamic type, whether it
+// is a typed nil (a nil pointer stored in an error), and its unwrap chain.
+func NoError(got error) string {
+	return defaults().noError(got)
+}
+
+func (c *config) noError(got error) string {
+	if c.outer() {
+		return c.run("NoError", got, func() string { return Describe(nil) },
+			func(c *config) string { return c.noError(got) })
+	}
+	if got == nil {
+		return ""
+	}
+	if c.quiet {
+		return quietFailure
+	}
+	var b strings.Builder
+	b.WriteString(c.failf(unexpected, message(got)))
+	if typedNil(got) {
+		b.WriteString(sprintf("\n\ttype: %T (typed nil)", got))
+	} else {
+		b.WriteString(sprintf("\n\ttype: %T", got))
+	}
+	b.WriteString(chain(got))
+	return b.String()
+}
+
+// HasError returns the empty string if got is not nil and matches each of
+// classifiers, as by Error, otherwise it returns a string indicating each
+// failure, one per line.  If got is nil the classifiers are described, as by
+// Describe.  It combines Error(got, true) with further checks:
+//
+//	// There must be an error and it must be a timeout.
+//	if s := check.HasError(err, ErrTimeout); s != "" {
+//		t.Error(s)
+//	}
+func HasError(got error, classifiers ...interface{}) string {
+	return defaults().hasError(got, classifiers)
+}
+
+func (c *config) hasError(got error, classifiers []interface{}) string {
+	if c.outer() {
+		return c.run("HasError", got, func() string { return describeAll(classifiers) },
+			func(c *config) string { return c.hasError(got, classifiers) })
+	}
+	if got == nil {
+		if len(classifiers) == 0 || c.quiet {
+			return c.failf(missing)
 		}
-	default:
-		return sprintf("Check does not support type %T", want)
+		return c.failf(missing) + "\nwant: " + describeAll(classifiers)
 	}
+	var failures []string
+	for _, want := range classifiers {
+		if s := c.checkError(got, want); s != "" {
+			failures = append(failures, s)
+		}
+	}
+	return strings.Join(failures, "\n")
 }
 
 // ErrorCase returns the empty string if got.Error() contains want, case
@@ -175,31 +398,179 @@ func ErrorCase(got error, want string) string {
 	return Error(got, Case(want))
 }
 
+// ErrorCase is the same as the ErrorCase function but uses the options of ck.
+func (ck *Checker) ErrorCase(got error, want string) Result {
+	return ck.Error(got, Case(want))
+}
+
 // ErrorCaseEqual returns the empty string if got.Error() matches want, case
 // insensitive, otherwise it returns a string indicating the error.
 func ErrorCaseEqual(got error, want string) string {
 	return Error(got, CaseEqual(want))
 }
 
+// ErrorCaseEqual is the same as the ErrorCaseEqual function but uses the
+// options of ck.
+func (ck *Checker) ErrorCaseEqual(got error, want string) Result {
+	return ck.Error(got, CaseEqual(want))
+}
+
 // ErrorEqual returns the empty string if got.Error() exactly matches want
 // otherwise it returns a string indicating the error.
 func ErrorEqual(got error, want string) string {
 	return Error(got, Equal(want))
 }
 
-// Is returns the empty string if want is is or is wrapped in got
-// otherwise it returns a string indicating the error.
+// ErrorEqual is the same as the ErrorEqual function but uses the options of ck.
+func (ck *Checker) ErrorEqual(got error, want string) Result {
+	return ck.Error(got, Equal(want))
+}
+
+// ErrorRegexp returns the empty string if got.Error() matches the regular
+// expression pattern, otherwise it returns a string indicating the error.
+func ErrorRegexp(got error, pattern string) string {
+	return Error(got, Regexp(pattern))
+}
+
+// ErrorRegexp is the same as the ErrorRegexp function but uses the options of
+// ck.
+func (ck *Checker) ErrorRegexp(got error, pattern string) Result {
+	return ck.Error(got, Regexp(pattern))
+}
+
+// regexps caches the compiled patterns of Regexp wants.
+var regexps sync.Map // map[string]*regexp.Regexp
+
+// compileRegexp returns pattern compiled, as by regexp.Compile.
+func compileRegexp(pattern string) (*regexp.Regexp, error) {
+	if re, ok := regexps.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	regexps.Store(pattern, re)
+	return re, nil
+}
+
+// MessagesEqual returns the empty string if got and want are both nil or both
+// have the same message, otherwise it returns a string indicating the error.
+// Only the messages are compared; unlike Error and Is, neither the identity
+// of the errors nor their chains are considered.  This is useful when checking
+// that a new implementation returns the same errors as an old one.
+func MessagesEqual(got, want error) string {
+	return defaults().messagesEqual(got, want)
+}
+
+func (c *config) messagesEqual(got, want error) string {
+	if c.outer() {
+		return c.run("MessagesEqual", got, func() string { return describeMessage(want) },
+			func(c *config) string { return c.messagesEqual(got, want) })
+	}
+	got, want = c.formatted(got), c.formatted(want)
+	switch {
+	case got == nil && want == nil:
+		return ""
+	case got == nil:
+		return c.failf(expected, message(want))
+	case want == nil:
+		return c.failf(unexpected, message(got))
+	}
+	g, w := message(got), message(want)
+	if g != w {
+		return c.failf(wrong, g, w) + c.caret(g, w, false) + c.hint(g, w, false, false)
+	}
+	return ""
+}
+
+// Is returns the empty string if want is or is wrapped in got, as determined
+// by errors.Is, otherwise it returns a string indicating the error.  The
+// check is made using the default options with opts applied.
+func Is(got, want error, opts ...Option) string {
+	return defaults().with(opts...).isError(got, want)
+}
+
+// IsError is the original name of Is and is the same as Is(got, want).
 func IsError(got, want error) string {
+	return Is(got, want)
+}
+
+// IsError is the same as the IsError function but uses the options of ck.
+func (ck *Checker) IsError(got, want error) Result {
+	return ck.Is(got, want)
+}
+
+// isError implements Is using the settings in c.
+func (c *config) isError(got, want error) string {
+	if c.outer() {
+		return c.run("Is", got, func() string { return Describe(want) },
+			func(c *config) string { return c.isError(got, want) })
+	}
+	if c.formatter != nil {
+		return c.format("Is", MatchIs, got, want,
+			func(c *config) string { return c.isError(got, want) })
+	}
 	switch {
 	case got == nil && want == nil:
 		return ""
 	case got == nil:
-		return sprintf(expected, want)
+		return c.failf(expected, wantArg(want))
 	case want == nil:
-		return sprintf(unexpected, got)
+		return c.failf(unexpected, got)
 	case !errors.Is(got, want):
-		return sprintf(wrong, got, want)
+		return c.failf(wrong, got, wantArg(want))
 	default:
 		return ""
 	}
 }
+
+const isNot = "got error %q, want an error that is not %q"
+
+// NotIsError returns the empty string if want is neither got nor wrapped in
+// got, as determined by errors.Is, otherwise it returns a string indicating
+// the error.  A nil got is never want unless want is also nil.  NotIsError
+// asserts that an error, if any, is not a particular error:
+//
+//	if s := check.NotIsError(err, context.Canceled); s != "" {
+func NotIsError(got, want error) string {
+	return defaults().notIsError(got, want)
+}
+
+// NotIsError is the same as the NotIsError function but uses the options of ck.
+func (ck *Checker) NotIsError(got, want error) Result {
+	ck.count()
+	return Result(ck.c.notIsError(got, want))
+}
+
+// notIsError implements NotIsError using the settings in c.
+func (c *config) notIsError(got, want error) string {
+	if c.outer() {
+		return c.run("NotIsError", got, func() string { return "not " + Describe(want) },
+			func(c *config) string { return c.notIsError(got, want) })
+	}
+	switch {
+	case got == nil && want == nil:
+		return c.failf(missing)
+	case errors.Is(got, want):
+		return c.failf(isNot, got, wantArg(want))
+	default:
+		return ""
+	}
+}
+
+// ErrorNot returns the empty string if got is not matched by want, as
+// determined by Error, otherwise it returns a string indicating the error.
+// ErrorNot is shorthand for Error(got, Not(want)).  As an error want is
+// matched by identity, NotIsError, rather than ErrorNot, asserts an error is
+// not a wrapped sentinel.  To also require an error, combine Not with Some:
+//
+//	check.Error(err, check.All(check.Some, check.Not("permission denied")))
+func ErrorNot(got error, want interface{}) string {
+	return Error(got, Not(want))
+}
+
+// ErrorNot is the same as the ErrorNot function but uses the options of ck.
+func (ck *Checker) ErrorNot(got error, want interface{}) Result {
+	return ck.Error(got, Not(want))
+}
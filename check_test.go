@@ -15,6 +15,7 @@
 package check
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"testing"
@@ -173,6 +174,33 @@ func TestError(t *testing.T) {
 			want: CaseEqual(err2u),
 			out:  sprintf(wrong, err1, err2u),
 		},
+		{
+			name: "regexp no-error",
+			want: Regexp(``),
+		}, {
+			name: "regexp expected",
+			got:  err1,
+			want: Regexp(`^Err o.e$`),
+		}, {
+			name: "regexp wrong",
+			got:  err1,
+			want: Regexp(`^Err t`),
+			out:  sprintf(noMatch, err1, `^Err t`),
+		}, {
+			name: "regexp missing",
+			want: Regexp(`^Err`),
+			out:  sprintf(expected, `^Err`),
+		}, {
+			name: "regexp unexpected",
+			got:  err1,
+			want: Regexp(``),
+			out:  sprintf(unexpected, err1),
+		}, {
+			name: "regexp bad pattern",
+			got:  err1,
+			want: Regexp(`Err (`),
+			out:  sprintf(badPattern, `Err (`, "error parsing regexp: missing closing ): `Err (`"),
+		},
 		{
 			name: `bad type`,
 			want: 1,
@@ -203,6 +231,11 @@ func TestError(t *testing.T) {
 			if s != tt.out {
 				t.Errorf(`case-equal-%s: got %q, want %q`, tt.name, s, tt.out)
 			}
+		case Regexp:
+			s := ErrorRegexp(tt.got, string(w))
+			if s != tt.out {
+				t.Errorf(`regexp-%s: got %q, want %q`, tt.name, s, tt.out)
+			}
 		}
 	}
 }
@@ -276,3 +309,282 @@ func TestIsError(t *testing.T) {
 		}
 	}
 }
+
+func TestErrorAs(t *testing.T) {
+	err1 := &errtype{E: "basic"}
+	wrap1 := fmt.Errorf("wrapped %w", err1)
+	err2 := errors.New("error 2")
+
+	var et *errtype
+	if s := Error(wrap1, &et); s != "" {
+		t.Errorf("errtype: %s", s)
+	} else if et != err1 {
+		t.Errorf("errtype: got %v, want %v", et, err1)
+	}
+
+	var e error
+	if s := Error(wrap1, &e); s != "" {
+		t.Errorf("error: %s", s)
+	} else if e != wrap1 {
+		t.Errorf("error: got %v, want %v", e, wrap1)
+	}
+
+	var tmp interface{ Timeout() bool }
+	for _, tt := range []struct {
+		name string
+		got  error
+		want interface{}
+		out  string
+	}{
+		{
+			name: "missing",
+			want: &et,
+			out:  sprintf(expectedType, "*check.errtype"),
+		}, {
+			name: "missing error",
+			want: &e,
+			out:  sprintf(expectedType, "error"),
+		}, {
+			name: "wrong",
+			got:  err2,
+			want: &et,
+			out:  sprintf(wrongType, err2, "*check.errtype"),
+		}, {
+			name: "interface",
+			got:  err2,
+			want: &tmp,
+			out:  sprintf(wrongType, err2, "interface { Timeout() bool }"),
+		}, {
+			name: "not error",
+			got:  err2,
+			want: new(int),
+			out:  `Check does not support type *int`,
+		},
+	} {
+		if s := Error(tt.got, tt.want); s != tt.out {
+			t.Errorf("%s: got %q, want %q", tt.name, s, tt.out)
+		}
+	}
+}
+
+func TestMatches(t *testing.T) {
+	err1 := errors.New("Err one")
+	for _, tt := range []struct {
+		got  error
+		want interface{}
+		ok   bool
+	}{
+		{nil, nil, true},
+		{nil, false, true},
+		{err1, true, true},
+		{err1, "one", true},
+		{err1, "two", false},
+		{err1, Case("ONE"), true},
+		{err1, Equal("Err"), false},
+		{err1, err1, true},
+		{err1, errors.New("Err one"), false},
+		{nil, err1, false},
+		{err1, MaxLen(3), false},
+		{err1, 1, false},
+	} {
+		if ok := Matches(tt.got, tt.want); ok != tt.ok {
+			t.Errorf("Matches(%v, %#v) got %t, want %t", tt.got, tt.want, ok, tt.ok)
+		}
+	}
+	// Options set on the defaults do not affect Matches.
+	setDefaults(t, Codes())
+	if s := quiet.checkError(err1, "two"); s != quietFailure {
+		t.Errorf("quiet failure got %q, want %q", s, quietFailure)
+	}
+}
+
+func TestIs(t *testing.T) {
+	err1 := &errtype{E: "basic"}
+	wrap1 := fmt.Errorf("wrapped %w", err1)
+	err2 := errors.New("error 2")
+
+	if s := Is(wrap1, err1); s != "" {
+		t.Errorf("wrapped: %s", s)
+	}
+	if s, out := Is(wrap1, err2, Codes(), Quote(QuoteBack)), "CHK-WRONG: got error `wrapped basic`, want `error 2`"; s != out {
+		t.Errorf("options: got %q, want %q", s, out)
+	}
+	// Options only apply to the call they are passed to.
+	if s, out := Is(wrap1, err2), sprintf(wrong, wrap1, err2); s != out {
+		t.Errorf("defaults: got %q, want %q", s, out)
+	}
+}
+
+// panicErr is an error whose Error method panics on a nil receiver.
+type panicErr struct{ msg string }
+
+func (e *panicErr) Error() string { return e.msg }
+
+func TestNoError(t *testing.T) {
+	err1 := &errtype{E: "basic"}
+	for _, tt := range []struct {
+		name string
+		got  error
+		out  string
+	}{
+		{
+			name: "nil",
+		}, {
+			name: "simple",
+			got:  errors.New("oops"),
+			out:  "got unexpected error \"oops\"\n\ttype: *errors.errorString\n\tchain:\n\t\t*errors.errorString: \"oops\"",
+		}, {
+			name: "wrapped",
+			got:  fmt.Errorf("outer: %w", err1),
+			out: "got unexpected error \"outer: basic\"\n\ttype: *fmt.wrapError\n\tchain:\n" +
+				"\t\t*fmt.wrapError: \"outer: basic\"\n" +
+				"\t\t*check.errtype: \"basic\"",
+		}, {
+			name: "joined",
+			got:  multi{errors.New("a"), fmt.Errorf("b: %w", err1)},
+			out: "got unexpected error:\n\ta\n\tb: basic\n\ttype: check.multi\n\tchain:\n" +
+				"\t\tcheck.multi: \"a\\nb: basic\"\n" +
+				"\t\t  *errors.errorString: \"a\"\n" +
+				"\t\t  *fmt.wrapError: \"b: basic\"\n" +
+				"\t\t  *check.errtype: \"basic\"",
+		}, {
+			name: "typed nil",
+			got:  (*errtype)(nil),
+			out:  "got unexpected error \"\"\n\ttype: *check.errtype (typed nil)\n\tchain:\n\t\t*check.errtype: \"\"",
+		}, {
+			name: "panics",
+			got:  (*panicErr)(nil),
+			out:  "got unexpected error \"<Error panicked: runtime error: invalid memory address or nil pointer dereference>\"\n\ttype: *check.panicErr (typed nil)\n\tchain:\n\t\t*check.panicErr: \"<Error panicked: runtime error: invalid memory address or nil pointer dereference>\"",
+		},
+	} {
+		if s := NoError(tt.got); s != tt.out {
+			t.Errorf("%s: got %q, want %q", tt.name, s, tt.out)
+		}
+	}
+}
+
+func TestHasError(t *testing.T) {
+	err1 := &errtype{E: "basic"}
+	wrap1 := fmt.Errorf("wrapped %w", err1)
+	var et *errtype
+	for _, tt := range []struct {
+		name        string
+		got         error
+		classifiers []interface{}
+		out         string
+	}{
+		{
+			name: "nil",
+			out:  "did not get expected error",
+		}, {
+			name:        "nil classified",
+			classifiers: []interface{}{"basic", SingleLine()},
+			out:         "did not get expected error\nwant: contains \"basic\" AND a single line message",
+		}, {
+			name: "any",
+			got:  err1,
+		}, {
+			name:        "classified",
+			got:         wrap1,
+			classifiers: []interface{}{"basic", &et, SingleLine()},
+		}, {
+			name:        "misclassified",
+			got:         wrap1,
+			classifiers: []interface{}{"basic", Equal("basic"), MaxLen(3)},
+			out: sprintf(wrong, wrap1, "basic") + "\n" +
+				`got error "wrapped basic", want a message of at most 3 runes (got 13)`,
+		},
+	} {
+		if s := HasError(tt.got, tt.classifiers...); s != tt.out {
+			t.Errorf("%s: got %q, want %q", tt.name, s, tt.out)
+		}
+	}
+}
+
+func TestMessagesEqual(t *testing.T) {
+	err1 := errors.New("basic")
+	err2 := &errtype{E: "basic"}
+	for _, tt := range []struct {
+		name string
+		got  error
+		want error
+		out  string
+	}{
+		{
+			name: "nil",
+		}, {
+			name: "same",
+			got:  err1,
+			want: err1,
+		}, {
+			name: "equivalent",
+			got:  err1,
+			want: err2,
+		}, {
+			name: "wrapped",
+			got:  fmt.Errorf("%w", err1),
+			want: err2,
+		}, {
+			name: "missing",
+			want: err1,
+			out:  sprintf(expected, "basic"),
+		}, {
+			name: "unexpected",
+			got:  err1,
+			out:  sprintf(unexpected, "basic"),
+		}, {
+			name: "different",
+			got:  err1,
+			want: errors.New("other"),
+			out:  sprintf(wrong, "basic", "other"),
+		},
+	} {
+		if s := MessagesEqual(tt.got, tt.want); s != tt.out {
+			t.Errorf("%s: got %q, want %q", tt.name, s, tt.out)
+		}
+	}
+}
+
+func TestNotIsError(t *testing.T) {
+	setDefaults(t)
+	canceled := fmt.Errorf("watch: %w", context.Canceled)
+	eof := errors.New("EOF")
+	for _, tt := range []struct {
+		got  error
+		want error
+		out  string
+	}{
+		{eof, context.Canceled, ""},
+		{nil, context.Canceled, ""},
+		{eof, nil, ""},
+		{nil, nil, missing},
+		{context.Canceled, context.Canceled, sprintf(isNot, context.Canceled, context.Canceled)},
+		{canceled, context.Canceled, sprintf(isNot, canceled, context.Canceled)},
+	} {
+		if s := NotIsError(tt.got, tt.want); s != tt.out {
+			t.Errorf("NotIsError(%v, %v): got %q, want %q", tt.got, tt.want, s, tt.out)
+		}
+	}
+}
+
+func TestErrorNot(t *testing.T) {
+	setDefaults(t)
+	eof := errors.New("EOF")
+	for _, tt := range []struct {
+		got  error
+		want interface{}
+		out  string
+	}{
+		{eof, "timeout", ""},
+		{nil, "timeout", ""},
+		{eof, nil, ""},
+		{nil, nil, "did not get expected error, want not no error"},
+		{eof, "EOF", `got error "EOF", want not contains "EOF"`},
+		{eof, eof, `got error "EOF", want not is the error "EOF" (*errors.errorString)`},
+		{fmt.Errorf("read: %w", eof), eof, ""},
+	} {
+		if s := ErrorNot(tt.got, tt.want); s != tt.out {
+			t.Errorf("ErrorNot(%v, %v): got %q, want %q", tt.got, tt.want, s, tt.out)
+		}
+	}
+}
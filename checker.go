@@ -0,0 +1,131 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"sync/atomic"
+	"testing"
+)
+
+// A Checker makes checks using its own options rather than the package wide
+// defaults.  This permits tests with different needs, such as tests of
+// localized error messages, to run in the same binary:
+//
+//	ck := check.NewChecker(check.Lowercase(cases.Lower(language.Turkish)))
+//	if s := ck.Error(err, check.Case("DOSYA BULUNAMADI")); s != "" {
+//		t.Error(s)
+//	}
+//
+// Each check of the package is also a method of Checker, except for AsFills
+// and AsError, which are generic, and Parity, whose Equivalence makes its own
+// check.  Use ck.Error(err, &target) in place of AsError.  The checks of a
+// Checker return a Result rather than a string.  A Checker is safe for
+// concurrent use.
+type Checker struct {
+	c *config
+
+	// checks counts the checks made by the Checker and the Checkers
+	// derived from it with With.
+	checks *uint64
+}
+
+// NewChecker returns a Checker that uses the current defaults, as set by
+// SetDefaults, with opts applied.  Later calls to SetDefaults do not change
+// the returned Checker.
+func NewChecker(opts ...Option) *Checker {
+	return &Checker{c: defaults().with(opts...), checks: new(uint64)}
+}
+
+// With returns a Checker that uses the options of ck with opts applied.  ck
+// is not changed.
+func (ck *Checker) With(opts ...Option) *Checker {
+	return &Checker{c: ck.c.with(opts...), checks: ck.checks}
+}
+
+// Child returns a Checker, named name, that uses the options of ck with opts
+// applied.  Each failure of a check made by the child is prefixed with its
+// name, and those of its parents, separated by slashes, as are the names of
+// subtests:
+//
+//	storage := ck.Child("storage", check.Transforms(scrubPaths))
+//	blobs := storage.Child("blobs")
+//	blobs.Error(err, "not found") // storage/blobs: got error ...
+//
+// The child shares the options of ck, such as its Reporters, and its count of
+// checks.  ck is not changed.
+func (ck *Checker) Child(name string, opts ...Option) *Checker {
+	nc := *ck.c.with(opts...)
+	if nc.scope != "" {
+		name = nc.scope + "/" + name
+	}
+	nc.scope = name
+	return &Checker{c: &nc, checks: ck.checks}
+}
+
+// count records that ck made a check.
+func (ck *Checker) count() {
+	atomic.AddUint64(ck.checks, 1)
+}
+
+// Expect causes t to fail, when t completes, unless exactly n checks were
+// made by ck, or by Checkers derived from ck, after Expect was called.  This
+// catches tests that silently pass because, for example, a loop over an empty
+// table checked nothing:
+//
+//	ck.Expect(t, len(cases))
+//	for _, tc := range cases {
+//		if s := ck.Error(tc.f(), tc.want); s != "" {
+//			t.Errorf("%s: %s", tc.name, s)
+//		}
+//	}
+func (ck *Checker) Expect(t testing.TB, n int) {
+	start := atomic.LoadUint64(ck.checks)
+	t.Cleanup(func() {
+		if ran := atomic.LoadUint64(ck.checks) - start; ran != uint64(n) {
+			t.Errorf("ran %d checks, want %d", ran, n)
+		}
+	})
+}
+
+// Error is the same as the Error function but uses the options of ck.
+func (ck *Checker) Error(got error, want interface{}) Result {
+	ck.count()
+	return Result(ck.c.checkError(got, want))
+}
+
+// Is is the same as the Is function but uses the options of ck.
+func (ck *Checker) Is(got, want error) Result {
+	ck.count()
+	return Result(ck.c.isError(got, want))
+}
+
+// NoError is the same as the NoError function but uses the options of ck.
+func (ck *Checker) NoError(got error) Result {
+	ck.count()
+	return Result(ck.c.noError(got))
+}
+
+// HasError is the same as the HasError function but uses the options of ck.
+func (ck *Checker) HasError(got error, classifiers ...interface{}) Result {
+	ck.count()
+	return Result(ck.c.hasError(got, classifiers))
+}
+
+// MessagesEqual is the same as the MessagesEqual function but uses the options
+// of ck.
+func (ck *Checker) MessagesEqual(got, want error) Result {
+	ck.count()
+	return Result(ck.c.messagesEqual(got, want))
+}
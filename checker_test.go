@@ -0,0 +1,156 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestChecker(t *testing.T) {
+	setDefaults(t, Quote(QuoteBack))
+	ck := NewChecker(Codes())
+	SetDefaults(Quote(QuoteGo)) // does not change ck
+	wrapped := fmt.Errorf("wrapped: %w", io.EOF)
+	for _, tt := range []struct {
+		name string
+		out  Result
+		want string
+	}{
+		{
+			name: "Error",
+			out:  ck.Error(io.EOF, "other"),
+			want: "CHK-WRONG: got error `EOF`, want `other`",
+		}, {
+			name: "Is",
+			out:  ck.Is(wrapped, io.EOF),
+		}, {
+			name: "Is failure",
+			out:  ck.Is(io.EOF, io.ErrUnexpectedEOF),
+			want: "CHK-WRONG: got error `EOF`, want `unexpected EOF`",
+		}, {
+			name: "NoError",
+			out:  ck.NoError(nil),
+		}, {
+			name: "HasError",
+			out:  ck.HasError(nil, "EOF"),
+			want: "CHK-MISSING: did not get expected error\nwant: contains \"EOF\"",
+		}, {
+			name: "MessagesEqual",
+			out:  ck.MessagesEqual(io.EOF, wrapped),
+			want: "CHK-WRONG: got error `EOF`, want `wrapped: EOF`",
+		},
+	} {
+		if string(tt.out) != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, tt.out, tt.want)
+		}
+	}
+}
+
+func TestExpect(t *testing.T) {
+	ck := NewChecker()
+	ck.Error(io.EOF, true)
+
+	var ft fakeTB
+	var cleanups []func()
+	ft.cleanup = func(f func()) { cleanups = append(cleanups, f) }
+	ck.Expect(&ft, 3)
+	ck.Expect(&ft, 2)
+	ck.Error(io.EOF, true)
+	ck.With(Codes()).Is(io.EOF, io.EOF)
+	ck.NoError(nil)
+	NewChecker().NoError(nil) // not counted
+	for _, f := range cleanups {
+		f()
+	}
+	if want := []string{"ran 3 checks, want 2"}; fmt.Sprint(ft.errors) != fmt.Sprint(want) {
+		t.Errorf("got errors %q, want %q", ft.errors, want)
+	}
+}
+
+func TestChild(t *testing.T) {
+	setDefaults(t)
+	var col collector
+	ck := NewChecker(Report(&col))
+	storage := ck.Child("storage", Quote(QuoteRaw))
+	blobs := storage.Child("blobs", Codes())
+	if s := storage.Error(io.EOF, "EOF"); s != "" {
+		t.Errorf("passing check got %q", s)
+	}
+	for _, tt := range []struct {
+		got, want Result
+	}{
+		{ck.Error(io.EOF, "x"), Result(sprintf(wrong, "EOF", "x"))},
+		{storage.Error(io.EOF, "x"), "storage: got error EOF, want x"},
+		{blobs.Error(io.EOF, nil), "CHK-UNEXPECTED: storage/blobs: got unexpected error EOF"},
+		{blobs.With(Quote(QuoteGo)).Is(nil, io.EOF), `CHK-MISSING: storage/blobs: did not get expected error "EOF"`},
+	} {
+		if tt.got != tt.want {
+			t.Errorf("got %q, want %q", tt.got, tt.want)
+		}
+	}
+	if len(col.failures) != 4 {
+		t.Fatalf("got %d failures, want 4", len(col.failures))
+	}
+	for i, want := range []string{"", "storage", "storage/blobs", "storage/blobs"} {
+		if f := col.failures[i]; f.Scope != want || !strings.Contains(f.Message, want) {
+			t.Errorf("failure %d: got scope %q and message %q, want scope %q", i, f.Scope, f.Message, want)
+		}
+	}
+	if col.failures[2].Code != CodeUnexpected {
+		t.Errorf("got code %q", col.failures[2].Code)
+	}
+
+	ck = NewChecker()
+	ck.Expect(t, 2)
+	ck.Child("a").Error(nil, nil)
+	ck.Child("b").Child("c").Error(nil, nil)
+}
+
+func TestCheckerChecks(t *testing.T) {
+	setDefaults(t)
+	var col collector
+	ck := NewChecker(Report(&col), Codes()).Child("store")
+	ck.Expect(t, 8)
+	for _, tt := range []struct {
+		check string
+		got   Result
+	}{
+		{"Retryable", ck.Retryable(io.EOF, true)},
+		{"Redacted", ck.Redacted(fmt.Errorf("key secret"), "secret")},
+		{"NoPII", ck.NoPII(fmt.Errorf("mail bob@example.com"))},
+		{"IsNotExist", ck.IsNotExist(io.EOF)},
+		{"Value", ck.Value(1, 2)},
+		{"Stable", ck.Stable(func() error { return io.EOF }, 1, "x")},
+		{"Error", ck.ErrorCase(io.EOF, "x")},
+		{"MessagesEqual", ck.SameMessage(io.EOF, io.ErrUnexpectedEOF)},
+	} {
+		if !strings.Contains(string(tt.got), "CHK-") || !strings.Contains(string(tt.got), "store: ") {
+			t.Errorf("%s: got %q without the options of the Checker", tt.check, tt.got)
+		}
+	}
+	var checks []string
+	for _, f := range col.failures {
+		if f.Scope != "store" {
+			t.Errorf("%s: got scope %q", f.Check, f.Scope)
+		}
+		checks = append(checks, f.Check)
+	}
+	if got, want := strings.Join(checks, ","), "Retryable,Redacted,NoPII,IsNotExist,Value,Stable,Error,MessagesEqual"; got != want {
+		t.Errorf("got checks %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,243 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package checkhttp checks HTTP responses and the errors of httptest servers
+// with the checks of package check.  It is a separate package so the check
+// package does not import net/http.
+package checkhttp
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"github.com/pborman/check"
+)
+
+// maxBodyQuote is the number of bytes of a body included in a status failure.
+const maxBodyQuote = 200
+
+// Response returns the empty string if resp has the status code wantStatus
+// and, if bodyWant is not nil, a body matched by bodyWant, as by check.Error
+// with an error whose message is the body, otherwise it returns a string
+// indicating each failure.  A status failure includes the start of the body,
+// which often explains it.  The body of resp is read and replaced so it may
+// be read again:
+//
+//	resp, err := http.Get(srv.URL + "/users/bob")
+//	...
+//	if s := checkhttp.Response(resp, http.StatusNotFound, check.Regexp(`"error":\s*"no such user"`)); s != "" {
+//		t.Error(s)
+//	}
+func Response(resp *http.Response, wantStatus int, bodyWant interface{}) string {
+	if resp == nil {
+		return "got no response, want status " + statusText(wantStatus)
+	}
+	var body []byte
+	if resp.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+		if err != nil {
+			return fmt.Sprintf("reading body: %q", err)
+		}
+	}
+	var failures []string
+	if resp.StatusCode != wantStatus {
+		quoted := string(body)
+		if len(quoted) > maxBodyQuote {
+			quoted = quoted[:maxBodyQuote] + "..."
+		}
+		failures = append(failures, fmt.Sprintf("got status %s, want %s, with body %q",
+			statusText(resp.StatusCode), statusText(wantStatus), quoted))
+	}
+	if bodyWant != nil {
+		if s := check.Error(errors.New(string(body)), bodyWant); s != "" {
+			failures = append(failures, "body: "+s)
+		}
+	}
+	return strings.Join(failures, "\n")
+}
+
+// statusText returns code and its text, e.g., "404 Not Found".
+func statusText(code int) string {
+	if text := http.StatusText(code); text != "" {
+		return fmt.Sprintf("%d %s", code, text)
+	}
+	return fmt.Sprintf("%d", code)
+}
+
+// A ServerErrors captures the errors of the server half of an
+// httptest.Server, which are otherwise only logged, so they may be checked
+// once the client half of a test completes.  The errors captured are:
+//
+//   - the messages logged by the server to its ErrorLog
+//   - the values its handler panics with
+//   - the errors passed to Add, such as by the error hook of the server
+//     under test
+//
+// The zero value is ready to use.  A ServerErrors is safe for concurrent use.
+type ServerErrors struct {
+	mu      sync.Mutex
+	errs    []error
+	running int           // handlers running
+	idle    chan struct{} // closed when running becomes 0
+}
+
+// Capture returns a new ServerErrors capturing the errors of ts, as by the
+// Capture method of ServerErrors.
+func Capture(ts *httptest.Server) *ServerErrors {
+	se := &ServerErrors{}
+	se.Capture(ts)
+	return se
+}
+
+// Capture causes se to capture the errors of ts, which must not yet be
+// started, as returned by httptest.NewUnstartedServer:
+//
+//	var se checkhttp.ServerErrors
+//	ts := httptest.NewUnstartedServer(api.Handler(api.WithErrorHook(se.Add)))
+//	se.Capture(ts)
+//	ts.Start()
+//	defer ts.Close()
+//	resp, err := http.Get(ts.URL + "/users/bob")
+//	...
+//	if s := se.Error("no such user"); s != "" {
+//		t.Error(s)
+//	}
+//
+// A handler that panics has its connection aborted, as by
+// http.ErrAbortHandler, rather than having its stack logged.
+func (se *ServerErrors) Capture(ts *httptest.Server) {
+	if ts.URL != "" {
+		panic("checkhttp: Capture called with a started server")
+	}
+	h := ts.Config.Handler
+	if h == nil {
+		h = http.DefaultServeMux
+	}
+	ts.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		se.enter()
+		defer se.exit()
+		defer func() {
+			p := recover()
+			if p == nil {
+				return
+			}
+			if p != http.ErrAbortHandler {
+				err, ok := p.(error)
+				if !ok {
+					err = errors.New(fmt.Sprint(p))
+				}
+				se.Add(fmt.Errorf("panic serving %s %s: %w", r.Method, r.URL.Path, err))
+			}
+			panic(http.ErrAbortHandler)
+		}()
+		h.ServeHTTP(w, r)
+	})
+	ts.Config.ErrorLog = log.New(serverLog{se}, "", 0)
+}
+
+// enter records that a handler is running.
+func (se *ServerErrors) enter() {
+	se.mu.Lock()
+	se.running++
+	se.mu.Unlock()
+}
+
+// exit records that a handler has returned.
+func (se *ServerErrors) exit() {
+	se.mu.Lock()
+	se.running--
+	if se.running == 0 && se.idle != nil {
+		close(se.idle)
+		se.idle = nil
+	}
+	se.mu.Unlock()
+}
+
+// serverLog adds each message logged to it to a ServerErrors.
+type serverLog struct{ se *ServerErrors }
+
+func (l serverLog) Write(p []byte) (int, error) {
+	l.se.Add(errors.New(strings.TrimSuffix(string(p), "\n")))
+	return len(p), nil
+}
+
+// Add adds err, if not nil, to the errors captured by se.
+func (se *ServerErrors) Add(err error) {
+	if err == nil {
+		return
+	}
+	se.mu.Lock()
+	se.errs = append(se.errs, err)
+	se.mu.Unlock()
+}
+
+// Errors returns the errors captured by se, in the order they were captured,
+// once the handlers running have returned.
+func (se *ServerErrors) Errors() []error {
+	se.mu.Lock()
+	defer se.mu.Unlock()
+	for se.running > 0 {
+		if se.idle == nil {
+			se.idle = make(chan struct{})
+		}
+		idle := se.idle
+		se.mu.Unlock()
+		<-idle
+		se.mu.Lock()
+	}
+	return append([]error(nil), se.errs...)
+}
+
+// Err returns nil if se has captured no errors, the error captured if it has
+// captured one, or else an error joining the errors captured, one per line,
+// that may be checked with check.JoinedErrors.  Err waits as Errors does.
+func (se *ServerErrors) Err() error {
+	errs := se.Errors()
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	default:
+		return errorList(errs)
+	}
+}
+
+// An errorList is an error joining a list of errors, one per line.
+type errorList []error
+
+func (e errorList) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+func (e errorList) Unwrap() []error { return e }
+
+// Error is shorthand for check.Error(se.Err(), want).
+func (se *ServerErrors) Error(want interface{}) string {
+	return check.Error(se.Err(), want)
+}
@@ -0,0 +1,161 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checkhttp
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pborman/check"
+)
+
+func TestResponse(t *testing.T) {
+	response := func(code int, body string) *http.Response {
+		rec := httptest.NewRecorder()
+		rec.WriteHeader(code)
+		rec.WriteString(body)
+		return rec.Result()
+	}
+	wrong := func(got, want string) string { return fmt.Sprintf("got error %q, want %q", got, want) }
+	long := strings.Repeat("x", maxBodyQuote+10)
+	for _, tt := range []struct {
+		name   string
+		resp   *http.Response
+		status int
+		want   interface{}
+		out    string
+	}{
+		{"ok", response(200, "hello"), 200, nil, ""},
+		{"body", response(404, `{"error": "no such user"}`), 404, check.Regexp(`"error":\s*"no such user"`), ""},
+		{"equal", response(200, "hello"), 200, check.Equal("hello"), ""},
+		{"status", response(500, "boom"), 200, nil, `got status 500 Internal Server Error, want 200 OK, with body "boom"`},
+		{"odd status", response(299, ""), 200, nil, `got status 299, want 200 OK, with body ""`},
+		{"long body", response(500, long), 200, nil, fmt.Sprintf(`got status 500 Internal Server Error, want 200 OK, with body %q`, long[:maxBodyQuote]+"...")},
+		{"wrong body", response(200, "hello"), 200, "bye", "body: " + wrong("hello", "bye")},
+		{"both", response(500, "boom"), 200, check.Equal("ok"),
+			`got status 500 Internal Server Error, want 200 OK, with body "boom"` + "\nbody: " + wrong("boom", "ok")},
+		{"nil", nil, 200, nil, "got no response, want status 200 OK"},
+	} {
+		if s := Response(tt.resp, tt.status, tt.want); s != tt.out {
+			t.Errorf("%s: got %q, want %q", tt.name, s, tt.out)
+		}
+	}
+	resp := response(200, "hello")
+	Response(resp, 200, "hello")
+	if body, err := ioutil.ReadAll(resp.Body); err != nil || string(body) != "hello" {
+		t.Errorf("body not replaced: got %q, %v", body, err)
+	}
+}
+
+var errNoUser = errors.New("no such user")
+
+// userHandler serves /users/, reporting errors to report.
+func userHandler(report func(error)) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/users/", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/users/panic":
+			panic("nil map")
+		case "/users/late":
+			w.WriteHeader(http.StatusAccepted)
+			w.(http.Flusher).Flush()
+			time.Sleep(10 * time.Millisecond)
+			report(errors.New("late failure"))
+		case "/users/bob":
+			report(errNoUser)
+			http.Error(w, errNoUser.Error(), http.StatusNotFound)
+		}
+	})
+	return mux
+}
+
+// get gets url, ignoring the response.  Connections are not reused so a
+// request aborted by a panic is not retried.
+func get(url string) {
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+	resp, err := client.Get(url)
+	if err != nil {
+		return
+	}
+	ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+}
+
+func TestServerErrors(t *testing.T) {
+	var se ServerErrors
+	ts := httptest.NewUnstartedServer(userHandler(se.Add))
+	se.Capture(ts)
+	ts.Start()
+	defer ts.Close()
+
+	if s := se.Error(nil); s != "" {
+		t.Errorf("no errors: %s", s)
+	}
+	get(ts.URL + "/users/alice")
+	if err := se.Err(); err != nil {
+		t.Errorf("alice: got error %v", err)
+	}
+	get(ts.URL + "/users/bob")
+	if err := se.Err(); err != errNoUser {
+		t.Errorf("bob: got error %v", err)
+	}
+	get(ts.URL + "/users/late")
+	get(ts.URL + "/users/panic")
+	if s := se.Error(check.All("late failure", "panic serving GET /users/panic: nil map")); s != "" {
+		t.Error(s)
+	}
+	if s := check.JoinedErrors(se.Err(), errNoUser); s != "" {
+		t.Error(s)
+	}
+	if errs := se.Errors(); len(errs) != 3 {
+		t.Errorf("got %d errors, want 3: %v", len(errs), errs)
+	}
+	se.Add(nil)
+	if errs := se.Errors(); len(errs) != 3 {
+		t.Errorf("Add(nil) added an error")
+	}
+}
+
+func TestServerErrorLog(t *testing.T) {
+	ts := httptest.NewUnstartedServer(http.NotFoundHandler())
+	se := Capture(ts)
+	ts.Config.ErrorLog.Print("http: TLS handshake error from 127.0.0.1: EOF")
+	if s := se.Error(check.Equal("http: TLS handshake error from 127.0.0.1: EOF")); s != "" {
+		t.Error(s)
+	}
+	ts.Start()
+	defer ts.Close()
+	if msg := panicked(func() { se.Capture(ts) }); msg != "checkhttp: Capture called with a started server" {
+		t.Errorf("got panic %q", msg)
+	}
+}
+
+// panicked returns the value f panics with, formatted with %v, or the empty
+// string if f does not panic.
+func panicked(f func()) (msg string) {
+	defer func() {
+		if r := recover(); r != nil {
+			msg = fmt.Sprintf("%v", r)
+		}
+	}()
+	f()
+	return ""
+}
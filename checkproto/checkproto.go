@@ -0,0 +1,152 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package checkproto checks the protocol buffer messages attached to errors.
+// It is a separate module so the check package does not depend on protocol
+// buffers.  Messages are compared with proto.Equal as comparing them with
+// reflect.DeepEqual or by their string form is not reliable.
+package checkproto
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/pborman/check"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+)
+
+// Details returns a check.CustomMatcher that matches an error whose details
+// are equal, in order, to want.  The details are taken from the first error
+// in the chain of the error that follows one of these conventions:
+//
+//	Details() []proto.Message  the details are the returned messages
+//	GRPCStatus()               the details are those of the returned status,
+//	                           as by the Details method of *status.Status
+//	                           from google.golang.org/grpc/status
+//
+// An error that follows neither convention has no details.  Failures are
+// those of check.Error, so they follow its options:
+//
+//	if s := check.Error(err, checkproto.Details(&errdetails.BadRequest{})); s != "" {
+//		t.Error(s)
+//	}
+func Details(want ...proto.Message) check.CustomMatcher {
+	return matcher(want)
+}
+
+// A matcher matches the details of an error, as described by Details.
+type matcher []proto.Message
+
+// Describe returns a description of the details wanted by m.
+func (m matcher) Describe() string {
+	if len(m) == 0 {
+		return "no details"
+	}
+	return "details " + join(m)
+}
+
+// Match returns the empty string if the details of got are those wanted by m,
+// otherwise it returns the details wanted and the reason they do not match.
+func (m matcher) Match(got error) string {
+	if got == nil {
+		return m.Describe()
+	}
+	details, err := details(got)
+	if err != nil {
+		return fmt.Sprintf("%s (got %v)", m.Describe(), err)
+	}
+	if len(details) != len(m) {
+		if len(details) == 0 {
+			return m.Describe() + " (got no details)"
+		}
+		return fmt.Sprintf("%s (got %d details: %s)", m.Describe(), len(details), join(details))
+	}
+	var diffs []string
+	for i, w := range m {
+		if !proto.Equal(details[i], w) {
+			diffs = append(diffs, fmt.Sprintf("detail %d is %s", i, format(details[i])))
+		}
+	}
+	if diffs == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s (got %s)", m.Describe(), strings.Join(diffs, ", "))
+}
+
+// details returns the details attached to err, as described by Details.  An
+// error is returned if a status detail is not a message, which happens when
+// the status package cannot decode it.
+func details(err error) ([]proto.Message, error) {
+	var found []proto.Message
+	var ferr error
+	check.Walk(err, func(err error) bool {
+		if d, ok := err.(interface{ Details() []proto.Message }); ok {
+			found = d.Details()
+			return false
+		}
+		s, ok := status(err)
+		if !ok {
+			return true
+		}
+		for _, d := range s.Details() {
+			switch d := d.(type) {
+			case proto.Message:
+				found = append(found, d)
+			case error:
+				ferr = fmt.Errorf("undecodable detail: %v", d)
+				return false
+			default:
+				ferr = fmt.Errorf("detail of type %T", d)
+				return false
+			}
+		}
+		return false
+	})
+	return found, ferr
+}
+
+// status returns the status returned by the GRPCStatus method of err, if err
+// has one and it returns a non-nil value with a Details method.  Reflection
+// is used so this package does not depend on gRPC.
+func status(err error) (interface{ Details() []interface{} }, bool) {
+	m := reflect.ValueOf(err).MethodByName("GRPCStatus")
+	if !m.IsValid() || m.Type().NumIn() != 0 || m.Type().NumOut() != 1 {
+		return nil, false
+	}
+	s := m.Call(nil)[0]
+	if s.Kind() == reflect.Ptr && s.IsNil() {
+		return nil, false
+	}
+	d, ok := s.Interface().(interface{ Details() []interface{} })
+	return d, ok
+}
+
+// format returns m as its full name followed by its compact text form.
+func format(m proto.Message) string {
+	if m == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("%s{%s}", m.ProtoReflect().Descriptor().FullName(), prototext.MarshalOptions{}.Format(m))
+}
+
+// join returns msgs formatted and separated by commas.
+func join(msgs []proto.Message) string {
+	parts := make([]string, len(msgs))
+	for i, m := range msgs {
+		parts[i] = format(m)
+	}
+	return strings.Join(parts, ", ")
+}
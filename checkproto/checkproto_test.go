@@ -0,0 +1,124 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checkproto
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/pborman/check"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+type detailErr []proto.Message
+
+func (e detailErr) Error() string            { return "detailed" }
+func (e detailErr) Details() []proto.Message { return e }
+
+// grpcStatus mimics *status.Status from google.golang.org/grpc/status.
+type grpcStatus struct{ details []interface{} }
+
+func (s *grpcStatus) Details() []interface{} { return s.details }
+
+type grpcErr struct{ s *grpcStatus }
+
+func (e grpcErr) Error() string           { return "rpc error" }
+func (e grpcErr) GRPCStatus() *grpcStatus { return e.s }
+
+func TestDetails(t *testing.T) {
+	str := wrapperspb.String("resource")
+	dur := durationpb.New(5e9)
+	for _, tt := range []struct {
+		name string
+		got  error
+		want []proto.Message
+		out  string
+	}{
+		{
+			name: "nil",
+			out:  "did not get expected error, want no details",
+		}, {
+			name: "no details",
+			got:  errors.New("plain"),
+		}, {
+			name: "details",
+			got:  detailErr{str, dur},
+			want: []proto.Message{wrapperspb.String("resource"), durationpb.New(5e9)},
+		}, {
+			name: "wrapped",
+			got:  fmt.Errorf("wrapped: %w", detailErr{str}),
+			want: []proto.Message{wrapperspb.String("resource")},
+		}, {
+			name: "status",
+			got:  grpcErr{&grpcStatus{[]interface{}{str, dur}}},
+			want: []proto.Message{str, dur},
+		}, {
+			name: "nil status",
+			got:  grpcErr{},
+		}, {
+			name: "wrong detail",
+			got:  detailErr{str, dur},
+			want: []proto.Message{str, durationpb.New(6e9)},
+			out: fmt.Sprintf(`got error "detailed", want details %s, %s (got detail 1 is %s)`,
+				format(str), format(durationpb.New(6e9)), format(dur)),
+		}, {
+			name: "wrong type",
+			got:  detailErr{str},
+			want: []proto.Message{wrapperspb.Bytes([]byte("resource"))},
+			out: fmt.Sprintf(`got error "detailed", want details %s (got detail 0 is %s)`,
+				format(wrapperspb.Bytes([]byte("resource"))), format(str)),
+		}, {
+			name: "missing detail",
+			got:  detailErr{str},
+			want: []proto.Message{str, dur},
+			out: fmt.Sprintf(`got error "detailed", want details %s, %s (got 1 details: %s)`,
+				format(str), format(dur), format(str)),
+		}, {
+			name: "undecodable",
+			got:  grpcErr{&grpcStatus{[]interface{}{errors.New("bad any")}}},
+			out:  `got error "rpc error", want no details (got undecodable detail: bad any)`,
+		},
+	} {
+		if s := check.Error(tt.got, Details(tt.want...)); s != tt.out {
+			t.Errorf("%s: got %q, want %q", tt.name, s, tt.out)
+		}
+	}
+}
+
+func TestDetailsOptions(t *testing.T) {
+	ck := check.NewChecker(check.Codes())
+	if s, want := ck.Error(detailErr{}, Details(wrapperspb.String("x"))), "CHK-WRONG: "; !strings.HasPrefix(string(s), want) {
+		t.Errorf("got %q, want prefix %q", s, want)
+	}
+	if got, want := check.Describe(Details()), "no details"; got != want {
+		t.Errorf("got description %q, want %q", got, want)
+	}
+	if err := check.Validate(Details()); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	if got, want := format(nil), "<nil>"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got, want := format(wrapperspb.String("x")), "google.protobuf.StringValue{"; got[:len(want)] != want {
+		t.Errorf("got %q, want prefix %q", got, want)
+	}
+}
@@ -0,0 +1,10 @@
+module github.com/pborman/check/checkproto
+
+go 1.23
+
+require (
+	github.com/pborman/check v0.0.0-00010101000000-000000000000
+	google.golang.org/protobuf v1.36.12
+)
+
+replace github.com/pborman/check => ../
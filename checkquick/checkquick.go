@@ -0,0 +1,86 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package checkquick makes the checks of package check properties tested with
+// testing/quick.  It is a separate package so the check package does not
+// import testing/quick, which adds its -quickchecks flag to every program
+// that imports it.
+package checkquick
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing/quick"
+
+	"github.com/pborman/check"
+)
+
+var stringType = reflect.TypeOf("")
+
+// Check returns the empty string if f, a function whose results are a single
+// string, such as the result of a check, returns the empty string for each of
+// the arguments generated by testing/quick with config, otherwise it returns
+// the failure for the first counterexample found, with its arguments, as by
+// check.Counterexample, e.g.:
+//
+//	counterexample found by check 3:
+//		arg 0 = "\x00"
+//	failure:
+//		got unexpected error "invalid byte"
+//
+// config may be nil, as for quick.Check:
+//
+//	if s := checkquick.Check(func(s string) string {
+//		_, err := Parse(Quote(s))
+//		return check.NoError(err)
+//	}, nil); s != "" {
+//		t.Error(s)
+//	}
+func Check(f interface{}, config *quick.Config) string {
+	fv := reflect.ValueOf(f)
+	ft := fv.Type()
+	if ft.Kind() != reflect.Func || ft.NumOut() != 1 || ft.Out(0) != stringType {
+		return fmt.Sprintf("checkquick.Check of %T, want a func returning a string", f)
+	}
+	var failure string // the failure of the last call of f
+	in := make([]reflect.Type, ft.NumIn())
+	for i := range in {
+		in[i] = ft.In(i)
+	}
+	prop := reflect.MakeFunc(reflect.FuncOf(in, []reflect.Type{reflect.TypeOf(false)}, false), func(args []reflect.Value) []reflect.Value {
+		var s string
+		if ft.IsVariadic() {
+			s = fv.CallSlice(args)[0].String()
+		} else {
+			s = fv.Call(args)[0].String()
+		}
+		failure = s
+		return []reflect.Value{reflect.ValueOf(s == "")}
+	})
+	err := quick.Check(prop.Interface(), config)
+	if err == nil {
+		return ""
+	}
+	ce, ok := err.(*quick.CheckError)
+	if !ok {
+		return fmt.Sprintf("checkquick.Check: %v", err)
+	}
+	inputs := make([]interface{}, 0, 2*len(ce.In))
+	for i, arg := range ce.In {
+		inputs = append(inputs, fmt.Sprintf("arg %d", i), arg)
+	}
+	s := check.Counterexample(failure, inputs...)
+	return strings.Replace(s, "counterexample:", fmt.Sprintf("counterexample found by check %d:", ce.Count), 1)
+}
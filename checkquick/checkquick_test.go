@@ -0,0 +1,71 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checkquick
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/quick"
+
+	"github.com/pborman/check"
+)
+
+func TestCheck(t *testing.T) {
+	roundTrip := func(n int) string {
+		_, err := strconv.Atoi(strconv.Itoa(n))
+		return check.NoError(err)
+	}
+	if s := Check(roundTrip, nil); s != "" {
+		t.Errorf("round trip: %s", s)
+	}
+	variadic := func(ns ...uint8) string { return "" }
+	if s := Check(variadic, nil); s != "" {
+		t.Errorf("variadic: %s", s)
+	}
+	short := errors.New("short")
+	long := func(s string, n uint8) string {
+		var err error
+		if n > 0 {
+			err = short
+		}
+		return check.Error(err, nil)
+	}
+	config := &quick.Config{Values: func(args []reflect.Value, _ *rand.Rand) {
+		args[0] = reflect.ValueOf("x")
+		args[1] = reflect.ValueOf(uint8(1))
+	}}
+	if s, want := Check(long, config), "counterexample found by check 1:\n\targ 0 = \"x\"\n\targ 1 = 0x1\nfailure:\n\tgot unexpected error \"short\""; s != want {
+		t.Errorf("failure: got %q, want %q", s, want)
+	}
+	coded := func(s string, n uint8) string {
+		return check.Errorf(short, nil, check.Codes())
+	}
+	if s, want := Check(coded, config), "CHK-UNEXPECTED: counterexample found by check 1:\n\targ 0 = \"x\"\n\targ 1 = 0x1\nfailure:\n\tgot unexpected error \"short\""; s != want {
+		t.Errorf("Codes: got %q, want %q", s, want)
+	}
+	for _, f := range []interface{}{42, func() bool { return true }, func() (string, error) { return "", nil }} {
+		if s, want := Check(f, nil), "checkquick.Check of "+reflect.TypeOf(f).String()+", want a func returning a string"; s != want {
+			t.Errorf("got %q, want %q", s, want)
+		}
+	}
+	unsupported := func(chan int) string { return "" }
+	if s := Check(unsupported, nil); !strings.HasPrefix(s, "checkquick.Check: ") {
+		t.Errorf("unsupported argument: got %q", s)
+	}
+}
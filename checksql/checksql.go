@@ -0,0 +1,129 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package checksql checks the errors of database/sql transactions with the
+// checks of package check.  It is a separate package so the check package
+// does not import database/sql.
+package checksql
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/pborman/check"
+)
+
+// A Tx is a transaction, such as a *sql.Tx.
+type Tx interface {
+	Commit() error
+	Rollback() error
+}
+
+// txDone is appended to failures caused by sql.ErrTxDone.
+const txDone = "\n(the transaction was already committed or rolled back)"
+
+// Commit returns the empty string if the error returned by tx.Commit matches
+// want, as by check.Error, otherwise it returns a string indicating the
+// error.  A failure caused by sql.ErrTxDone notes that the transaction was
+// already complete.
+func Commit(tx Tx, want interface{}) string {
+	err := tx.Commit()
+	s := check.Error(err, want)
+	if s != "" && errors.Is(err, sql.ErrTxDone) {
+		s += txDone
+	}
+	return s
+}
+
+// Rollback registers tx to be rolled back when t and its subtests complete,
+// as by t.Cleanup, and the error returned by Rollback to be checked against
+// want, as by check.Error.  A Rollback that returns sql.ErrTxDone, because
+// tx was committed, is treated as returning nil.  A failure is reported with
+// t.Error, prefixed with "rollback: ".  Rollback replaces
+//
+//	defer tx.Rollback()
+//
+// with
+//
+//	checksql.Rollback(t, tx, nil)
+func Rollback(t testing.TB, tx Tx, want interface{}) {
+	t.Helper()
+	t.Cleanup(func() {
+		t.Helper()
+		err := tx.Rollback()
+		if errors.Is(err, sql.ErrTxDone) {
+			err = nil
+		}
+		if s := check.Error(err, want); s != "" {
+			t.Error("rollback: " + s)
+		}
+	})
+}
+
+// A sentinel is a check.CustomMatcher of a database/sql sentinel error that
+// reports failures in the terms of what the sentinel means.
+type sentinel struct {
+	err     error
+	name    string // e.g., "sql.ErrNoRows"
+	missing string // what getting no error means
+}
+
+// NoRows returns a check.CustomMatcher that matches an error that is
+// sql.ErrNoRows, as determined by errors.Is.  The failure for no error notes
+// that the query unexpectedly returned rows.
+func NoRows() check.CustomMatcher {
+	return sentinel{sql.ErrNoRows, "sql.ErrNoRows", "query unexpectedly returned rows"}
+}
+
+// ConnDone returns a check.CustomMatcher that matches an error that is
+// sql.ErrConnDone, as determined by errors.Is.
+func ConnDone() check.CustomMatcher {
+	return sentinel{sql.ErrConnDone, "sql.ErrConnDone", "connection unexpectedly still open"}
+}
+
+// TxDone returns a check.CustomMatcher that matches an error that is
+// sql.ErrTxDone, as determined by errors.Is.
+func TxDone() check.CustomMatcher {
+	return sentinel{sql.ErrTxDone, "sql.ErrTxDone", "transaction unexpectedly still open"}
+}
+
+// Match implements check.CustomMatcher.
+func (m sentinel) Match(got error) string {
+	switch {
+	case got == nil:
+		return m.name + " (" + m.missing + ")"
+	case !errors.Is(got, m.err):
+		return m.name
+	}
+	return ""
+}
+
+// Describe implements check.Describer.
+func (m sentinel) Describe() string { return "is " + m.name }
+
+// noRows is appended to failures caused by sql.ErrNoRows.
+const noRows = "\n(the query unexpectedly returned no rows)"
+
+// Rows returns the empty string if got is nil, otherwise it returns a string
+// indicating the error, as by check.Error.  Rows is the counterpart of
+// NoRows; a failure caused by sql.ErrNoRows notes that the query returned no
+// rows.
+func Rows(got error) string {
+	s := check.Error(got, nil)
+	if s != "" && errors.Is(got, sql.ErrNoRows) {
+		s += noRows
+	}
+	return s
+}
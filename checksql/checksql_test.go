@@ -0,0 +1,144 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checksql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/pborman/check"
+)
+
+// fakeTx is a Tx that behaves like a *sql.Tx whose Commit and Rollback
+// return commitErr and rollbackErr.
+type fakeTx struct {
+	commitErr, rollbackErr error
+	done                   bool
+}
+
+func (tx *fakeTx) Commit() error {
+	if tx.done {
+		return sql.ErrTxDone
+	}
+	tx.done = true
+	return tx.commitErr
+}
+
+func (tx *fakeTx) Rollback() error {
+	if tx.done {
+		return sql.ErrTxDone
+	}
+	tx.done = true
+	return tx.rollbackErr
+}
+
+// fakeTB is a testing.TB that records its errors and cleanup.
+type fakeTB struct {
+	testing.TB
+	errors  []string
+	cleanup func()
+}
+
+func (t *fakeTB) Helper()                   {}
+func (t *fakeTB) Error(args ...interface{}) { t.errors = append(t.errors, fmt.Sprint(args...)) }
+func (t *fakeTB) Cleanup(f func())          { t.cleanup = f }
+
+func TestCommit(t *testing.T) {
+	conflict := errors.New("serialization failure")
+	if s := Commit(&fakeTx{}, nil); s != "" {
+		t.Errorf("commit: %s", s)
+	}
+	if s := Commit(&fakeTx{commitErr: conflict}, "serialization"); s != "" {
+		t.Errorf("commit conflict: %s", s)
+	}
+	if s, want := Commit(&fakeTx{done: true}, nil), fmt.Sprintf("got unexpected error %q", sql.ErrTxDone)+txDone; s != want {
+		t.Errorf("done: got %q, want %q", s, want)
+	}
+	if s := Commit(&fakeTx{done: true}, sql.ErrTxDone); s != "" {
+		t.Errorf("done wanted: %s", s)
+	}
+	if s, want := Commit(&fakeTx{}, conflict), fmt.Sprintf("did not get expected error %q", conflict); s != want {
+		t.Errorf("missing: got %q, want %q", s, want)
+	}
+}
+
+func TestRollback(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		tx     *fakeTx
+		commit bool
+		want   interface{}
+		errors []string
+	}{
+		{name: "rolled back", tx: &fakeTx{}},
+		{name: "committed", tx: &fakeTx{}, commit: true},
+		{name: "rollback fails", tx: &fakeTx{rollbackErr: errors.New("conn lost")}, errors: []string{`rollback: got unexpected error "conn lost"`}},
+		{name: "rollback wanted", tx: &fakeTx{rollbackErr: errors.New("conn lost")}, want: "conn"},
+	} {
+		ft := &fakeTB{}
+		Rollback(ft, tt.tx, tt.want)
+		if tt.commit {
+			if s := Commit(tt.tx, nil); s != "" {
+				t.Errorf("%s: commit: %s", tt.name, s)
+			}
+		}
+		ft.cleanup()
+		if !tt.tx.done {
+			t.Errorf("%s: transaction not complete", tt.name)
+		}
+		if fmt.Sprint(ft.errors) != fmt.Sprint(tt.errors) {
+			t.Errorf("%s: got errors %q, want %q", tt.name, ft.errors, tt.errors)
+		}
+	}
+}
+
+func TestSentinels(t *testing.T) {
+	noRows := fmt.Errorf("get user: %w", sql.ErrNoRows)
+	for _, tt := range []struct {
+		name string
+		got  error
+		want check.CustomMatcher
+		out  string
+	}{
+		{"no rows", noRows, NoRows(), ""},
+		{"conn done", sql.ErrConnDone, ConnDone(), ""},
+		{"tx done", fmt.Errorf("commit: %w", sql.ErrTxDone), TxDone(), ""},
+		{"rows", nil, NoRows(), "did not get expected error, want sql.ErrNoRows (query unexpectedly returned rows)"},
+		{"conn open", nil, ConnDone(), "did not get expected error, want sql.ErrConnDone (connection unexpectedly still open)"},
+		{"tx open", nil, TxDone(), "did not get expected error, want sql.ErrTxDone (transaction unexpectedly still open)"},
+		{"wrong", sql.ErrTxDone, NoRows(), fmt.Sprintf("got error %q, want sql.ErrNoRows", sql.ErrTxDone)},
+	} {
+		if s := check.Error(tt.got, tt.want); s != tt.out {
+			t.Errorf("%s: got %q, want %q", tt.name, s, tt.out)
+		}
+	}
+	if d := check.Describe(NoRows()); d != "is sql.ErrNoRows" {
+		t.Errorf("got description %q", d)
+	}
+	for _, tt := range []struct {
+		got error
+		out string
+	}{
+		{nil, ""},
+		{noRows, fmt.Sprintf("got unexpected error %q", noRows) + "\n(the query unexpectedly returned no rows)"},
+		{sql.ErrConnDone, fmt.Sprintf("got unexpected error %q", sql.ErrConnDone)},
+	} {
+		if s := Rows(tt.got); s != tt.out {
+			t.Errorf("Rows(%v): got %q, want %q", tt.got, s, tt.out)
+		}
+	}
+}
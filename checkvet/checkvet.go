@@ -0,0 +1,188 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package checkvet provides an analyzer that reports discarded check
+// results.  A type is a check result if its declaration is annotated with
+// the directive
+//
+//	//check:mustuse
+//
+// as is check.Result.  The analyzer reports calls of any function or method,
+// including interface methods and helpers in other packages, whose result is
+// of an annotated type and is not used, such as
+//
+//	ck.Error(err, io.EOF)
+//	_ = ck.Error(err, io.EOF)
+//
+// It is a separate module so the check package does not depend on
+// golang.org/x/tools.
+package checkvet
+
+import (
+	"go/ast"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// Directive is the comment that marks a type as a check result.
+const Directive = "//check:mustuse"
+
+// Analyzer reports discarded check results.
+var Analyzer = &analysis.Analyzer{
+	Name:      "checkvet",
+	Doc:       "report discarded check results\n\nA check result is a value of a type annotated with " + Directive + ", such as check.Result.",
+	Run:       run,
+	FactTypes: []analysis.Fact{new(mustUse)},
+}
+
+// mustUse is the fact that a type is annotated with Directive.
+type mustUse struct{}
+
+func (*mustUse) AFact()         {}
+func (*mustUse) String() string { return "mustuse" }
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	// Export the annotated types of this package first so they are
+	// known when checking its own calls.
+	for _, f := range pass.Files {
+		for _, decl := range f.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok {
+				continue
+			}
+			for _, spec := range gd.Specs {
+				ts, ok := spec.(*ast.TypeSpec)
+				if !ok || !(annotated(gd.Doc) || annotated(ts.Doc)) {
+					continue
+				}
+				if obj := pass.TypesInfo.Defs[ts.Name]; obj != nil {
+					pass.ExportObjectFact(obj, new(mustUse))
+				}
+			}
+		}
+	}
+	for _, f := range pass.Files {
+		ast.Inspect(f, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.ExprStmt:
+				check(pass, n.X, "discarded")
+			case *ast.AssignStmt:
+				if len(n.Lhs) != len(n.Rhs) {
+					// x, _ := f() where f returns a tuple
+					if len(n.Rhs) == 1 {
+						checkTuple(pass, n.Lhs, n.Rhs[0])
+					}
+					return true
+				}
+				for i, lhs := range n.Lhs {
+					if blank(lhs) {
+						check(pass, n.Rhs[i], "assigned to _")
+					}
+				}
+			case *ast.GoStmt:
+				check(pass, n.Call, "discarded by go statement")
+			case *ast.DeferStmt:
+				check(pass, n.Call, "discarded by defer statement")
+			}
+			return true
+		})
+	}
+	return nil, nil
+}
+
+// check reports expr if it is a call whose result is a check result.
+func check(pass *analysis.Pass, expr ast.Expr, how string) {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok {
+		return
+	}
+	t := pass.TypesInfo.TypeOf(call)
+	if tuple, ok := t.(*types.Tuple); ok {
+		for i := 0; i < tuple.Len(); i++ {
+			if name, ok := isResult(pass, tuple.At(i).Type()); ok {
+				pass.Reportf(call.Pos(), "%s returned by %s is %s", name, callee(call), how)
+				return
+			}
+		}
+		return
+	}
+	if name, ok := isResult(pass, t); ok {
+		pass.Reportf(call.Pos(), "%s returned by %s is %s", name, callee(call), how)
+	}
+}
+
+// checkTuple reports the check results of the call expr that are assigned to
+// the blank identifiers of lhs.
+func checkTuple(pass *analysis.Pass, lhs []ast.Expr, expr ast.Expr) {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok {
+		return
+	}
+	tuple, ok := pass.TypesInfo.TypeOf(call).(*types.Tuple)
+	if !ok || tuple.Len() != len(lhs) {
+		return
+	}
+	for i, l := range lhs {
+		if name, ok := isResult(pass, tuple.At(i).Type()); ok && blank(l) {
+			pass.Reportf(call.Pos(), "%s returned by %s is assigned to _", name, callee(call))
+		}
+	}
+}
+
+// isResult returns the name of t and true if t is a check result.
+func isResult(pass *analysis.Pass, t types.Type) (string, bool) {
+	named, ok := t.(*types.Named)
+	if !ok {
+		return "", false
+	}
+	obj := named.Obj()
+	if !pass.ImportObjectFact(obj, new(mustUse)) {
+		return "", false
+	}
+	if obj.Pkg() == nil {
+		return obj.Name(), true
+	}
+	return obj.Pkg().Name() + "." + obj.Name(), true
+}
+
+// annotated reports whether doc includes Directive.
+func annotated(doc *ast.CommentGroup) bool {
+	if doc == nil {
+		return false
+	}
+	for _, c := range doc.List {
+		if strings.TrimSpace(c.Text) == Directive {
+			return true
+		}
+	}
+	return false
+}
+
+func blank(expr ast.Expr) bool {
+	id, ok := expr.(*ast.Ident)
+	return ok && id.Name == "_"
+}
+
+// callee returns a short description of the function called by call.
+func callee(call *ast.CallExpr) string {
+	switch fn := ast.Unparen(call.Fun).(type) {
+	case *ast.Ident:
+		return fn.Name
+	case *ast.SelectorExpr:
+		return fn.Sel.Name
+	}
+	return "call"
+}
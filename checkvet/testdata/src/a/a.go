@@ -0,0 +1,35 @@
+package a
+
+import (
+	"io"
+
+	"github.com/pborman/check"
+)
+
+// Checker hides the Checker behind an interface.
+type Checker interface {
+	Error(got error, want interface{}) check.Result
+}
+
+func wrap(ck *check.Checker) (check.Result, int) { return ck.Error(nil, nil), 0 }
+
+func uses(ck *check.Checker, iface Checker) {
+	ck.Error(io.EOF, true)       // want `check.Result returned by Error is discarded`
+	_ = ck.Error(io.EOF, true)   // want `check.Result returned by Error is assigned to _`
+	iface.Error(io.EOF, true)    // want `check.Result returned by Error is discarded`
+	(ck.Error(io.EOF, true))     // want `check.Result returned by Error is discarded`
+	_, _ = wrap(ck)              // want `check.Result returned by wrap is assigned to _`
+	wrap(ck)                     // want `check.Result returned by wrap is discarded`
+	defer ck.Error(io.EOF, true) // want `check.Result returned by Error is discarded by defer statement`
+	go iface.Error(io.EOF, true) // want `check.Result returned by Error is discarded by go statement`
+	check.Error(io.EOF, true)    // strings are not checked
+
+	if r := ck.Error(io.EOF, true); r != "" {
+		panic(r)
+	}
+	r, _ := wrap(ck)
+	_ = r
+	var rs []check.Result
+	rs = append(rs, iface.Error(nil, nil))
+	_ = rs
+}
@@ -0,0 +1,19 @@
+// Package check is a stub of github.com/pborman/check for testing.
+package check
+
+// Result is a check result.
+//
+//check:mustuse
+type Result string // want Result:"mustuse"
+
+type Checker struct{}
+
+func (*Checker) Error(got error, want interface{}) Result { return "" }
+
+func Error(got error, want interface{}) string { return "" }
+
+func helper() Result { return "" }
+
+func init() {
+	helper() // want `check.Result returned by helper is discarded`
+}
@@ -0,0 +1,90 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"bytes"
+	"io"
+	"strings"
+)
+
+// A Command runs a command line program with the arguments args, not
+// including the name of the program, writing its output, including any usage
+// or help message, to out, and returns its error.  A Command must return its
+// errors, such as those of parsing its flags, rather than exit.
+type Command func(args []string, out io.Writer) error
+
+// CLI returns the empty string if cmd, run with args, returns an error
+// matched by want and, if outputWant is not nil, writes output matched by
+// outputWant, as by Error with an error whose message is the output, or no
+// error if there is no output, otherwise it returns a string indicating each
+// failure.  An outputWant of false matches no output.  A cmd that panics
+// fails.  A Command is easily made from a github.com/spf13/cobra command:
+//
+//	run := func(args []string, out io.Writer) error {
+//		cmd := newRootCmd()
+//		cmd.SetArgs(args)
+//		cmd.SetOut(out)
+//		cmd.SetErr(out)
+//		return cmd.Execute()
+//	}
+//	if s := check.CLI(run, []string{"--port=x"}, check.FlagError(check.BadFlagValue, "port"), "Usage:"); s != "" {
+//		t.Error(s)
+//	}
+//
+// or from a function using a flag.FlagSet made with flag.ContinueOnError,
+// whose output is set to out.
+func CLI(cmd Command, args []string, want, outputWant interface{}) string {
+	return defaults().cli(cmd, args, want, outputWant)
+}
+
+// CLI is the same as the CLI function but uses the options of ck.
+func (ck *Checker) CLI(cmd Command, args []string, want, outputWant interface{}) Result {
+	ck.count()
+	return Result(ck.c.cli(cmd, args, want, outputWant))
+}
+
+// cli implements CLI using the settings in c.
+func (c *config) cli(cmd Command, args []string, want, outputWant interface{}) string {
+	if c.outer() {
+		return c.run("CLI", nil, func() string { return Describe(want) },
+			func(c *config) string { return c.cli(cmd, args, want, outputWant) })
+	}
+	var out bytes.Buffer
+	var err error
+	if p, panicked := recovered(func() { err = cmd(args, &out) }); panicked {
+		return c.failf(unexpectedPanic, p)
+	}
+	var failures []string
+	if s := c.checkError(err, want); s != "" {
+		failures = append(failures, s)
+	}
+	if outputWant != nil {
+		var output error
+		if out.Len() > 0 {
+			output = errorString(out.String())
+		}
+		if s := c.checkError(output, outputWant); s != "" {
+			if !c.quiet {
+				s = prefixed("output: ", s)
+			}
+			failures = append(failures, s)
+		}
+	}
+	if c.quiet && len(failures) > 0 {
+		return quietFailure
+	}
+	return strings.Join(failures, "\n")
+}
@@ -0,0 +1,67 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"testing"
+)
+
+// serve is a command that parses its flags with a flag.FlagSet.
+func serve(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	fs.SetOutput(out)
+	port := fs.Int("port", 80, "port to serve on")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unexpected argument %q", fs.Arg(0))
+	}
+	fmt.Fprintf(out, "serving on %d\n", *port)
+	return nil
+}
+
+func TestCLI(t *testing.T) {
+	setDefaults(t)
+	badPort := `invalid value "x" for flag -port: parse error`
+	for _, tt := range []struct {
+		name   string
+		cmd    Command
+		args   []string
+		want   interface{}
+		output interface{}
+		out    string
+	}{
+		{"ok", serve, nil, nil, Equal("serving on 80\n"), ""},
+		{"port", serve, []string{"-port=8080"}, nil, "8080", ""},
+		{"unchecked output", serve, []string{"-port=8080"}, nil, nil, ""},
+		{"bad flag", serve, []string{"-port=x"}, FlagError(BadFlagValue, "port"), "Usage of serve:", ""},
+		{"help", serve, []string{"-h"}, flag.ErrHelp, "-port int", ""},
+		{"argument", serve, []string{"x"}, "unexpected argument", false, ""},
+		{"wrong error", serve, []string{"-port=x"}, "unknown", nil, sprintf(wrong, badPort, "unknown")},
+		{"wrong output", serve, []string{"-port=x"}, true, "Usage of run:",
+			"output: " + defaults().failf(wrong, badPort+"\nUsage of serve:\n  -port int\n    \tport to serve on (default 80)\n", "Usage of run:")},
+		{"both", serve, nil, true, "8080", defaults().failf(missing) + "\noutput: " + defaults().failf(wrong, "serving on 80\n", "8080")},
+		{"output", serve, nil, nil, false, "output: " + defaults().failf(unexpected, "serving on 80\n")},
+		{"panic", func([]string, io.Writer) error { panic("boom") }, nil, nil, nil, sprintf(unexpectedPanic, "boom")},
+	} {
+		if s := CLI(tt.cmd, tt.args, tt.want, tt.output); s != tt.out {
+			t.Errorf("%s: got %q, want %q", tt.name, s, tt.out)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// A Collector collects the failures of checks made by many goroutines, such
+// as the workers of a pool, where calling t.Error is not possible or not
+// wanted.  Each failure is recorded with a trimmed stack of the goroutine
+// that added it, so a failure identifies the code path that produced the bad
+// error.  The zero value is ready to use.  A Collector is safe for concurrent
+// use.
+//
+//	var col check.Collector
+//	for _, job := range jobs {
+//		go func(job Job) {
+//			defer wg.Done()
+//			col.Add(check.Error(job.Run(), nil))
+//		}(job)
+//	}
+//	wg.Wait()
+//	col.Report(t)
+//
+// In a table test a Collector also gathers the several checks of a row, each
+// with a label, into one report:
+//
+//	var col check.Collector
+//	col.AddLabel("error", check.Error(err, tt.err))
+//	col.AddLabel("value", check.Value(got, tt.want))
+//	if s := col.Join(); s != "" {
+//		t.Errorf("%s:\n%s", tt.name, s)
+//	}
+type Collector struct {
+	mu       sync.Mutex
+	failures []Collected
+}
+
+// A Collected is a failure recorded by a Collector.
+type Collected struct {
+	Label   string // the label passed to AddLabel, if any
+	Failure string
+	Stack   []string // "function file:line", innermost first
+}
+
+// String returns the labeled failure followed by its stack, one frame per
+// line.
+func (c Collected) String() string {
+	var b strings.Builder
+	b.WriteString(Label(c.Label, c.Failure))
+	for _, frame := range c.Stack {
+		b.WriteString("\n\t")
+		b.WriteString(frame)
+	}
+	return b.String()
+}
+
+// maxStackFrames is the maximum number of frames recorded by a Collector.
+const maxStackFrames = 8
+
+// Add records the failure s, the result of a check, with the stack of the
+// calling goroutine.  Add does nothing if s is the empty string.  Add reports
+// whether s was recorded.
+func (col *Collector) Add(s string) bool {
+	return col.add("", s)
+}
+
+// AddLabel is Add with the failure s labeled with label, such as the name of
+// the check that produced it.
+func (col *Collector) AddLabel(label, s string) bool {
+	return col.add(label, s)
+}
+
+func (col *Collector) add(label, s string) bool {
+	if s == "" {
+		return false
+	}
+	c := Collected{Label: label, Failure: s, Stack: stack(3)}
+	col.mu.Lock()
+	col.failures = append(col.failures, c)
+	col.mu.Unlock()
+	return true
+}
+
+// Failures returns the failures recorded by col, in the order they were
+// added.
+func (col *Collector) Failures() []Collected {
+	col.mu.Lock()
+	defer col.mu.Unlock()
+	return append([]Collected(nil), col.failures...)
+}
+
+// Report calls t.Error with each failure recorded by col, including its
+// stack.
+func (col *Collector) Report(t testing.TB) {
+	t.Helper()
+	for _, c := range col.Failures() {
+		t.Error(c.String())
+	}
+}
+
+// Join returns the failures recorded by col, labeled and without their
+// stacks, one per line, or the empty string if there are none.
+func (col *Collector) Join() string {
+	var ss []string
+	for _, c := range col.Failures() {
+		ss = append(ss, Label(c.Label, c.Failure))
+	}
+	return Join(ss...)
+}
+
+// Join returns the non-empty failures in ss, one per line, or the empty
+// string if there are none, combining several checks into one:
+//
+//	if s := check.Join(
+//		check.Label("error", check.Error(err, tt.err)),
+//		check.Label("value", check.Value(got, tt.want)),
+//	); s != "" {
+//		t.Errorf("%s:\n%s", tt.name, s)
+//	}
+func Join(ss ...string) string {
+	var out []string
+	for _, s := range ss {
+		if s != "" {
+			out = append(out, s)
+		}
+	}
+	return strings.Join(out, "\n")
+}
+
+// Label returns the failure s prefixed with label and a colon, or the empty
+// string if s is the empty string.  The label follows the Code of s, if any.
+// An empty label returns s.
+func Label(label, s string) string {
+	if s == "" || label == "" {
+		return s
+	}
+	return prefixed(label+": ", s)
+}
+
+// stack returns the trimmed stack of the calling goroutine, skipping skip
+// frames, as by runtime.Callers.  Frames of this package, other than its
+// tests, and of the runtime and testing packages are omitted, and at most
+// maxStackFrames frames are returned.
+func stack(skip int) []string {
+	pcs := make([]uintptr, 64)
+	return frameLines(pcs[:runtime.Callers(skip+1, pcs)])
+}
+
+// frameLines returns the frames of pcs, as returned by runtime.Callers, as
+// "function file:line" lines, trimmed as by stack.
+func frameLines(pcs []uintptr) []string {
+	frames := runtime.CallersFrames(pcs)
+	var lines []string
+	for len(lines) < maxStackFrames {
+		f, more := frames.Next()
+		switch {
+		case strings.HasPrefix(f.Function, pkgPrefix) && !strings.HasSuffix(f.File, "_test.go"):
+		case strings.HasPrefix(f.Function, "runtime."), strings.HasPrefix(f.Function, "testing."):
+		default:
+			lines = append(lines, fmt.Sprintf("%s %s:%d", f.Function, f.File, f.Line))
+		}
+		if !more {
+			break
+		}
+	}
+	return lines
+}
@@ -0,0 +1,122 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"io"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// collectWorker adds the result of checking err to col.
+func collectWorker(col *Collector, err error) {
+	col.Add(Error(err, nil))
+}
+
+func TestCollector(t *testing.T) {
+	setDefaults(t)
+	var col Collector
+	if col.Add("") {
+		t.Errorf("Add recorded an empty failure")
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			var err error
+			if i%5 == 0 {
+				err = io.EOF
+			}
+			collectWorker(&col, err)
+		}(i)
+	}
+	wg.Wait()
+	failures := col.Failures()
+	if len(failures) != 2 {
+		t.Fatalf("got %d failures, want 2", len(failures))
+	}
+	for _, f := range failures {
+		if f.Failure != sprintf(unexpected, "EOF") {
+			t.Errorf("got failure %q", f.Failure)
+		}
+		if len(f.Stack) == 0 || !strings.HasPrefix(f.Stack[0], pkgPrefix+"collectWorker ") || !strings.Contains(f.Stack[0], "collect_test.go:") {
+			t.Errorf("got stack %q", f.Stack)
+		}
+		for _, frame := range f.Stack {
+			if strings.HasPrefix(frame, "runtime.") || strings.HasPrefix(frame, "testing.") {
+				t.Errorf("stack includes %q", frame)
+			}
+		}
+		if s := f.String(); !strings.HasPrefix(s, f.Failure+"\n\t"+f.Stack[0]) {
+			t.Errorf("got string %q", s)
+		}
+	}
+
+	var ft fakeTB
+	col.Report(&ft)
+	if len(ft.errors) != 2 || ft.errors[0] != failures[0].String() {
+		t.Errorf("got reported errors %q", ft.errors)
+	}
+}
+
+func TestStackLimit(t *testing.T) {
+	var f func(n int) []string
+	f = func(n int) []string {
+		if n == 0 {
+			return stack(1)
+		}
+		return f(n - 1)
+	}
+	if got := f(2 * maxStackFrames); len(got) != maxStackFrames {
+		t.Errorf("got %d frames, want %d", len(got), maxStackFrames)
+	}
+}
+
+func TestJoin(t *testing.T) {
+	setDefaults(t)
+	for _, tt := range []struct {
+		ss  []string
+		out string
+	}{
+		{nil, ""},
+		{[]string{"", ""}, ""},
+		{[]string{"a", "", "b"}, "a\nb"},
+		{[]string{Label("error", ""), Label("value", "bad")}, "value: bad"},
+		{[]string{Label("", "bad")}, "bad"},
+		{[]string{Label("error", "CHK-WRONG: bad")}, "CHK-WRONG: error: bad"},
+	} {
+		if s := Join(tt.ss...); s != tt.out {
+			t.Errorf("Join(%q): got %q, want %q", tt.ss, s, tt.out)
+		}
+	}
+
+	var col Collector
+	if s := col.Join(); s != "" {
+		t.Errorf("empty Collector joined %q", s)
+	}
+	col.AddLabel("error", Error(io.EOF, nil))
+	col.AddLabel("value", "")
+	col.Add(Error(nil, io.EOF))
+	want := "error: " + sprintf(unexpected, "EOF") + "\n" + sprintf(expected, "EOF")
+	if s := col.Join(); s != want {
+		t.Errorf("got %q, want %q", s, want)
+	}
+	f := col.Failures()[0]
+	if f.Label != "error" || !strings.HasPrefix(f.String(), "error: ") || len(f.Stack) == 0 || !strings.Contains(f.Stack[0], "collect_test.go:") {
+		t.Errorf("got %#v", f)
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import "strings"
+
+type all []interface{}
+
+// All returns a Matcher that matches an error matched, as by Error, by each of
+// wants.  A failure lists the failure of each want that did not match:
+//
+//	check.Error(err, check.All("connect", check.Not("timeout")))
+func All(wants ...interface{}) Matcher { return all(wants) }
+
+func (m all) match(c *config, got error) string {
+	var failures []string
+	var failed []interface{}
+	for _, want := range m {
+		if s := c.checkError(got, want); s != "" {
+			failures = append(failures, s)
+			failed = append(failed, want)
+		}
+	}
+	if c.nearest && len(failures) > 1 {
+		s := failures[nearestWant(c, got, failed)]
+		if c.quiet {
+			return s
+		}
+		return s + sprintf("\n(and %d more failing wants)", len(failures)-1)
+	}
+	return strings.Join(failures, "\n")
+}
+
+type anyOf []interface{}
+
+// Any returns a Matcher that matches an error matched, as by Error, by at
+// least one of wants.  A failure lists the failure of each want.
+func Any(wants ...interface{}) Matcher { return anyOf(wants) }
+
+func (m anyOf) match(c *config, got error) string {
+	failures := make([]string, len(m))
+	for i, want := range m {
+		failures[i] = c.checkError(got, want)
+		if failures[i] == "" {
+			return ""
+		}
+	}
+	if c.quiet {
+		return quietFailure
+	}
+	code := CodeWrong
+	if got == nil {
+		code = CodeMissing
+	}
+	var b strings.Builder
+	b.WriteString(c.code(code))
+	if c.nearest && len(m) > 1 {
+		b.WriteString(sprintf("no want matched, nearest of %d:\n", len(m)))
+		b.WriteString(indent(failures[nearestWant(c, got, m)]))
+		return b.String()
+	}
+	b.WriteString("no want matched:")
+	for _, s := range failures {
+		b.WriteString("\n")
+		b.WriteString(indent(s))
+	}
+	return b.String()
+}
+
+type not struct {
+	want interface{}
+}
+
+// Not returns a Matcher that matches an error, or no error, not matched by
+// want, as by Error.  Not("timeout") matches nil as well as any error whose
+// message does not contain "timeout".
+func Not(want interface{}) Matcher { return not{want: want} }
+
+func (m not) match(c *config, got error) string {
+	q := *c
+	q.quiet = true
+	if q.checkError(got, m.want) != "" {
+		return ""
+	}
+	if got == nil {
+		return c.failc(CodeMissing, "did not get expected error, want %q", description(m.Describe()))
+	}
+	return c.failc(CodeWrong, "got error %q, want %q", got, description(m.Describe()))
+}
+
+// Nearest returns an Option that causes a failing All or Any to report only
+// its nearest miss, the failing want closest to matching, rather than the
+// failure of each of its wants.  Nearness is judged by the similarity of the
+// message of the error to the messages wanted; a want that is not a message,
+// such as an error, is never near.
+func Nearest() Option {
+	return func(c *config) { c.nearest = true }
+}
+
+// nearestWant returns the index of the want in wants nearest to matching got.
+// The first is returned when several are equally near.
+func nearestWant(c *config, got error, wants []interface{}) int {
+	best, score := 0, -1.0
+	for i, want := range wants {
+		if n := nearness(c, got, want); n > score {
+			best, score = i, n
+		}
+	}
+	return best
+}
+
+// nearness returns how near got is to matching want, from 0 (not near) to 1
+// (matched).
+func nearness(c *config, got error, want interface{}) float64 {
+	q := *c
+	q.quiet = true
+	if q.checkError(got, want) == "" {
+		return 1
+	}
+	if got == nil {
+		return 0
+	}
+	msg := got.Error()
+	switch w := want.(type) {
+	case string:
+		return containsNearness(msg, w)
+	case Case:
+		return containsNearness(c.toLower(msg), c.toLower(string(w)))
+	case Equal:
+		return similarity(msg, string(w))
+	case CaseEqual:
+		return similarity(c.toLower(msg), c.toLower(string(w)))
+	case all:
+		// The mean nearness of the wants.
+		var sum float64
+		for _, want := range w {
+			sum += nearness(c, got, want)
+		}
+		return sum / float64(len(w))
+	case anyOf:
+		var max float64
+		for _, want := range w {
+			if n := nearness(c, got, want); n > max {
+				max = n
+			}
+		}
+		return max
+	}
+	return 0
+}
+
+// containsNearness returns the greatest similarity of sub to a substring of
+// msg of the same length, in runes.
+func containsNearness(msg, sub string) float64 {
+	rm, rs := []rune(msg), []rune(sub)
+	if len(rm) <= len(rs) {
+		return similarity(msg, sub)
+	}
+	var max float64
+	for i := 0; i+len(rs) <= len(rm); i++ {
+		if n := similarity(string(rm[i:i+len(rs)]), sub); n > max {
+			max = n
+		}
+	}
+	return max
+}
@@ -0,0 +1,127 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestCombine(t *testing.T) {
+	setDefaults(t)
+	refused := errors.New("connect: connection refused")
+	timeout := errors.New("connect: timeout")
+	for _, tt := range []struct {
+		name string
+		got  error
+		want Matcher
+		out  string
+	}{
+		{"all", refused, All("connect", Not("timeout")), ""},
+		{"all empty", refused, All(), ""},
+		{"all one", timeout, All("connect", Not("timeout")), `got error "connect: timeout", want not contains "timeout"`},
+		{"all both", io.EOF, All("connect", "refused"), sprintf(wrong, io.EOF, "connect") + "\n" + sprintf(wrong, io.EOF, "refused")},
+		{"any first", refused, Any("refused", io.EOF), ""},
+		{"any last", io.EOF, Any("refused", io.EOF), ""},
+		{"any none", timeout, Any("refused", io.EOF),
+			"no want matched:\n\t" + sprintf(wrong, timeout, "refused") + "\n\t" + sprintf(wrong, timeout, io.EOF)},
+		{"any nil", nil, Any("refused", nil), ""},
+		{"not", refused, Not("timeout"), ""},
+		{"not nil got", nil, Not("timeout"), ""},
+		{"not nil", io.EOF, Not(nil), ""},
+		{"not matched", nil, Not(nil), "did not get expected error, want not no error"},
+		{"not not", timeout, Not(Not("timeout")), ""},
+		{"nested", refused, Any(All("connect", "timeout"), Equal("connect: connection refused")), ""},
+	} {
+		if s := Error(tt.got, tt.want); s != tt.out {
+			t.Errorf("%s: got %q, want %q", tt.name, s, tt.out)
+		}
+	}
+	setDefaults(t, Codes())
+	if s := Error(nil, Any("x")); s != "CHK-MISSING: no want matched:\n\t"+defaults().failf(expected, "x") {
+		t.Errorf("with Codes got %q", s)
+	}
+}
+
+func TestCombineDescribe(t *testing.T) {
+	for _, tt := range []struct {
+		want Matcher
+		out  string
+	}{
+		{All("a", Not("b")), `contains "a" AND not contains "b"`},
+		{Any("a", io.EOF), `contains "a" OR is the error "EOF" (*errors.errorString)`},
+		{Not(nil), "not no error"},
+	} {
+		if d := Describe(tt.want); d != tt.out {
+			t.Errorf("got %q, want %q", d, tt.out)
+		}
+	}
+	for _, tt := range []struct {
+		want Matcher
+		err  string
+	}{
+		{All("a", 1), "want 0: All want 1: unsupported type int"},
+		{Any(1), "want 0: Any want 0: unsupported type int"},
+		{Not(All(1.0)), "want 0: Not: All want 0: unsupported type float64"},
+	} {
+		if err := Validate(tt.want); err == nil || err.Error() != tt.err {
+			t.Errorf("Validate(%s): got %v, want %s", Describe(tt.want), err, tt.err)
+		}
+	}
+}
+
+func TestNearest(t *testing.T) {
+	setDefaults(t, Nearest())
+	got := errors.New("open x: permission deny")
+	for _, tt := range []struct {
+		name string
+		want Matcher
+		out  string
+	}{
+		{"any", Any("connection refused", "permission denied", io.EOF),
+			"no want matched, nearest of 3:\n\t" + sprintf(wrong, got, "permission denied")},
+		{"any equal", Any(Equal("open y: permission denied"), Equal("close x")),
+			"no want matched, nearest of 2:\n\t" + sprintf(wrong, got, "open y: permission denied")},
+		{"any one", Any("refused"), "no want matched:\n\t" + sprintf(wrong, got, "refused")},
+		{"any passes", Any("refused", "deny"), ""},
+		{"all", All("open", "refused", "permision", io.EOF),
+			sprintf(wrong, got, "permision") + "\n(and 2 more failing wants)"},
+		{"all one", All("open", "refused"), sprintf(wrong, got, "refused")},
+		{"nested", Any(All("open", "timeout"), All("close", "timeout")),
+			"no want matched, nearest of 2:\n\t" + sprintf(wrong, got, "timeout")},
+	} {
+		if s := Error(got, tt.want); s != tt.out {
+			t.Errorf("%s: got %q, want %q", tt.name, s, tt.out)
+		}
+	}
+}
+
+func TestContainsNearness(t *testing.T) {
+	for _, tt := range []struct {
+		msg, sub string
+		want     float64
+	}{
+		{"abc", "abc", 1},
+		{"xxabcxx", "abc", 1},
+		{"xxabdxx", "abc", similarity("abd", "abc")},
+		{"ab", "abcd", 0.5},
+		{"", "", 1},
+	} {
+		if got := containsNearness(tt.msg, tt.sub); got != tt.want {
+			t.Errorf("containsNearness(%q, %q): got %v, want %v", tt.msg, tt.sub, got, tt.want)
+		}
+	}
+}
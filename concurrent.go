@@ -0,0 +1,95 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"strings"
+	"sync"
+)
+
+// An Outcome is the number of calls expected to return an error matching
+// Want, as by Error.  A nil Want is a call that succeeds.
+type Outcome struct {
+	Want  interface{}
+	Count int
+}
+
+// Concurrently calls f from n goroutines, passing each the number of its
+// call, from 0, and returns the empty string if the errors returned match
+// outcomes, otherwise it returns a string indicating the differences.  The
+// goroutines are released together to maximize contention.  Each error is
+// counted against the first of outcomes it matches, so outcomes with more
+// specific wants should come first.  Concurrently tests exactly-once
+// semantics:
+//
+//	s := check.Concurrently(10, func(int) error {
+//		return store.Create(ctx, "key")
+//	}, check.Outcome{nil, 1}, check.Outcome{ErrAlreadyExists, 9})
+func Concurrently(n int, f func(i int) error, outcomes ...Outcome) string {
+	return defaults().concurrently(n, f, outcomes)
+}
+
+// Concurrently is the same as the Concurrently function but uses the options of
+// ck.
+func (ck *Checker) Concurrently(n int, f func(i int) error, outcomes ...Outcome) Result {
+	ck.count()
+	return Result(ck.c.concurrently(n, f, outcomes))
+}
+
+func (c *config) concurrently(n int, f func(i int) error, outcomes []Outcome) string {
+	if c.outer() {
+		return c.run("Concurrently", nil, nil,
+			func(c *config) string { return c.concurrently(n, f, outcomes) })
+	}
+	errs := make([]error, n)
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(n)
+	for i := 0; i < n; i++ {
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			errs[i] = f(i)
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	q := *c
+	q.quiet = true
+	var failures []string
+	counts := make([]int, len(outcomes))
+Calls:
+	for i, err := range errs {
+		for j, o := range outcomes {
+			if q.checkError(err, o.Want) == "" {
+				counts[j]++
+				continue Calls
+			}
+		}
+		prefix := sprintf("call %d: ", i)
+		if err == nil {
+			failures = append(failures, prefixed(prefix, c.failc(CodeMissing, "did not get an expected error")))
+		} else {
+			failures = append(failures, prefixed(prefix, c.failf(unexpected, err)))
+		}
+	}
+	for j, o := range outcomes {
+		if counts[j] != o.Count {
+			failures = append(failures, c.failc(CodeWrong, sprintf("got %d calls, want %d, with: %%q", counts[j], o.Count), description(Describe(o.Want))))
+		}
+	}
+	return strings.Join(failures, "\n")
+}
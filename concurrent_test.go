@@ -0,0 +1,113 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"testing"
+)
+
+// onceStore creates a key at most once.
+type onceStore struct {
+	mu   sync.Mutex
+	keys map[string]bool
+}
+
+func (s *onceStore) Create(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.keys[key] {
+		return os.ErrExist
+	}
+	s.keys[key] = true
+	return nil
+}
+
+// racyStore creates keys without checking if they exist.
+type racyStore struct{}
+
+func (racyStore) Create(string) error { return nil }
+
+func TestConcurrently(t *testing.T) {
+	setDefaults(t)
+	create := func(s interface{ Create(string) error }) func(int) error {
+		return func(int) error { return s.Create("key") }
+	}
+	for _, tt := range []struct {
+		name     string
+		f        func(int) error
+		outcomes []Outcome
+		out      string
+	}{
+		{
+			name:     "exactly once",
+			f:        create(&onceStore{keys: map[string]bool{}}),
+			outcomes: []Outcome{{nil, 1}, {os.ErrExist, 4}},
+		}, {
+			name:     "racy",
+			f:        create(racyStore{}),
+			outcomes: []Outcome{{nil, 1}, {os.ErrExist, 4}},
+			out:      "got 5 calls, want 1, with: no error\ngot 0 calls, want 4, with: is the error \"file already exists\" (*errors.errorString)",
+		}, {
+			name:     "unexpected",
+			f:        func(i int) error { return []error{nil, io.EOF, nil, nil, nil}[i] },
+			outcomes: []Outcome{{nil, 4}},
+			out:      "call 1: " + sprintf(unexpected, "EOF"),
+		}, {
+			name: "missing",
+			f: func(i int) error {
+				if i == 0 {
+					return nil
+				}
+				return errors.New("busy")
+			},
+			outcomes: []Outcome{{"busy", 5}},
+			out:      "call 0: did not get an expected error\ngot 4 calls, want 5, with: contains \"busy\"",
+		}, {
+			name:     "first outcome wins",
+			f:        func(int) error { return io.EOF },
+			outcomes: []Outcome{{io.EOF, 5}, {true, 0}},
+		},
+	} {
+		if s := Concurrently(5, tt.f, tt.outcomes...); s != tt.out {
+			t.Errorf("%s: got:\n%s\nwant:\n%s", tt.name, s, tt.out)
+		}
+	}
+}
+
+func TestConcurrentlySettings(t *testing.T) {
+	setDefaults(t, IgnoreWrapping(), Codes())
+	busy := func(i int) error {
+		if i == 0 {
+			return nil
+		}
+		return fmt.Errorf("create: %w", errors.New("busy"))
+	}
+	if s := Concurrently(5, busy, Outcome{nil, 1}, Outcome{Equal("busy"), 4}); s != "" {
+		t.Errorf("IgnoreWrapping: %s", s)
+	}
+	want := "CHK-MISSING: call 0: did not get an expected error\nCHK-WRONG: got 4 calls, want 5, with: is \"busy\""
+	if s := Concurrently(5, busy, Outcome{Equal("busy"), 5}); s != want {
+		t.Errorf("Codes: got %q, want %q", s, want)
+	}
+	want = "CHK-UNEXPECTED: call 1: " + sprintf(unexpected, "create: busy")
+	if s := Concurrently(2, busy, Outcome{nil, 1}); s != want {
+		t.Errorf("Codes: got %q, want %q", s, want)
+	}
+}
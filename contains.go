@@ -0,0 +1,71 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import "strings"
+
+// ContainsAll is a Matcher that matches an error whose message contains each
+// of its fragments, such as the operation, the file name, and the errno text
+// of the error.  A failure lists each fragment that is missing:
+//
+//	check.Error(err, check.ContainsAll{"open", "config.yaml", "no such file"})
+type ContainsAll []string
+
+// ContainsAny is a Matcher that matches an error whose message contains at
+// least one of its fragments.
+type ContainsAny []string
+
+func (m ContainsAll) match(c *config, got error) string {
+	if got == nil {
+		return c.failc(CodeMissing, "did not get expected error, want %q", description(m.Describe()))
+	}
+	var missing []string
+	for _, f := range m {
+		if !strings.Contains(got.Error(), f) {
+			missing = append(missing, f)
+		}
+	}
+	if len(missing) == 0 {
+		return ""
+	}
+	if c.quiet {
+		return quietFailure
+	}
+	return c.failc(CodeWrong, "got error %q", got) + ", missing " + quoteAll(missing)
+}
+
+func (m ContainsAny) match(c *config, got error) string {
+	if got == nil {
+		return c.failc(CodeMissing, "did not get expected error, want %q", description(m.Describe()))
+	}
+	for _, f := range m {
+		if strings.Contains(got.Error(), f) {
+			return ""
+		}
+	}
+	if c.quiet {
+		return quietFailure
+	}
+	return c.failc(CodeWrong, "got error %q", got) + ", want any of " + quoteAll(m)
+}
+
+// quoteAll returns ss quoted and separated by commas.
+func quoteAll(ss []string) string {
+	qs := make([]string, len(ss))
+	for i, s := range ss {
+		qs[i] = sprintf("%q", s)
+	}
+	return strings.Join(qs, ", ")
+}
@@ -0,0 +1,51 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestContains(t *testing.T) {
+	setDefaults(t)
+	got := errors.New("open config.yaml: no such file or directory")
+	for _, tt := range []struct {
+		got  error
+		want Matcher
+		out  string
+	}{
+		{got, ContainsAll{"open", "config.yaml", "no such file"}, ""},
+		{got, ContainsAll{"open", "app.yaml", "permission"}, sprintf("got error %q, missing \"app.yaml\", \"permission\"", got)},
+		{nil, ContainsAll{"open"}, `did not get expected error, want contains all of "open"`},
+		{got, ContainsAny{"permission", "no such file"}, ""},
+		{got, ContainsAny{"permission", "exists"}, sprintf("got error %q, want any of \"permission\", \"exists\"", got)},
+		{nil, ContainsAny{"a", "b"}, `did not get expected error, want contains any of "a", "b"`},
+	} {
+		if s := Error(tt.got, tt.want); s != tt.out {
+			t.Errorf("Error(%v, %v): got %q, want %q", tt.got, tt.want, s, tt.out)
+		}
+	}
+	if Matches(got, ContainsAll{"close"}) || !Matches(got, ContainsAny{"open"}) {
+		t.Errorf("Matches disagrees with Error")
+	}
+	if d := Describe(ContainsAll{"a", "b"}); d != `contains all of "a", "b"` {
+		t.Errorf("got description %q", d)
+	}
+	if err := Validate(ContainsAll{"a"}, ContainsAll{}, ContainsAny{"a", ""}); fmt.Sprint(err) != "want 1: ContainsAll has no fragments\nwant 2: ContainsAny fragment 1 is empty" {
+		t.Errorf("got validation error %v", err)
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"context"
+	"sync"
+)
+
+// DeadlineExceeded is a Matcher that matches an error that is
+// context.DeadlineExceeded, as determined by errors.Is.  The Category
+// Canceled is its counterpart for context.Canceled, while the Category
+// Timeout also matches other timeouts.
+var DeadlineExceeded Matcher = sentinel{context.DeadlineExceeded, "context.DeadlineExceeded"}
+
+// Context returns the empty string if the error of ctx, as returned by its Err
+// method, matches want, as by Error, otherwise it returns a string indicating
+// the failure, prefixed with "context: ".  A want of nil matches a context
+// that is not done:
+//
+//	for _, tt := range []struct {
+//		name string
+//		stop func(context.CancelFunc)
+//		err  interface{}
+//	}{
+//		{"running", func(context.CancelFunc) {}, nil},
+//		{"canceled", func(cancel context.CancelFunc) { cancel() }, check.Canceled},
+//	} {
+//		...
+//		if s := check.Context(ctx, tt.err); s != "" {
+func Context(ctx context.Context, want interface{}) string {
+	return defaults().context(ctx, want)
+}
+
+// Context is the same as the Context function but uses the options of ck.
+func (ck *Checker) Context(ctx context.Context, want interface{}) Result {
+	ck.count()
+	return Result(ck.c.context(ctx, want))
+}
+
+func (c *config) context(ctx context.Context, want interface{}) string {
+	if c.outer() {
+		return c.run("Context", nil, func() string { return Describe(want) },
+			func(c *config) string { return c.context(ctx, want) })
+	}
+	s := c.checkError(ctx.Err(), want)
+	if s == "" || c.quiet {
+		return s
+	}
+	return prefixed("context: ", s)
+}
+
+// A ContextErrors captures the errors recorded, by CaptureError, in a context
+// returned by WithCapture, such as the errors handled by middleware, so they
+// may be checked once a request completes.  A ContextErrors is safe for
+// concurrent use.
+type ContextErrors struct {
+	mu   sync.Mutex
+	errs []error
+}
+
+// captureKey is the context key of the ContextErrors of a context.
+type captureKey struct{}
+
+// WithCapture returns a copy of ctx in which the errors passed to CaptureError
+// are captured by the returned ContextErrors:
+//
+//	ctx, captured := check.WithCapture(context.Background())
+//	req := httptest.NewRequest("GET", "/users/bob", nil).WithContext(ctx)
+//	handler.ServeHTTP(httptest.NewRecorder(), req)
+//	if s := captured.Error(check.NotFound); s != "" {
+//		t.Error(s)
+//	}
+//
+// If ctx already captures errors, the errors are captured by the returned
+// ContextErrors instead.
+func WithCapture(ctx context.Context) (context.Context, *ContextErrors) {
+	ce := &ContextErrors{}
+	return context.WithValue(ctx, captureKey{}, ce), ce
+}
+
+// CaptureError records err, if not nil, in the ContextErrors of ctx, as
+// returned by WithCapture.  CaptureError does nothing if ctx does not capture
+// errors, so it may be called by code, such as the error hook of middleware,
+// that also runs outside of tests:
+//
+//	api.Handler(api.WithErrorHook(func(r *http.Request, err error) {
+//		check.CaptureError(r.Context(), err)
+//	}))
+func CaptureError(ctx context.Context, err error) {
+	if err == nil {
+		return
+	}
+	ce, ok := ctx.Value(captureKey{}).(*ContextErrors)
+	if !ok {
+		return
+	}
+	ce.mu.Lock()
+	ce.errs = append(ce.errs, err)
+	ce.mu.Unlock()
+}
+
+// Errors returns the errors captured by ce, in the order they were captured.
+func (ce *ContextErrors) Errors() []error {
+	ce.mu.Lock()
+	defer ce.mu.Unlock()
+	return append([]error(nil), ce.errs...)
+}
+
+// Err returns nil if ce has captured no errors, the error captured if it has
+// captured one, or else an error joining the errors captured, one per line.
+func (ce *ContextErrors) Err() error {
+	return joinErrors(ce.Errors())
+}
+
+// Error is shorthand for Error(ce.Err(), want).
+func (ce *ContextErrors) Error(want interface{}) string {
+	return Error(ce.Err(), want)
+}
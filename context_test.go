@@ -0,0 +1,100 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestContext(t *testing.T) {
+	setDefaults(t)
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	running, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	for _, tt := range []struct {
+		name string
+		ctx  context.Context
+		want interface{}
+		out  string
+	}{
+		{"running", running, nil, ""},
+		{"canceled", canceled, Canceled, ""},
+		{"expired", expired, DeadlineExceeded, ""},
+		{"expired timeout", expired, Timeout, ""},
+		{"canceled any", canceled, Some, ""},
+		{"running canceled", running, Canceled, "context: " + sprintf(expectedCategory, Canceled)},
+		{"canceled running", canceled, nil, "context: " + sprintf(unexpected, context.Canceled)},
+		{"canceled expired", canceled, DeadlineExceeded, "context: " + sprintf(wrong, context.Canceled, context.DeadlineExceeded)},
+	} {
+		if s := Context(tt.ctx, tt.want); s != tt.out {
+			t.Errorf("%s: got %q, want %q", tt.name, s, tt.out)
+		}
+	}
+	if s := Error(fmt.Errorf("fetch: %w", context.DeadlineExceeded), DeadlineExceeded); s != "" {
+		t.Error(s)
+	}
+	if d := Describe(DeadlineExceeded); d != "is context.DeadlineExceeded" {
+		t.Errorf("got description %q", d)
+	}
+	setDefaults(t, Codes())
+	if s := Context(running, DeadlineExceeded); s != "CHK-MISSING: context: "+sprintf(expected, context.DeadlineExceeded) {
+		t.Errorf("got %q", s)
+	}
+}
+
+func TestWithCapture(t *testing.T) {
+	setDefaults(t)
+	ctx, captured := WithCapture(context.Background())
+	if s := captured.Error(nil); s != "" {
+		t.Errorf("nothing captured: %s", s)
+	}
+	CaptureError(ctx, nil)
+	CaptureError(context.Background(), io.EOF)
+	if errs := captured.Errors(); len(errs) != 0 {
+		t.Errorf("got errors %v, want none", errs)
+	}
+
+	child, cancel := context.WithCancel(ctx)
+	defer cancel()
+	CaptureError(child, fmt.Errorf("get user: %w", os.ErrNotExist))
+	if s := captured.Error(NotFound); s != "" {
+		t.Errorf("one captured: %s", s)
+	}
+
+	inner, innerCaptured := WithCapture(child)
+	CaptureError(inner, io.EOF)
+	if s := innerCaptured.Error(io.EOF); s != "" {
+		t.Errorf("inner: %s", s)
+	}
+	if n := len(captured.Errors()); n != 1 {
+		t.Errorf("outer captured %d errors, want 1", n)
+	}
+
+	CaptureError(ctx, io.ErrUnexpectedEOF)
+	if s := captured.Error(Equal("get user: file does not exist\nunexpected EOF")); s != "" {
+		t.Errorf("two captured: %s", s)
+	}
+	if s := captured.Error(io.ErrUnexpectedEOF); s == "" {
+		t.Errorf("joined errors matched by identity")
+	}
+}
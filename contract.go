@@ -0,0 +1,159 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"strings"
+	"testing"
+)
+
+// A Contract declares the error behaviors that every implementation of an
+// interface, such as a storage driver, must exhibit.  The author of the
+// interface declares the Contract and the author of each implementation
+// verifies it:
+//
+//	// In package storage
+//	var Contract = &check.Contract{
+//		Name: "storage",
+//		Clauses: []check.Clause{{
+//			Name: "get missing key",
+//			Call: func(impl interface{}) error {
+//				_, err := impl.(Store).Get("missing")
+//				return err
+//			},
+//			Want: check.NotFound,
+//		}},
+//	}
+//
+//	// In package memstore
+//	func TestContract(t *testing.T) {
+//		storage.Contract.Verify(t, memstore.New())
+//	}
+type Contract struct {
+	Name    string
+	Clauses []Clause
+}
+
+// A Clause is a single behavior of a Contract: calling Call with an
+// implementation returns an error that matches Want, as by Error.
+type Clause struct {
+	Name string
+	Call func(impl interface{}) error
+	Want interface{}
+}
+
+// A ContractReport is the result of checking an implementation against a
+// Contract.
+type ContractReport struct {
+	Contract string
+	Impl     string // the type of the implementation
+	Results  []ClauseResult
+}
+
+// A ClauseResult is the result of checking a single Clause.  Failure is empty
+// if the clause was satisfied.
+type ClauseResult struct {
+	Clause  string
+	Failure string
+}
+
+// Passed reports whether every clause of the contract was satisfied.
+func (r *ContractReport) Passed() bool {
+	for _, cr := range r.Results {
+		if cr.Failure != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns r in the standard report form:
+//
+//	contract storage: *memstore.Store: 1 of 2 clauses satisfied
+//	PASS get missing key
+//	FAIL put existing key: did not get expected error
+func (r *ContractReport) String() string {
+	passed := 0
+	for _, cr := range r.Results {
+		if cr.Failure == "" {
+			passed++
+		}
+	}
+	var b strings.Builder
+	b.WriteString(sprintf("contract %s: %s: %d of %d clauses satisfied", r.Contract, r.Impl, passed, len(r.Results)))
+	for _, cr := range r.Results {
+		if cr.Failure == "" {
+			b.WriteString("\nPASS ")
+			b.WriteString(cr.Clause)
+			continue
+		}
+		b.WriteString("\nFAIL ")
+		b.WriteString(cr.Clause)
+		b.WriteString(": ")
+		b.WriteString(strings.Replace(cr.Failure, "\n", "\n\t", -1))
+	}
+	return b.String()
+}
+
+// Check checks impl against each clause of ct and returns the report.  A
+// clause whose Call panics is not satisfied.
+func (ct *Contract) Check(impl interface{}) *ContractReport {
+	r := &ContractReport{
+		Contract: ct.Name,
+		Impl:     sprintf("%T", impl),
+	}
+	c := defaults()
+	for _, cl := range ct.Clauses {
+		r.Results = append(r.Results, ClauseResult{
+			Clause:  cl.Name,
+			Failure: c.satisfies(cl, impl),
+		})
+	}
+	return r
+}
+
+// satisfies returns the failure, if any, of calling cl with impl.
+func (c *config) satisfies(cl Clause, impl interface{}) (failure string) {
+	defer func() {
+		if p := recover(); p != nil {
+			failure = sprintf("panicked: %v", p)
+		}
+	}()
+	return c.checkError(cl.Call(impl), cl.Want)
+}
+
+// Verify checks impl against each clause of ct, as a subtest of t named after
+// the clause, and logs the report.
+func (ct *Contract) Verify(t *testing.T, impl interface{}) *ContractReport {
+	t.Helper()
+	r := &ContractReport{
+		Contract: ct.Name,
+		Impl:     sprintf("%T", impl),
+	}
+	c := defaults()
+	for _, cl := range ct.Clauses {
+		cl := cl
+		var failure string
+		t.Run(cl.Name, func(t *testing.T) {
+			t.Helper()
+			if failure = c.satisfies(cl, impl); failure != "" {
+				t.Error(failure)
+			}
+		})
+		r.Results = append(r.Results, ClauseResult{Clause: cl.Name, Failure: failure})
+	}
+	t.Log(r)
+	return r
+}
@@ -0,0 +1,94 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+// store is the interface of the test contract.
+type store interface {
+	Get(key string) (string, error)
+}
+
+type goodStore map[string]string
+
+func (s goodStore) Get(key string) (string, error) {
+	if v, ok := s[key]; ok {
+		return v, nil
+	}
+	return "", &os.PathError{Op: "get", Path: key, Err: os.ErrNotExist}
+}
+
+type badStore struct{}
+
+func (badStore) Get(key string) (string, error) {
+	if key == "" {
+		panic("empty key")
+	}
+	return "", errors.New("not found")
+}
+
+var storeContract = &Contract{
+	Name: "store",
+	Clauses: []Clause{{
+		Name: "get missing",
+		Call: func(impl interface{}) error {
+			_, err := impl.(store).Get("missing")
+			return err
+		},
+		Want: NotFound,
+	}, {
+		Name: "get present",
+		Call: func(impl interface{}) error {
+			_, err := impl.(store).Get("present")
+			return err
+		},
+		Want: nil,
+	}, {
+		Name: "get empty",
+		Call: func(impl interface{}) error {
+			_, err := impl.(store).Get("")
+			return err
+		},
+		Want: true,
+	}},
+}
+
+func TestContract(t *testing.T) {
+	setDefaults(t)
+	good := goodStore{"present": "value"}
+	if r := storeContract.Check(good); !r.Passed() {
+		t.Errorf("good store failed:\n%s", r)
+	}
+	r := storeContract.Verify(t, good)
+	if !r.Passed() || len(r.Results) != 3 {
+		t.Errorf("good store verify failed:\n%s", r)
+	}
+
+	r = storeContract.Check(badStore{})
+	if r.Passed() {
+		t.Errorf("bad store passed")
+	}
+	want := `contract store: check.badStore: 0 of 3 clauses satisfied
+FAIL get missing: got error "not found" of category "Unknown", want category "NotFound"
+FAIL get present: got unexpected error "not found"
+FAIL get empty: panicked: empty key`
+	if got := r.String(); got != want {
+		t.Errorf("got report:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,220 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"math"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// corpusHeader is the first line of each file in a go test fuzz corpus.
+const corpusHeader = "go test fuzz v1"
+
+// Corpus returns a Row for each entry of the go test fuzz corpus in dir, such
+// as testdata/fuzz/FuzzParse, turning the inputs found by fuzzing into
+// permanent regression rows.  The values of each entry are passed to decode,
+// which returns the Row to test them with, typically with a Test that calls
+// the fuzzed function with values and a Want for its error.  Rows returned
+// without a Name are named after the entry's file.
+//
+// Values have the types used by the fuzz corpus: []byte, string, bool, byte,
+// rune, and the sized and unsized int, uint, and float types.
+func Corpus(dir string, decode func(values ...interface{}) Row) ([]Row, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var rows []Row
+	for _, fi := range files {
+		if fi.IsDir() {
+			continue
+		}
+		path := filepath.Join(dir, fi.Name())
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		values, err := parseCorpus(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		row := decode(values...)
+		if row.Name == "" {
+			row.Name = fi.Name()
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// parseCorpus returns the values in the fuzz corpus entry data.
+func parseCorpus(data string) ([]interface{}, error) {
+	lines := strings.Split(data, "\n")
+	if strings.TrimSpace(lines[0]) != corpusHeader {
+		return nil, fmt.Errorf("missing %q header", corpusHeader)
+	}
+	var values []interface{}
+	for i, line := range lines[1:] {
+		if line = strings.TrimSpace(line); line == "" {
+			continue
+		}
+		v, err := parseCorpusValue(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", i+2, err)
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+// intBits is the size, in bits, of each integer type in a fuzz corpus.
+var intBits = map[string]int{
+	"int": strconv.IntSize, "int8": 8, "int16": 16, "int32": 32, "int64": 64,
+	"uint": strconv.IntSize, "uint8": 8, "uint16": 16, "uint32": 32, "uint64": 64,
+}
+
+// parseCorpusValue returns the value of a single line of a fuzz corpus
+// entry, such as string("x"), int(-3), float64(+Inf), or
+// math.Float64frombits(0x7ff8000000000001), as written by go test.
+func parseCorpusValue(line string) (interface{}, error) {
+	expr, err := parser.ParseExpr(line)
+	if err != nil {
+		return nil, err
+	}
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 {
+		return nil, fmt.Errorf("malformed value %q", line)
+	}
+	typ := strings.Replace(line[:call.Lparen-1], " ", "", -1)
+	arg := call.Args[0]
+
+	// NaNs other than math.NaN() are written as calls of
+	// math.Float64frombits or math.Float32frombits rather than as
+	// conversions.
+	switch typ {
+	case "math.Float64frombits", "math.Float32frombits":
+		lit, ok := arg.(*ast.BasicLit)
+		if !ok || lit.Kind != token.INT {
+			return nil, fmt.Errorf("malformed value %q", line)
+		}
+		if typ == "math.Float32frombits" {
+			bits, err := strconv.ParseUint(lit.Value, 0, 32)
+			if err != nil {
+				return nil, err
+			}
+			return math.Float32frombits(uint32(bits)), nil
+		}
+		bits, err := strconv.ParseUint(lit.Value, 0, 64)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(bits), nil
+	}
+
+	// Negative numbers and infinities, such as -1 and +Inf, are signed.
+	sign := ""
+	if u, ok := arg.(*ast.UnaryExpr); ok && (u.Op == token.SUB || u.Op == token.ADD) {
+		sign, arg = u.Op.String(), u.X
+	}
+	var lit string
+	switch a := arg.(type) {
+	case *ast.BasicLit:
+		lit = a.Value
+	case *ast.Ident:
+		lit = a.Name
+	default:
+		return nil, fmt.Errorf("malformed value %q", line)
+	}
+	lit = sign + lit
+
+	switch typ {
+	case "[]byte", "string":
+		s, err := strconv.Unquote(lit)
+		if err != nil {
+			return nil, err
+		}
+		if typ == "string" {
+			return s, nil
+		}
+		return []byte(s), nil
+	case "bool":
+		return strconv.ParseBool(lit)
+	case "byte", "rune":
+		if strings.HasPrefix(lit, "'") {
+			r, _, _, err := strconv.UnquoteChar(lit[1:len(lit)-1], '\'')
+			if err != nil {
+				return nil, err
+			}
+			if typ == "byte" {
+				return byte(r), nil
+			}
+			return r, nil
+		}
+		if typ == "byte" {
+			n, err := strconv.ParseUint(lit, 0, 8)
+			return byte(n), err
+		}
+		n, err := strconv.ParseInt(lit, 0, 32)
+		return rune(n), err
+	case "int", "int8", "int16", "int32", "int64":
+		n, err := strconv.ParseInt(lit, 0, intBits[typ])
+		if err != nil {
+			return nil, err
+		}
+		switch typ {
+		case "int8":
+			return int8(n), nil
+		case "int16":
+			return int16(n), nil
+		case "int32":
+			return int32(n), nil
+		case "int64":
+			return n, nil
+		}
+		return int(n), nil
+	case "uint", "uint8", "uint16", "uint32", "uint64":
+		n, err := strconv.ParseUint(lit, 0, intBits[typ])
+		if err != nil {
+			return nil, err
+		}
+		switch typ {
+		case "uint8":
+			return uint8(n), nil
+		case "uint16":
+			return uint16(n), nil
+		case "uint32":
+			return uint32(n), nil
+		case "uint64":
+			return n, nil
+		}
+		return uint(n), nil
+	case "float32":
+		f, err := strconv.ParseFloat(lit, 32)
+		if err != nil {
+			return nil, err
+		}
+		return float32(f), nil
+	case "float64":
+		return strconv.ParseFloat(lit, 64)
+	}
+	return nil, fmt.Errorf("unsupported type %q", typ)
+}
@@ -0,0 +1,143 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"errors"
+	"io/ioutil"
+	"math"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseCorpusValue(t *testing.T) {
+	for _, tt := range []struct {
+		in   string
+		want interface{}
+		err  interface{}
+	}{
+		{in: `[]byte("a\x00b")`, want: []byte("a\x00b")},
+		{in: `string("hello")`, want: "hello"},
+		{in: "string(`raw`)", want: "raw"},
+		{in: `bool(true)`, want: true},
+		{in: `byte('\x01')`, want: byte(1)},
+		{in: `byte(7)`, want: byte(7)},
+		{in: `rune('世')`, want: '世'},
+		{in: `int(-3)`, want: -3},
+		{in: `int8(-128)`, want: int8(-128)},
+		{in: `int16(300)`, want: int16(300)},
+		{in: `int32(5)`, want: int32(5)},
+		{in: `int64(0x10)`, want: int64(16)},
+		{in: `uint(3)`, want: uint(3)},
+		{in: `uint8(255)`, want: uint8(255)},
+		{in: `uint16(3)`, want: uint16(3)},
+		{in: `uint32(3)`, want: uint32(3)},
+		{in: `uint64(3)`, want: uint64(3)},
+		{in: `float32(1.5)`, want: float32(1.5)},
+		{in: `float64(-2.25)`, want: -2.25},
+		{in: `float64(+Inf)`, want: math.Inf(1)},
+		{in: `float64(-Inf)`, want: math.Inf(-1)},
+		{in: `float32(+Inf)`, want: float32(math.Inf(1))},
+		{in: `float64(-0)`, want: math.Copysign(0, -1)},
+		{in: `float32(0.1)`, want: float32(0.1)},
+		{in: `int32(-1)`, want: int32(-1)},
+		{in: `math.Float64frombits(0x3ff0000000000000)`, want: 1.0},
+		{in: `math.Float64frombits(1.5)`, err: `malformed value`},
+		{in: `math.Float32frombits(0x100000000)`, err: true},
+		{in: `float64(*Inf)`, err: `malformed value`},
+		{in: `complex64(1)`, err: `unsupported type "complex64"`},
+		{in: `string(x, y)`, err: `malformed value`},
+		{in: `string(`, err: true},
+		{in: `int8(300)`, err: true},
+	} {
+		got, err := parseCorpusValue(tt.in)
+		if s := Error(err, tt.err); s != "" {
+			t.Errorf("%s: %s", tt.in, s)
+			continue
+		}
+		if err == nil && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %#v, want %#v", tt.in, got, tt.want)
+		}
+	}
+	for _, in := range []string{`float64(NaN)`, `math.Float64frombits(0x7ff8000000000001)`} {
+		v, err := parseCorpusValue(in)
+		if f, ok := v.(float64); err != nil || !ok || !math.IsNaN(f) {
+			t.Errorf("%s: got %v, %v", in, v, err)
+		}
+	}
+	v, err := parseCorpusValue(`math.Float64frombits(0x7ff8000000000001)`)
+	if f, _ := v.(float64); err != nil || math.Float64bits(f) != 0x7ff8000000000001 {
+		t.Errorf("NaN bits: got %#x, %v", math.Float64bits(f), err)
+	}
+	v, err = parseCorpusValue(`math.Float32frombits(0x7fc00001)`)
+	if f, _ := v.(float32); err != nil || math.Float32bits(f) != 0x7fc00001 {
+		t.Errorf("float32 NaN bits: got %#x, %v", math.Float32bits(f), err)
+	}
+}
+
+func TestCorpus(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, data string) {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("a1", "go test fuzz v1\nstring(\"\")\nint(1)\n")
+	write("b2", "go test fuzz v1\nstring(\"ok\")\nint(2)\n")
+	write("b3", "go test fuzz v1\nstring(\"inf\")\nint(3)\nfloat64(+Inf)\nmath.Float64frombits(0x7ff8000000000001)\n")
+
+	parse := func(s string, n int) error {
+		if s == "" {
+			return errors.New("empty input")
+		}
+		return nil
+	}
+	var floats []interface{}
+	rows, err := Corpus(dir, func(values ...interface{}) Row {
+		s, n := values[0].(string), values[1].(int)
+		floats = append(floats, values[2:]...)
+		row := Row{Test: func(*T) error { return parse(s, n) }}
+		if s == "" {
+			row.Want = "empty"
+		}
+		return row
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 3 || rows[0].Name != "a1" || rows[1].Name != "b2" || rows[2].Name != "b3" {
+		t.Fatalf("got rows %+v", rows)
+	}
+	if len(floats) != 2 || !math.IsInf(floats[0].(float64), 1) || !math.IsNaN(floats[1].(float64)) {
+		t.Errorf("got float values %v", floats)
+	}
+	Run(t, rows...)
+
+	write("c3", "string(\"no header\")\n")
+	_, err = Corpus(dir, func(...interface{}) Row { return Row{} })
+	if s := Error(err, `c3: missing "go test fuzz v1" header`); s != "" {
+		t.Error(s)
+	}
+	write("c3", "go test fuzz v1\nstring(\"ok\")\nchan(1)\n")
+	_, err = Corpus(dir, func(...interface{}) Row { return Row{} })
+	if s := Error(err, `c3: line 3:`); s != "" {
+		t.Error(s)
+	}
+	_, err = Corpus(filepath.Join(dir, "missing"), nil)
+	if s := Error(err, true); s != "" {
+		t.Error(s)
+	}
+}
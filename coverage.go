@@ -0,0 +1,78 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import "errors"
+
+const uncovered = "no want expects sentinel %q"
+
+// Covers returns the empty string if each of sentinels, such as the errors a
+// package exports, is expected by at least one of wants, typically the wants
+// of a table test, otherwise it returns a string naming each sentinel that is
+// not expected, one per line.  An error want expects a sentinel if it is the
+// sentinel, as determined by errors.Is; any other want expects a sentinel if
+// the sentinel matches it, as by Matches.  Covers enforces that each error
+// path of a package is tested:
+//
+//	var wants []interface{}
+//	for _, tt := range tests {
+//		wants = append(wants, tt.err)
+//	}
+//	if s := check.Covers(wants, store.ErrNotFound, store.ErrConflict); s != "" {
+//		t.Error(s)
+//	}
+func Covers(wants []interface{}, sentinels ...error) string {
+	return defaults().covers(wants, sentinels)
+}
+
+// Covers is the same as the Covers function but uses the options of ck.
+func (ck *Checker) Covers(wants []interface{}, sentinels ...error) Result {
+	ck.count()
+	return Result(ck.c.covers(wants, sentinels))
+}
+
+func (c *config) covers(wants []interface{}, sentinels []error) string {
+	if c.outer() {
+		return c.run("Covers", nil, nil,
+			func(c *config) string { return c.covers(wants, sentinels) })
+	}
+	var failures []string
+	for _, sentinel := range sentinels {
+		if !expects(wants, sentinel) {
+			failures = append(failures, c.failf(uncovered, sentinel))
+		}
+	}
+	return Join(failures...)
+}
+
+// expects reports whether any of wants expects sentinel, as described by
+// Covers.
+func expects(wants []interface{}, sentinel error) bool {
+	for _, want := range wants {
+		if want == nil {
+			continue
+		}
+		if err, ok := want.(error); ok {
+			if errors.Is(err, sentinel) {
+				return true
+			}
+			continue
+		}
+		if Matches(sentinel, want) {
+			return true
+		}
+	}
+	return false
+}
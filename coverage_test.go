@@ -0,0 +1,48 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestCovers(t *testing.T) {
+	setDefaults(t)
+	errA := errors.New("no such key")
+	errB := errors.New("version conflict")
+	errC := errors.New("quota exceeded")
+	for _, tt := range []struct {
+		name  string
+		wants []interface{}
+		out   string
+	}{
+		{"all", []interface{}{nil, errA, errB, errC}, ""},
+		{"wrapped", []interface{}{fmt.Errorf("get: %w", errA), errB, errC}, ""},
+		{"matchers", []interface{}{"no such", Equal("version conflict"), Regexp("^quota")}, ""},
+		{"one", []interface{}{errA, nil, "conflict"}, sprintf(uncovered, errC)},
+		{"none", nil, sprintf(uncovered, errA) + "\n" + sprintf(uncovered, errB) + "\n" + sprintf(uncovered, errC)},
+		{"not is", []interface{}{errors.New("no such key"), errB, errC}, sprintf(uncovered, errA)},
+	} {
+		if s := Covers(tt.wants, errA, errB, errC); s != tt.out {
+			t.Errorf("%s: got %q, want %q", tt.name, s, tt.out)
+		}
+	}
+	setDefaults(t, Codes())
+	if s := Covers(nil, errA); s != "CHK-MISSING: "+sprintf(uncovered, errA) {
+		t.Errorf("got %q", s)
+	}
+}
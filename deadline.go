@@ -0,0 +1,59 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import "time"
+
+const timedOut = "error rendering timed out"
+
+// Deadline returns an Option that bounds how long a check may take to d.  A
+// check that takes longer, such as because the Error or Unwrap method of the
+// error being checked performs I/O or never returns, fails with "error
+// rendering timed out".  The check continues to run in the background.  A d of
+// 0, the default, does not bound the time.  Within a testing/synctest bubble d
+// is measured in the bubble's virtual time.
+func Deadline(d time.Duration) Option {
+	return func(c *config) { c.deadline = d }
+}
+
+// bounded returns the result of calling check with c, or a timedOut failure
+// if check does not return within d.  A panic in check is propagated to the
+// caller.
+func (c *config) bounded(d time.Duration, check func(*config) string) string {
+	type result struct {
+		s string
+		p interface{}
+	}
+	ch := make(chan result, 1)
+	go func() {
+		var r result
+		defer func() {
+			r.p = recover()
+			ch <- r
+		}()
+		r.s = check(c)
+	}()
+	timeout, stop := after(d)
+	defer stop()
+	select {
+	case r := <-ch:
+		if r.p != nil {
+			panic(r.p)
+		}
+		return r.s
+	case <-timeout:
+		return c.failf(timedOut)
+	}
+}
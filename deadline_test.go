@@ -0,0 +1,88 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+// blockedErr is an error whose methods do not return until it is closed.
+type blockedErr chan struct{}
+
+func (e blockedErr) Error() string { <-e; return "blocked" }
+func (e blockedErr) Unwrap() error { <-e; return io.EOF }
+
+func TestDeadline(t *testing.T) {
+	blocked := make(blockedErr)
+	defer close(blocked)
+
+	setDefaults(t, Deadline(10*time.Millisecond))
+	for _, tt := range []struct {
+		name  string
+		check func() string
+		out   string
+	}{
+		{
+			name:  "fast",
+			check: func() string { return Error(io.EOF, "EOF") },
+		}, {
+			name:  "fast failure",
+			check: func() string { return Error(io.EOF, "other") },
+			out:   sprintf(wrong, "EOF", "other"),
+		}, {
+			name:  "Error",
+			check: func() string { return Error(blocked, "blocked") },
+			out:   timedOut,
+		}, {
+			name:  "Is",
+			check: func() string { return Is(blocked, io.EOF) },
+			out:   timedOut,
+		}, {
+			name:  "NoError",
+			check: func() string { return NoError(blocked) },
+			out:   timedOut,
+		}, {
+			name:  "MessagesEqual",
+			check: func() string { return MessagesEqual(blocked, errors.New("blocked")) },
+			out:   timedOut,
+		}, {
+			name:  "HasError",
+			check: func() string { return HasError(blocked, "blocked") },
+			out:   timedOut,
+		}, {
+			name:  "codes",
+			check: func() string { return Is(blocked, io.EOF, Codes()) },
+			out:   "CHK-TIMEOUT: " + timedOut,
+		}, {
+			name:  "unbounded",
+			check: func() string { return Is(io.ErrUnexpectedEOF, io.EOF, Deadline(0)) },
+			out:   sprintf(wrong, "unexpected EOF", "EOF"),
+		},
+	} {
+		if s := tt.check(); s != tt.out {
+			t.Errorf("%s: got %q, want %q", tt.name, s, tt.out)
+		}
+	}
+}
+
+func TestDeadlinePanic(t *testing.T) {
+	setDefaults(t, Deadline(time.Second), Strict())
+	if msg := panicked(func() { Error(io.EOF, 1) }); msg != "check: unsupported type int" {
+		t.Errorf("got panic %q", msg)
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"archive/zip"
+	"compress/flate"
+	"compress/gzip"
+	"errors"
+)
+
+// A sentinel is a Matcher of errors that are err, as determined by errors.Is.
+type sentinel struct {
+	err  error
+	name string // e.g., "zip.ErrFormat"
+}
+
+func (m sentinel) match(c *config, got error) string {
+	switch {
+	case got == nil:
+		return c.failf(expected, m.err)
+	case !errors.Is(got, m.err):
+		return c.failf(wrong, got, m.err)
+	}
+	return ""
+}
+
+// ZipFormat returns a Matcher that matches an error that is zip.ErrFormat,
+// as determined by errors.Is.
+func ZipFormat() Matcher { return sentinel{zip.ErrFormat, "zip.ErrFormat"} }
+
+// ZipChecksum returns a Matcher that matches an error that is
+// zip.ErrChecksum, as determined by errors.Is.
+func ZipChecksum() Matcher { return sentinel{zip.ErrChecksum, "zip.ErrChecksum"} }
+
+// GzipHeader returns a Matcher that matches an error that is gzip.ErrHeader,
+// as determined by errors.Is.
+func GzipHeader() Matcher { return sentinel{gzip.ErrHeader, "gzip.ErrHeader"} }
+
+// GzipChecksum returns a Matcher that matches an error that is
+// gzip.ErrChecksum, as determined by errors.Is.
+func GzipChecksum() Matcher { return sentinel{gzip.ErrChecksum, "gzip.ErrChecksum"} }
+
+type flateCorrupt struct {
+	min, max int64
+}
+
+// FlateCorrupt returns a Matcher that matches an error wrapping a
+// flate.CorruptInputError whose offset is between min and max, inclusive.
+// The offset at which corruption is detected may shift as the decoder
+// changes, so fuzz regression tests may assert a range rather than the exact
+// offset.
+func FlateCorrupt(min, max int64) Matcher {
+	return flateCorrupt{min: min, max: max}
+}
+
+const flateCorruptType = "flate.CorruptInputError"
+
+func (m flateCorrupt) match(c *config, got error) string {
+	if got == nil {
+		return c.failf(expectedType, flateCorruptType)
+	}
+	var ce flate.CorruptInputError
+	if !errors.As(got, &ce) {
+		return c.failf(wrongType, got, flateCorruptType)
+	}
+	if int64(ce) < m.min || int64(ce) > m.max {
+		return c.failc(CodeWrong, sprintf("got error %%q, want corruption at an offset from %d to %d", m.min, m.max), got)
+	}
+	return ""
+}
@@ -0,0 +1,71 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"testing"
+)
+
+func TestDecodeErrors(t *testing.T) {
+	setDefaults(t)
+	_, zipErr := zip.NewReader(bytes.NewReader([]byte("not a zip file")), 14)
+	_, gzipErr := gzip.NewReader(bytes.NewReader([]byte("not a gzip file")))
+	_, flateErr := ioutil.ReadAll(flate.NewReader(bytes.NewReader([]byte{0xff, 0xff, 0xff})))
+	var offset int64
+	if ce, ok := flateErr.(flate.CorruptInputError); ok {
+		offset = int64(ce)
+	} else {
+		t.Fatalf("flate returned %T", flateErr)
+	}
+
+	for _, tt := range []struct {
+		name string
+		got  error
+		want Matcher
+		out  string
+	}{
+		{"zip", zipErr, ZipFormat(), ""},
+		{"zip wrapped", fmt.Errorf("open: %w", zipErr), ZipFormat(), ""},
+		{"zip checksum", zipErr, ZipChecksum(), sprintf(wrong, zipErr, zip.ErrChecksum)},
+		{"gzip", gzipErr, GzipHeader(), ""},
+		{"gzip nil", nil, GzipHeader(), sprintf(expected, gzip.ErrHeader)},
+		{"gzip checksum", gzip.ErrChecksum, GzipChecksum(), ""},
+		{"flate", flateErr, FlateCorrupt(offset, offset), ""},
+		{"flate range", fmt.Errorf("read: %w", flateErr), FlateCorrupt(0, 100), ""},
+		{"flate offset", flateErr, FlateCorrupt(offset+1, offset+5), sprintf("got error %q, want corruption at an offset from %d to %d", flateErr, offset+1, offset+5)},
+		{"flate type", io.EOF, FlateCorrupt(0, 1), sprintf(wrongType, "EOF", flateCorruptType)},
+		{"flate nil", nil, FlateCorrupt(0, 1), sprintf(expectedType, flateCorruptType)},
+	} {
+		if s := Error(tt.got, tt.want); s != tt.out {
+			t.Errorf("%s: got %q, want %q", tt.name, s, tt.out)
+		}
+	}
+	if d := Describe(ZipFormat()); d != "is zip.ErrFormat" {
+		t.Errorf("got description %q", d)
+	}
+	if d := Describe(FlateCorrupt(1, 2)); d != "a flate.CorruptInputError at an offset from 1 to 2" {
+		t.Errorf("got description %q", d)
+	}
+	if err := Validate(FlateCorrupt(2, 1), FlateCorrupt(-1, 1)); fmt.Sprint(err) != "want 0: FlateCorrupt range 2 to 1 is invalid\nwant 1: FlateCorrupt range -1 to 1 is invalid" {
+		t.Errorf("got validation error %v", err)
+	}
+}
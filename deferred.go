@@ -0,0 +1,68 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+// Deferred returns the empty string if f returns an error matching want, as
+// determined by Error, otherwise it returns a string indicating the failure.
+// A panic by f, including one raised by its deferred functions, is reported
+// as a failure rather than crashing the test.
+//
+// Deferred is meant for functions that change their error in a deferred
+// function, such as one joining the error of Close, through a named result:
+//
+//	func save(path string, data []byte) (err error) {
+//		f, err := os.Create(path)
+//		if err != nil {
+//			return err
+//		}
+//		defer func() {
+//			if cerr := f.Close(); err == nil {
+//				err = cerr
+//			}
+//		}()
+//		_, err = f.Write(data)
+//		return err
+//	}
+//
+// The error checked is the value f finally returns, after its deferred
+// functions have run.  A deferred function only changes that value when it
+// assigns to the named result, not to a local variable shadowing it or when
+// the result is unnamed, which is the mistake Deferred exists to catch:
+//
+//	if s := check.Deferred(func() error { return save(path, data) }, "file already closed"); s != "" {
+//		t.Error(s)
+//	}
+func Deferred(f func() (err error), want interface{}) string {
+	return defaults().deferred(f, want)
+}
+
+// Deferred is the same as the Deferred function but uses the options of ck.
+func (ck *Checker) Deferred(f func() (err error), want interface{}) Result {
+	ck.count()
+	return Result(ck.c.deferred(f, want))
+}
+
+func (c *config) deferred(f func() error, want interface{}) string {
+	if c.outer() {
+		return c.run("Deferred", nil, func() string { return Describe(want) },
+			func(c *config) string { return c.deferred(f, want) })
+	}
+	var err error
+	p, panicked := recovered(func() { err = f() })
+	if panicked {
+		return c.failf(unexpectedPanic, p)
+	}
+	return c.checkError(err, want)
+}
@@ -0,0 +1,68 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"errors"
+	"testing"
+)
+
+var errClose = errors.New("close failed")
+
+// closeNamed returns the error of close when body succeeds, through its named
+// result.
+func closeNamed(body error) (err error) {
+	defer func() {
+		if err == nil {
+			err = errClose
+		}
+	}()
+	return body
+}
+
+// closeShadowed tries to do the same as closeNamed but assigns the error of
+// close to a local variable.
+func closeShadowed(body error) error {
+	err := body
+	defer func() {
+		if err == nil {
+			err = errClose // lost: the result is not named
+		}
+	}()
+	return err
+}
+
+func TestDeferred(t *testing.T) {
+	setDefaults(t)
+	errWrite := errors.New("write failed")
+	for _, tt := range []struct {
+		name string
+		f    func() error
+		want interface{}
+		out  string
+	}{
+		{"named", func() error { return closeNamed(nil) }, errClose, ""},
+		{"named body", func() error { return closeNamed(errWrite) }, errWrite, ""},
+		{"shadowed", func() error { return closeShadowed(nil) }, errClose, sprintf(expected, errClose)},
+		{"panic", func() error {
+			defer func() { panic("close twice") }()
+			return nil
+		}, nil, sprintf(unexpectedPanic, "close twice")},
+	} {
+		if s := Deferred(tt.f, tt.want); s != tt.out {
+			t.Errorf("%s: got %q, want %q", tt.name, s, tt.out)
+		}
+	}
+}
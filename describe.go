@@ -0,0 +1,336 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// A Describer describes the errors it matches.  A CustomMatcher, or an error
+// used as a want, that is a Describer is described by Describe, and rendered
+// in failures, by its description rather than by its message:
+//
+//	got error "EOF", want any timeout error
+type Describer interface {
+	Describe() string
+}
+
+// Describe returns a human readable description of the errors matched by
+// want, as interpreted by Error, e.g.:
+//
+//	Describe("open")            contains "open"
+//	Describe(Equal("EOF"))      is "EOF"
+//	Describe(&perr)             error of type *fs.PathError
+//	Describe(SingleLine())      a single line message
+//
+// Describe is used to keep failures of composed wants readable rather than
+// printing the wants in Go syntax.
+func Describe(want interface{}) string {
+	switch w := want.(type) {
+	case Matcher:
+		return w.Describe()
+	case CustomMatcher:
+		if d, ok := w.(Describer); ok {
+			return d.Describe()
+		}
+		return sprintf("matches %T", w)
+	case func(error) bool:
+		return "satisfies a func(error) bool"
+	case func(error) string:
+		return "satisfies a func(error) string"
+	case nil:
+		return "no error"
+	case bool:
+		if w {
+			return "any error"
+		}
+		return "no error"
+	case string:
+		return sprintf("contains %q", w)
+	case Case:
+		return sprintf("contains %q, case insensitive", string(w))
+	case Equal:
+		return sprintf("is %q", string(w))
+	case CaseEqual:
+		return sprintf("is %q, case insensitive", string(w))
+	case Regexp:
+		return sprintf("matches pattern %q", string(w))
+	case Describer:
+		return w.Describe()
+	case error:
+		return sprintf("is the error %q (%T)", message(w), w)
+	}
+	if t := asTarget(want); t != nil {
+		return sprintf("error of type %v", t)
+	}
+	return sprintf("unsupported want of type %T", want)
+}
+
+// A description is the description of a want rendered, unquoted, in a
+// failure in place of the want.
+type description string
+
+// wantArg returns want as rendered in a failure: its description if it is a
+// Describer, otherwise want itself.
+func wantArg(want interface{}) interface{} {
+	if d, ok := want.(Describer); ok {
+		return description(d.Describe())
+	}
+	return want
+}
+
+// describeAll returns the descriptions of wants joined by " AND ".
+func describeAll(wants []interface{}) string {
+	descs := make([]string, len(wants))
+	for i, want := range wants {
+		descs[i] = Describe(want)
+	}
+	return strings.Join(descs, " AND ")
+}
+
+// describeMessage returns a description of an error whose message is that of
+// want.
+func describeMessage(want error) string {
+	if want == nil {
+		return Describe(nil)
+	}
+	return Describe(Equal(message(want)))
+}
+
+func (m maxLen) Describe() string {
+	return sprintf("a message of at most %d runes", int(m))
+}
+
+func (singleLine) Describe() string { return "a single line message" }
+
+func (nonEmpty) Describe() string { return "a non-empty message" }
+
+func (s similar) Describe() string {
+	return sprintf("similar to %q (threshold %.2f)", s.want, s.threshold)
+}
+
+func (w words) Describe() string {
+	return sprintf("the words of %q, within %d", w.want, w.tolerance)
+}
+
+func (s scrub) Describe() string {
+	return Describe(s.want) + ", scrubbed"
+}
+
+func (m errnoError) Describe() string {
+	return sprintf("errno %q", syscall.Errno(m).Error())
+}
+
+func (m status) Describe() string {
+	if m.msg == nil {
+		return "a gRPC status with code " + grpcCodeName(m.code)
+	}
+	return sprintf("a gRPC status with code %s and a message that %s", grpcCodeName(m.code), Describe(m.msg))
+}
+
+func (m selector) Describe() string {
+	want, err := m.resolve()
+	if err != nil {
+		return err.Error()
+	}
+	return Describe(want)
+}
+
+func (m byOS) Describe() string {
+	want, err := m.resolve()
+	if err != nil {
+		return err.Error()
+	}
+	return Describe(want)
+}
+
+func (m byGoVersion) Describe() string {
+	want, err := m.resolve()
+	if err != nil {
+		return err.Error()
+	}
+	return Describe(want)
+}
+
+func (b bind) Describe() string {
+	return Describe(b.bound())
+}
+
+func (m all) Describe() string {
+	return describeAll(m)
+}
+
+func (m anyOf) Describe() string {
+	descs := make([]string, len(m))
+	for i, want := range m {
+		descs[i] = Describe(want)
+	}
+	return strings.Join(descs, " OR ")
+}
+
+func (m not) Describe() string {
+	return "not " + Describe(m.want)
+}
+
+func (f firstLine) Describe() string {
+	return Describe(f.want) + ", first line only"
+}
+
+func (o own) Describe() string {
+	return Describe(o.want) + ", own message only"
+}
+
+func (d durations) Describe() string {
+	return sprintf("%s, with durations within %v", Describe(d.want), d.tolerance)
+}
+
+func (m wantsEqual) Describe() string {
+	if msg, ok := m.w.lookup(m.key); ok {
+		return Describe(Equal(msg))
+	}
+	return Describe(nil)
+}
+
+func (m golden) Describe() string {
+	return sprintf("is the contents of golden file %q", m.path())
+}
+
+func (m logfmt) Describe() string {
+	pairs := make([]string, len(m.keys))
+	for i, k := range m.keys {
+		pairs[i] = k + "=" + strconv.Quote(m.values[k])
+	}
+	return "a message with " + strings.Join(pairs, " ")
+}
+
+func (m syscallError) Describe() string {
+	d := "an *os.SyscallError"
+	if m.name != "" {
+		d += sprintf(" from %q", m.name)
+	}
+	if m.errno != 0 {
+		d += sprintf(" with errno %q", m.errno.Error())
+	}
+	return d
+}
+
+func (m sentinel) Describe() string { return "is " + m.name }
+
+func (m flateCorrupt) Describe() string {
+	return sprintf("a %s at an offset from %d to %d", flateCorruptType, m.min, m.max)
+}
+
+func (m csvParse) Describe() string {
+	d := "a " + csvParseType
+	if m.line != 0 {
+		d += sprintf(" at line %d", m.line)
+	}
+	if m.column != 0 {
+		d += sprintf(" column %d", m.column)
+	}
+	if m.err != nil {
+		d += sprintf(" of %q", message(m.err))
+	}
+	return d
+}
+
+func (m xmlSyntax) Describe() string {
+	if m == 0 {
+		return "an " + xmlSyntaxType
+	}
+	return sprintf("an %s at line %d", xmlSyntaxType, int(m))
+}
+
+func (m templateExec) Describe() string {
+	d := "a " + templateExecType
+	if m.name != "" {
+		d += sprintf(" from template %q", m.name)
+	}
+	if m.mention != "" {
+		d += sprintf(" mentioning %q", m.mention)
+	}
+	return d
+}
+
+func (m flagError) Describe() string {
+	if m.name == "" {
+		return "a " + m.problem.String()
+	}
+	return sprintf("a %s for flag %q", m.problem, m.name)
+}
+
+func (m saved) Describe() string {
+	return sprintf("is the error saved as %q", m.key)
+}
+
+func (m jsonMessage) Describe() string {
+	var b bytes.Buffer
+	if err := json.Compact(&b, []byte(m.want)); err != nil {
+		return sprintf("JSON containing %s", m.want)
+	}
+	return "JSON containing " + b.String()
+}
+
+func (m ExitStatus) Describe() string {
+	return sprintf("exit status %d", int(m))
+}
+
+func (m errCode) Describe() string {
+	return sprintf("has code %q", fmt.Sprint(m.code))
+}
+
+func (m method) Describe() string {
+	return sprintf("has %s() returning %q", m.name, fmt.Sprint(m.want))
+}
+
+func (m implements) Describe() string {
+	return sprintf("implements %s", m.t)
+}
+
+func (m hasStack) Describe() string {
+	if m {
+		return "has a stack trace"
+	}
+	return "has no stack trace"
+}
+
+func (m fields) Describe() string {
+	wv := structOf(m.want)
+	if !wv.IsValid() {
+		return sprintf("a %T", m.want)
+	}
+	var fs []string
+	for i := 0; i < wv.NumField(); i++ {
+		f := wv.Type().Field(i)
+		if w := wv.Field(i); f.PkgPath == "" && !w.IsZero() {
+			fs = append(fs, f.Name+": "+formatValue(w))
+		}
+	}
+	if fs == nil {
+		return sprintf("a %T", m.want)
+	}
+	return sprintf("a %T with %s", m.want, strings.Join(fs, ", "))
+}
+
+func (m presence) Describe() string { return Describe(bool(m)) }
+
+func (m ContainsAll) Describe() string { return "contains all of " + quoteAll(m) }
+
+func (m ContainsAny) Describe() string { return "contains any of " + quoteAll(m) }
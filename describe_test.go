@@ -0,0 +1,88 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"io"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestDescribe(t *testing.T) {
+	var perr *os.PathError
+	for _, tt := range []struct {
+		want interface{}
+		out  string
+	}{
+		{nil, "no error"},
+		{true, "any error"},
+		{false, "no error"},
+		{"open", `contains "open"`},
+		{Case("open"), `contains "open", case insensitive`},
+		{Equal("EOF"), `is "EOF"`},
+		{CaseEqual("EOF"), `is "EOF", case insensitive`},
+		{Regexp("^EOF$"), `matches pattern "^EOF$"`},
+		{io.EOF, `is the error "EOF" (*errors.errorString)`},
+		{&perr, "error of type *fs.PathError"},
+		{1, "unsupported want of type int"},
+		{MaxLen(10), "a message of at most 10 runes"},
+		{SingleLine(), "a single line message"},
+		{NonEmptyMessage(), "a non-empty message"},
+		{Similar("not found", 0.8), `similar to "not found" (threshold 0.80)`},
+		{Words("file not found", 1), `the words of "file not found", within 1`},
+		{Scrub("dial <ip>", ScrubNetwork), `contains "dial <ip>", scrubbed`},
+		{Durations(Equal("after 1s"), time.Second), `is "after 1s", with durations within 1s`},
+	} {
+		if got := Describe(tt.want); got != tt.out {
+			t.Errorf("Describe(%#v): got %q, want %q", tt.want, got, tt.out)
+		}
+	}
+}
+
+// anyTimeout is an error, used as a want, that is any timeout error.
+type anyTimeout struct{}
+
+func (anyTimeout) Error() string    { return "timeout" }
+func (anyTimeout) Describe() string { return "any timeout error" }
+
+func (anyTimeout) Is(err error) bool { return err == os.ErrDeadlineExceeded }
+
+func TestDescriber(t *testing.T) {
+	setDefaults(t)
+	want := anyTimeout{}
+	if d := Describe(want); d != "any timeout error" {
+		t.Errorf("Describe: got %q", d)
+	}
+	for _, tt := range []struct {
+		name string
+		s    string
+		out  string
+	}{
+		{"Error", Error(io.EOF, want), `got error "EOF", want any timeout error`},
+		{"Error nil", Error(nil, want), "did not get expected error any timeout error"},
+		{"Is", Is(io.EOF, want), `got error "EOF", want any timeout error`},
+		{"Is nil", Is(nil, want), "did not get expected error any timeout error"},
+		{"NotIsError", NotIsError(want, want), `got error "timeout", want an error that is not any timeout error`},
+		{"Any", Error(io.EOF, Any(want, "x")), "no want matched:\n\t" + `got error "EOF", want any timeout error` + "\n\t" + sprintf(wrong, io.EOF, "x")},
+		{"Not", Error(want, Not(Any(want, "x"))), `got error "timeout", want not any timeout error OR contains "x"`},
+		{"quoting", NewChecker(Quote(QuoteBack)).Error(io.EOF, want).String(), "got error `EOF`, want any timeout error"},
+		{"columns", NewChecker(Render(RenderColumns)).Error(io.EOF, want).String(), "got error | want\nEOF       | any timeout error"},
+	} {
+		if tt.s != tt.out {
+			t.Errorf("%s: got %q, want %q", tt.name, tt.s, tt.out)
+		}
+	}
+}
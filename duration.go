@@ -0,0 +1,90 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"regexp"
+	"time"
+)
+
+// durationRE matches durations as formatted by time.Duration's String method,
+// e.g., 1.003s, 250ms, and 1h2m3s.
+var durationRE = regexp.MustCompile(`\b(?:\d+(?:\.\d+)?(?:ns|us|µs|μs|ms|s|m|h))+\b`)
+
+type durations struct {
+	want      interface{}
+	tolerance time.Duration
+}
+
+// Durations returns a Matcher that checks got against want, as by Error, but
+// with each duration in the message of got, such as the 1.003s in "timed out
+// after 1.003s", that is within tolerance of a duration in want replaced by
+// that duration.  This prevents spurious failures when the timing of a loaded
+// machine leaks into a message:
+//
+//	check.Durations(check.Equal("timed out after 1s"), 50*time.Millisecond)
+//
+// matches "timed out after 1.003s" but not "timed out after 1.2s".  Durations
+// are written as by time.Duration's String method.  Only wants of type string,
+// Equal, Case, and CaseEqual contain durations, other wants are checked
+// against got unchanged.
+func Durations(want interface{}, tolerance time.Duration) Matcher {
+	return durations{want: want, tolerance: tolerance}
+}
+
+func (d durations) match(c *config, got error) string {
+	var w string
+	switch want := d.want.(type) {
+	case string:
+		w = want
+	case Equal:
+		w = string(want)
+	case Case:
+		w = string(want)
+	case CaseEqual:
+		w = string(want)
+	}
+	if got == nil || w == "" {
+		return c.checkError(got, d.want)
+	}
+	var wants []time.Duration
+	var texts []string
+	for _, s := range durationRE.FindAllString(w, -1) {
+		if v, err := time.ParseDuration(s); err == nil {
+			wants = append(wants, v)
+			texts = append(texts, s)
+		}
+	}
+	msg := durationRE.ReplaceAllStringFunc(got.Error(), func(s string) string {
+		v, err := time.ParseDuration(s)
+		if err != nil {
+			return s
+		}
+		for i, wv := range wants {
+			if diff := v - wv; diff >= -d.tolerance && diff <= d.tolerance {
+				return texts[i]
+			}
+		}
+		return s
+	})
+	// On failure, report the original message rather than the
+	// replaced one.
+	q := *c
+	q.quiet = true
+	if q.checkError(&scrubbed{msg: msg, err: got}, d.want) == "" {
+		return ""
+	}
+	return c.checkError(got, d.want)
+}
@@ -0,0 +1,93 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestDurations(t *testing.T) {
+	setDefaults(t)
+	timeout := errors.New("timed out after 1.003s")
+	for _, tt := range []struct {
+		name      string
+		got       error
+		want      interface{}
+		tolerance time.Duration
+		out       string
+	}{
+		{
+			name:      "within",
+			got:       timeout,
+			want:      Equal("timed out after 1s"),
+			tolerance: 50 * time.Millisecond,
+		}, {
+			name:      "outside",
+			got:       timeout,
+			want:      Equal("timed out after 1s"),
+			tolerance: time.Millisecond,
+			out:       sprintf(wrong, "timed out after 1.003s", "timed out after 1s"),
+		}, {
+			name:      "contains",
+			got:       timeout,
+			want:      "after 1s",
+			tolerance: 50 * time.Millisecond,
+		}, {
+			name:      "case",
+			got:       timeout,
+			want:      Case("AFTER 997ms"),
+			tolerance: 10 * time.Millisecond,
+		}, {
+			name:      "case equal",
+			got:       timeout,
+			want:      CaseEqual("TIMED OUT AFTER 1S"),
+			tolerance: 10 * time.Millisecond,
+			out:       sprintf(wrong, "timed out after 1.003s", "TIMED OUT AFTER 1S"),
+		}, {
+			name:      "compound",
+			got:       errors.New("waited 1m30.2s of 2m0s"),
+			want:      Equal("waited 1m30s of 2m0s"),
+			tolerance: time.Second,
+		}, {
+			name:      "several",
+			got:       errors.New("retry in 98ms after 2.01s"),
+			want:      Equal("retry in 100ms after 2s"),
+			tolerance: 20 * time.Millisecond,
+		}, {
+			name:      "not a duration",
+			got:       errors.New("wrote 5mb"),
+			want:      Equal("wrote 6mb"),
+			tolerance: time.Hour,
+			out:       sprintf(wrong, "wrote 5mb", "wrote 6mb"),
+		}, {
+			name: "nil",
+			want: "after 1s",
+			out:  sprintf(expected, "after 1s"),
+		}, {
+			name:      "other want",
+			got:       fmt.Errorf("after 1.1s: %w", context.DeadlineExceeded),
+			want:      &timeout,
+			tolerance: time.Second,
+		},
+	} {
+		if s := Error(tt.got, Durations(tt.want, tt.tolerance)); s != tt.out {
+			t.Errorf("%s: got %q, want %q", tt.name, s, tt.out)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"fmt"
+	"reflect"
+)
+
+type errCode struct{ code interface{} }
+
+const (
+	noErrCode    = "no error in the chain of %q has a Code or ErrorCode method"
+	wrongErrCode = "got error %q with code %q, want code %q"
+)
+
+// ErrCode returns a Matcher that matches an error whose chain includes an
+// error with a Code or ErrorCode method, such as Code() int or
+// ErrorCode() string, returning code.  The first such error in the chain, as
+// found by errors.As, is compared.  Integer codes of different types, such as
+// an untyped constant and a named type, are compared by their values, as are
+// string codes:
+//
+//	check.Error(err, check.ErrCode(404))
+//	check.Error(err, check.ErrCode("E_QUOTA"))
+func ErrCode(code interface{}) Matcher {
+	return errCode{code: code}
+}
+
+// errCodeOf returns the result of the Code or ErrorCode method of err, if it
+// has one.
+func errCodeOf(err error) (interface{}, bool) {
+	if code, ok := call(err, "Code"); ok {
+		return code, true
+	}
+	return call(err, "ErrorCode")
+}
+
+// sameCode reports whether the codes got and want are equal.
+func sameCode(got, want interface{}) bool {
+	gk, gok := codeKey(got)
+	wk, wok := codeKey(want)
+	if gok && wok {
+		return gk == wk
+	}
+	return reflect.DeepEqual(got, want)
+}
+
+// codeKey returns the value of an integer or string code, regardless of its
+// type, as a string.
+func codeKey(code interface{}) (string, bool) {
+	v := reflect.ValueOf(code)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return fmt.Sprint(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return fmt.Sprint(v.Uint()), true
+	case reflect.String:
+		return "s" + v.String(), true
+	}
+	return "", false
+}
+
+func (m errCode) match(c *config, got error) string {
+	if got == nil {
+		return c.failf(missing)
+	}
+	var code interface{}
+	var found bool
+	walk(got, func(err error) bool {
+		code, found = errCodeOf(err)
+		return !found
+	})
+	if !found {
+		return c.failf(noErrCode, got)
+	}
+	if !sameCode(code, m.code) {
+		return c.failf(wrongErrCode, got, fmt.Sprint(code), fmt.Sprint(m.code))
+	}
+	return ""
+}
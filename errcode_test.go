@@ -0,0 +1,82 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+)
+
+// httpCode is an HTTP status code with a name.
+type httpCode int
+
+func (c httpCode) String() string {
+	if c == 404 {
+		return "NotFound"
+	}
+	return fmt.Sprintf("HTTP(%d)", int(c))
+}
+
+// requestErr is an error with an integer code.
+type requestErr struct{ code httpCode }
+
+func (e requestErr) Error() string  { return "request failed" }
+func (e requestErr) Code() httpCode { return e.code }
+
+// namedCodeErr is an error with a string code.
+type namedCodeErr string
+
+func (e namedCodeErr) Error() string     { return "quota exceeded" }
+func (e namedCodeErr) ErrorCode() string { return string(e) }
+
+func TestErrCode(t *testing.T) {
+	setDefaults(t)
+	notFound := requestErr{404}
+	wrapped := fmt.Errorf("get: %w", notFound)
+	for _, tt := range []struct {
+		name string
+		got  error
+		want Matcher
+		out  string
+	}{
+		{"code", notFound, ErrCode(httpCode(404)), ""},
+		{"untyped", notFound, ErrCode(404), ""},
+		{"unsigned", notFound, ErrCode(uint16(404)), ""},
+		{"wrapped", wrapped, ErrCode(404), ""},
+		{"string", namedCodeErr("E_QUOTA"), ErrCode("E_QUOTA"), ""},
+		{"joined", &multi{io.EOF, namedCodeErr("E_QUOTA")}, ErrCode("E_QUOTA"), ""},
+		{"wrong code", wrapped, ErrCode(500), sprintf(wrongErrCode, "get: request failed", "NotFound", "500")},
+		{"wrong string", namedCodeErr("E_QUOTA"), ErrCode("E_AUTH"), sprintf(wrongErrCode, "quota exceeded", "E_QUOTA", "E_AUTH")},
+		{"string and int", namedCodeErr("404"), ErrCode(404), sprintf(wrongErrCode, "quota exceeded", "404", "404")},
+		{"first", fmt.Errorf("%w", requestErr{500}), ErrCode(500), ""},
+		{"no code", io.EOF, ErrCode(404), sprintf(noErrCode, io.EOF)},
+		{"nil", nil, ErrCode(404), missing},
+	} {
+		if s := Error(tt.got, tt.want); s != tt.out {
+			t.Errorf("%s: got %q, want %q", tt.name, s, tt.out)
+		}
+	}
+	if d := Describe(ErrCode(httpCode(404))); d != `has code "NotFound"` {
+		t.Errorf("got description %q", d)
+	}
+	if err := Validate(ErrCode(nil)); err == nil || err.Error() != "want 0: ErrCode code is nil" {
+		t.Errorf("got validation error %v", err)
+	}
+	if Matches(errors.New("x"), ErrCode(1)) {
+		t.Errorf("Matches of an error without a code")
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Errorf is Error with opts applied to the default options, tuning how the
+// check is made:
+//
+//	check.Errorf(err, check.CaseEqual("Straße geschlossen"), check.Fold(), check.NormalizeMessages(check.TrimSpace))
+func Errorf(got error, want interface{}, opts ...Option) string {
+	return defaults().with(opts...).checkError(got, want)
+}
+
+// Errorf is the same as the Errorf function but uses the options of ck.
+func (ck *Checker) Errorf(got error, want interface{}, opts ...Option) Result {
+	ck.count()
+	return Result(ck.c.with(opts...).checkError(got, want))
+}
+
+// NormalizeMessages returns an Option that applies each of norms, in order,
+// to messages before they are checked against a want that is not an error,
+// e.g., NormalizeMessages(TrimSpace) ignores leading and trailing white
+// space.  NormalizeMessages with no norms restores the default.
+func NormalizeMessages(norms ...Normalizer) Option {
+	return func(c *config) {
+		if len(norms) == 0 {
+			c.norms = nil
+			return
+		}
+		c.norms = &norms
+	}
+}
+
+// IgnoreWrapping returns an Option that checks the message of the innermost
+// error wrapped by an error, as found by errors.Unwrap, rather than the
+// message of the error itself, against a want that is not an error.  The
+// context added by wrapping, such as "open config.yaml: ", is ignored.
+func IgnoreWrapping() Option {
+	return func(c *config) { c.ignoreWrapping = true }
+}
+
+// MaxMessageLen returns an Option that truncates messages longer than n runes
+// in failures, noting how much was cut.  Messages are checked in full.  A
+// MaxMessageLen of 0 does not truncate.
+func MaxMessageLen(n int) Option {
+	return func(c *config) { c.maxMessageLen = n }
+}
+
+// Fold returns an Option that compares messages in case insensitive checks,
+// such as Case and CaseEqual, using Unicode simple case folding, as does
+// strings.EqualFold, rather than strings.ToLower.  With Fold, "ſ" (long s)
+// and "S" are the same, as are "ς" and "σ".  Fold replaces any Lowercase option.
+func Fold() Option {
+	return Lowercase(folder{})
+}
+
+// folder is a Caser that maps each rune to the smallest rune it folds to.
+type folder struct{}
+
+func (folder) String(s string) string {
+	return strings.Map(func(r rune) rune {
+		min := r
+		for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+			if f < min {
+				min = f
+			}
+		}
+		return min
+	}, s)
+}
+
+// normalized returns got with the message to check as selected by the
+// IgnoreWrapping and NormalizeMessages options.
+func (c *config) normalized(got error) error {
+	if got == nil || (c.norms == nil && !c.ignoreWrapping) {
+		return got
+	}
+	inner := got
+	if c.ignoreWrapping {
+		for u := errors.Unwrap(inner); u != nil; u = errors.Unwrap(inner) {
+			inner = u
+		}
+	}
+	msg := inner.Error()
+	if c.norms != nil {
+		msg = Normalize(msg, *c.norms...)
+	}
+	if msg == got.Error() {
+		return got
+	}
+	return &scrubbed{msg: msg, err: got}
+}
+
+// truncated returns msg truncated as set by the MaxMessageLen option.
+func (c *config) truncated(msg string) string {
+	if c.maxMessageLen <= 0 || utf8.RuneCountInString(msg) <= c.maxMessageLen {
+		return msg
+	}
+	rs := []rune(msg)
+	return sprintf("%s... (%d more runes)", string(rs[:c.maxMessageLen]), len(rs)-c.maxMessageLen)
+}
@@ -0,0 +1,69 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestErrorf(t *testing.T) {
+	setDefaults(t)
+	padded := errors.New("  no such user ")
+	wrapped := fmt.Errorf("load config: %w", fmt.Errorf("open: %w", io.EOF))
+	longS := errors.New("ſtop")
+	for _, tt := range []struct {
+		name string
+		got  error
+		want interface{}
+		opts []Option
+		out  string
+	}{
+		{"default", padded, Equal("no such user"), nil, sprintf(wrong, padded, "no such user")},
+		{"trim", padded, Equal("no such user"), []Option{NormalizeMessages(TrimSpace)}, ""},
+		{"pipeline", errors.New(" NO  such\tuser "), Equal("no such user"), []Option{NormalizeMessages(CollapseSpace, FoldCase)}, ""},
+		{"reset", padded, Equal("no such user"), []Option{NormalizeMessages(TrimSpace), NormalizeMessages()}, sprintf(wrong, padded, "no such user")},
+		{"wrapped", wrapped, Equal("EOF"), nil, sprintf(wrong, wrapped, "EOF")},
+		{"ignore wrapping", wrapped, Equal("EOF"), []Option{IgnoreWrapping()}, ""},
+		{"ignore wrapping fails", wrapped, Equal("load config"), []Option{IgnoreWrapping()}, `got error "EOF", want "load config"`},
+		{"ignore wrapping error want", wrapped, io.EOF, []Option{IgnoreWrapping()}, sprintf(wrong, wrapped, io.EOF)},
+		{"lower", longS, CaseEqual("STOP"), nil, sprintf(wrong, longS, "STOP")},
+		{"fold", longS, CaseEqual("STOP"), []Option{Fold()}, ""},
+		{"lower sigma", errors.New("ΣΊΣΥΦΟΣ"), Case("σίσυφος"), nil, `got error "ΣΊΣΥΦΟΣ", want "σίσυφος"`},
+		{"fold case", errors.New("STRASSE ΣΊΣΥΦΟΣ"), Case("σίσυφος"), []Option{Fold()}, ""},
+	} {
+		if s := Errorf(tt.got, tt.want, tt.opts...); s != tt.out {
+			t.Errorf("%s: got %q, want %q", tt.name, s, tt.out)
+		}
+	}
+}
+
+func TestMaxMessageLen(t *testing.T) {
+	setDefaults(t)
+	long := errors.New(strings.Repeat("x", 100))
+	want := sprintf("got unexpected error %q", strings.Repeat("x", 10)+"... (90 more runes)")
+	if s := Errorf(long, nil, MaxMessageLen(10)); s != want {
+		t.Errorf("got %q, want %q", s, want)
+	}
+	if s := Errorf(long, nil, MaxMessageLen(100)); s != sprintf(unexpected, long) {
+		t.Errorf("got %q", s)
+	}
+	if s := Errorf(long, Equal(strings.Repeat("x", 100)), MaxMessageLen(10)); s != "" {
+		t.Errorf("truncated before the check: %q", s)
+	}
+}
@@ -0,0 +1,199 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import "strings"
+
+// AnyOrder returns an Option that causes Errors to match each error to any
+// of the wants, rather than to the want at its index.  Each want must match
+// a different error.
+func AnyOrder() Option {
+	return func(c *config) { c.anyOrder = true }
+}
+
+// Errors returns the empty string if each error in got is matched by the
+// want, as by Error, at the same index in want, otherwise it returns a string
+// indicating the failure of each index, one per line.  A nil want matches a
+// nil error.  Errors suits batch and validation APIs that return an error per
+// input:
+//
+//	errs := v.ValidateAll(users)
+//	if s := check.Errors(errs, []interface{}{nil, "missing name", check.Regexp(`age -\d+`)}); s != "" {
+//		t.Error(s)
+//	}
+//
+// With the AnyOrder option, as set with a Checker, the order of got is
+// ignored.
+func Errors(got []error, want []interface{}) string {
+	return defaults().errors(got, want)
+}
+
+// Errors is the same as the Errors function but uses the options of ck.
+func (ck *Checker) Errors(got []error, want []interface{}) Result {
+	ck.count()
+	return Result(ck.c.errors(got, want))
+}
+
+// errors implements Errors using the settings in c.
+func (c *config) errors(got []error, want []interface{}) string {
+	if c.outer() {
+		return c.run("Errors", joinErrors(got), func() string { return describeAll(want) },
+			func(c *config) string { return c.errors(got, want) })
+	}
+	var failures []string
+	if c.anyOrder {
+		failures = c.unorderedErrors(got, want)
+	} else {
+		failures = c.orderedErrors(got, want)
+	}
+	if len(failures) == 0 {
+		return ""
+	}
+	if c.quiet {
+		return quietFailure
+	}
+	return strings.Join(failures, "\n")
+}
+
+// errorCount returns the failure of got and want differing in length, or ""
+// if they do not.
+func (c *config) errorCount(got []error, want []interface{}) string {
+	if len(got) == len(want) {
+		return ""
+	}
+	return c.failc(CodeWrong, sprintf("got %d errors, want %d", len(got), len(want)))
+}
+
+// orderedErrors returns the failures of matching each error in got to the
+// want at its index in want.
+func (c *config) orderedErrors(got []error, want []interface{}) []string {
+	var failures []string
+	if s := c.errorCount(got, want); s != "" {
+		failures = append(failures, s)
+	}
+	for i := 0; i < len(got) || i < len(want); i++ {
+		var g error
+		var w interface{}
+		if i < len(got) {
+			g = got[i]
+		}
+		if i < len(want) {
+			w = want[i]
+		}
+		if s := c.checkError(g, w); s != "" {
+			failures = append(failures, prefixed(sprintf("error %d: ", i), s))
+		}
+	}
+	return failures
+}
+
+// unorderedErrors returns the failures of matching each error in got to a
+// different want in want.
+func (c *config) unorderedErrors(got []error, want []interface{}) []string {
+	q := *c
+	q.quiet = true
+	matches := make([][]bool, len(got))
+	for i, g := range got {
+		matches[i] = make([]bool, len(want))
+		for j, w := range want {
+			matches[i][j] = q.checkError(g, w) == ""
+		}
+	}
+	gotOf := assign(matches, len(want))
+	var failures []string
+	if s := c.errorCount(got, want); s != "" {
+		failures = append(failures, s)
+	}
+	matched := make([]bool, len(got))
+	for j, i := range gotOf {
+		if i < 0 {
+			failures = append(failures, c.failc(CodeMissing, sprintf("no error matched want %d: %%q", j), description(Describe(want[j]))))
+		} else {
+			matched[i] = true
+		}
+	}
+	for i, g := range got {
+		switch {
+		case matched[i]:
+		case g == nil:
+			failures = append(failures, c.failc(CodeUnexpected, sprintf("error %d: got no error, matched by no want", i)))
+		default:
+			failures = append(failures, c.failc(CodeUnexpected, sprintf("error %d: got error %%q, matched by no want", i), g))
+		}
+	}
+	return failures
+}
+
+// assign returns, for each of n wants, the index of the error it is assigned
+// to, or -1, such that as many wants as possible are assigned to different
+// errors.  matches[i][j] reports whether error i matches want j.
+func assign(matches [][]bool, n int) []int {
+	gotOf := make([]int, n)
+	for j := range gotOf {
+		gotOf[j] = -1
+	}
+	// augment assigns error i, reassigning the errors of the wants it
+	// matches if need be.  Kuhn's algorithm.
+	var augment func(i int, seen []bool) bool
+	augment = func(i int, seen []bool) bool {
+		for j, ok := range matches[i] {
+			if !ok || seen[j] {
+				continue
+			}
+			seen[j] = true
+			if gotOf[j] < 0 || augment(gotOf[j], seen) {
+				gotOf[j] = i
+				return true
+			}
+		}
+		return false
+	}
+	for i := range matches {
+		augment(i, make([]bool, n))
+	}
+	return gotOf
+}
+
+// joinErrors returns nil if errs holds no errors other than nil, the error if
+// it holds one, or else an error joining its errors, one per line.
+func joinErrors(errs []error) error {
+	var nonNil errorList
+	for _, err := range errs {
+		if err != nil {
+			nonNil = append(nonNil, err)
+		}
+	}
+	switch len(nonNil) {
+	case 0:
+		return nil
+	case 1:
+		return nonNil[0]
+	default:
+		return nonNil
+	}
+}
+
+// An errorList is an error joining a list of errors, one per line.
+type errorList []error
+
+func (e errorList) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+func (e errorList) Unwrap() []error { return e }
@@ -0,0 +1,93 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestErrors(t *testing.T) {
+	setDefaults(t)
+	name := errors.New("missing name")
+	age := errors.New("bad age -3")
+	for _, tt := range []struct {
+		name string
+		got  []error
+		want []interface{}
+		out  string
+	}{
+		{"empty", nil, nil, ""},
+		{"match", []error{nil, name, age}, []interface{}{nil, "name", Regexp(`age -\d+`)}, ""},
+		{"wrong", []error{nil, name, age}, []interface{}{nil, "age", "age"},
+			"error 1: " + sprintf(wrong, name, "age")},
+		{"several", []error{io.EOF, nil}, []interface{}{nil, "name"},
+			"error 0: " + sprintf(unexpected, io.EOF) + "\nerror 1: " + sprintf(expected, "name")},
+		{"short", []error{name}, []interface{}{"name", "age"},
+			"got 1 errors, want 2\nerror 1: " + sprintf(expected, "age")},
+		{"long", []error{name, age}, []interface{}{"name"},
+			"got 2 errors, want 1\nerror 1: " + sprintf(unexpected, age)},
+		{"order", []error{age, name}, []interface{}{"name", "age"},
+			"error 0: " + sprintf(wrong, age, "name") + "\nerror 1: " + sprintf(wrong, name, "age")},
+	} {
+		if s := Errors(tt.got, tt.want); s != tt.out {
+			t.Errorf("%s: got %q, want %q", tt.name, s, tt.out)
+		}
+	}
+	ck := NewChecker(Codes()).Child("users")
+	if s := ck.Errors([]error{io.EOF}, []interface{}{"name"}); s != "CHK-WRONG: users: error 0: "+Result(sprintf(wrong, io.EOF, "name")) {
+		t.Errorf("Checker: got %q", s)
+	}
+}
+
+func TestErrorsAnyOrder(t *testing.T) {
+	setDefaults(t, AnyOrder())
+	name := errors.New("missing name")
+	age := errors.New("bad age -3")
+	for _, tt := range []struct {
+		name string
+		got  []error
+		want []interface{}
+		out  string
+	}{
+		{"empty", nil, nil, ""},
+		{"order", []error{age, name, nil}, []interface{}{nil, "name", "age"}, ""},
+		{"overlap", []error{errors.New("a b"), errors.New("a")}, []interface{}{"a", "b"}, ""},
+		{"duplicate", []error{name, age}, []interface{}{"name", "name"},
+			`no error matched want 1: contains "name"` + "\n" + sprintf(`error 1: got error %q, matched by no want`, age)},
+		{"nil", []error{nil}, []interface{}{"name"},
+			`no error matched want 0: contains "name"` + "\nerror 0: got no error, matched by no want"},
+		{"short", []error{name}, []interface{}{"age", "name"},
+			"got 1 errors, want 2\n" + `no error matched want 0: contains "age"`},
+		{"long", []error{name, age}, []interface{}{"age"},
+			"got 2 errors, want 1\n" + sprintf(`error 0: got error %q, matched by no want`, name)},
+	} {
+		if s := Errors(tt.got, tt.want); s != tt.out {
+			t.Errorf("%s: got %q, want %q", tt.name, s, tt.out)
+		}
+	}
+	if s := NewChecker(Codes()).Errors([]error{nil}, []interface{}{"name"}); s != "CHK-MISSING: no error matched want 0: contains \"name\"\nCHK-UNEXPECTED: error 0: got no error, matched by no want" {
+		t.Errorf("Codes: got %q", s)
+	}
+}
+
+func TestAssign(t *testing.T) {
+	// Error 0 matches both wants, error 1 only want 0.
+	got := assign([][]bool{{true, true}, {true, false}}, 2)
+	if got[0] != 1 || got[1] != 0 {
+		t.Errorf("got %v, want [1 0]", got)
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import "time"
+
+// Eventually returns the empty string if f, called every interval, returns an
+// error matched by want, as by Error, within timeout, otherwise it returns
+// the failure of the last error f returned, noting the time and attempts
+// taken.  f is always called at least once.  A want of nil waits for f to
+// succeed:
+//
+//	if s := check.Eventually(func() error { return ping(addr) }, nil, 5*time.Second, 100*time.Millisecond); s != "" {
+//		t.Error(s)
+//	}
+//
+// f is not interrupted; a call to f that is running when timeout expires is
+// allowed to finish.  Within a testing/synctest bubble timeout and interval
+// are measured in the bubble's virtual time.
+func Eventually(f func() error, want interface{}, timeout, interval time.Duration) string {
+	return defaults().eventually(f, want, timeout, interval)
+}
+
+// Eventually is the same as the Eventually function but uses the options of ck.
+func (ck *Checker) Eventually(f func() error, want interface{}, timeout, interval time.Duration) Result {
+	ck.count()
+	return Result(ck.c.eventually(f, want, timeout, interval))
+}
+
+// eventually implements Eventually using the settings in c.
+func (c *config) eventually(f func() error, want interface{}, timeout, interval time.Duration) string {
+	if c.outer() {
+		return c.run("Eventually", nil, func() string { return Describe(want) },
+			func(c *config) string { return c.eventually(f, want, timeout, interval) })
+	}
+	q := *c
+	q.quiet = true
+	deadline, stop := after(timeout)
+	defer stop()
+	for attempts := 1; ; attempts++ {
+		err := f()
+		if q.checkError(err, want) == "" {
+			return ""
+		}
+		tick, stopTick := after(interval)
+		select {
+		case <-tick:
+			continue
+		case <-deadline:
+			stopTick()
+		}
+		s := c.checkError(err, want)
+		if c.quiet {
+			return s
+		}
+		return prefixed(sprintf("after %v and %d attempts: ", timeout, attempts), s)
+	}
+}
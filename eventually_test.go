@@ -0,0 +1,74 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+// countdown returns a function that returns io.EOF until it has been called n
+// times, then returns err.
+func countdown(n int, err error) (func() error, *int) {
+	calls := 0
+	return func() error {
+		calls++
+		if calls < n {
+			return io.EOF
+		}
+		return err
+	}, &calls
+}
+
+func TestEventually(t *testing.T) {
+	setDefaults(t)
+	done := errors.New("done")
+	for _, tt := range []struct {
+		name string
+		n    int
+		err  error
+		want interface{}
+	}{
+		{"first", 1, nil, nil},
+		{"third", 3, nil, nil},
+		{"error", 3, done, "done"},
+		{"eof", 1, io.EOF, io.EOF},
+	} {
+		f, calls := countdown(tt.n, tt.err)
+		if s := Eventually(f, tt.want, time.Second, time.Millisecond); s != "" {
+			t.Errorf("%s: %s", tt.name, s)
+		}
+		if *calls != tt.n {
+			t.Errorf("%s: called %d times, want %d", tt.name, *calls, tt.n)
+		}
+	}
+
+	f, calls := countdown(1000, nil)
+	s := Eventually(f, nil, 20*time.Millisecond, time.Millisecond)
+	if want := sprintf("after 20ms and %d attempts: ", *calls) + sprintf(unexpected, io.EOF); s != want {
+		t.Errorf("timeout: got %q, want %q", s, want)
+	}
+	if *calls < 2 {
+		t.Errorf("timeout: called %d times", *calls)
+	}
+
+	f, _ = countdown(1, done)
+	s = NewChecker(Codes()).c.eventually(f, "ready", 0, time.Hour)
+	if want := "CHK-WRONG: after 0s and 1 attempts: " + sprintf(wrong, done, "ready"); s != want {
+		t.Errorf("Codes: got %q, want %q", s, want)
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check_test
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/pborman/check"
+)
+
+// parsePort returns the port number in s.
+func parsePort(s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, err
+	}
+	if n < 1 || n > 65535 {
+		return 0, errors.New("port out of range")
+	}
+	return n, nil
+}
+
+// testParsePort is a test, such as TestParsePort, using Run.
+func testParsePort(t *testing.T) {
+	check.Run(t,
+		check.Row{Test: func(*check.T) error { _, err := parsePort("80"); return err }},
+		check.Row{Test: func(*check.T) error { _, err := parsePort("x"); return err }, Want: strconv.ErrSyntax},
+		check.Row{Test: func(*check.T) error { _, err := parsePort("0"); return err }, Want: "out of range"},
+	)
+}
+
+func ExampleRun() {
+	// Run is called from a test function, such as testParsePort, which
+	// names each row's subtest after its Want, e.g.,
+	// TestParsePort/contains_out_of_range.
+	_ = testParsePort
+}
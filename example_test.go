@@ -0,0 +1,184 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check_test
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/pborman/check"
+)
+
+func Example() {
+	err := fmt.Errorf("open config.yaml: %w", os.ErrNotExist)
+	fmt.Println(check.Error(err, "does not exist") == "")
+	fmt.Println(check.Error(err, "permission denied"))
+	fmt.Println(check.Error(nil, "permission denied"))
+	fmt.Println(check.Error(err, nil))
+	// Output:
+	// true
+	// got error "open config.yaml: file does not exist", want "permission denied"
+	// did not get expected error "permission denied"
+	// got unexpected error "open config.yaml: file does not exist"
+}
+
+func ExampleError_regexp() {
+	err := errors.New("line 12: unexpected '}'")
+	fmt.Printf("%q\n", check.Error(err, check.Regexp(`^line \d+: unexpected`)))
+	fmt.Println(check.Error(err, check.Regexp(`^line \d+: missing`)))
+	// Output:
+	// ""
+	// got error "line 12: unexpected '}'", want a match of pattern "^line \\d+: missing"
+}
+
+func ExampleIs() {
+	err := fmt.Errorf("read header: %w", io.ErrUnexpectedEOF)
+	fmt.Printf("%q\n", check.Is(err, io.ErrUnexpectedEOF))
+	fmt.Printf("%q\n", check.Error(err, io.ErrUnexpectedEOF))
+	// Output:
+	// ""
+	// "got error \"read header: unexpected EOF\", want \"unexpected EOF\""
+}
+
+func ExampleError_as() {
+	err := fmt.Errorf("read header: %w", io.ErrUnexpectedEOF)
+	var serr *json.SyntaxError
+	fmt.Println(check.Error(err, &serr))
+	// Output:
+	// got error "read header: unexpected EOF", want error of type "*json.SyntaxError"
+}
+
+func ExampleChecker() {
+	ck := check.NewChecker(check.Codes())
+	db := ck.Child("db")
+	fmt.Println(db.Error(io.EOF, "timeout"))
+	fmt.Println(db.Error(nil, true))
+	// Output:
+	// CHK-WRONG: db: got error "EOF", want "timeout"
+	// CHK-MISSING: db: did not get expected error
+}
+
+func ExampleUseFormatter() {
+	asJSON := check.FormatterFunc(func(m check.Mismatch) string {
+		b, _ := json.Marshal(map[string]string{
+			"check": m.Check,
+			"kind":  string(m.Kind),
+			"got":   m.Got.Error(),
+			"want":  m.Description,
+		})
+		return string(b)
+	})
+	ck := check.NewChecker(check.UseFormatter(asJSON), check.Codes())
+	fmt.Println(ck.Error(io.EOF, check.Equal("timeout")))
+	// Output:
+	// CHK-WRONG: {"check":"Error","got":"EOF","kind":"equal","want":"is \"timeout\""}
+}
+
+func ExampleCaret() {
+	ck := check.NewChecker(check.Caret())
+	fmt.Println(ck.Error(errors.New("Err one"), check.Equal("Err two")))
+	fmt.Println(ck.Error(errors.New("connection Time-Out"), "timeout"))
+	// Output:
+	// got error "Err one", want "Err two"
+	// first difference at byte 4 (rune 4):
+	// 	Err one
+	// 	Err two
+	// 	    ^
+	// got error "connection Time-Out", want "timeout"
+	// near match at byte 11 (rune 11): "Time-Out"
+}
+
+func ExampleErrors() {
+	errs := []error{nil, errors.New("missing name"), errors.New("bad age -3")}
+	fmt.Printf("%q\n", check.Errors(errs, []interface{}{nil, "name", check.Regexp(`age -\d+`)}))
+	fmt.Println(check.Errors(errs, []interface{}{nil, "age", "name"}))
+	fmt.Printf("%q\n", check.NewChecker(check.AnyOrder()).Errors(errs, []interface{}{"age", nil, "name"}))
+	// Output:
+	// ""
+	// error 1: got error "missing name", want "age"
+	// error 2: got error "bad age -3", want "name"
+	// ""
+}
+
+// quotaError is an error with a code.
+type quotaError struct{ resource string }
+
+func (e *quotaError) Error() string     { return e.resource + " quota exceeded" }
+func (e *quotaError) ErrorCode() string { return "E_QUOTA" }
+
+func ExampleErrCode() {
+	err := fmt.Errorf("upload: %w", &quotaError{"disk"})
+	fmt.Printf("%q\n", check.Error(err, check.ErrCode("E_QUOTA")))
+	fmt.Println(check.Error(err, check.ErrCode("E_AUTH")))
+	fmt.Println(check.Error(io.EOF, check.ErrCode("E_AUTH")))
+	// Output:
+	// ""
+	// got error "upload: disk quota exceeded" with code "E_QUOTA", want code "E_AUTH"
+	// no error in the chain of "EOF" has a Code or ErrorCode method
+}
+
+func ExampleOwnMessage() {
+	err := fmt.Errorf("load config.yaml: %w", fmt.Errorf("open config.yaml: %w", os.ErrPermission))
+	fmt.Println(check.OwnMessage(err))
+	fmt.Printf("%q\n", check.Error(err, check.Own(check.Equal("load config.yaml"))))
+	// Output:
+	// load config.yaml
+	// ""
+}
+
+func ExampleErrorFrom() {
+	errc := make(chan error, 1)
+	go func() { errc <- io.EOF }()
+	fmt.Printf("%q\n", check.ErrorFrom(errc, time.Second, io.EOF))
+	fmt.Println(check.ErrorFrom(errc, 10*time.Millisecond, io.EOF))
+	// Output:
+	// ""
+	// timed out after 10ms waiting for error, want is the error "EOF" (*errors.errorString)
+}
+
+func ExampleCLI() {
+	serve := func(args []string, out io.Writer) error {
+		fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+		fs.SetOutput(out)
+		fs.Int("port", 80, "port to serve on")
+		return fs.Parse(args)
+	}
+	fmt.Printf("%q\n", check.CLI(serve, []string{"-port=x"}, check.FlagError(check.BadFlagValue, "port"), "Usage of serve"))
+	fmt.Println(check.CLI(serve, []string{"-port=8080"}, nil, "serving"))
+	// Output:
+	// ""
+	// output: did not get expected error "serving"
+}
+
+// versionError is an error whose %v form has drifted from its message.
+type versionError struct{}
+
+func (versionError) Error() string { return "unsupported version" }
+
+func (versionError) Format(f fmt.State, verb rune) {
+	fmt.Fprint(f, "unsupported version (want 2)")
+}
+
+func ExampleFormatConsistent() {
+	fmt.Println(check.FormatConsistent(versionError{}))
+	// Output:
+	// got "unsupported version (want 2)" formatting error with "%v", want "unsupported version"
+	// got "unsupported version (want 2)" formatting error with "%s", want "unsupported version"
+}
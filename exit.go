@@ -0,0 +1,71 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"bytes"
+	"errors"
+	"os/exec"
+)
+
+// An ExitStatus is a Matcher that matches an error whose chain includes an
+// *exec.ExitError with the exit status, as returned by its ExitCode method.
+// ExitStatus(0) also matches no error, a command that succeeded:
+//
+//	err := exec.Command("grep", "-q", "x", "/dev/null").Run()
+//	check.Error(err, check.ExitStatus(1))
+//
+// Failures include the standard error of the command, if captured in the
+// ExitError, such as by the Output method of exec.Cmd.
+type ExitStatus int
+
+// ExitCode is shorthand for Error(got, ExitStatus(want)).
+func ExitCode(got error, want int) string {
+	return Error(got, ExitStatus(want))
+}
+
+// ExitCode is the same as the ExitCode function but uses the options of ck.
+func (ck *Checker) ExitCode(got error, want int) Result {
+	return ck.Error(got, ExitStatus(want))
+}
+
+func (m ExitStatus) match(c *config, got error) string {
+	if got == nil {
+		if m == 0 {
+			return ""
+		}
+		return c.failc(CodeMissing, sprintf("command succeeded, want exit status %d", int(m)))
+	}
+	var ee *exec.ExitError
+	notExit := sprintf("got error %%q, want exit status %d", int(m))
+	if !errors.As(got, &ee) || ee.ProcessState == nil {
+		return c.failc(CodeWrong, notExit, got)
+	}
+	code := ee.ExitCode()
+	if code == int(m) {
+		return ""
+	}
+	var s string
+	if code < 0 {
+		// Killed by a signal.
+		s = c.failc(CodeWrong, notExit, got)
+	} else {
+		s = c.failc(CodeWrong, sprintf("got exit status %d, want exit status %d", code, int(m)))
+	}
+	if stderr := bytes.TrimRight(ee.Stderr, "\n"); len(stderr) > 0 && !c.quiet {
+		s += "\nstderr:\n" + indent(string(stderr))
+	}
+	return s
+}
@@ -0,0 +1,93 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"testing"
+)
+
+// exitEnv is the environment variable that causes the test binary to act as
+// a command that writes to its standard error and exits with the status in
+// the variable.
+const exitEnv = "CHECK_TEST_EXIT"
+
+func init() {
+	if s := os.Getenv(exitEnv); s != "" {
+		code, _ := strconv.Atoi(s)
+		fmt.Fprintf(os.Stderr, "exiting with %d\n", code)
+		os.Exit(code)
+	}
+}
+
+// exit returns the error of running the test binary as a command exiting
+// with code.  If output is true, the standard error of the command is
+// captured in the error.
+func exit(code int, output bool) error {
+	cmd := exec.Command(os.Args[0], "-test.run=^$")
+	cmd.Env = append(os.Environ(), sprintf("%s=%d", exitEnv, code))
+	if output {
+		_, err := cmd.Output()
+		return err
+	}
+	return cmd.Run()
+}
+
+func TestExitStatus(t *testing.T) {
+	setDefaults(t)
+	ok := exit(0, false)
+	if ok != nil {
+		t.Fatalf("exit 0: %v", ok)
+	}
+	three := exit(3, false)
+	captured := exit(3, true)
+	wrapped := fmt.Errorf("run: %w", three)
+	for _, tt := range []struct {
+		name string
+		got  error
+		want int
+		out  string
+	}{
+		{"ok", ok, 0, ""},
+		{"three", three, 3, ""},
+		{"wrapped", wrapped, 3, ""},
+		{"captured", captured, 3, ""},
+		{"succeeded", nil, 2, "command succeeded, want exit status 2"},
+		{"failed", three, 0, "got exit status 3, want exit status 0"},
+		{"wrong", wrapped, 2, "got exit status 3, want exit status 2"},
+		{"stderr", captured, 2, "got exit status 3, want exit status 2\nstderr:\n\texiting with 3"},
+		{"not exit", errors.New("exec: not found"), 2, `got error "exec: not found", want exit status 2`},
+	} {
+		if s := ExitCode(tt.got, tt.want); s != tt.out {
+			t.Errorf("%s: got %q, want %q", tt.name, s, tt.out)
+		}
+	}
+	if s := NewChecker(Codes()).Error(nil, ExitStatus(1)); s != "CHK-MISSING: command succeeded, want exit status 1" {
+		t.Errorf("Codes: got %q", s)
+	}
+	if Matches(captured, ExitStatus(2)) {
+		t.Errorf("Matches of the wrong status")
+	}
+	if d := Describe(ExitStatus(2)); d != "exit status 2" {
+		t.Errorf("got description %q", d)
+	}
+	if err := Validate(ExitStatus(-1)); err == nil || err.Error() != "want 0: ExitStatus -1 is negative" {
+		t.Errorf("got validation error %v", err)
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+)
+
+type fields struct{ want interface{} }
+
+// Fields returns a Matcher that matches an error wrapping an error of the
+// same type as want, as determined by errors.As, whose exported fields equal
+// the exported fields of want that are not zero.  Fields holding errors are
+// compared as by errors.Is, and other fields as by reflect.DeepEqual.  Want
+// is a struct, or pointer to a struct, that implements error:
+//
+//	check.Error(err, check.Fields(&net.OpError{Op: "dial", Net: "tcp"}))
+//
+// Asserting fields avoids matching messages that embed details, such as
+// addresses, that vary between runs.
+func Fields(want interface{}) Matcher {
+	return fields{want: want}
+}
+
+// ErrorFields is shorthand for Error(got, Fields(want)).  Each field that
+// differs is reported, e.g.:
+//
+//	got error "dial tcp 10.0.0.1:80: i/o timeout" with fields that differ:
+//		.Op: got "dial", want "read"
+func ErrorFields(got error, want interface{}) string {
+	return Error(got, Fields(want))
+}
+
+// ErrorFields is the same as the ErrorFields function but uses the options of
+// ck.
+func (ck *Checker) ErrorFields(got error, want interface{}) Result {
+	return ck.Error(got, Fields(want))
+}
+
+// structOf returns the struct value of want, a struct or a non-nil pointer to
+// a struct, or an invalid Value.
+func structOf(want interface{}) reflect.Value {
+	v := reflect.ValueOf(want)
+	if v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return reflect.Value{}
+	}
+	return v
+}
+
+func (m fields) match(c *config, got error) string {
+	wv := structOf(m.want)
+	t := reflect.TypeOf(m.want)
+	if !wv.IsValid() || !t.Implements(errorType) {
+		if c.quiet {
+			return quietFailure
+		}
+		return c.code(CodeUnsupported) + sprintf("Fields does not support type %T", m.want)
+	}
+	if got == nil {
+		return c.failf(expectedType, t)
+	}
+	target := reflect.New(t)
+	if !errors.As(got, target.Interface()) {
+		return c.failf(wrongType, got, t)
+	}
+	gv := structOf(target.Elem().Interface())
+	if !gv.IsValid() {
+		// A nil pointer of type t.
+		return c.failf(wrongType, got, t)
+	}
+	d := differ{visited: map[visit]bool{}}
+	for i := 0; i < wv.NumField(); i++ {
+		f := wv.Type().Field(i)
+		w := wv.Field(i)
+		if f.PkgPath != "" || w.IsZero() {
+			continue
+		}
+		path := "." + f.Name
+		g := gv.Field(i)
+		if f.Type == errorType {
+			if !errors.Is(errorOf(g), errorOf(w)) {
+				d.addf(path, "got %s, want %s", formatError(g), formatError(w))
+			}
+			continue
+		}
+		d.diff(path, g, w)
+	}
+	if len(d.diffs) == 0 {
+		return ""
+	}
+	if c.quiet {
+		return quietFailure
+	}
+	var b strings.Builder
+	b.WriteString(c.failc(CodeWrong, "got error %q with fields that differ:", got))
+	for _, s := range d.diffs {
+		b.WriteString("\n")
+		b.WriteString(indent(s))
+	}
+	return b.String()
+}
+
+// errorOf returns the error held by v, a Value of type error.
+func errorOf(v reflect.Value) error {
+	err, _ := v.Interface().(error)
+	return err
+}
+
+// formatError returns the error held by v, a Value of type error, formatted
+// for a difference.
+func formatError(v reflect.Value) string {
+	if err := errorOf(v); err != nil {
+		return sprintf("error %q", err)
+	}
+	return "no error"
+}
@@ -0,0 +1,79 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+)
+
+// rangeErr is a struct error that is not a pointer.
+type rangeErr struct {
+	Min, Max int
+	Tags     []string
+	hidden   string
+}
+
+func (e rangeErr) Error() string { return fmt.Sprintf("not in [%d, %d]", e.Min, e.Max) }
+
+func TestFields(t *testing.T) {
+	setDefaults(t)
+	opErr := &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}
+	wrapped := fmt.Errorf("connect: %w", opErr)
+	var jsonErr error
+	if err := json.Unmarshal([]byte(`{"a":}`), new(interface{})); err != nil {
+		jsonErr = err
+	}
+	for _, tt := range []struct {
+		name string
+		got  error
+		want interface{}
+		out  string
+	}{
+		{"match", wrapped, &net.OpError{Op: "dial", Net: "tcp"}, ""},
+		{"zero want", opErr, &net.OpError{}, ""},
+		{"error field", wrapped, &net.OpError{Err: syscall.ECONNREFUSED}, ""},
+		{"json", jsonErr, &json.SyntaxError{Offset: 6}, ""},
+		{"value", rangeErr{Min: 1, Max: 3, Tags: []string{"a"}, hidden: "x"}, rangeErr{Max: 3, Tags: []string{"a"}, hidden: "y"}, ""},
+		{"one field", wrapped, &net.OpError{Op: "read", Net: "tcp"},
+			sprintf("got error %q with fields that differ:\n\t.Op: got \"dial\", want \"read\"", wrapped)},
+		{"two fields", rangeErr{Min: 1, Max: 3}, rangeErr{Min: 2, Tags: []string{"a"}},
+			"got error \"not in [1, 3]\" with fields that differ:\n\t.Min: got 1, want 2\n\t.Tags: got []string(nil), want [a]"},
+		{"wrong error", opErr, &net.OpError{Err: io.EOF},
+			sprintf("got error %q with fields that differ:\n\t.Err: got error %q, want error \"EOF\"", opErr, opErr.Err)},
+		{"wrong type", io.EOF, &net.OpError{}, sprintf(wrongType, "EOF", "*net.OpError")},
+		{"nil", nil, &json.SyntaxError{}, sprintf(expectedType, "*json.SyntaxError")},
+		{"unsupported", io.EOF, "EOF", "Fields does not support type string"},
+		{"not error", io.EOF, struct{ A int }{}, "Fields does not support type struct { A int }"},
+	} {
+		if s := ErrorFields(tt.got, tt.want); s != tt.out {
+			t.Errorf("%s: got %q, want %q", tt.name, s, tt.out)
+		}
+	}
+	if d := Describe(Fields(&net.OpError{Op: "dial", Net: "tcp"})); d != `a *net.OpError with Op: "dial", Net: "tcp"` {
+		t.Errorf("got description %q", d)
+	}
+	if d := Describe(Fields(rangeErr{})); d != "a check.rangeErr" {
+		t.Errorf("got description %q", d)
+	}
+	if err := Validate(Fields(&net.OpError{}), Fields("EOF")); fmt.Sprint(err) != "want 1: Fields: unsupported type string" {
+		t.Errorf("got validation error %v", err)
+	}
+}
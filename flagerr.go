@@ -0,0 +1,125 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import "regexp"
+
+// A FlagProblem is the kind of a command line flag parsing error.
+type FlagProblem int
+
+const (
+	// AnyFlagProblem matches any kind of flag parsing error.
+	AnyFlagProblem FlagProblem = iota
+
+	// UnknownFlag is a flag that is not defined.
+	UnknownFlag
+
+	// BadFlagValue is a flag whose value could not be parsed.
+	BadFlagValue
+
+	// MissingFlagValue is a flag given without its required value.
+	MissingFlagValue
+)
+
+func (p FlagProblem) String() string {
+	switch p {
+	case AnyFlagProblem:
+		return "flag error"
+	case UnknownFlag:
+		return "unknown flag"
+	case BadFlagValue:
+		return "bad flag value"
+	case MissingFlagValue:
+		return "missing flag value"
+	}
+	return sprintf("FlagProblem(%d)", int(p))
+}
+
+type flagError struct {
+	problem FlagProblem
+	name    string
+}
+
+// FlagError returns a Matcher that matches a flag parsing error, as returned
+// by the flag package or by github.com/spf13/pflag, of kind problem for the
+// flag name, given without dashes.  An empty name matches any flag.  A flag
+// defined with pflag with both a shorthand and a name matches either.  The
+// messages of the two packages differ, and change, while the problem and the
+// flag do not:
+//
+//	check.Error(err, check.FlagError(check.BadFlagValue, "port"))
+func FlagError(problem FlagProblem, name string) Matcher {
+	return flagError{problem: problem, name: name}
+}
+
+// flagPatterns match the messages of flag parsing errors of the flag and
+// pflag packages.  The submatches are the names of the flag.
+var flagPatterns = []struct {
+	problem FlagProblem
+	re      *regexp.Regexp
+}{
+	{UnknownFlag, regexp.MustCompile(`flag provided but not defined: -+(\S+)`)},
+	{UnknownFlag, regexp.MustCompile(`unknown flag: -+(\S+)`)},
+	{UnknownFlag, regexp.MustCompile(`unknown shorthand flag: '(.)'`)},
+	{BadFlagValue, regexp.MustCompile(`invalid (?:boolean )?value ".*?" for (?:flag )?-+([^:\s]+)`)},
+	{BadFlagValue, regexp.MustCompile(`invalid argument ".*?" for "(?:-(\S+), )?--([^"]+)" flag`)},
+	{MissingFlagValue, regexp.MustCompile(`flag needs an argument: '(.)'`)},
+	{MissingFlagValue, regexp.MustCompile(`flag needs an argument: -+(\S+)`)},
+}
+
+// parseFlagError returns the problem of the flag parsing error with the
+// message msg and the names of its flag.  It returns AnyFlagProblem if msg is
+// not the message of a flag parsing error.
+func parseFlagError(msg string) (FlagProblem, []string) {
+	for _, p := range flagPatterns {
+		if m := p.re.FindStringSubmatch(msg); m != nil {
+			var names []string
+			for _, name := range m[1:] {
+				if name != "" {
+					names = append(names, name)
+				}
+			}
+			return p.problem, names
+		}
+	}
+	return AnyFlagProblem, nil
+}
+
+const (
+	notFlagError = "got error %q, want a flag error"
+	wrongFlag    = "got error %q, want flag %q"
+)
+
+func (m flagError) match(c *config, got error) string {
+	if got == nil {
+		return c.failf(missing)
+	}
+	problem, names := parseFlagError(got.Error())
+	if problem == AnyFlagProblem {
+		return c.failf(notFlagError, got)
+	}
+	if m.problem != AnyFlagProblem && problem != m.problem {
+		return c.failc(CodeWrong, sprintf("got error %%q, want %s (got %s)", m.problem, problem), got)
+	}
+	if m.name == "" {
+		return ""
+	}
+	for _, name := range names {
+		if name == m.name {
+			return ""
+		}
+	}
+	return c.failf(wrongFlag, got, m.name)
+}
@@ -0,0 +1,99 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"testing"
+)
+
+// parseFlags returns the error of parsing args with a flag set defining -port
+// and -v.
+func parseFlags(args ...string) error {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	fs.Int("port", 0, "")
+	fs.Bool("v", false, "")
+	return fs.Parse(args)
+}
+
+func TestParseFlagError(t *testing.T) {
+	for _, tt := range []struct {
+		msg     string
+		problem FlagProblem
+		names   []string
+	}{
+		// The flag package.
+		{parseFlags("-bogus").Error(), UnknownFlag, []string{"bogus"}},
+		{parseFlags("--bogus=1").Error(), UnknownFlag, []string{"bogus"}},
+		{parseFlags("-port=x").Error(), BadFlagValue, []string{"port"}},
+		{parseFlags("-v=x").Error(), BadFlagValue, []string{"v"}},
+		{parseFlags("-port").Error(), MissingFlagValue, []string{"port"}},
+
+		// The pflag package.
+		{"unknown flag: --bogus", UnknownFlag, []string{"bogus"}},
+		{"unknown shorthand flag: 'b' in -b", UnknownFlag, []string{"b"}},
+		{`invalid argument "x" for "-p, --port" flag: strconv.ParseInt: parsing "x": invalid syntax`, BadFlagValue, []string{"p", "port"}},
+		{`invalid argument "x" for "--port" flag: strconv.ParseInt: parsing "x": invalid syntax`, BadFlagValue, []string{"port"}},
+		{"flag needs an argument: --port", MissingFlagValue, []string{"port"}},
+		{"flag needs an argument: 'p' in -p", MissingFlagValue, []string{"p"}},
+
+		{"EOF", AnyFlagProblem, nil},
+	} {
+		problem, names := parseFlagError(tt.msg)
+		if problem != tt.problem || fmt.Sprint(names) != fmt.Sprint(tt.names) {
+			t.Errorf("%q: got %v %q, want %v %q", tt.msg, problem, names, tt.problem, tt.names)
+		}
+	}
+}
+
+func TestFlagError(t *testing.T) {
+	setDefaults(t)
+	bad := parseFlags("-port=x")
+	pflagBad := errors.New(`invalid argument "x" for "-p, --port" flag: strconv.ParseInt: parsing "x": invalid syntax`)
+	for _, tt := range []struct {
+		problem FlagProblem
+		name    string
+		got     error
+		out     string
+	}{
+		{BadFlagValue, "port", bad, ""},
+		{BadFlagValue, "port", pflagBad, ""},
+		{BadFlagValue, "p", pflagBad, ""},
+		{AnyFlagProblem, "", fmt.Errorf("parsing: %w", bad), ""},
+		{AnyFlagProblem, "port", bad, ""},
+		{BadFlagValue, "v", bad, sprintf(wrongFlag, bad, "v")},
+		{UnknownFlag, "port", bad, sprintf("got error %q, want unknown flag (got bad flag value)", bad)},
+		{UnknownFlag, "", io.EOF, sprintf(notFlagError, "EOF")},
+		{UnknownFlag, "", nil, sprintf(missing)},
+	} {
+		if s := Error(tt.got, FlagError(tt.problem, tt.name)); s != tt.out {
+			t.Errorf("FlagError(%v, %q) %v: got %q, want %q", tt.problem, tt.name, tt.got, s, tt.out)
+		}
+	}
+	if d := Describe(FlagError(BadFlagValue, "port")); d != `a bad flag value for flag "port"` {
+		t.Errorf("got description %q", d)
+	}
+	if d := Describe(FlagError(AnyFlagProblem, "")); d != "a flag error" {
+		t.Errorf("got description %q", d)
+	}
+	if s := FlagProblem(9).String(); s != "FlagProblem(9)" {
+		t.Errorf("got %q", s)
+	}
+}
@@ -0,0 +1,537 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// A Quoting selects how messages are rendered in failure strings.
+type Quoting int
+
+const (
+	QuoteGo   Quoting = iota // "message", as by %q (the default)
+	QuoteRaw                 // message, unquoted
+	QuoteBack                // `message`, falling back to %q when needed
+)
+
+// Quote returns an Option that renders messages in failures using q.
+// Regardless of q, a message that spans multiple lines is rendered as an
+// indented block following its label.
+func Quote(q Quoting) Option {
+	return func(c *config) { c.quoting = q }
+}
+
+// Width returns an Option that wraps failures to fit in n columns.  A
+// failure longer than n is rendered with each message as an indented block
+// whose lines are wrapped to fit.  If n is TerminalWidth the width is taken
+// from the COLUMNS environment variable, if set.  If n is 0 (the default)
+// failures are never wrapped.
+func Width(n int) Option {
+	return func(c *config) { c.width = n }
+}
+
+// TerminalWidth is the width that, passed to Width, wraps failures to the
+// width of the terminal, as given by the COLUMNS environment variable.
+const TerminalWidth = -1
+
+// A Code classifies a failure.  Codes are stable and are included in
+// failures when the Codes option is set.
+type Code string
+
+const (
+	CodeUnexpected  = Code("CHK-UNEXPECTED")  // got an error when none was wanted
+	CodeMissing     = Code("CHK-MISSING")     // did not get a wanted error
+	CodeWrong       = Code("CHK-WRONG")       // got an error other than the one wanted
+	CodeUnsupported = Code("CHK-UNSUPPORTED") // the want is of an unsupported type
+	CodeTimeout     = Code("CHK-TIMEOUT")     // the check did not complete in time
+)
+
+// formatCodes maps each failure format to its Code.
+var formatCodes = map[string]Code{
+	unexpected: CodeUnexpected,
+	expected:   CodeMissing,
+	missing:    CodeMissing,
+	wrong:      CodeWrong,
+	isNot:      CodeWrong,
+	noMatch:    CodeWrong,
+	badPattern: CodeUnsupported,
+
+	expectedType: CodeMissing,
+	wrongType:    CodeWrong,
+	unwantedType: CodeWrong,
+
+	multiLine:    CodeWrong,
+	emptyMessage: CodeWrong,
+	isRetryable:  CodeWrong,
+	notRetryable: CodeWrong,
+	wrongJSON:    CodeWrong,
+	encodeError:  CodeWrong,
+	invalidJSON:  CodeUnsupported,
+
+	decodeError:   CodeWrong,
+	changedType:   CodeWrong,
+	changedString: CodeWrong,
+	changedCode:   CodeWrong,
+
+	timedOut: CodeTimeout,
+
+	expectedCategory: CodeMissing,
+	wrongCategory:    CodeWrong,
+
+	missingKey: CodeWrong,
+	wrongValue: CodeWrong,
+
+	isFalse: CodeWrong,
+	isTrue:  CodeWrong,
+
+	returnedEarly: CodeWrong,
+	returnedNil:   CodeWrong,
+	notCanceled:   CodeWrong,
+
+	wrongSyscall: CodeWrong,
+	wrongErrno:   CodeWrong,
+
+	wrongErrnoOnly: CodeWrong,
+
+	wrongTemplate:  CodeWrong,
+	missingMention: CodeWrong,
+
+	missingJoined:    CodeMissing,
+	unexpectedJoined: CodeUnexpected,
+
+	unexpectedPanic: CodeUnexpected,
+	wrongPanic:      CodeWrong,
+
+	notStatus: CodeWrong,
+
+	notFlagError: CodeWrong,
+	wrongFlag:    CodeWrong,
+
+	uncovered: CodeMissing,
+
+	looseAny:    CodeWrong,
+	looseMutant: CodeWrong,
+
+	notSatisfied: CodeWrong,
+
+	inconsistentVerb: CodeWrong,
+
+	noErrCode:    CodeWrong,
+	wrongErrCode: CodeWrong,
+
+	notSaved: CodeUnsupported,
+
+	noMethod:     CodeWrong,
+	wrongMethod:  CodeWrong,
+	noImplements: CodeWrong,
+
+	noStack:       CodeWrong,
+	unwantedStack: CodeWrong,
+}
+
+// Codes returns an Option that prefixes each failure with its Code and a
+// colon, e.g.:
+//
+//	CHK-WRONG: got error "Err one", want "Err two"
+func Codes() Option {
+	return func(c *config) { c.codes = true }
+}
+
+// code returns the prefix for a failure classified as code, or "" if the
+// Codes option is not set.
+func (c *config) code(code Code) string {
+	if !c.codes {
+		return ""
+	}
+	return string(code) + ": "
+}
+
+// A Rendering selects how a failure that has both a got and a want message
+// is laid out.
+type Rendering int
+
+const (
+	// RenderInline renders got and want on one line (the default).
+	RenderInline Rendering = iota
+
+	// RenderColumns renders got and want in two aligned columns:
+	//
+	//	got error | want
+	//	Err one   | Err two
+	RenderColumns
+
+	// RenderStacked renders want beneath got with a caret marking the
+	// first difference:
+	//
+	//	got error: Err one
+	//	want:      Err two
+	//	               ^
+	RenderStacked
+)
+
+// Render returns an Option that lays out failures that have both a got and a
+// want message using r.  Failures with a single message are not affected.
+func Render(r Rendering) Option {
+	return func(c *config) { c.rendering = r }
+}
+
+// tabWidth is the number of columns assumed for the tab used to indent
+// blocks.
+const tabWidth = 8
+
+// termWidth returns the number of columns to wrap failures to, or 0 if they
+// should not be wrapped.
+func (c *config) termWidth() int {
+	switch {
+	case c.width > 0:
+		return c.width
+	case c.width != TerminalWidth:
+		return 0
+	}
+	n, err := strconv.Atoi(os.Getenv("COLUMNS"))
+	if err != nil || n <= 0 {
+		return 0
+	}
+	return n
+}
+
+// quote returns s quoted as selected by c.
+func (c *config) quote(s string) string {
+	switch c.quoting {
+	case QuoteRaw:
+		return s
+	case QuoteBack:
+		if strconv.CanBackquote(s) {
+			return "`" + s + "`"
+		}
+	}
+	return strconv.Quote(s)
+}
+
+// failf returns the failure described by format, which must use %q for each
+// of its args and no other verbs.  Each arg is rendered as a string and
+// quoted as selected by c.  If any arg contains a newline, or the failure
+// does not fit in the width selected by c, then each arg is instead written
+// as an indented block following its label, and any text following the last
+// arg is written on its own line, e.g.:
+//
+//	got error:
+//		line one
+//		line two
+//	want:
+//		line one
+func (c *config) failf(format string, args ...interface{}) string {
+	return c.failc(formatCodes[format], format, args...)
+}
+
+// failc is like failf but classifies the failure as code rather than by its
+// format.  It is used for failures whose format is built at run time.
+func (c *config) failc(code Code, format string, args ...interface{}) string {
+	if c.quiet {
+		return quietFailure
+	}
+	return c.code(code) + c.render(format, args...)
+}
+
+// quietFailure is returned for all failures by a quiet config.
+const quietFailure = "failed"
+
+// render returns the failure described by format and args, as described by
+// failf, without its Code.
+func (c *config) render(format string, args ...interface{}) string {
+	parts := strings.Split(format, "%q")
+	msgs := make([]string, len(args))
+	raw := make([]bool, len(args))
+	block := false
+	for i, arg := range args {
+		if d, ok := arg.(description); ok {
+			msgs[i], raw[i] = string(d), true
+		} else {
+			msgs[i] = c.truncated(fmt.Sprint(arg))
+		}
+		if strings.Contains(msgs[i], "\n") {
+			block = true
+		}
+	}
+	width := c.termWidth()
+	if len(msgs) == 2 {
+		labels := []string{label(parts[0]), label(parts[1])}
+		switch c.rendering {
+		case RenderColumns:
+			return sideBySide(labels, msgs, width)
+		case RenderStacked:
+			return stacked(labels, msgs)
+		}
+	}
+	if !block {
+		var b strings.Builder
+		for i, msg := range msgs {
+			b.WriteString(parts[i])
+			if raw[i] {
+				b.WriteString(msg)
+			} else {
+				b.WriteString(c.quote(msg))
+			}
+		}
+		b.WriteString(parts[len(msgs)])
+		if width == 0 || len(msgs) == 0 || utf8.RuneCountInString(b.String()) <= width {
+			return b.String()
+		}
+	}
+	var b strings.Builder
+	for i, msg := range msgs {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		if width > 0 {
+			msg = wrap(msg, width-tabWidth)
+		}
+		b.WriteString(label(parts[i]))
+		b.WriteString(":\n")
+		b.WriteString(indent(msg))
+	}
+	if l := label(parts[len(msgs)]); l != "" {
+		b.WriteByte('\n')
+		b.WriteString(l)
+	}
+	return b.String()
+}
+
+// label returns the label found in part of a format, e.g., "want" from
+// ", want " or "did not compile" from " did not compile: ".
+func label(part string) string {
+	return strings.TrimSuffix(strings.TrimSpace(strings.TrimLeft(part, ",")), ":")
+}
+
+// sideBySide renders msgs in two columns headed by labels.  If width is not 0
+// the messages are wrapped to fit.
+func sideBySide(labels, msgs []string, width int) string {
+	if width > 0 {
+		for i := range msgs {
+			msgs[i] = wrap(msgs[i], (width-3)/2)
+		}
+	}
+	left := append([]string{labels[0]}, strings.Split(msgs[0], "\n")...)
+	right := append([]string{labels[1]}, strings.Split(msgs[1], "\n")...)
+	n := 0
+	for _, line := range left {
+		if w := utf8.RuneCountInString(line); w > n {
+			n = w
+		}
+	}
+	var b strings.Builder
+	for i := 0; i < len(left) || i < len(right); i++ {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		var l, r string
+		if i < len(left) {
+			l = left[i]
+		}
+		if i < len(right) {
+			r = right[i]
+		}
+		b.WriteString(l)
+		b.WriteString(strings.Repeat(" ", n-utf8.RuneCountInString(l)))
+		b.WriteString(" | ")
+		b.WriteString(r)
+	}
+	return strings.TrimRight(b.String(), " ")
+}
+
+// stacked renders msgs one above the other, headed by labels, followed by a
+// line with a caret beneath the first difference.  Multiline messages are
+// rendered as blocks followed by the line and column of the first difference.
+func stacked(labels, msgs []string) string {
+	line, col := firstDiff(msgs[0], msgs[1])
+	if strings.Contains(msgs[0]+msgs[1], "\n") {
+		return sprintf("%s:\n%s\n%s:\n%s\nfirst difference at line %d, column %d",
+			labels[0], indent(msgs[0]), labels[1], indent(msgs[1]), line, col)
+	}
+	n := utf8.RuneCountInString(labels[0])
+	if w := utf8.RuneCountInString(labels[1]); w > n {
+		n = w
+	}
+	n += 2 // for ": "
+	pad := func(s string) string {
+		return s + ":" + strings.Repeat(" ", n-utf8.RuneCountInString(s)-1)
+	}
+	return pad(labels[0]) + msgs[0] + "\n" +
+		pad(labels[1]) + msgs[1] + "\n" +
+		strings.Repeat(" ", n+col-1) + "^"
+}
+
+// firstDiff returns the line and column, both starting at 1, of the first
+// rune that differs between a and b.  The column counts runes.
+func firstDiff(a, b string) (line, col int) {
+	return position(a, diffOffset(a, b, nil))
+}
+
+// diffOffset returns the byte offset in a of the first rune that differs
+// between a and b.  If lower is not nil runes are compared case insensitive,
+// using lower to map them to lower case.
+func diffOffset(a, b string, lower func(string) string) int {
+	off := 0
+	for off < len(a) && len(b) > 0 {
+		ra, na := utf8.DecodeRuneInString(a[off:])
+		rb, nb := utf8.DecodeRuneInString(b)
+		if ra != rb && (lower == nil || lower(string(ra)) != lower(string(rb))) {
+			break
+		}
+		off += na
+		b = b[nb:]
+	}
+	return off
+}
+
+// position returns the line and column, both starting at 1, of the byte
+// offset off in s.  The column counts runes.
+func position(s string, off int) (line, col int) {
+	s = s[:off]
+	line = strings.Count(s, "\n") + 1
+	if i := strings.LastIndex(s, "\n"); i >= 0 {
+		s = s[i+1:]
+	}
+	return line, utf8.RuneCountInString(s) + 1
+}
+
+// Caret returns an Option that adds the location of the first difference,
+// and a caret marking it, to failures of Equal and CaseEqual checks:
+//
+//	got error "Err one", want "Err two"
+//	first difference at byte 4 (rune 4):
+//		Err one
+//		Err two
+//		    ^
+//
+// Caret also adds the location of a near match, text that contains the
+// wanted text when case, white space, and punctuation are ignored, to
+// failures of string and Case checks:
+//
+//	got error "connection Time-Out", want "timeout"
+//	near match at byte 11 (rune 11): "Time-Out"
+func Caret() Option {
+	return func(c *config) { c.showCaret = true }
+}
+
+// caret returns the text added by the Caret option to a failure comparing
+// got and want, or "" if the option is not set.  If fold is true the
+// comparison was case insensitive.
+func (c *config) caret(got, want string, fold bool) string {
+	if !c.showCaret || (c.rendering == RenderStacked && !strings.Contains(got+want, "\n")) {
+		return ""
+	}
+	var lower func(string) string
+	if fold {
+		lower = c.toLower
+	}
+	off := diffOffset(got, want, lower)
+	line, col := position(got, off)
+	lineOf := func(s string) string {
+		lines := strings.Split(s, "\n")
+		if line > len(lines) {
+			return ""
+		}
+		return lines[line-1]
+	}
+	where := sprintf("byte %d (rune %d)", off, utf8.RuneCountInString(got[:off]))
+	if line > 1 {
+		where += sprintf(", line %d", line)
+	}
+	return sprintf("\nfirst difference at %s:\n\t%s\n\t%s\n\t%s^",
+		where, lineOf(got), lineOf(want), strings.Repeat(" ", col-1))
+}
+
+// nearMatch returns a note locating the text of got that contains want when
+// case, white space, and punctuation are ignored, such as "Time-Out" for the
+// want "timeout", or "" if there is no such text or the Caret option is not
+// set.
+func (c *config) nearMatch(got, want string) string {
+	if !c.showCaret || c.quiet {
+		return ""
+	}
+	var w []string
+	for _, r := range want {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			w = append(w, c.toLower(string(r)))
+		}
+	}
+	if len(w) == 0 {
+		return ""
+	}
+	// g holds the significant runes of got, and offs their byte offsets.
+	var g []string
+	var offs []int
+	for off, r := range got {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			g = append(g, c.toLower(string(r)))
+			offs = append(offs, off)
+		}
+	}
+Search:
+	for i := 0; i+len(w) <= len(g); i++ {
+		for j := range w {
+			if g[i+j] != w[j] {
+				continue Search
+			}
+		}
+		start := offs[i]
+		last := offs[i+len(w)-1]
+		_, n := utf8.DecodeRuneInString(got[last:])
+		where := sprintf("byte %d (rune %d)", start, utf8.RuneCountInString(got[:start]))
+		if line, _ := position(got, start); line > 1 {
+			where += sprintf(", line %d", line)
+		}
+		return sprintf("\nnear match at %s: %q", where, got[start:last+n])
+	}
+	return ""
+}
+
+// wrap returns s with each of its lines broken into lines of at most n
+// runes.  Lines are broken at the last space that fits, if any.
+func wrap(s string, n int) string {
+	if n < 1 {
+		n = 1
+	}
+	lines := strings.Split(s, "\n")
+	var out []string
+	for _, line := range lines {
+		for utf8.RuneCountInString(line) > n {
+			r := []rune(line)
+			cut := n
+			for i := n; i > 0; i-- {
+				if r[i] == ' ' {
+					cut = i
+					break
+				}
+			}
+			out = append(out, strings.TrimRight(string(r[:cut]), " "))
+			line = strings.TrimLeft(string(r[cut:]), " ")
+		}
+		out = append(out, line)
+	}
+	return strings.Join(out, "\n")
+}
+
+// indent returns s with each line prefixed by a tab.
+func indent(s string) string {
+	return "\t" + strings.Replace(strings.TrimRight(s, "\n"), "\n", "\n\t", -1)
+}
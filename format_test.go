@@ -0,0 +1,383 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+// setDefaults replaces the default configuration with one built from opts
+// for the duration of t.  COLUMNS is unset so failures are not wrapped unless
+// requested by opts.
+func setDefaults(t *testing.T, opts ...Option) {
+	setenv(t, "COLUMNS", "")
+	saved := defaults()
+	settings.Store(&config{})
+	SetDefaults(opts...)
+	t.Cleanup(func() { settings.Store(saved) })
+}
+
+func TestQuote(t *testing.T) {
+	err1 := errors.New(`Err "one"`)
+	err2 := errors.New("Err `two`")
+	multi := errors.New("line one\nline two\n")
+
+	for _, tt := range []struct {
+		name  string
+		quote Quoting
+		got   error
+		want  interface{}
+		out   string
+	}{
+		{
+			name: "go",
+			got:  err1,
+			want: err2,
+			out:  "got error \"Err \\\"one\\\"\", want \"Err `two`\"",
+		}, {
+			name:  "raw",
+			quote: QuoteRaw,
+			got:   err1,
+			want:  err2,
+			out:   "got error Err \"one\", want Err `two`",
+		}, {
+			name:  "back",
+			quote: QuoteBack,
+			got:   err1,
+			want:  err2,
+			out:   "got error `Err \"one\"`, want \"Err `two`\"",
+		}, {
+			name:  "back unexpected",
+			quote: QuoteBack,
+			got:   err1,
+			out:   "got unexpected error `Err \"one\"`",
+		}, {
+			name: "block",
+			got:  multi,
+			want: "line three",
+			out:  "got error:\n\tline one\n\tline two\nwant:\n\tline three",
+		}, {
+			name:  "raw block",
+			quote: QuoteRaw,
+			got:   multi,
+			out:   "got unexpected error:\n\tline one\n\tline two",
+		}, {
+			name: "block expected",
+			want: Equal("line one\nline two"),
+			out:  "did not get expected error:\n\tline one\n\tline two",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			setDefaults(t, Quote(tt.quote))
+			if s := Error(tt.got, tt.want); s != tt.out {
+				t.Errorf("got %q, want %q", s, tt.out)
+			}
+		})
+	}
+}
+
+func TestBlockLabels(t *testing.T) {
+	setDefaults(t, Width(60))
+	want := "pattern:\n\tErr (\ndid not compile:\n\terror parsing regexp: missing closing ): `Err (`"
+	if s := Error(errors.New("Err"), Regexp("Err (")); s != want {
+		t.Errorf("got %q, want %q", s, want)
+	}
+}
+
+func TestWidth(t *testing.T) {
+	long := errors.New("the quick brown fox jumps over the lazy dog")
+	for _, tt := range []struct {
+		name    string
+		width   int
+		columns string
+		out     string
+	}{
+		{
+			name:  "fits",
+			width: 80,
+			out:   `got unexpected error "the quick brown fox jumps over the lazy dog"`,
+		}, {
+			name:    "never",
+			columns: "32",
+			out:     `got unexpected error "the quick brown fox jumps over the lazy dog"`,
+		}, {
+			name:  "wrapped",
+			width: 32,
+			out:   "got unexpected error:\n\tthe quick brown fox\n\tjumps over the lazy dog",
+		}, {
+			name:    "columns",
+			width:   TerminalWidth,
+			columns: "32",
+			out:     "got unexpected error:\n\tthe quick brown fox\n\tjumps over the lazy dog",
+		}, {
+			name:    "bad columns",
+			width:   TerminalWidth,
+			columns: "wide",
+			out:     `got unexpected error "the quick brown fox jumps over the lazy dog"`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			setDefaults(t, Width(tt.width))
+			setenv(t, "COLUMNS", tt.columns)
+			if s := Error(long, nil); s != tt.out {
+				t.Errorf("got %q, want %q", s, tt.out)
+			}
+		})
+	}
+}
+
+func TestWrap(t *testing.T) {
+	for _, tt := range []struct {
+		in  string
+		n   int
+		out string
+	}{
+		{"", 5, ""},
+		{"abc", 5, "abc"},
+		{"abcdefgh", 3, "abc\ndef\ngh"},
+		{"ab cd ef", 5, "ab cd\nef"},
+		{"ab\ncd ef gh", 5, "ab\ncd ef\ngh"},
+		{"日本語 日本語", 4, "日本語\n日本語"},
+	} {
+		if out := wrap(tt.in, tt.n); out != tt.out {
+			t.Errorf("wrap(%q, %d) got %q, want %q", tt.in, tt.n, out, tt.out)
+		}
+	}
+}
+
+// setenv sets the environment variable key to value, or unsets it if value
+// is empty, for the duration of t.
+func setenv(t *testing.T, key, value string) {
+	saved, ok := os.LookupEnv(key)
+	if value == "" {
+		os.Unsetenv(key)
+	} else {
+		os.Setenv(key, value)
+	}
+	t.Cleanup(func() {
+		if ok {
+			os.Setenv(key, saved)
+		} else {
+			os.Unsetenv(key)
+		}
+	})
+}
+
+func TestRender(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		render Rendering
+		width  int
+		got    error
+		want   interface{}
+		out    string
+	}{
+		{
+			name:   "inline",
+			render: RenderInline,
+			got:    errors.New("Err one"),
+			want:   "Err two",
+			out:    `got error "Err one", want "Err two"`,
+		}, {
+			name:   "columns",
+			render: RenderColumns,
+			got:    errors.New("Err one"),
+			want:   "Err two",
+			out:    "got error | want\nErr one   | Err two",
+		}, {
+			name:   "columns multiline",
+			render: RenderColumns,
+			got:    errors.New("a long line\nshort"),
+			want:   "a\nb\nc",
+			out:    "got error   | want\na long line | a\nshort       | b\n            | c",
+		}, {
+			name:   "columns wrapped",
+			render: RenderColumns,
+			width:  19,
+			got:    errors.New("abc def ghi"),
+			want:   Equal("abc"),
+			out:    "got error | want\nabc def   | abc\nghi       |",
+		}, {
+			name:   "columns single",
+			render: RenderColumns,
+			got:    errors.New("Err one"),
+			out:    `got unexpected error "Err one"`,
+		}, {
+			name:   "stacked",
+			render: RenderStacked,
+			got:    errors.New("Err one"),
+			want:   "Err two",
+			out:    "got error: Err one\nwant:      Err two\n               ^",
+		}, {
+			name:   "stacked prefix",
+			render: RenderStacked,
+			got:    errors.New("Err"),
+			want:   Equal("Err one"),
+			out:    "got error: Err\nwant:      Err one\n              ^",
+		}, {
+			name:   "stacked multiline",
+			render: RenderStacked,
+			got:    errors.New("one\ntwo"),
+			want:   "one\nTwo",
+			out:    "got error:\n\tone\n\ttwo\nwant:\n\tone\n\tTwo\nfirst difference at line 2, column 1",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			setDefaults(t, Render(tt.render), Width(tt.width))
+			if s := Error(tt.got, tt.want); s != tt.out {
+				t.Errorf("got %q, want %q", s, tt.out)
+			}
+		})
+	}
+}
+
+func TestCaret(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		opts []Option
+		got  error
+		want interface{}
+		out  string
+	}{
+		{
+			name: "off",
+			got:  errors.New("Err one"),
+			want: Equal("Err two"),
+			out:  `got error "Err one", want "Err two"`,
+		}, {
+			name: "equal",
+			opts: []Option{Caret()},
+			got:  errors.New("Err one"),
+			want: Equal("Err two"),
+			out:  "got error \"Err one\", want \"Err two\"\nfirst difference at byte 4 (rune 4):\n\tErr one\n\tErr two\n\t    ^",
+		}, {
+			name: "case equal",
+			opts: []Option{Caret()},
+			got:  errors.New("ÀB one"),
+			want: CaseEqual("àb two"),
+			out:  "got error \"ÀB one\", want \"àb two\"\nfirst difference at byte 4 (rune 3):\n\tÀB one\n\tàb two\n\t   ^",
+		}, {
+			name: "multiline",
+			opts: []Option{Caret()},
+			got:  errors.New("one\ntwo"),
+			want: Equal("one\nTwo"),
+			out:  "got error:\n\tone\n\ttwo\nwant:\n\tone\n\tTwo\nfirst difference at byte 4 (rune 4), line 2:\n\ttwo\n\tTwo\n\t^",
+		}, {
+			name: "stacked",
+			opts: []Option{Caret(), Render(RenderStacked)},
+			got:  errors.New("Err one"),
+			want: Equal("Err two"),
+			out:  "got error: Err one\nwant:      Err two\n               ^",
+		}, {
+			name: "contains",
+			opts: []Option{Caret()},
+			got:  errors.New("Err one"),
+			want: "two",
+			out:  `got error "Err one", want "two"`,
+		}, {
+			name: "near match",
+			opts: []Option{Caret()},
+			got:  errors.New("connection Time-Out"),
+			want: "timeout",
+			out:  "got error \"connection Time-Out\", want \"timeout\"\nnear match at byte 11 (rune 11): \"Time-Out\"",
+		}, {
+			name: "near match off",
+			got:  errors.New("connection Time-Out"),
+			want: "timeout",
+			out:  `got error "connection Time-Out", want "timeout"`,
+		}, {
+			name: "near match runes",
+			opts: []Option{Caret()},
+			got:  errors.New("größe: not-found"),
+			want: "Not Found",
+			out:  "got error \"größe: not-found\", want \"Not Found\"\nnear match at byte 9 (rune 7): \"not-found\"",
+		}, {
+			name: "near match case",
+			opts: []Option{Caret()},
+			got:  errors.New("no such file"),
+			want: Case("SUCHFILE"),
+			out:  "got error \"no such file\", want \"SUCHFILE\"\nnear match at byte 3 (rune 3): \"such file\"",
+		}, {
+			name: "near match line",
+			opts: []Option{Caret()},
+			got:  errors.New("one\ntwo, three"),
+			want: "two three",
+			out:  "got error:\n\tone\n\ttwo, three\nwant:\n\ttwo three\nnear match at byte 4 (rune 4), line 2: \"two, three\"",
+		}, {
+			name: "punctuation want",
+			opts: []Option{Caret()},
+			got:  errors.New("a: b"),
+			want: "::",
+			out:  `got error "a: b", want "::"`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			setDefaults(t, tt.opts...)
+			if s := Error(tt.got, tt.want); s != tt.out {
+				t.Errorf("got %q, want %q", s, tt.out)
+			}
+		})
+	}
+}
+
+func TestCodes(t *testing.T) {
+	err1 := errors.New("Err one")
+	for _, tt := range []struct {
+		name string
+		got  error
+		want interface{}
+		out  string
+	}{
+		{
+			name: "pass",
+			got:  err1,
+			want: err1,
+		}, {
+			name: "unexpected",
+			got:  err1,
+			out:  `CHK-UNEXPECTED: got unexpected error "Err one"`,
+		}, {
+			name: "missing",
+			want: "Err one",
+			out:  `CHK-MISSING: did not get expected error "Err one"`,
+		}, {
+			name: "missing bool",
+			want: true,
+			out:  `CHK-MISSING: did not get expected error`,
+		}, {
+			name: "wrong",
+			got:  err1,
+			want: Equal("Err two"),
+			out:  `CHK-WRONG: got error "Err one", want "Err two"`,
+		}, {
+			name: "unsupported",
+			want: 1,
+			out:  `CHK-UNSUPPORTED: Check does not support type int`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			setDefaults(t, Codes())
+			if s := Error(tt.got, tt.want); s != tt.out {
+				t.Errorf("got %q, want %q", s, tt.out)
+			}
+		})
+	}
+	setDefaults(t, Codes())
+	if s, out := IsError(nil, err1), `CHK-MISSING: did not get expected error "Err one"`; s != out {
+		t.Errorf("IsError got %q, want %q", s, out)
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import "regexp"
+
+// A MatchKind is how a check compared an error to its want.
+type MatchKind string
+
+// The kinds of matches.
+const (
+	MatchNone        = MatchKind("none")        // want no error (nil or false)
+	MatchAny         = MatchKind("any")         // want any error (true)
+	MatchContains    = MatchKind("contains")    // a string want
+	MatchCase        = MatchKind("case")        // a Case want
+	MatchEqual       = MatchKind("equal")       // an Equal want
+	MatchCaseEqual   = MatchKind("case-equal")  // a CaseEqual want
+	MatchRegexp      = MatchKind("regexp")      // a Regexp want
+	MatchIdentity    = MatchKind("identity")    // an error want, matched by identity
+	MatchIs          = MatchKind("is")          // an error matched by errors.Is, as by Is
+	MatchAs          = MatchKind("as")          // a *T want, matched by errors.As
+	MatchMatcher     = MatchKind("matcher")     // a Matcher or CustomMatcher want
+	MatchPredicate   = MatchKind("predicate")   // a func(error) bool or func(error) string want
+	MatchUnsupported = MatchKind("unsupported") // a want of an unsupported type
+)
+
+// A Mismatch describes a failed check to a Formatter.
+type Mismatch struct {
+	Check       string      // the check, "Error" or "Is"
+	Kind        MatchKind   // how got was compared to want
+	Code        Code        // the Code of the failure
+	Got         error       // the error checked
+	Want        interface{} // the want
+	Description string      // the description of want, as by Describe
+	Message     string      // the failure as rendered by default, without its Code
+}
+
+// A Formatter renders the failures of checks, replacing the default
+// rendering, such as "got error "EOF", want "timeout"".  A Formatter must be
+// safe for concurrent use.
+type Formatter interface {
+	Format(m Mismatch) string
+}
+
+// A FormatterFunc is a function that is a Formatter.
+type FormatterFunc func(m Mismatch) string
+
+// Format returns f(m).
+func (f FormatterFunc) Format(m Mismatch) string { return f(m) }
+
+// formatter holds a Formatter so a config remains comparable.
+type formatter struct{ f Formatter }
+
+// UseFormatter returns an Option that renders the failures of Error and Is,
+// and of the checks made with them, such as by a Checker, with f, e.g., as
+// JSON or in another language.  The Codes option, if set, still prefixes the
+// rendered failure with its Code.  UseFormatter(nil) restores the default
+// rendering.
+func UseFormatter(f Formatter) Option {
+	return func(c *config) {
+		if f == nil {
+			c.formatter = nil
+			return
+		}
+		c.formatter = &formatter{f: f}
+	}
+}
+
+// SetFormatter sets the Formatter used by default, as by
+// SetDefaults(UseFormatter(f)).
+func SetFormatter(f Formatter) {
+	SetDefaults(UseFormatter(f))
+}
+
+// format returns the failure of check, the result of calling check with a copy
+// of c without its Formatter, rendered by the Formatter of c.  name is the
+// name of the check.
+func (c *config) format(name string, kind MatchKind, got error, want interface{}, check func(*config) string) string {
+	nc := *c
+	nc.formatter = nil
+	nc.codes = true
+	s := check(&nc)
+	if s == "" || c.quiet {
+		return s
+	}
+	var code Code
+	if loc := codePrefix.FindStringIndex(s); loc != nil && loc[0] == 0 {
+		code, s = Code(s[:loc[1]-2]), s[loc[1]:]
+	}
+	if !c.codes {
+		s = nestedCode.ReplaceAllString(s, "$1")
+	}
+	s = c.formatter.f.Format(Mismatch{
+		Check:       name,
+		Kind:        kind,
+		Code:        code,
+		Got:         got,
+		Want:        want,
+		Description: Describe(want),
+		Message:     s,
+	})
+	if c.codes && code != "" {
+		s = string(code) + ": " + s
+	}
+	return s
+}
+
+// nestedCode matches the Codes of the failures nested, and indented, in a
+// failure.
+var nestedCode = regexp.MustCompile(`(?m)^(\t*)CHK-[A-Z]+: `)
+
+// matchKind returns how Error compares an error to want.
+func matchKind(want interface{}) MatchKind {
+	switch w := want.(type) {
+	case nil:
+		return MatchNone
+	case bool:
+		if w {
+			return MatchAny
+		}
+		return MatchNone
+	case Matcher, CustomMatcher:
+		return MatchMatcher
+	case func(error) bool, func(error) string:
+		return MatchPredicate
+	case string:
+		if w == "" {
+			return MatchNone
+		}
+		return MatchContains
+	case Case:
+		return MatchCase
+	case Equal:
+		return MatchEqual
+	case CaseEqual:
+		return MatchCaseEqual
+	case Regexp:
+		return MatchRegexp
+	case error:
+		return MatchIdentity
+	}
+	if asTarget(want) != nil {
+		return MatchAs
+	}
+	return MatchUnsupported
+}
@@ -0,0 +1,88 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+)
+
+// kindFormatter renders a Mismatch as its kind, code, got, and description.
+var kindFormatter = FormatterFunc(func(m Mismatch) string {
+	got := "nil"
+	if m.Got != nil {
+		got = m.Got.Error()
+	}
+	return fmt.Sprintf("%s %s [%s] got=%s want=%s", m.Check, m.Kind, m.Code, got, m.Description)
+})
+
+func TestFormatter(t *testing.T) {
+	setDefaults(t, UseFormatter(kindFormatter))
+	var perr *os.PathError
+	for _, tt := range []struct {
+		got  error
+		want interface{}
+		out  string
+	}{
+		{io.EOF, "EOF", ""},
+		{io.EOF, nil, "Error none [CHK-UNEXPECTED] got=EOF want=no error"},
+		{nil, true, "Error any [CHK-MISSING] got=nil want=any error"},
+		{io.EOF, "timeout", `Error contains [CHK-WRONG] got=EOF want=contains "timeout"`},
+		{io.EOF, Case("x"), `Error case [CHK-WRONG] got=EOF want=contains "x", case insensitive`},
+		{io.EOF, Equal("x"), `Error equal [CHK-WRONG] got=EOF want=is "x"`},
+		{io.EOF, CaseEqual("x"), `Error case-equal [CHK-WRONG] got=EOF want=is "x", case insensitive`},
+		{io.EOF, Regexp("^x"), `Error regexp [CHK-WRONG] got=EOF want=matches pattern "^x"`},
+		{io.EOF, io.ErrUnexpectedEOF, `Error identity [CHK-WRONG] got=EOF want=is the error "unexpected EOF" (*errors.errorString)`},
+		{io.EOF, &perr, "Error as [CHK-WRONG] got=EOF want=error of type *fs.PathError"},
+		{io.EOF, MaxLen(1), "Error matcher [CHK-WRONG] got=EOF want=a message of at most 1 runes"},
+		{io.EOF, func(error) bool { return false }, "Error predicate [CHK-WRONG] got=EOF want=satisfies a func(error) bool"},
+		{io.EOF, 1, "Error unsupported [CHK-UNSUPPORTED] got=EOF want=unsupported want of type int"},
+		{io.EOF, Any("a", "b"), `Error matcher [CHK-WRONG] got=EOF want=contains "a" OR contains "b"`},
+	} {
+		if s := Error(tt.got, tt.want); s != tt.out {
+			t.Errorf("Error(%v, %#v): got %q, want %q", tt.got, tt.want, s, tt.out)
+		}
+	}
+	if s := Is(io.EOF, io.ErrUnexpectedEOF); s != `Is is [CHK-WRONG] got=EOF want=is the error "unexpected EOF" (*errors.errorString)` {
+		t.Errorf("Is: got %q", s)
+	}
+	if s := NewChecker(UseFormatter(nil)).Error(io.EOF, "x").String(); s != sprintf(wrong, io.EOF, "x") {
+		t.Errorf("UseFormatter(nil): got %q", s)
+	}
+	ck := NewChecker(Codes()).Child("db")
+	if s := ck.Error(io.EOF, nil).String(); s != "CHK-UNEXPECTED: db: Error none [CHK-UNEXPECTED] got=EOF want=no error" {
+		t.Errorf("Checker: got %q", s)
+	}
+}
+
+func TestFormatterMessage(t *testing.T) {
+	var got Mismatch
+	setDefaults(t, UseFormatter(FormatterFunc(func(m Mismatch) string {
+		got = m
+		b, _ := json.Marshal(map[string]string{"kind": string(m.Kind), "message": m.Message})
+		return string(b)
+	})))
+	err := errors.New("boom")
+	if s := Error(err, Any("x", "y")); s != `{"kind":"matcher","message":"no want matched:\n\tgot error \"boom\", want \"x\"\n\tgot error \"boom\", want \"y\""}` {
+		t.Errorf("got %s", s)
+	}
+	if got.Got != err || got.Code != CodeWrong {
+		t.Errorf("got mismatch %+v", got)
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+type similar struct {
+	want      string
+	threshold float64
+}
+
+// Similar returns a Matcher that matches an error whose message is similar to
+// want.  The similarity of two messages is 1 minus their edit distance
+// divided by the length, in runes, of the longer message.  The error matches
+// if its similarity is at least threshold, which should be between 0 and 1.
+// On failure the computed similarity is reported.
+func Similar(want string, threshold float64) Matcher {
+	return similar{want: want, threshold: threshold}
+}
+
+func (s similar) match(c *config, got error) string {
+	if got == nil {
+		return c.failf(expected, s.want)
+	}
+	if score := similarity(got.Error(), s.want); score < s.threshold {
+		return c.failf(wrong, got, s.want) + sprintf(" (similarity %.2f < %.2f)", score, s.threshold)
+	}
+	return ""
+}
+
+// similarity returns 1 minus the edit distance between a and b divided by
+// the length of the longer of a and b, in runes.
+func similarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	n := len(ra)
+	if len(rb) > n {
+		n = len(rb)
+	}
+	if n == 0 {
+		return 1
+	}
+	return 1 - float64(distance(ra, rb))/float64(n)
+}
+
+// distance returns the Levenshtein edit distance between a and b.
+func distance(a, b []rune) int {
+	row := make([]int, len(b)+1)
+	for j := range row {
+		row[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		diag := row[0]
+		row[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			next := min3(row[j]+1, row[j-1]+1, diag+cost)
+			diag, row[j] = row[j], next
+		}
+	}
+	return row[len(b)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
+
+type words struct {
+	want      string
+	tolerance int
+}
+
+// Words returns a Matcher that matches an error whose message has the same
+// words as want, in any order.  A word is a run of letters and digits.  Words
+// are compared as multisets, so repeated words must appear as many times in
+// each.  The error matches if no more than tolerance words are missing from
+// or extra in its message.  On failure the missing and extra words are
+// reported.
+func Words(want string, tolerance int) Matcher {
+	return words{want: want, tolerance: tolerance}
+}
+
+func (w words) match(c *config, got error) string {
+	if got == nil {
+		return c.failf(expected, w.want)
+	}
+	missing, extra := wordDiff(got.Error(), w.want)
+	if len(missing)+len(extra) <= w.tolerance {
+		return ""
+	}
+	var notes []string
+	if len(missing) > 0 {
+		notes = append(notes, sprintf("missing %q", missing))
+	}
+	if len(extra) > 0 {
+		notes = append(notes, sprintf("extra %q", extra))
+	}
+	return c.failf(wrong, got, w.want) + " (" + strings.Join(notes, ", ") + ")"
+}
+
+// wordDiff returns the words of want missing from got and the words of got
+// not in want, each sorted.
+func wordDiff(got, want string) (missing, extra []string) {
+	counts := map[string]int{}
+	for _, w := range splitWords(want) {
+		counts[w]++
+	}
+	for _, w := range splitWords(got) {
+		if counts[w] > 0 {
+			counts[w]--
+		} else {
+			extra = append(extra, w)
+		}
+	}
+	for w, n := range counts {
+		for ; n > 0; n-- {
+			missing = append(missing, w)
+		}
+	}
+	sort.Strings(missing)
+	sort.Strings(extra)
+	return missing, extra
+}
+
+// splitWords returns the runs of letters and digits in s.
+func splitWords(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
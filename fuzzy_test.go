@@ -0,0 +1,107 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDistance(t *testing.T) {
+	for _, tt := range []struct {
+		a, b string
+		d    int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"", "abc", 3},
+		{"kitten", "sitting", 3},
+		{"flaw", "lawn", 2},
+		{"日本", "日本語", 1},
+	} {
+		if d := distance([]rune(tt.a), []rune(tt.b)); d != tt.d {
+			t.Errorf("distance(%q, %q) got %d, want %d", tt.a, tt.b, d, tt.d)
+		}
+	}
+}
+
+func TestSimilar(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		got  error
+		want Matcher
+		out  string
+	}{
+		{
+			name: "exact",
+			got:  errors.New("file not found"),
+			want: Similar("file not found", 1),
+		}, {
+			name: "close",
+			got:  errors.New("file was not found"),
+			want: Similar("file not found", 0.75),
+		}, {
+			name: "not close",
+			got:  errors.New("permission denied"),
+			want: Similar("file not found", 0.75),
+			out:  `got error "permission denied", want "file not found" (similarity 0.18 < 0.75)`,
+		}, {
+			name: "missing",
+			want: Similar("file not found", 0.75),
+			out:  `did not get expected error "file not found"`,
+		},
+	} {
+		if s := Error(tt.got, tt.want); s != tt.out {
+			t.Errorf("%s: got %q, want %q", tt.name, s, tt.out)
+		}
+	}
+}
+
+func TestWords(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		got  error
+		want Matcher
+		out  string
+	}{
+		{
+			name: "same",
+			got:  errors.New("connection refused: dial tcp"),
+			want: Words("dial tcp: connection refused", 0),
+		}, {
+			name: "tolerated",
+			got:  errors.New("dial tcp: connection was refused"),
+			want: Words("dial tcp: connection refused", 1),
+		}, {
+			name: "repeated",
+			got:  errors.New("no no"),
+			want: Words("no", 0),
+			out:  `got error "no no", want "no" (extra ["no"])`,
+		}, {
+			name: "different",
+			got:  errors.New("dial udp: connection was refused"),
+			want: Words("dial tcp: connection refused", 1),
+			out:  `got error "dial udp: connection was refused", want "dial tcp: connection refused" (missing ["tcp"], extra ["udp" "was"])`,
+		}, {
+			name: "missing",
+			want: Words("refused", 0),
+			out:  `did not get expected error "refused"`,
+		},
+	} {
+		if s := Error(tt.got, tt.want); s != tt.out {
+			t.Errorf("%s: got %q, want %q", tt.name, s, tt.out)
+		}
+	}
+}
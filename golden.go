@@ -0,0 +1,79 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// goldenDir is the directory holding golden files.
+var goldenDir = "testdata"
+
+type golden struct{ name string }
+
+// Golden returns a Matcher that matches an error whose message is the
+// contents of the golden file testdata/name, less a single trailing newline.
+// Golden files suit the long, multi-line messages of parsers and validators,
+// which are painful to embed in table literals:
+//
+//	check.Error(err, check.Golden("bad-schema.golden"))
+//
+// When the -check.update flag is set a missing or mismatched golden file is
+// not a failure, rather it is written with the message of the error checked.
+// A nil error is never matched.
+func Golden(name string) Matcher {
+	return golden{name: name}
+}
+
+// path returns the path of the golden file of m.
+func (m golden) path() string {
+	return filepath.Join(goldenDir, filepath.FromSlash(m.name))
+}
+
+func (m golden) match(c *config, got error) string {
+	if got == nil {
+		return c.failf(missing)
+	}
+	path := m.path()
+	data, err := ioutil.ReadFile(path)
+	switch {
+	case err == nil:
+		s := c.checkError(got, Equal(strings.TrimSuffix(string(data), "\n")))
+		if s == "" || !*updateFlag {
+			return s
+		}
+	case *updateFlag && os.IsNotExist(err):
+	case c.quiet:
+		return quietFailure
+	case os.IsNotExist(err):
+		return c.failc(CodeMissing, "missing golden file %q (run with -check.update to create it)", path)
+	default:
+		return c.failc(CodeUnsupported, "reading golden file: %q", err)
+	}
+	err = os.MkdirAll(filepath.Dir(path), 0755)
+	if err == nil {
+		err = ioutil.WriteFile(path, []byte(message(got)+"\n"), 0644)
+	}
+	if err != nil {
+		if c.quiet {
+			return quietFailure
+		}
+		return c.failc(CodeUnsupported, "updating golden file: %q", err)
+	}
+	return ""
+}
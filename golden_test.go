@@ -0,0 +1,112 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// setGoldenDir sets goldenDir to dir until t completes.
+func setGoldenDir(t *testing.T, dir string) {
+	old := goldenDir
+	goldenDir = dir
+	t.Cleanup(func() { goldenDir = old })
+}
+
+func TestGolden(t *testing.T) {
+	setDefaults(t)
+	dir := t.TempDir()
+	setGoldenDir(t, dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, "eof.golden"), []byte("EOF\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "lines.golden"), []byte("line 1: bad\nline 2: bad"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	missingPath := filepath.Join(dir, "missing.golden")
+	for _, tt := range []struct {
+		name string
+		got  error
+		out  string
+	}{
+		{"eof.golden", io.EOF, ""},
+		{"lines.golden", errors.New("line 1: bad\nline 2: bad"), ""},
+		{"eof.golden", io.ErrUnexpectedEOF, sprintf(wrong, io.ErrUnexpectedEOF, "EOF")},
+		{"eof.golden", nil, sprintf(missing)},
+		{"missing.golden", io.EOF, sprintf(`missing golden file %q (run with -check.update to create it)`, missingPath)},
+	} {
+		if s := Error(tt.got, Golden(tt.name)); s != tt.out {
+			t.Errorf("%s, %v: got %q, want %q", tt.name, tt.got, s, tt.out)
+		}
+	}
+	if s := Describe(Golden("eof.golden")); s != sprintf("is the contents of golden file %q", filepath.Join(dir, "eof.golden")) {
+		t.Errorf("Describe: got %q", s)
+	}
+	if Matches(io.EOF, Golden("missing.golden")) {
+		t.Errorf("Matches of a missing golden file")
+	}
+}
+
+func TestGoldenUpdate(t *testing.T) {
+	setDefaults(t)
+	dir := t.TempDir()
+	setGoldenDir(t, dir)
+	*updateFlag = true
+	defer func() { *updateFlag = false }()
+	path := filepath.Join(dir, "eof.golden")
+	if err := ioutil.WriteFile(path, []byte("EOF\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		name string
+		got  error
+		out  string
+	}{
+		{"eof.golden", io.EOF, ""},
+		{"eof.golden", io.ErrUnexpectedEOF, ""},
+		{"sub/new.golden", errors.New("a\nb"), ""},
+		{"eof.golden", nil, sprintf(missing)},
+	} {
+		if s := Error(tt.got, Golden(tt.name)); s != tt.out {
+			t.Errorf("%s, %v: got %q, want %q", tt.name, tt.got, s, tt.out)
+		}
+	}
+	for name, want := range map[string]string{
+		"eof.golden":     "unexpected EOF\n",
+		"sub/new.golden": "a\nb\n",
+	} {
+		data, err := ioutil.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil || string(data) != want {
+			t.Errorf("%s: got %q, %v, want %q", name, data, err, want)
+		}
+	}
+	*updateFlag = false
+	if s := Error(errors.New("a\nb"), Golden("sub/new.golden")); s != "" {
+		t.Errorf("updated golden file: %s", s)
+	}
+	if err := os.Chmod(dir, 0555); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chmod(dir, 0755)
+	*updateFlag = true
+	if s := Error(io.EOF, Golden("readonly/x.golden")); s == "" && os.Getuid() != 0 {
+		t.Errorf("update of unwritable golden file succeeded")
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import "reflect"
+
+// gRPC status codes, from google.golang.org/grpc/codes.  They are duplicated
+// here so this package does not depend on gRPC.
+const (
+	grpcCanceled           = 1
+	grpcInvalidArgument    = 3
+	grpcDeadlineExceeded   = 4
+	grpcNotFound           = 5
+	grpcAlreadyExists      = 6
+	grpcPermissionDenied   = 7
+	grpcResourceExhausted  = 8
+	grpcFailedPrecondition = 9
+	grpcAborted            = 10
+	grpcOutOfRange         = 11
+	grpcUnavailable        = 14
+	grpcUnauthenticated    = 16
+)
+
+// grpcCodeNames are the names of the gRPC status codes, indexed by code.
+var grpcCodeNames = []string{
+	"OK", "Canceled", "Unknown", "InvalidArgument", "DeadlineExceeded",
+	"NotFound", "AlreadyExists", "PermissionDenied", "ResourceExhausted",
+	"FailedPrecondition", "Aborted", "OutOfRange", "Unimplemented",
+	"Internal", "Unavailable", "DataLoss", "Unauthenticated",
+}
+
+// grpcCodeName returns the name of the gRPC status code, e.g., "NotFound".
+func grpcCodeName(code uint32) string {
+	if int(code) < len(grpcCodeNames) {
+		return grpcCodeNames[code]
+	}
+	return sprintf("Code(%d)", code)
+}
+
+// grpcStatusOf returns the non-nil value returned by the GRPCStatus method of
+// err, as used by google.golang.org/grpc/status, if err has one.
+func grpcStatusOf(err error) (reflect.Value, bool) {
+	m := reflect.ValueOf(err).MethodByName("GRPCStatus")
+	if !m.IsValid() || m.Type().NumIn() != 0 || m.Type().NumOut() != 1 {
+		return reflect.Value{}, false
+	}
+	s := m.Call(nil)[0]
+	if s.Kind() == reflect.Ptr && s.IsNil() {
+		return reflect.Value{}, false
+	}
+	return s, true
+}
+
+// grpcCode returns the gRPC status code carried by err, if any.  An error
+// carries a status if it has a GRPCStatus method, as used by
+// google.golang.org/grpc/status, that returns a non-nil value with a Code
+// method.
+func grpcCode(err error) (code uint32, ok bool) {
+	s, ok := grpcStatusOf(err)
+	if !ok {
+		return 0, false
+	}
+	c := s.MethodByName("Code")
+	if !c.IsValid() || c.Type().NumIn() != 0 || c.Type().NumOut() != 1 {
+		return 0, false
+	}
+	return codeValue(c.Call(nil)[0])
+}
+
+// codeValue returns v, an integer such as a codes.Code, as a uint32.
+func codeValue(v reflect.Value) (uint32, bool) {
+	switch v.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return uint32(v.Uint()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return uint32(v.Int()), true
+	}
+	return 0, false
+}
+
+// grpcMessage returns the message of the gRPC status carried by err, as
+// returned by its Message method.
+func grpcMessage(err error) string {
+	s, ok := grpcStatusOf(err)
+	if !ok {
+		return ""
+	}
+	m := s.MethodByName("Message")
+	if !m.IsValid() || m.Type().NumIn() != 0 || m.Type().NumOut() != 1 || m.Type().Out(0).Kind() != reflect.String {
+		return ""
+	}
+	return m.Call(nil)[0].String()
+}
+
+type status struct {
+	code uint32
+	msg  interface{}
+}
+
+const notStatus = "got error %q, want an error with a gRPC status"
+
+// Status returns a Matcher that matches an error whose chain includes an
+// error carrying a gRPC status, as by status.FromError from
+// google.golang.org/grpc/status, with code and, if msg is not nil, a message
+// matched by msg, as by Error.  code is a codes.Code or other integer.  This
+// package does not depend on gRPC:
+//
+//	check.Error(err, check.Status(codes.NotFound, check.Equal("no such user")))
+//
+// Failures name the codes, e.g., NotFound, rather than giving their numbers.
+func Status(code interface{}, msg interface{}) Matcher {
+	n, ok := codeValue(reflect.ValueOf(code))
+	if !ok {
+		// Reported by validate.
+		n = ^uint32(0)
+	}
+	return status{code: n, msg: msg}
+}
+
+func (m status) match(c *config, got error) string {
+	if got == nil {
+		return c.failf(missing)
+	}
+	var found error
+	walk(got, func(err error) bool {
+		if _, ok := grpcCode(err); ok {
+			found = err
+			return false
+		}
+		return true
+	})
+	if found == nil {
+		return c.failf(notStatus, got)
+	}
+	if code, _ := grpcCode(found); code != m.code {
+		return c.failc(CodeWrong, sprintf("got error %%q with code %s, want code %s", grpcCodeName(code), grpcCodeName(m.code)), got)
+	}
+	if m.msg == nil {
+		return ""
+	}
+	s := c.checkError(errorString(grpcMessage(found)), m.msg)
+	if s == "" || c.quiet {
+		return s
+	}
+	return prefixed("status message: ", s)
+}
+
+// An errorString is an error with a message.
+type errorString string
+
+func (e errorString) Error() string { return string(e) }
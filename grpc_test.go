@@ -0,0 +1,70 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"fmt"
+	"io"
+	"testing"
+)
+
+// code is like codes.Code.
+type code uint32
+
+func TestStatus(t *testing.T) {
+	setDefaults(t)
+	notFound := grpcErr{&grpcStatus{code: grpcNotFound, msg: "no such user"}}
+	wrapped := fmt.Errorf("get: %w", notFound)
+	for _, tt := range []struct {
+		name string
+		got  error
+		want Matcher
+		out  string
+	}{
+		{"code", notFound, Status(code(5), nil), ""},
+		{"wrapped", wrapped, Status(5, "such"), ""},
+		{"message", notFound, Status(code(5), Equal("no such user")), ""},
+		{"wrong code", wrapped, Status(code(7), nil),
+			`got error "get: rpc error: no such user" with code NotFound, want code PermissionDenied`},
+		{"unknown code", notFound, Status(uint8(99), nil),
+			`got error "rpc error: no such user" with code NotFound, want code Code(99)`},
+		{"wrong message", notFound, Status(code(5), Equal("no such group")),
+			"status message: " + sprintf(wrong, "no such user", "no such group")},
+		{"not status", io.EOF, Status(code(5), nil), sprintf(notStatus, io.EOF)},
+		{"nil status", grpcErr{}, Status(code(0), nil), sprintf(notStatus, "rpc error: ")},
+		{"nil", nil, Status(code(5), nil), missing},
+	} {
+		if s := Error(tt.got, tt.want); s != tt.out {
+			t.Errorf("%s: got %q, want %q", tt.name, s, tt.out)
+		}
+	}
+	if d := Describe(Status(code(5), "user")); d != `a gRPC status with code NotFound and a message that contains "user"` {
+		t.Errorf("got description %q", d)
+	}
+	if d := Describe(Status(code(14), nil)); d != "a gRPC status with code Unavailable" {
+		t.Errorf("got description %q", d)
+	}
+	for _, tt := range []struct {
+		want Matcher
+		err  string
+	}{
+		{Status("NotFound", nil), "want 0: Status code is not an integer"},
+		{Status(code(5), 1), "want 0: Status: unsupported type int"},
+	} {
+		if err := Validate(tt.want); err == nil || err.Error() != tt.err {
+			t.Errorf("got validation error %v, want %s", err, tt.err)
+		}
+	}
+}
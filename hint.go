@@ -0,0 +1,150 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"regexp"
+	"strings"
+)
+
+// hintThreshold is the similarity, as computed for Similar, at or above which
+// the Hints option adds a word diff to a failure.
+const hintThreshold = 0.6
+
+// Hints returns an Option that adds a word diff to the failures of string,
+// Case, Equal, and CaseEqual checks, and of MessagesEqual, when the message of
+// the error is similar to the message wanted, as after a small change of
+// wording:
+//
+//	got error "file was not found", want "file not found"
+//	messages differ at byte 5 (similarity 0.78): file {+was+} not found
+//
+// Words of the message that are not wanted are marked with {+ +} and wanted
+// words that are missing with [- -].  For string and Case checks the text of
+// the message most similar to the want, of about as many words, is compared.
+// Messages are similar if their similarity, as reported by Similar, is at
+// least 0.6.
+func Hints() Option {
+	return func(c *config) { c.hints = true }
+}
+
+// wordRE matches a word of a message, as compared by the Hints option.
+var wordRE = regexp.MustCompile(`\S+`)
+
+// hint returns the text added by the Hints option to a failure comparing got
+// and want, or "" if the option is not set or got is not similar to want.  If
+// fold is true the comparison was case insensitive, and if contains is true
+// got was to contain want.
+func (c *config) hint(got, want string, fold, contains bool) string {
+	if !c.hints || c.quiet {
+		return ""
+	}
+	norm := func(s string) string { return s }
+	var lower func(string) string
+	if fold {
+		norm, lower = c.toLower, c.toLower
+	}
+	text, start := got, 0
+	if contains {
+		text, start = closestText(got, want, norm)
+	}
+	score := similarity(norm(text), norm(want))
+	if score < hintThreshold {
+		return ""
+	}
+	diff, ok := markWords(text, want, norm)
+	if !ok {
+		return ""
+	}
+	off := start + diffOffset(text, want, lower)
+	return sprintf("\nmessages differ at byte %d (similarity %.2f): %s", off, score, diff)
+}
+
+// closestText returns the run of words in got most similar to want, and its
+// byte offset in got.  The runs considered have as many words as want, give
+// or take one.
+func closestText(got, want string, norm func(string) string) (string, int) {
+	words := wordRE.FindAllStringIndex(got, -1)
+	n := len(wordRE.FindAllStringIndex(want, -1))
+	best, start, score := got, 0, -1.0
+	for k := n - 1; k <= n+1; k++ {
+		if k < 1 {
+			continue
+		}
+		for i := 0; i+k <= len(words); i++ {
+			text := got[words[i][0]:words[i+k-1][1]]
+			if s := similarity(norm(text), norm(want)); s > score {
+				best, start, score = text, words[i][0], s
+			}
+		}
+	}
+	return best, start
+}
+
+// markWords returns the words of want and got, with the words only in got
+// marked with {+ +} and the words only in want marked with [- -].  Words are
+// compared after norm is applied to them.  markWords reports false if got and
+// want have the same words.
+func markWords(got, want string, norm func(string) string) (string, bool) {
+	g, w := strings.Fields(got), strings.Fields(want)
+	// lcs[i][j] is the length of the longest common subsequence of g[i:]
+	// and w[j:].
+	lcs := make([][]int, len(g)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(w)+1)
+	}
+	for i := len(g) - 1; i >= 0; i-- {
+		for j := len(w) - 1; j >= 0; j-- {
+			switch {
+			case norm(g[i]) == norm(w[j]):
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	var out, added, removed []string
+	flush := func() {
+		if len(removed) > 0 {
+			out = append(out, "[-"+strings.Join(removed, " ")+"-]")
+		}
+		if len(added) > 0 {
+			out = append(out, "{+"+strings.Join(added, " ")+"+}")
+		}
+		added, removed = nil, nil
+	}
+	differ := false
+	i, j := 0, 0
+	for i < len(g) || j < len(w) {
+		switch {
+		case i < len(g) && j < len(w) && norm(g[i]) == norm(w[j]):
+			flush()
+			out = append(out, g[i])
+			i, j = i+1, j+1
+			continue
+		case j < len(w) && (i == len(g) || lcs[i][j+1] >= lcs[i+1][j]):
+			removed = append(removed, w[j])
+			j++
+		default:
+			added = append(added, g[i])
+			i++
+		}
+		differ = true
+	}
+	flush()
+	return strings.Join(out, " "), differ
+}
@@ -0,0 +1,59 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestHints(t *testing.T) {
+	setDefaults(t)
+	ck := NewChecker(Hints())
+	notFound := errors.New("file was not found")
+	for _, tt := range []struct {
+		name string
+		got  error
+		want interface{}
+		hint string
+	}{
+		{"equal", notFound, Equal("file not found"),
+			"\nmessages differ at byte 5 (similarity 0.78): file {+was+} not found"},
+		{"replaced", errors.New("open failed: permission denied"), Equal("open failed: access denied"),
+			"\nmessages differ at byte 13 (similarity 0.73): open failed: [-access-] {+permission+} denied"},
+		{"removed", errors.New("no such user"), Equal("no such user name"),
+			"\nmessages differ at byte 12 (similarity 0.71): no such user [-name-]"},
+		{"case equal", errors.New("File Was Not Found"), CaseEqual("file not found"),
+			"\nmessages differ at byte 5 (similarity 0.78): File {+Was+} Not Found"},
+		{"contains", errors.New("stat /tmp/x: file was not found"), "file not found",
+			"\nmessages differ at byte 18 (similarity 0.78): file {+was+} not found"},
+		{"case", errors.New("stat: File Was Not Found"), Case("file not found"),
+			"\nmessages differ at byte 11 (similarity 0.78): File {+Was+} Not Found"},
+		{"not similar", notFound, Equal("permission denied"), ""},
+		{"same words", errors.New("file  not found"), Equal("file not found"), ""},
+	} {
+		s := ck.Error(tt.got, tt.want).String()
+		if want := Error(tt.got, tt.want) + tt.hint; s != want {
+			t.Errorf("%s: got %q, want %q", tt.name, s, want)
+		}
+	}
+	s := NewChecker(Hints()).MessagesEqual(notFound, errors.New("file not found")).String()
+	if want := MessagesEqual(notFound, errors.New("file not found")) + "\nmessages differ at byte 5 (similarity 0.78): file {+was+} not found"; s != want {
+		t.Errorf("MessagesEqual: got %q, want %q", s, want)
+	}
+	if s := Error(notFound, Equal("file not found")); s != sprintf(wrong, notFound, "file not found") {
+		t.Errorf("without Hints: got %q", s)
+	}
+}
@@ -0,0 +1,31 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.18
+// +build go1.18
+
+package check
+
+import "reflect"
+
+// Implements returns a Matcher that matches an error whose chain includes an
+// error that implements T, normally an interface type, or, if T is not an
+// interface type, that is of type T:
+//
+//	check.Error(err, check.Implements[interface{ Retryable() bool }]())
+//
+// Use Method to also assert what a method returns.
+func Implements[T any]() Matcher {
+	return implements{t: reflect.TypeOf((*T)(nil)).Elem()}
+}
@@ -0,0 +1,53 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.18
+// +build go1.18
+
+package check
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestImplements(t *testing.T) {
+	setDefaults(t)
+	netError := reflect.TypeOf((*net.Error)(nil)).Elem()
+	wrapped := fmt.Errorf("dial: %w", netErr{})
+	for _, tt := range []struct {
+		name string
+		got  error
+		want Matcher
+		out  string
+	}{
+		{"interface", wrapped, Implements[net.Error](), ""},
+		{"method set", wrapped, Implements[interface{ Timeout() bool }](), ""},
+		{"type", wrapped, Implements[netErr](), ""},
+		{"joined", &multi{io.EOF, netErr{}}, Implements[net.Error](), ""},
+		{"missing", io.EOF, Implements[net.Error](), sprintf(noImplements, io.EOF, netError)},
+		{"wrong type", wrapped, Implements[timeoutErr](), sprintf(noImplements, wrapped, reflect.TypeOf(timeoutErr{}))},
+		{"nil", nil, Implements[net.Error](), missing},
+	} {
+		if s := Error(tt.got, tt.want); s != tt.out {
+			t.Errorf("%s: got %q, want %q", tt.name, s, tt.out)
+		}
+	}
+	if d := Describe(Implements[net.Error]()); d != "implements net.Error" {
+		t.Errorf("got description %q", d)
+	}
+}
@@ -0,0 +1,215 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"errors"
+	"strings"
+)
+
+// IsSymmetric returns the empty string if errors.Is(a, b) and errors.Is(b, a)
+// agree, otherwise it returns a string indicating which direction holds.
+// errors.Is is not symmetric in general; a wrapping error is its wrapped
+// error but not the reverse, so IsSymmetric is meant for errors that should
+// be interchangeable, such as two errors of a type with a custom Is method.
+func IsSymmetric(a, b error) string {
+	return defaults().isSymmetric(a, b)
+}
+
+// IsSymmetric is the same as the IsSymmetric function but uses the options of
+// ck.
+func (ck *Checker) IsSymmetric(a, b error) Result {
+	ck.count()
+	return Result(ck.c.isSymmetric(a, b))
+}
+
+func (c *config) isSymmetric(a, b error) string {
+	if c.outer() {
+		return c.run("IsSymmetric", a, func() string { return Describe(b) },
+			func(c *config) string { return c.isSymmetric(a, b) })
+	}
+	ab, ba := errors.Is(a, b), errors.Is(b, a)
+	if ab == ba {
+		return ""
+	}
+	return c.failc(CodeWrong, sprintf("errors.Is(%%q, %%q) is %t but errors.Is(%%q, %%q) is %t", ab, ba), a, b, b, a)
+}
+
+// IsConsistent returns the empty string if the Is methods of got and of the
+// errors it wraps behave consistently with Unwrap, otherwise it returns a
+// string indicating each inconsistency.  IsConsistent checks that:
+//
+//	got is each error in its chain, as determined by errors.Is
+//	no Is method panics or returns true when passed nil
+//	no Is method matches an unrelated error with the same message
+//
+// The first fails for errors of uncomparable types without an Is method; the
+// others catch common mistakes in custom Is methods.
+func IsConsistent(got error) string {
+	return defaults().isConsistent(got)
+}
+
+// IsConsistent is the same as the IsConsistent function but uses the options of
+// ck.
+func (ck *Checker) IsConsistent(got error) Result {
+	ck.count()
+	return Result(ck.c.isConsistent(got))
+}
+
+func (c *config) isConsistent(got error) string {
+	if c.outer() {
+		return c.run("IsConsistent", got, nil,
+			func(c *config) string { return c.isConsistent(got) })
+	}
+	if got == nil {
+		return c.failf(missing)
+	}
+	var failures []string
+	walk(got, func(err error) bool {
+		if !errors.Is(got, err) {
+			failures = append(failures, c.failc(CodeWrong, sprintf("errors.Is(%%q, %%q) is false for an error in its chain (%T)", err), got, err))
+		}
+		e, ok := err.(interface{ Is(error) bool })
+		if !ok {
+			return true
+		}
+		if is, p := callIs(e, nil); p != nil {
+			failures = append(failures, c.failc(CodeWrong, sprintf("(%T).Is(nil) panicked: %%q", err), p))
+		} else if is {
+			failures = append(failures, c.failc(CodeWrong, sprintf("(%T).Is(nil) is true", err)))
+		}
+		if is, _ := callIs(e, errors.New(message(err))); is {
+			failures = append(failures, c.failc(CodeWrong, sprintf("(%T).Is matches an unrelated error with the message %%q", err), err))
+		}
+		return true
+	})
+	return strings.Join(failures, "\n")
+}
+
+// callIs returns the result of e.Is(target), or, if Is panics, the value
+// passed to panic.
+func callIs(e interface{ Is(error) bool }, target error) (is bool, p interface{}) {
+	defer func() {
+		if r := recover(); r != nil {
+			p = r
+		}
+	}()
+	return e.Is(target), nil
+}
+
+const (
+	isFalse = "errors.Is(%q, %q) is false, want true"
+	isTrue  = "errors.Is(%q, %q) is true, want false"
+)
+
+// CustomIs returns the empty string if errors.Is(got, target) is true for
+// each target in shouldMatch and false for each target in shouldNotMatch,
+// otherwise it returns a string indicating each target not as expected.
+// CustomIs exercises the Is method of an error type in a single call:
+//
+//	err := &QuotaError{Resource: "disk"}
+//	if s := check.CustomIs(err,
+//		[]error{ErrQuota, &QuotaError{Resource: "disk"}},
+//		[]error{ErrNotFound, &QuotaError{Resource: "cpu"}},
+//	); s != "" {
+//		t.Error(s)
+//	}
+func CustomIs(got error, shouldMatch, shouldNotMatch []error) string {
+	return defaults().customIs(got, shouldMatch, shouldNotMatch)
+}
+
+// CustomIs is the same as the CustomIs function but uses the options of ck.
+func (ck *Checker) CustomIs(got error, shouldMatch, shouldNotMatch []error) Result {
+	ck.count()
+	return Result(ck.c.customIs(got, shouldMatch, shouldNotMatch))
+}
+
+func (c *config) customIs(got error, shouldMatch, shouldNotMatch []error) string {
+	if c.outer() {
+		return c.run("CustomIs", got, nil,
+			func(c *config) string { return c.customIs(got, shouldMatch, shouldNotMatch) })
+	}
+	if got == nil {
+		return c.failf(missing)
+	}
+	var failures []string
+	for _, target := range shouldMatch {
+		if !errors.Is(got, target) {
+			failures = append(failures, c.failf(isFalse, got, describeTarget(target)))
+		}
+	}
+	for _, target := range shouldNotMatch {
+		if errors.Is(got, target) {
+			failures = append(failures, c.failf(isTrue, got, describeTarget(target)))
+		}
+	}
+	return strings.Join(failures, "\n")
+}
+
+// describeTarget returns the message of target, or "<nil>" if it is nil.
+func describeTarget(target error) string {
+	if target == nil {
+		return "<nil>"
+	}
+	return message(target)
+}
+
+// IsPartition returns the empty string if errors.Is holds, in at least one
+// direction, between each pair of errors in the same class and in neither
+// direction between errors of different classes, otherwise it returns a string
+// indicating each offending pair.  Classes are numbered from 0 in failures.
+// IsPartition is a safety net for refactoring sentinel errors:
+//
+//	s := check.IsPartition(
+//		[]error{ErrNotFound, &LookupError{Key: "k"}, fmt.Errorf("x: %w", ErrNotFound)},
+//		[]error{ErrDenied, &AuthError{}},
+//	)
+func IsPartition(classes ...[]error) string {
+	return defaults().isPartition(classes)
+}
+
+// IsPartition is the same as the IsPartition function but uses the options of
+// ck.
+func (ck *Checker) IsPartition(classes ...[]error) Result {
+	ck.count()
+	return Result(ck.c.isPartition(classes))
+}
+
+func (c *config) isPartition(classes [][]error) string {
+	if c.outer() {
+		return c.run("IsPartition", nil, nil,
+			func(c *config) string { return c.isPartition(classes) })
+	}
+	var failures []string
+	for i, class := range classes {
+		for x, a := range class {
+			for _, b := range class[x+1:] {
+				if !errors.Is(a, b) && !errors.Is(b, a) {
+					failures = append(failures, c.failc(CodeWrong, sprintf("errors.Is is false between %%q and %%q, both in class %d", i), describeTarget(a), describeTarget(b)))
+				}
+			}
+			for j := i + 1; j < len(classes); j++ {
+				for _, b := range classes[j] {
+					for _, p := range [][2]error{{a, b}, {b, a}} {
+						if errors.Is(p[0], p[1]) {
+							failures = append(failures, c.failc(CodeWrong, sprintf("errors.Is(%%q, %%q) is true across classes %d and %d", i, j), describeTarget(p[0]), describeTarget(p[1])))
+						}
+					}
+				}
+			}
+		}
+	}
+	return strings.Join(failures, "\n")
+}
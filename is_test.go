@@ -0,0 +1,165 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+)
+
+// kindErr is an error whose Is method matches any kindErr of the same kind.
+type kindErr struct{ kind, msg string }
+
+func (e *kindErr) Error() string { return e.msg }
+func (e *kindErr) Is(target error) bool {
+	t, ok := target.(*kindErr)
+	return ok && t.kind == e.kind
+}
+
+// msgIsErr is an error whose Is method compares messages.
+type msgIsErr struct{ msg string }
+
+func (e *msgIsErr) Error() string        { return e.msg }
+func (e *msgIsErr) Is(target error) bool { return target.Error() == e.msg }
+
+// nilIsErr is an error whose Is method matches nil.
+type nilIsErr struct{}
+
+func (nilIsErr) Error() string        { return "nil is" }
+func (nilIsErr) Is(target error) bool { return target == nil }
+
+func TestIsSymmetric(t *testing.T) {
+	setDefaults(t)
+	wrapped := fmt.Errorf("read: %w", io.EOF)
+	for _, tt := range []struct {
+		a, b error
+		out  string
+	}{
+		{io.EOF, io.EOF, ""},
+		{io.EOF, io.ErrUnexpectedEOF, ""},
+		{&kindErr{"a", "x"}, &kindErr{"a", "y"}, ""},
+		{&kindErr{"a", "x"}, &kindErr{"b", "x"}, ""},
+		{wrapped, io.EOF, `errors.Is("read: EOF", "EOF") is true but errors.Is("EOF", "read: EOF") is false`},
+		{io.EOF, wrapped, `errors.Is("EOF", "read: EOF") is false but errors.Is("read: EOF", "EOF") is true`},
+		{nil, nil, ""},
+	} {
+		if s := IsSymmetric(tt.a, tt.b); s != tt.out {
+			t.Errorf("IsSymmetric(%v, %v) got %q, want %q", tt.a, tt.b, s, tt.out)
+		}
+	}
+}
+
+func TestIsConsistent(t *testing.T) {
+	setDefaults(t)
+	for _, tt := range []struct {
+		got error
+		out string
+	}{
+		{io.EOF, ""},
+		{fmt.Errorf("x: %w", &kindErr{"a", "y"}), ""},
+		{nil, sprintf(missing)},
+		{uncomparable{"x"}, `errors.Is("uncomparable", "uncomparable") is false for an error in its chain (check.uncomparable)`},
+		{&msgIsErr{"boom"}, `(*check.msgIsErr).Is(nil) panicked: "runtime error: invalid memory address or nil pointer dereference"
+(*check.msgIsErr).Is matches an unrelated error with the message "boom"`},
+		{fmt.Errorf("wrap: %w", nilIsErr{}), "(check.nilIsErr).Is(nil) is true"},
+	} {
+		if s := IsConsistent(tt.got); s != tt.out {
+			t.Errorf("IsConsistent(%v) got:\n%s\nwant:\n%s", tt.got, s, tt.out)
+		}
+	}
+	if s := IsConsistent(errors.New("plain")); s != "" {
+		t.Errorf("plain error: %s", s)
+	}
+}
+
+func TestCustomIs(t *testing.T) {
+	setDefaults(t)
+	got := fmt.Errorf("quota: %w", &kindErr{"disk", "disk full"})
+	for _, tt := range []struct {
+		name           string
+		got            error
+		match, noMatch []error
+		out            string
+	}{
+		{
+			name:    "pass",
+			got:     got,
+			match:   []error{&kindErr{"disk", "other"}, got},
+			noMatch: []error{&kindErr{"cpu", "disk full"}, io.EOF, nil},
+		}, {
+			name: "empty",
+			got:  got,
+		}, {
+			name:  "nil",
+			match: []error{io.EOF},
+			out:   sprintf(missing),
+		}, {
+			name:    "misses",
+			got:     got,
+			match:   []error{io.EOF, &kindErr{"disk", "x"}, nil},
+			noMatch: []error{&kindErr{"disk", "y"}},
+			out: `errors.Is("quota: disk full", "EOF") is false, want true
+errors.Is("quota: disk full", "<nil>") is false, want true
+errors.Is("quota: disk full", "y") is true, want false`,
+		},
+	} {
+		if s := CustomIs(tt.got, tt.match, tt.noMatch); s != tt.out {
+			t.Errorf("%s: got:\n%s\nwant:\n%s", tt.name, s, tt.out)
+		}
+	}
+}
+
+func TestIsPartition(t *testing.T) {
+	setDefaults(t)
+	disk := &kindErr{"disk", "disk full"}
+	for _, tt := range []struct {
+		name    string
+		classes [][]error
+		out     string
+	}{
+		{name: "none"},
+		{
+			name: "pass",
+			classes: [][]error{
+				{io.EOF, fmt.Errorf("read: %w", io.EOF)},
+				{disk, &kindErr{"disk", "other"}, fmt.Errorf("x: %w", disk)},
+				{io.ErrUnexpectedEOF},
+			},
+		}, {
+			name: "split",
+			classes: [][]error{
+				{io.EOF, io.ErrUnexpectedEOF},
+			},
+			out: `errors.Is is false between "EOF" and "unexpected EOF", both in class 0`,
+		}, {
+			name: "across",
+			classes: [][]error{
+				{disk},
+				{io.EOF},
+				{&kindErr{"disk", "cpu"}, fmt.Errorf("wrap: %w", io.EOF)},
+			},
+			out: `errors.Is("disk full", "cpu") is true across classes 0 and 2
+errors.Is("cpu", "disk full") is true across classes 0 and 2
+errors.Is("wrap: EOF", "EOF") is true across classes 1 and 2
+errors.Is is false between "cpu" and "wrap: EOF", both in class 2`,
+		},
+	} {
+		if s := IsPartition(tt.classes...); s != tt.out {
+			t.Errorf("%s: got:\n%s\nwant:\n%s", tt.name, s, tt.out)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"reflect"
+	"strings"
+)
+
+const (
+	missingJoined    = "got error %q, want it to wrap %q"
+	unexpectedJoined = "got error %q, with unexpected joined error %q"
+)
+
+// JoinedErrors returns the empty string if got wraps each of wants, as by
+// errors.Is, including through the Unwrap() []error method of errors such as
+// those returned by errors.Join, otherwise it returns a string listing each
+// want that is missing.
+func JoinedErrors(got error, wants ...error) string {
+	return defaults().joinedErrors(got, wants, false)
+}
+
+// JoinedErrors is the same as the JoinedErrors function but uses the options of
+// ck.
+func (ck *Checker) JoinedErrors(got error, wants ...error) Result {
+	ck.count()
+	return Result(ck.c.joinedErrors(got, wants, false))
+}
+
+// OnlyJoinedErrors is like JoinedErrors but also fails if got joins an error
+// that does not wrap any of wants.  The joined errors are those returned by
+// the first Unwrap() []error method in the chain of got, or got itself if
+// there is none.
+func OnlyJoinedErrors(got error, wants ...error) string {
+	return defaults().joinedErrors(got, wants, true)
+}
+
+// OnlyJoinedErrors is the same as the OnlyJoinedErrors function but uses the
+// options of ck.
+func (ck *Checker) OnlyJoinedErrors(got error, wants ...error) Result {
+	ck.count()
+	return Result(ck.c.joinedErrors(got, wants, true))
+}
+
+// StrictUnwrap returns an Option that causes JoinedErrors and
+// OnlyJoinedErrors to also fail if got, or an error it wraps, is malformed:
+// it has an Unwrap() []error method that returns no errors or a nil error, or
+// an Unwrap method that panics.  Without StrictUnwrap such errors are
+// tolerated; nil errors are skipped and a panicking Unwrap wraps nothing.
+func StrictUnwrap() Option {
+	return func(c *config) { c.strictUnwrap = true }
+}
+
+// joinedErrors implements JoinedErrors, and OnlyJoinedErrors if only is true,
+// using the settings in c.
+func (c *config) joinedErrors(got error, wants []error, only bool) string {
+	if c.outer() {
+		name := "JoinedErrors"
+		if only {
+			name = "OnlyJoinedErrors"
+		}
+		return c.run(name, got, nil,
+			func(c *config) string { return c.joinedErrors(got, wants, only) })
+	}
+	if got == nil {
+		if len(wants) == 0 {
+			return ""
+		}
+		return c.failf(missing)
+	}
+	var failures []string
+	if c.strictUnwrap {
+		for _, problem := range malformed(got) {
+			failures = append(failures, c.failc(CodeWrong, "got error %q with a malformed join", got)+": "+problem)
+		}
+	}
+	for _, want := range wants {
+		if !wraps(got, want) {
+			failures = append(failures, c.failf(missingJoined, got, want))
+		}
+	}
+	if only {
+		for _, member := range joined(got) {
+			found := false
+			for _, want := range wants {
+				if wraps(member, want) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				failures = append(failures, c.failf(unexpectedJoined, got, member))
+			}
+		}
+	}
+	return strings.Join(failures, "\n")
+}
+
+// wraps reports whether err or an error it wraps, through either form of
+// Unwrap, is target or has an Is method that reports it matches target.
+func wraps(err, target error) bool {
+	if target == nil {
+		return err == nil
+	}
+	comparable := reflect.TypeOf(target).Comparable()
+	return !walk(err, func(e error) bool {
+		if comparable && e == target {
+			return false
+		}
+		if x, ok := e.(interface{ Is(error) bool }); ok {
+			if is, _ := callIs(x, target); is {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// joined returns the non-nil errors joined by the first Unwrap() []error
+// method in the chain of err, or err itself if there is none.
+func joined(err error) []error {
+	for e := err; e != nil; {
+		if _, ok := e.(interface{ Unwrap() []error }); ok {
+			return unwrapAll(e)
+		}
+		errs := unwrapAll(e)
+		if len(errs) == 0 {
+			break
+		}
+		e = errs[0]
+	}
+	return []error{err}
+}
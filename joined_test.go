@@ -0,0 +1,140 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+)
+
+func TestJoinedErrors(t *testing.T) {
+	setDefaults(t, Quote(QuoteRaw))
+	perm := &os.PathError{Op: "open", Path: "x", Err: os.ErrPermission}
+	got := fmt.Errorf("close: %w", multi{io.EOF, perm})
+	other := errors.New("other")
+	failf := defaults().failf
+	for _, tt := range []struct {
+		name  string
+		got   error
+		wants []error
+		out   string
+	}{
+		{name: "nil"},
+		{name: "all", got: got, wants: []error{io.EOF, os.ErrPermission}},
+		{name: "wrapped", got: got, wants: []error{perm}},
+		{
+			name:  "missing",
+			got:   got,
+			wants: []error{io.EOF, other, io.ErrUnexpectedEOF},
+			out: failf(missingJoined, got, other) + "\n" +
+				failf(missingJoined, got, io.ErrUnexpectedEOF),
+		},
+		{name: "nil got", wants: []error{io.EOF}, out: missing},
+		{name: "no join", got: fmt.Errorf("x: %w", io.EOF), wants: []error{io.EOF}},
+		{name: "none wanted", got: got},
+	} {
+		if s := JoinedErrors(tt.got, tt.wants...); s != tt.out {
+			t.Errorf("%s: got %q, want %q", tt.name, s, tt.out)
+		}
+	}
+
+	for _, tt := range []struct {
+		name  string
+		got   error
+		wants []error
+		out   string
+	}{
+		{name: "exact", got: got, wants: []error{io.EOF, os.ErrPermission}},
+		{name: "extra", got: got, wants: []error{io.EOF}, out: failf(unexpectedJoined, got, perm)},
+		{
+			name:  "missing and extra",
+			got:   got,
+			wants: []error{os.ErrPermission, other},
+			out:   failf(missingJoined, got, other) + "\n" + failf(unexpectedJoined, got, io.EOF),
+		},
+		{name: "no join", got: io.EOF, wants: []error{io.EOF}},
+		{name: "no join unexpected", got: io.EOF, out: failf(unexpectedJoined, io.EOF, io.EOF)},
+	} {
+		if s := OnlyJoinedErrors(tt.got, tt.wants...); s != tt.out {
+			t.Errorf("%s: got %q, want %q", tt.name, s, tt.out)
+		}
+	}
+}
+
+// boxErr is an error whose Unwrap panics when it is a nil *boxErr.
+type boxErr struct{ err error }
+
+func (e *boxErr) Error() string { return "box" }
+func (e *boxErr) Unwrap() error { return e.err }
+
+// sloppyJoin is a join that may hold nil errors.
+type sloppyJoin []error
+
+func (sloppyJoin) Error() string     { return "sloppy" }
+func (s sloppyJoin) Unwrap() []error { return s }
+
+func TestSloppyJoins(t *testing.T) {
+	setDefaults(t)
+	sloppy := sloppyJoin{nil, io.EOF, nil}
+	empty := fmt.Errorf("wrapped: %w", sloppyJoin(nil))
+	panics := sloppyJoin{io.EOF, (*boxErr)(nil)}
+	for _, tt := range []struct {
+		name  string
+		got   error
+		wants []error
+		only  bool
+		out   string
+	}{
+		{name: "nil elements", got: sloppy, wants: []error{io.EOF}},
+		{name: "nil elements only", got: sloppy, wants: []error{io.EOF}, only: true},
+		{name: "nil slice", got: empty, wants: []error{io.EOF}, out: sprintf(missingJoined, empty, io.EOF)},
+		{name: "nil slice only", got: empty, only: true},
+		{name: "panicking unwrap", got: panics, wants: []error{io.EOF}},
+	} {
+		var s string
+		if tt.only {
+			s = OnlyJoinedErrors(tt.got, tt.wants...)
+		} else {
+			s = JoinedErrors(tt.got, tt.wants...)
+		}
+		if s != tt.out {
+			t.Errorf("%s: got %q, want %q", tt.name, s, tt.out)
+		}
+	}
+	if lines := chainLines(fmt.Errorf("x: %w", panics)); len(lines) != 4 {
+		t.Errorf("got chain %q", lines)
+	}
+
+	setDefaults(t, StrictUnwrap())
+	malformedJoin := "got error %q with a malformed join: "
+	for _, tt := range []struct {
+		name string
+		got  error
+		out  string
+	}{
+		{"well formed", sloppyJoin{io.EOF}, ""},
+		{"nil elements", sloppy, sprintf(malformedJoin, sloppy) + "(check.sloppyJoin).Unwrap returned nil at index 0\n" +
+			sprintf(malformedJoin, sloppy) + "(check.sloppyJoin).Unwrap returned nil at index 2"},
+		{"nil slice", sloppyJoin{io.EOF, sloppyJoin{}}, sprintf(malformedJoin, "sloppy") + "(check.sloppyJoin).Unwrap returned no errors"},
+		{"panicking unwrap", panics, sprintf(malformedJoin, "sloppy") + "(*check.boxErr).Unwrap panicked: runtime error: invalid memory address or nil pointer dereference"},
+	} {
+		if s := JoinedErrors(tt.got, io.EOF); s != tt.out {
+			t.Errorf("strict %s: got %q, want %q", tt.name, s, tt.out)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// An Encoder encodes an error as JSON, as done by middleware that returns
+// errors on the wire.
+type Encoder func(error) ([]byte, error)
+
+// StandardEncoder encodes err as a JSON object with the fields:
+//
+//	message  the message of err
+//	code     the result of the Code method of err, if it has one
+//	details  the result of the Details method of err, if it has one
+//
+// The code and details fields are omitted if err does not have the
+// corresponding method.  Only err itself, not its chain, is consulted.
+func StandardEncoder(err error) ([]byte, error) {
+	shape := map[string]interface{}{"message": err.Error()}
+	if v, ok := call(err, "Code"); ok {
+		shape["code"] = v
+	}
+	if v, ok := call(err, "Details"); ok {
+		shape["details"] = v
+	}
+	return json.Marshal(shape)
+}
+
+// call calls the method name of v that takes no arguments and returns a
+// single value, returning the value and true.  It returns false if v has no
+// such method.
+func call(v interface{}, name string) (interface{}, bool) {
+	m := reflect.ValueOf(v).MethodByName(name)
+	if !m.IsValid() || m.Type().NumIn() != 0 || m.Type().NumOut() != 1 {
+		return nil, false
+	}
+	return m.Call(nil)[0].Interface(), true
+}
+
+const (
+	wrongJSON   = "got JSON %q, want %q"
+	encodeError = "encoding error %q failed: %q"
+	invalidJSON = "want is not valid JSON: %q"
+)
+
+// JSONShape returns the empty string if got, encoded by enc, has the same JSON
+// shape as want, otherwise it returns a string indicating the error.  If enc
+// is nil then StandardEncoder is used.  A want of type string or []byte is
+// JSON, such as from a golden file, any other want, such as a
+// map[string]interface{}, is first encoded with json.Marshal.  The shapes are
+// compared as JSON values, so the order of object fields and white space do
+// not matter.
+//
+//	want := map[string]interface{}{"message": "not found", "code": 404}
+//	if s := check.JSONShape(err, nil, want); s != "" {
+//		t.Error(s)
+//	}
+func JSONShape(got error, enc Encoder, want interface{}) string {
+	return defaults().jsonShape(got, enc, want)
+}
+
+// JSONShape is the same as the JSONShape function but uses the options of ck.
+func (ck *Checker) JSONShape(got error, enc Encoder, want interface{}) Result {
+	ck.count()
+	return Result(ck.c.jsonShape(got, enc, want))
+}
+
+func (c *config) jsonShape(got error, enc Encoder, want interface{}) string {
+	if c.outer() {
+		return c.run("JSONShape", got, func() string { return Describe(want) },
+			func(c *config) string { return c.jsonShape(got, enc, want) })
+	}
+	if got == nil {
+		return c.failf(missing)
+	}
+	if enc == nil {
+		enc = StandardEncoder
+	}
+	var wdata []byte
+	switch w := want.(type) {
+	case string:
+		wdata = []byte(w)
+	case []byte:
+		wdata = w
+	default:
+		var err error
+		if wdata, err = json.Marshal(want); err != nil {
+			return c.failf(invalidJSON, err)
+		}
+	}
+	var wv interface{}
+	if err := json.Unmarshal(wdata, &wv); err != nil {
+		return c.failf(invalidJSON, err)
+	}
+	gdata, err := enc(got)
+	if err != nil {
+		return c.failf(encodeError, got, err)
+	}
+	var gv interface{}
+	if err := json.Unmarshal(gdata, &gv); err != nil {
+		return c.failf(encodeError, got, err)
+	}
+	if reflect.DeepEqual(gv, wv) {
+		return ""
+	}
+	// Marshaling the decoded values sorts the fields of objects, putting
+	// both in the same canonical form.
+	gdata, _ = json.Marshal(gv)
+	wdata, _ = json.Marshal(wv)
+	g, w := string(gdata), string(wdata)
+	return c.failf(wrongJSON, g, w) + c.caret(g, w, false)
+}
+
+type jsonMessage struct{ want string }
+
+// JSON returns a Matcher that matches an error whose message is a JSON value
+// containing the JSON value want: objects contain the fields of the objects
+// wanted, with values containing the values wanted, and arrays have the same
+// length as the arrays wanted, with elements containing the elements wanted.
+// Other values are equal.  The order of object fields and white space do not
+// matter.  Each difference is reported with its path, e.g.:
+//
+//	got error "{\"error\":{\"code\":404}}" with JSON that differs:
+//		.error.code: got 404, want 403
+//		.error.reason: missing, want "quota"
+func JSON(want string) Matcher {
+	return jsonMessage{want: want}
+}
+
+const notJSON = "got error %q, want a JSON message: %q"
+
+func (m jsonMessage) match(c *config, got error) string {
+	var wv interface{}
+	if err := json.Unmarshal([]byte(m.want), &wv); err != nil {
+		return c.failf(invalidJSON, err)
+	}
+	if got == nil {
+		return c.failf(missing)
+	}
+	var gv interface{}
+	if err := json.Unmarshal([]byte(message(got)), &gv); err != nil {
+		return c.failf(notJSON, got, err)
+	}
+	d := differ{}
+	jsonDiff(&d, "", gv, wv)
+	if len(d.diffs) == 0 {
+		return ""
+	}
+	if c.quiet {
+		return quietFailure
+	}
+	var b strings.Builder
+	b.WriteString(c.failc(CodeWrong, "got error %q with JSON that differs:", got))
+	for _, s := range d.diffs {
+		b.WriteString("\n")
+		b.WriteString(indent(s))
+	}
+	return b.String()
+}
+
+// jsonDiff adds the ways the decoded JSON value got, found at path, does not
+// contain want to d.
+func jsonDiff(d *differ, path string, got, want interface{}) {
+	switch w := want.(type) {
+	case map[string]interface{}:
+		g, ok := got.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(w))
+		for key := range w {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			kpath := path + jsonKey(key)
+			gf, ok := g[key]
+			if !ok {
+				d.addf(kpath, "missing, want %s", jsonText(w[key]))
+				continue
+			}
+			jsonDiff(d, kpath, gf, w[key])
+		}
+		return
+	case []interface{}:
+		g, ok := got.([]interface{})
+		if !ok {
+			break
+		}
+		if len(g) != len(w) {
+			d.addf(path, "got %d elements, want %d", len(g), len(w))
+			return
+		}
+		for i := range w {
+			jsonDiff(d, sprintf("%s[%d]", path, i), g[i], w[i])
+		}
+		return
+	}
+	if !reflect.DeepEqual(got, want) {
+		d.addf(path, "got %s, want %s", jsonText(got), jsonText(want))
+	}
+}
+
+// jsonKeyRE matches the keys of objects that need not be quoted in paths.
+var jsonKeyRE = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// jsonKey returns the path element of the object field key.
+func jsonKey(key string) string {
+	if jsonKeyRE.MatchString(key) {
+		return "." + key
+	}
+	return sprintf("[%q]", key)
+}
+
+// jsonText returns the decoded JSON value v as JSON.
+func jsonText(v interface{}) string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
@@ -0,0 +1,155 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"errors"
+	"io"
+	"testing"
+)
+
+type codedErr struct {
+	msg     string
+	code    int
+	details []string
+}
+
+func (e *codedErr) Error() string     { return e.msg }
+func (e *codedErr) Code() int         { return e.code }
+func (e *codedErr) Details() []string { return e.details }
+
+func TestJSONShape(t *testing.T) {
+	coded := &codedErr{msg: "not found", code: 404, details: []string{"no such user"}}
+	for _, tt := range []struct {
+		name string
+		got  error
+		enc  Encoder
+		want interface{}
+		out  string
+	}{
+		{
+			name: "nil",
+			want: `{"message":"x"}`,
+			out:  "did not get expected error",
+		}, {
+			name: "message",
+			got:  errors.New("bad"),
+			want: `{"message": "bad"}`,
+		}, {
+			name: "bytes",
+			got:  errors.New("bad"),
+			want: []byte(`{"message":"bad"}`),
+		}, {
+			name: "coded",
+			got:  coded,
+			want: map[string]interface{}{
+				"details": []string{"no such user"},
+				"code":    404,
+				"message": "not found",
+			},
+		}, {
+			name: "golden",
+			got:  coded,
+			want: `{
+				"message": "not found",
+				"code": 404,
+				"details": ["no such user"]
+			}`,
+		}, {
+			name: "wrong code",
+			got:  coded,
+			want: `{"message":"not found","code":500,"details":["no such user"]}`,
+			out: sprintf(wrongJSON,
+				`{"code":404,"details":["no such user"],"message":"not found"}`,
+				`{"code":500,"details":["no such user"],"message":"not found"}`),
+		}, {
+			name: "custom",
+			got:  errors.New("bad"),
+			enc: func(err error) ([]byte, error) {
+				return []byte(`{"error":{"msg":"` + err.Error() + `"}}`), nil
+			},
+			want: map[string]interface{}{"error": map[string]string{"msg": "bad"}},
+		}, {
+			name: "encode error",
+			got:  errors.New("bad"),
+			enc: func(err error) ([]byte, error) {
+				return nil, errors.New("cannot encode")
+			},
+			want: `{}`,
+			out:  sprintf(encodeError, "bad", "cannot encode"),
+		}, {
+			name: "invalid encoding",
+			got:  errors.New("bad"),
+			enc: func(err error) ([]byte, error) {
+				return []byte(`{`), nil
+			},
+			want: `{}`,
+			out:  sprintf(encodeError, "bad", "unexpected end of JSON input"),
+		}, {
+			name: "invalid want",
+			got:  errors.New("bad"),
+			want: `{"message"}`,
+			out:  sprintf(invalidJSON, "invalid character '}' after object key"),
+		},
+	} {
+		if s := JSONShape(tt.got, tt.enc, tt.want); s != tt.out {
+			t.Errorf("%s: got %q, want %q", tt.name, s, tt.out)
+		}
+	}
+}
+
+func TestJSON(t *testing.T) {
+	setDefaults(t)
+	payload := errors.New(`{"error": {"code": 404, "reason": "not found", "tags": ["a", "b"]}, "id": 7}`)
+	for _, tt := range []struct {
+		name string
+		got  error
+		want string
+		out  string
+	}{
+		{"equal", payload, `{"id":7,"error":{"tags":["a","b"],"reason":"not found","code":404}}`, ""},
+		{"subset", payload, `{"error": {"code": 404}}`, ""},
+		{"empty object", payload, `{}`, ""},
+		{"array subset", errors.New(`[{"a": 1, "b": 2}]`), `[{"a": 1}]`, ""},
+		{"scalar", errors.New(`"boom"`), `"boom"`, ""},
+		{"differs", payload, `{"error": {"code": 403, "detail": "quota"}, "id": 7}`,
+			sprintf(`got error %q with JSON that differs:`, payload) +
+				"\n\t.error.code: got 404, want 403" +
+				"\n\t.error.detail: missing, want \"quota\""},
+		{"array length", payload, `{"error": {"tags": ["a"]}}`,
+			sprintf(`got error %q with JSON that differs:`, payload) + "\n\t.error.tags: got 2 elements, want 1"},
+		{"array element", payload, `{"error": {"tags": ["a", "c"]}}`,
+			sprintf(`got error %q with JSON that differs:`, payload) + "\n\t.error.tags[1]: got \"b\", want \"c\""},
+		{"type", payload, `{"error": "not found"}`,
+			sprintf(`got error %q with JSON that differs:`, payload) +
+				"\n\t.error: got {\"code\":404,\"reason\":\"not found\",\"tags\":[\"a\",\"b\"]}, want \"not found\""},
+		{"key", errors.New(`{"a-b": 1}`), `{"a-b": 2}`,
+			"got error \"{\\\"a-b\\\": 1}\" with JSON that differs:\n\tvalue[\"a-b\"]: got 1, want 2"},
+		{"root", errors.New(`1`), `2`, "got error \"1\" with JSON that differs:\n\tvalue: got 1, want 2"},
+		{"not JSON", io.EOF, `{}`, `got error "EOF", want a JSON message: "invalid character 'E' looking for beginning of value"`},
+		{"invalid want", payload, `{`, `want is not valid JSON: "unexpected end of JSON input"`},
+		{"nil", nil, `{}`, missing},
+	} {
+		if s := Error(tt.got, JSON(tt.want)); s != tt.out {
+			t.Errorf("%s: got %q, want %q", tt.name, s, tt.out)
+		}
+	}
+	if d := Describe(JSON(`{"code": 404}`)); d != `JSON containing {"code":404}` {
+		t.Errorf("got description %q", d)
+	}
+	if err := Validate(JSON(`{`)); err == nil || err.Error() != "want 0: JSON: invalid want: unexpected end of JSON input" {
+		t.Errorf("got validation error %v", err)
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"strings"
+	"sync"
+)
+
+// A Caser maps strings to a single case.  A cases.Caser from
+// golang.org/x/text/cases is a Caser.
+type Caser interface {
+	String(s string) string
+}
+
+// lowerer serializes the use of a Caser as a cases.Caser is not safe for
+// concurrent use.
+type lowerer struct {
+	mu    sync.Mutex
+	caser Caser
+}
+
+// Lowercase returns an Option that uses caser, rather than strings.ToLower,
+// to map messages to lower case when making case insensitive checks, such as
+// Case and CaseEqual.  This permits locale specific comparisons, e.g.:
+//
+//	check.Lowercase(cases.Lower(language.Turkish))
+//
+// where "KAPI" and "kapi" differ, as the Turkish lower case of "I" is "ı",
+// but "İ" and "i" are the same.  Lowercase(nil) restores the default.
+func Lowercase(caser Caser) Option {
+	return func(c *config) {
+		if caser == nil {
+			c.lower = nil
+			return
+		}
+		c.lower = &lowerer{caser: caser}
+	}
+}
+
+// toLower returns s mapped to lower case as set by the Lowercase option.
+func (c *config) toLower(s string) string {
+	if c.lower == nil {
+		return strings.ToLower(s)
+	}
+	c.lower.mu.Lock()
+	defer c.lower.mu.Unlock()
+	return c.lower.caser.String(s)
+}
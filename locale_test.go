@@ -0,0 +1,75 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// turkish mimics cases.Lower(language.Turkish) from golang.org/x/text/cases.
+type turkish struct{}
+
+func (turkish) String(s string) string {
+	return strings.ToLower(strings.NewReplacer("I", "ı", "İ", "i").Replace(s))
+}
+
+func TestLowercase(t *testing.T) {
+	setDefaults(t)
+	tr := NewChecker(Lowercase(turkish{}))
+	err := errors.New("dosya bulunamadı")
+	for _, tt := range []struct {
+		name string
+		ck   *Checker
+		want interface{}
+		out  string
+	}{
+		{
+			name: "default",
+			ck:   NewChecker(),
+			want: Case("BULUNAMADI"),
+			out:  sprintf(wrong, "dosya bulunamadı", "BULUNAMADI"),
+		}, {
+			name: "turkish",
+			ck:   tr,
+			want: Case("BULUNAMADI"),
+		}, {
+			name: "turkish equal",
+			ck:   tr,
+			want: CaseEqual("DOSYA BULUNAMADI"),
+		}, {
+			name: "turkish dotted",
+			ck:   tr,
+			want: Case("BULUNAMADİ"),
+			out:  sprintf(wrong, "dosya bulunamadı", "BULUNAMADİ"),
+		}, {
+			name: "restored",
+			ck:   NewChecker(Lowercase(turkish{}), Lowercase(nil)),
+			want: CaseEqual("DOSYA BULUNAMADI"),
+			out:  sprintf(wrong, "dosya bulunamadı", "DOSYA BULUNAMADI"),
+		}, {
+			name: "caret",
+			ck:   NewChecker(Lowercase(turkish{}), Caret()),
+			want: CaseEqual("DOSYA BULUNAMADİ"),
+			out: sprintf(wrong, "dosya bulunamadı", "DOSYA BULUNAMADİ") +
+				"\nfirst difference at byte 15 (rune 15):\n\tdosya bulunamadı\n\tDOSYA BULUNAMADİ\n\t               ^",
+		},
+	} {
+		if s := tt.ck.Error(err, tt.want); string(s) != tt.out {
+			t.Errorf("%s: got %q, want %q", tt.name, s, tt.out)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type logfmt struct {
+	keys   []string // sorted
+	values map[string]string
+}
+
+// Logfmt returns a Matcher that matches an error whose message contains the
+// key=value pairs in want, in any order, as in logfmt.  Other text in the
+// message, and keys not in want, are ignored.  A value may be quoted, in
+// which case it is compared unquoted:
+//
+//	check.Logfmt(map[string]string{"user": "alice", "reason": "bad token"})
+//
+// matches
+//
+//	auth failed: reason="bad token" user=alice attempt=3
+func Logfmt(want map[string]string) Matcher {
+	m := logfmt{values: map[string]string{}}
+	for k, v := range want {
+		m.keys = append(m.keys, k)
+		m.values[k] = v
+	}
+	sort.Strings(m.keys)
+	return m
+}
+
+const (
+	missingKey = "got error %q, want key %q"
+	wrongValue = "got error %q, want %q for key %q (got %q)"
+)
+
+func (m logfmt) match(c *config, got error) string {
+	if got == nil {
+		return c.failf(missing)
+	}
+	pairs := parseLogfmt(got.Error())
+	for _, k := range m.keys {
+		v, ok := pairs[k]
+		switch {
+		case !ok:
+			return c.failf(missingKey, got, k)
+		case v != m.values[k]:
+			return c.failf(wrongValue, got, m.values[k], k, v)
+		}
+	}
+	return ""
+}
+
+// parseLogfmt returns the key=value pairs found in msg.  A key is a run of
+// characters other than white space, '=', and '"' that is immediately followed
+// by an '='.  A value is either a quoted string or a run of characters other
+// than white space.  When a key is repeated the last value is used.
+func parseLogfmt(msg string) map[string]string {
+	pairs := map[string]string{}
+	for i := 0; i < len(msg); {
+		eq := strings.IndexByte(msg[i:], '=')
+		if eq < 0 {
+			break
+		}
+		eq += i
+		start := eq
+		for start > i && !isLogfmtBreak(rune(msg[start-1])) {
+			start--
+		}
+		key := msg[start:eq]
+		i = eq + 1
+		if key == "" {
+			continue
+		}
+		var value string
+		if i < len(msg) && msg[i] == '"' {
+			end := quotedEnd(msg, i)
+			value = msg[i:end]
+			if s, err := strconv.Unquote(value); err == nil {
+				value = s
+			} else {
+				value = strings.Trim(value, `"`)
+			}
+			i = end
+		} else {
+			end := strings.IndexFunc(msg[i:], unicode.IsSpace)
+			if end < 0 {
+				end = len(msg) - i
+			}
+			value = msg[i : i+end]
+			i += end
+		}
+		pairs[key] = value
+	}
+	return pairs
+}
+
+// isLogfmtBreak reports whether r may not be part of a logfmt key.
+func isLogfmtBreak(r rune) bool {
+	return r == '=' || r == '"' || unicode.IsSpace(r)
+}
+
+// quotedEnd returns the index just past the closing quote of the quoted
+// string starting at msg[i], or len(msg) if it is not closed.
+func quotedEnd(msg string, i int) int {
+	for j := i + 1; j < len(msg); j++ {
+		switch msg[j] {
+		case '\\':
+			j++
+		case '"':
+			return j + 1
+		}
+	}
+	return len(msg)
+}
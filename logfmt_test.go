@@ -0,0 +1,68 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestParseLogfmt(t *testing.T) {
+	for _, tt := range []struct {
+		in   string
+		want map[string]string
+	}{
+		{"", map[string]string{}},
+		{"no pairs here", map[string]string{}},
+		{"a=1 b=two", map[string]string{"a": "1", "b": "two"}},
+		{"failed: user=alice", map[string]string{"user": "alice"}},
+		{`reason="bad token" x=1`, map[string]string{"reason": "bad token", "x": "1"}},
+		{`q="say \"hi\"" end=`, map[string]string{"q": `say "hi"`, "end": ""}},
+		{`open="unclosed`, map[string]string{"open": "unclosed"}},
+		{"a=1 a=2", map[string]string{"a": "2"}},
+		{"=x k==v", map[string]string{"k": "=v"}},
+		{"tab\tk=v\nnext=w", map[string]string{"k": "v", "next": "w"}},
+	} {
+		if got := parseLogfmt(tt.in); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("%q: got %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestLogfmt(t *testing.T) {
+	setDefaults(t)
+	const msg = `auth failed: reason="bad token" user=alice attempt=3`
+	got := errors.New(msg)
+	for _, tt := range []struct {
+		got  error
+		want map[string]string
+		out  string
+	}{
+		{got, nil, ""},
+		{got, map[string]string{"user": "alice", "reason": "bad token"}, ""},
+		{got, map[string]string{"attempt": "3"}, ""},
+		{nil, map[string]string{"user": "alice"}, sprintf(missing)},
+		{got, map[string]string{"user": "bob"}, sprintf(wrongValue, msg, "bob", "user", "alice")},
+		{got, map[string]string{"host": "x"}, sprintf(missingKey, msg, "host")},
+	} {
+		if s := Error(tt.got, Logfmt(tt.want)); s != tt.out {
+			t.Errorf("%v %v: got %q, want %q", tt.got, tt.want, s, tt.out)
+		}
+	}
+	if d := Describe(Logfmt(map[string]string{"user": "alice", "a": "b c"})); d != `a message with a="b c" user="alice"` {
+		t.Errorf("got description %s", d)
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"strings"
+	"sync"
+)
+
+// Logs holds the output captured by CaptureLogs or CaptureSlog.  Logs is an
+// io.Writer, so the output of other loggers may also be written to it.  A Logs
+// is safe for concurrent use.
+type Logs struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+// CaptureLogs returns the output logged by the standard logger of the log
+// package while f runs, such as by log.Printf.  The standard logger is
+// restored when f returns.  While f runs the standard logger has no flags or
+// prefix, so each line logged is exactly the message logged, permitting it to
+// be matched with Equal:
+//
+//	logs := check.CaptureLogs(func() { srv.Handle(req) })
+//	if s := logs.Contains(check.Regexp(`^retrying after \d+ms$`)); s != "" {
+//		t.Error(s)
+//	}
+//
+// The output of log/slog is captured as well, unless slog.SetDefault has been
+// called, as slog writes through the standard logger by default.  Output
+// logged by other goroutines while f runs is captured too.
+func CaptureLogs(f func()) *Logs {
+	l := &Logs{}
+	restore := saveLog()
+	defer restore()
+	log.SetOutput(l)
+	log.SetFlags(0)
+	log.SetPrefix("")
+	f()
+	return l
+}
+
+// saveLog returns a function that restores the output, flags, and prefix of
+// the standard logger to what they are now.
+func saveLog() func() {
+	out, flags, prefix := log.Writer(), log.Flags(), log.Prefix()
+	return func() {
+		log.SetOutput(out)
+		log.SetFlags(flags)
+		log.SetPrefix(prefix)
+	}
+}
+
+// Write appends p to the output held by l.
+func (l *Logs) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.buf.Write(p)
+}
+
+// String returns the output held by l.
+func (l *Logs) String() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.buf.String()
+}
+
+// Lines returns the lines of output held by l, without their newlines.
+func (l *Logs) Lines() []string {
+	s := strings.TrimSuffix(l.String(), "\n")
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
+
+// Contains returns the empty string if a line of output held by l is matched
+// by want, as by Error of an error whose message is the line, otherwise it
+// returns a failure listing the lines logged.  A want of a string matches a
+// line containing it, and Case, Equal, CaseEqual, and Regexp wants match as
+// they do for Error.
+func (l *Logs) Contains(want interface{}) string {
+	return defaults().logsContain(l.Lines(), want)
+}
+
+// logsContain implements Contains using the settings in c.
+func (c *config) logsContain(lines []string, want interface{}) string {
+	if c.outer() {
+		return c.run("Contains", nil, func() string { return Describe(want) },
+			func(c *config) string { return c.logsContain(lines, want) })
+	}
+	q := *c
+	q.quiet = true
+	for _, line := range lines {
+		if q.checkError(errors.New(line), want) == "" {
+			return ""
+		}
+	}
+	if c.quiet {
+		return quietFailure
+	}
+	if len(lines) == 0 {
+		return c.failc(CodeMissing, "nothing logged, want a line that %q", description(Describe(want)))
+	}
+	var b strings.Builder
+	b.WriteString(c.failc(CodeMissing, "no logged line %q, logged:", description(Describe(want))))
+	for _, line := range lines {
+		b.WriteString("\n\t")
+		b.WriteString(line)
+	}
+	return b.String()
+}
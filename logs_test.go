@@ -0,0 +1,77 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"reflect"
+	"testing"
+)
+
+func TestCaptureLogs(t *testing.T) {
+	setDefaults(t)
+	var out bytes.Buffer
+	defer saveLog()()
+	log.SetOutput(&out)
+	log.SetFlags(log.LstdFlags)
+	log.SetPrefix("app: ")
+
+	logs := CaptureLogs(func() {
+		log.Printf("retrying after %dms", 250)
+		log.Print("request failed: EOF")
+	})
+	log.Print("after")
+	if got, want := logs.Lines(), []string{"retrying after 250ms", "request failed: EOF"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got lines %q, want %q", got, want)
+	}
+	if log.Writer() != &out || log.Flags() != log.LstdFlags || log.Prefix() != "app: " {
+		t.Errorf("standard logger not restored")
+	}
+	if !bytes.HasPrefix(out.Bytes(), []byte("app: ")) || bytes.Contains(out.Bytes(), []byte("retrying")) {
+		t.Errorf("got output %q after capture", out.String())
+	}
+
+	for _, tt := range []struct {
+		want interface{}
+		out  string
+	}{
+		{"request failed", ""},
+		{Case("REQUEST"), ""},
+		{Equal("retrying after 250ms"), ""},
+		{Regexp(`^retrying after \d+ms$`), ""},
+		{true, ""},
+		{Equal("retrying"), defaults().failc(CodeMissing, `no logged line is "retrying", logged:`) +
+			"\n\tretrying after 250ms\n\trequest failed: EOF"},
+	} {
+		if s := logs.Contains(tt.want); s != tt.out {
+			t.Errorf("%v: got %q, want %q", tt.want, s, tt.out)
+		}
+	}
+
+	empty := CaptureLogs(func() {})
+	if lines := empty.Lines(); lines != nil {
+		t.Errorf("got lines %q, want none", lines)
+	}
+	if s, want := empty.Contains("x"), `nothing logged, want a line that contains "x"`; s != want {
+		t.Errorf("empty: got %q, want %q", s, want)
+	}
+
+	io.WriteString(empty, "written\n")
+	if s := empty.Contains("written"); s != "" {
+		t.Errorf("written: %s", s)
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// A Matcher is a want value that makes its own check of an error.  Matchers
+// are returned by functions such as Similar and are passed to Error as want.
+type Matcher interface {
+	// Describe returns a human readable description of the errors
+	// matched, such as "a single line message".
+	Describe() string
+
+	// match returns the empty string if got matches or a string describing
+	// why it did not, using the settings in c.
+	match(c *config, got error) string
+}
+
+// A CustomMatcher is a want value, defined outside of this package, that makes
+// its own check of an error, such as checking a project's own error codes.
+// Match returns the empty string if got, which may be nil, matches, otherwise
+// it returns what was wanted, e.g., "code 7 (got code 3)".  Error renders
+// the result as a standard failure:
+//
+//	got error "bad request", want code 7 (got code 3)
+//
+// If a CustomMatcher also has a Describe() string method it is used to
+// describe the matcher.
+type CustomMatcher interface {
+	Match(got error) string
+}
+
+// matchCustom returns the result of checking got with m.
+func (c *config) matchCustom(m CustomMatcher, got error) string {
+	s := m.Match(got)
+	switch {
+	case s == "":
+		return ""
+	case got == nil:
+		return c.failc(CodeMissing, "did not get expected error, want %q", description(s))
+	default:
+		return c.failc(CodeWrong, "got error %q, want %q", got, description(s))
+	}
+}
+
+// A matchFunc is a func(error) string want used as a CustomMatcher.
+type matchFunc func(got error) string
+
+func (f matchFunc) Match(got error) string { return f(got) }
+
+const notSatisfied = "got error %q, want an error satisfying the predicate"
+
+// matchPredicate checks got against the func(error) bool want pred, which is
+// passed got even if it is nil.
+func (c *config) matchPredicate(pred func(error) bool, got error) string {
+	switch {
+	case pred == nil:
+		if c.quiet {
+			return quietFailure
+		}
+		return c.code(CodeUnsupported) + "Check does not support a nil func"
+	case pred(got):
+		return ""
+	case got == nil:
+		return c.failc(CodeMissing, "did not get expected error, want an error satisfying the predicate")
+	default:
+		return c.failf(notSatisfied, got)
+	}
+}
+
+type presence bool
+
+// None and Some are wants that unambiguously express that no error, or any
+// error, is wanted.  They are the same as the wants nil and false, and true,
+// but read clearly in a table and are not confused with an empty message.
+const (
+	None = presence(false) // no error is wanted
+	Some = presence(true)  // any error is wanted
+)
+
+func (m presence) match(c *config, got error) string {
+	return c.checkError(got, bool(m))
+}
+
+type maxLen int
+
+// MaxLen returns a Matcher that matches an error whose message is at most n
+// runes long.
+func MaxLen(n int) Matcher { return maxLen(n) }
+
+func (m maxLen) match(c *config, got error) string {
+	if got == nil {
+		return c.failf(missing)
+	}
+	if n := utf8.RuneCountInString(got.Error()); n > int(m) {
+		return c.failc(CodeWrong, sprintf("got error %%q, want a message of at most %d runes (got %d)", m, n), got)
+	}
+	return ""
+}
+
+type singleLine struct{}
+
+// SingleLine returns a Matcher that matches an error whose message is a
+// single line, that is, has no newlines.
+func SingleLine() Matcher { return singleLine{} }
+
+const multiLine = "got error %q, want a single line message"
+
+func (singleLine) match(c *config, got error) string {
+	switch {
+	case got == nil:
+		return c.failf(missing)
+	case strings.Contains(got.Error(), "\n"):
+		return c.failf(multiLine, got)
+	default:
+		return ""
+	}
+}
+
+type nonEmpty struct{}
+
+// NonEmptyMessage returns a Matcher that matches an error whose message is
+// not empty or only white space.
+func NonEmptyMessage() Matcher { return nonEmpty{} }
+
+const emptyMessage = "got error %q, want a non-empty message"
+
+func (nonEmpty) match(c *config, got error) string {
+	switch {
+	case got == nil:
+		return c.failf(missing)
+	case strings.TrimSpace(got.Error()) == "":
+		return c.failf(emptyMessage, got)
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,246 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestShape(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		got  error
+		want Matcher
+		out  string
+	}{
+		{
+			name: "maxlen",
+			got:  errors.New("short"),
+			want: MaxLen(5),
+		}, {
+			name: "maxlen runes",
+			got:  errors.New("日本語"),
+			want: MaxLen(3),
+		}, {
+			name: "maxlen long",
+			got:  errors.New("too long"),
+			want: MaxLen(5),
+			out:  `got error "too long", want a message of at most 5 runes (got 8)`,
+		}, {
+			name: "maxlen missing",
+			want: MaxLen(5),
+			out:  `did not get expected error`,
+		}, {
+			name: "single line",
+			got:  errors.New("one line"),
+			want: SingleLine(),
+		}, {
+			name: "single line multi",
+			got:  errors.New("line one\nline two"),
+			want: SingleLine(),
+			out:  "got error:\n\tline one\n\tline two\nwant a single line message",
+		}, {
+			name: "single line missing",
+			want: SingleLine(),
+			out:  `did not get expected error`,
+		}, {
+			name: "non-empty",
+			got:  errors.New("x"),
+			want: NonEmptyMessage(),
+		}, {
+			name: "empty",
+			got:  errors.New(" "),
+			want: NonEmptyMessage(),
+			out:  `got error " ", want a non-empty message`,
+		}, {
+			name: "empty missing",
+			want: NonEmptyMessage(),
+			out:  `did not get expected error`,
+		},
+	} {
+		if s := Error(tt.got, tt.want); s != tt.out {
+			t.Errorf("%s: got %q, want %q", tt.name, s, tt.out)
+		}
+	}
+}
+
+// codeErr is an error with a code.
+type codeErr int
+
+func (e codeErr) Error() string { return sprintf("error %d", int(e)) }
+
+// hasCode is a CustomMatcher that matches a codeErr with its code.
+type hasCode int
+
+func (m hasCode) Match(got error) string {
+	var e codeErr
+	switch {
+	case !errors.As(got, &e):
+		return sprintf("code %d", int(m))
+	case int(e) != int(m):
+		return sprintf("code %d (got code %d)", int(m), int(e))
+	}
+	return ""
+}
+
+// describedCode is a hasCode with a description.
+type describedCode struct{ hasCode }
+
+func (describedCode) Describe() string { return "a described code" }
+
+func TestCustomMatcher(t *testing.T) {
+	setDefaults(t)
+	for _, tt := range []struct {
+		got  error
+		want interface{}
+		out  string
+	}{
+		{codeErr(7), hasCode(7), ""},
+		{codeErr(3), hasCode(7), `got error "error 3", want code 7 (got code 3)`},
+		{errors.New("other"), hasCode(7), `got error "other", want code 7`},
+		{nil, hasCode(7), "did not get expected error, want code 7"},
+		{codeErr(3), describedCode{7}, `got error "error 3", want code 7 (got code 3)`},
+	} {
+		if s := Error(tt.got, tt.want); s != tt.out {
+			t.Errorf("Error(%v, %v): got %q, want %q", tt.got, tt.want, s, tt.out)
+		}
+	}
+	setDefaults(t, Codes())
+	if s := Error(nil, hasCode(7)); s != "CHK-MISSING: did not get expected error, want code 7" {
+		t.Errorf("with Codes got %q", s)
+	}
+	if d := Describe(hasCode(7)); d != "matches check.hasCode" {
+		t.Errorf("got description %q", d)
+	}
+	if d := Describe(describedCode{7}); d != "a described code" {
+		t.Errorf("got description %q", d)
+	}
+	if err := Validate(hasCode(7)); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+}
+
+// verbatim is a CustomMatcher that wants an error with its message and
+// returns itself when got does not match.
+type verbatim string
+
+func (m verbatim) Match(got error) string {
+	if got != nil && got.Error() == string(m) {
+		return ""
+	}
+	return string(m)
+}
+
+func TestVerbsInDescriptions(t *testing.T) {
+	setDefaults(t)
+	for _, tt := range []struct {
+		name string
+		s    string
+		out  string
+	}{
+		{"Match", Error(io.EOF, verbatim(`a "%q" %s b`)), `got error "EOF", want a "%q" %s b`},
+		{"Match nil", Error(nil, verbatim("%q")), "did not get expected error, want %q"},
+		{"Not", Error(errors.New("%q"), Not("%q")), `got error "%q", want ` + Describe(Not("%q"))},
+		{"ContainsAll", Error(nil, ContainsAll{"%q"}), "did not get expected error, want " + Describe(ContainsAll{"%q"})},
+		{"Panic", Panic(func() {}, "%q"), "did not panic, want panic: " + Describe("%q")},
+		{"Batch", Batch(io.EOF, FirstFailure, "%q"), `got error "EOF", want the first failure: ` + Describe("%q")},
+	} {
+		if tt.s != tt.out {
+			t.Errorf("%s: got %q, want %q", tt.name, tt.s, tt.out)
+		}
+	}
+	setDefaults(t, Render(RenderColumns))
+	if s := Batch(io.EOF, FirstFailure, true, "EOF"); !strings.Contains(s, "| want only the first failure, not failure 1\n") {
+		t.Errorf("columns: got:\n%s", s)
+	}
+}
+
+func TestPredicate(t *testing.T) {
+	setDefaults(t)
+	temporary := func(err error) bool {
+		var te interface{ Temporary() bool }
+		return errors.As(err, &te) && te.Temporary()
+	}
+	noErrorOrTemporary := func(err error) bool { return err == nil || temporary(err) }
+	short := func(err error) string {
+		if err == nil || len(err.Error()) <= 3 {
+			return ""
+		}
+		return "a message of 3 bytes or less"
+	}
+	eof := errors.New("EOF")
+	long := errors.New("too long")
+	for _, tt := range []struct {
+		name string
+		got  error
+		want interface{}
+		out  string
+	}{
+		{"bool", timeoutErr{}, temporary, ""},
+		{"bool false", eof, temporary, sprintf(notSatisfied, eof)},
+		{"bool nil", nil, temporary, "did not get expected error, want an error satisfying the predicate"},
+		{"bool nil true", nil, noErrorOrTemporary, ""},
+		{"string", eof, short, ""},
+		{"string nil", nil, short, ""},
+		{"string fail", long, short, `got error "too long", want a message of 3 bytes or less`},
+		{"nil bool", eof, (func(error) bool)(nil), "Check does not support a nil func"},
+		{"nil string", eof, (func(error) string)(nil), "Check does not support a nil func"},
+	} {
+		if s := Error(tt.got, tt.want); s != tt.out {
+			t.Errorf("%s: got %q, want %q", tt.name, s, tt.out)
+		}
+	}
+	if d := Describe(temporary); d != "satisfies a func(error) bool" {
+		t.Errorf("got description %q", d)
+	}
+	if d := Describe(short); d != "satisfies a func(error) string" {
+		t.Errorf("got description %q", d)
+	}
+	if err := Validate(temporary, short, (func(error) bool)(nil)); err == nil || err.Error() != "want 2: nil func(error) bool" {
+		t.Errorf("got validation error %v", err)
+	}
+}
+
+func TestPresence(t *testing.T) {
+	setDefaults(t)
+	eof := errors.New("EOF")
+	for _, tt := range []struct {
+		got  error
+		want interface{}
+		out  string
+	}{
+		{nil, None, ""},
+		{eof, None, sprintf(unexpected, eof)},
+		{eof, Some, ""},
+		{nil, Some, missing},
+	} {
+		if s := Error(tt.got, tt.want); s != tt.out {
+			t.Errorf("Error(%v, %v): got %q, want %q", tt.got, tt.want, s, tt.out)
+		}
+	}
+	if d := Describe(None) + ", " + Describe(Some); d != "no error, any error" {
+		t.Errorf("got descriptions %q", d)
+	}
+	if n := wantName(None) + " " + wantName(Some); n != "no_error any_error" {
+		t.Errorf("got names %q", n)
+	}
+	setDefaults(t, NoEmptyWants())
+	if s := Error(nil, None); s != "" {
+		t.Errorf("NoEmptyWants: got %q", s)
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// A Cache memoizes the results of checks that depend only on the message of
+// the error being checked, which are those whose want is a string, Case,
+// Equal, CaseEqual, or Regexp.  A Cache is useful when the same checks are repeated
+// many times, such as when fuzzing or soak testing.  A Cache is safe for
+// concurrent use.
+type Cache struct {
+	size   int
+	hits   uint64
+	misses uint64
+
+	mu      sync.Mutex
+	results map[cacheKey]string
+}
+
+// cacheKey identifies a check.  The settings that change how a failure is
+// rendered, or how messages are compared, are part of the key.  Others, such
+// as the test of a Tester, are not, so checks made with different settings
+// share results.
+type cacheKey struct {
+	quoting   Quoting
+	width     int
+	rendering Rendering
+	showCaret bool
+	codes     bool
+	hints     bool
+	quiet     bool
+	maxLen    int
+	lower     *lowerer
+	norms     *[]Normalizer
+	verb      string
+
+	msg  string
+	want interface{}
+}
+
+// CacheStats are the statistics of a Cache.
+type CacheStats struct {
+	Hits    uint64 // checks answered by the cache
+	Misses  uint64 // checks that were made and added to the cache
+	Entries int    // the number of results in the cache
+}
+
+// HitRate returns the fraction of lookups answered by the cache, or 0 if
+// there have been none.
+func (s CacheStats) HitRate() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+// NewCache returns a new Cache that holds at most size results.  When a full
+// cache needs to add a result it first discards all the results it holds.  A
+// size of 0 or less is unlimited.
+func NewCache(size int) *Cache {
+	return &Cache{
+		size:    size,
+		results: map[cacheKey]string{},
+	}
+}
+
+// Stats returns the statistics of c.
+func (c *Cache) Stats() CacheStats {
+	c.mu.Lock()
+	n := len(c.results)
+	c.mu.Unlock()
+	return CacheStats{
+		Hits:    atomic.LoadUint64(&c.hits),
+		Misses:  atomic.LoadUint64(&c.misses),
+		Entries: n,
+	}
+}
+
+// Reset discards the results held by c and zeros its statistics.
+func (c *Cache) Reset() {
+	c.mu.Lock()
+	c.results = map[cacheKey]string{}
+	atomic.StoreUint64(&c.hits, 0)
+	atomic.StoreUint64(&c.misses, 0)
+	c.mu.Unlock()
+}
+
+// Memoize returns an Option that causes checks to use cache, which is
+// normally shared by many checks:
+//
+//	cache := check.NewCache(0)
+//	check.SetDefaults(check.Memoize(cache))
+//
+// Memoize(nil) disables memoization, which is the default.
+func Memoize(cache *Cache) Option {
+	return func(c *config) { c.cache = cache }
+}
+
+// memoized returns the result of checking got against want, using the cache
+// of c if the result only depends on the message of got.  It returns false if
+// the check cannot be memoized.
+func (c *config) memoized(got error, want interface{}) (string, bool) {
+	if got == nil {
+		return "", false
+	}
+	switch want.(type) {
+	case string, Case, Equal, CaseEqual, Regexp:
+	default:
+		return "", false
+	}
+	cache := c.cache
+	key := cacheKey{
+		quoting:   c.quoting,
+		width:     c.width,
+		rendering: c.rendering,
+		showCaret: c.showCaret,
+		codes:     c.codes,
+		hints:     c.hints,
+		quiet:     c.quiet,
+		maxLen:    c.maxMessageLen,
+		lower:     c.lower,
+		norms:     c.norms,
+		verb:      c.verb,
+		msg:       got.Error(),
+		want:      want,
+	}
+	cache.mu.Lock()
+	s, ok := cache.results[key]
+	cache.mu.Unlock()
+	if ok {
+		atomic.AddUint64(&cache.hits, 1)
+		return s, true
+	}
+	atomic.AddUint64(&cache.misses, 1)
+	s, _ = c.matchMessage(got, want)
+	cache.mu.Lock()
+	if cache.size > 0 && len(cache.results) >= cache.size {
+		cache.results = map[cacheKey]string{}
+	}
+	cache.results[key] = s
+	cache.mu.Unlock()
+	return s, true
+}
@@ -0,0 +1,123 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestMemoize(t *testing.T) {
+	cache := NewCache(0)
+	setDefaults(t, Memoize(cache))
+	err1 := errors.New("Error One")
+	for i := 0; i < 3; i++ {
+		if s := Error(err1, Case("one")); s != "" {
+			t.Errorf("got %q", s)
+		}
+		if s, want := Error(err1, "two"), sprintf(wrong, "Error One", "two"); s != want {
+			t.Errorf("got %q, want %q", s, want)
+		}
+		// Options are part of the key.
+		if s, want := Is(err1, io.EOF, Codes()), "CHK-WRONG: "+sprintf(wrong, "Error One", "EOF"); s != want {
+			t.Errorf("got %q, want %q", s, want)
+		}
+		// Not cacheable.
+		if s := Error(err1, err1); s != "" {
+			t.Errorf("got %q", s)
+		}
+	}
+	want := CacheStats{Hits: 4, Misses: 2, Entries: 2}
+	if got := cache.Stats(); got != want {
+		t.Errorf("got stats %+v, want %+v", got, want)
+	}
+	if got, want := cache.Stats().HitRate(), 4.0/6; got != want {
+		t.Errorf("got hit rate %v, want %v", got, want)
+	}
+	// A different message is a different key.
+	if s := Error(errors.New("error one"), Case("one")); s != "" {
+		t.Errorf("got %q", s)
+	}
+	if got := cache.Stats().Entries; got != 3 {
+		t.Errorf("got %d entries, want 3", got)
+	}
+	cache.Reset()
+	if got := cache.Stats(); got != (CacheStats{}) {
+		t.Errorf("after Reset got stats %+v", got)
+	}
+	if got := cache.Stats().HitRate(); got != 0 {
+		t.Errorf("after Reset got hit rate %v", got)
+	}
+
+	// Disabled
+	SetDefaults(Memoize(nil))
+	Error(err1, "two")
+	if got := cache.Stats(); got != (CacheStats{}) {
+		t.Errorf("disabled got stats %+v", got)
+	}
+}
+
+func TestMemoizeKey(t *testing.T) {
+	cache := NewCache(0)
+	calls := 0
+	count := func(msg string) string {
+		calls++
+		return msg
+	}
+	setDefaults(t, Memoize(cache), NormalizeMessages(count))
+	// The test of a Tester is not part of the key.
+	for _, name := range []string{"TestA", "TestB"} {
+		New(&namedTB{name: name}).Checker().Error(io.EOF, "EOF")
+	}
+	if got, want := cache.Stats(), (CacheStats{Hits: 1, Misses: 1, Entries: 1}); got != want {
+		t.Errorf("got stats %+v, want %+v", got, want)
+	}
+	// The message is normalized once, not again on a miss.
+	if calls != 2 {
+		t.Errorf("normalized %d times, want 2", calls)
+	}
+	if s, want := Error(io.EOF, Regexp("x")), sprintf(noMatch, "EOF", "x"); s != want {
+		t.Errorf("Regexp: got %q, want %q", s, want)
+	}
+	if got := cache.Stats().Entries; got != 2 {
+		t.Errorf("Regexp: got %d entries, want 2", got)
+	}
+}
+
+func TestCacheSize(t *testing.T) {
+	cache := NewCache(2)
+	setDefaults(t, Memoize(cache))
+	for _, want := range []string{"a", "b", "c"} {
+		Error(io.EOF, want)
+	}
+	if got := cache.Stats(); got.Entries != 1 || got.Misses != 3 {
+		t.Errorf("got stats %+v, want 1 entry and 3 misses", got)
+	}
+}
+
+var benchErr = errors.New(strings.Repeat("a long error message ", 20))
+
+func benchmarkError(b *testing.B, opts ...Option) {
+	c := defaults().with(append(opts, Caret())...)
+	for i := 0; i < b.N; i++ {
+		c.checkError(benchErr, CaseEqual("a long error message"))
+	}
+}
+
+func BenchmarkError(b *testing.B) { benchmarkError(b) }
+
+func BenchmarkErrorMemoized(b *testing.B) { benchmarkError(b, Memoize(NewCache(0))) }
@@ -0,0 +1,95 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"fmt"
+	"reflect"
+)
+
+type method struct {
+	name string
+	want interface{}
+}
+
+type implements struct{ t reflect.Type }
+
+const (
+	noMethod     = "no error in the chain of %q has a %q method"
+	wrongMethod  = "got error %q with %q returning %q, want %q"
+	noImplements = "no error in the chain of %q implements %q"
+)
+
+// Method returns a Matcher that matches an error whose chain includes an
+// error with a method named name, taking no arguments and returning a single
+// value, that returns want.  The first such error in the chain is called.
+// Integer and string results are compared by value, regardless of their
+// types, as by ErrCode, and other results as by reflect.DeepEqual:
+//
+//	check.Error(err, check.Method("Retryable", true))
+//
+// The failure says whether no error in the chain has the method or the
+// method returned a value other than want.
+func Method(name string, want interface{}) Matcher {
+	return method{name: name, want: want}
+}
+
+// TimeoutMethod returns a Matcher that matches an error whose chain includes
+// an error with a Timeout method, as does a net.Error, that returns want.
+// It is shorthand for Method("Timeout", want).
+func TimeoutMethod(want bool) Matcher {
+	return Method("Timeout", want)
+}
+
+// TemporaryMethod returns a Matcher that matches an error whose chain
+// includes an error with a Temporary method that returns want.  It is
+// shorthand for Method("Temporary", want).
+func TemporaryMethod(want bool) Matcher {
+	return Method("Temporary", want)
+}
+
+func (m method) match(c *config, got error) string {
+	if got == nil {
+		return c.failf(missing)
+	}
+	var v interface{}
+	var found bool
+	walk(got, func(err error) bool {
+		v, found = call(err, m.name)
+		return !found
+	})
+	if !found {
+		return c.failf(noMethod, got, m.name)
+	}
+	if !sameCode(v, m.want) {
+		return c.failf(wrongMethod, got, m.name, fmt.Sprint(v), fmt.Sprint(m.want))
+	}
+	return ""
+}
+
+func (m implements) match(c *config, got error) string {
+	if got == nil {
+		return c.failf(missing)
+	}
+	var found bool
+	walk(got, func(err error) bool {
+		found = reflect.TypeOf(err).AssignableTo(m.t)
+		return !found
+	})
+	if !found {
+		return c.failf(noImplements, got, m.t)
+	}
+	return ""
+}
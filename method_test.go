@@ -0,0 +1,68 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+)
+
+// netErr is a net.Error whose Timeout method returns timeout.
+type netErr struct{ timeout bool }
+
+func (e netErr) Error() string   { return "network error" }
+func (e netErr) Timeout() bool   { return e.timeout }
+func (e netErr) Temporary() bool { return false }
+
+var _ net.Error = netErr{}
+
+func TestMethod(t *testing.T) {
+	setDefaults(t)
+	wrapped := fmt.Errorf("dial: %w", netErr{timeout: true})
+	for _, tt := range []struct {
+		name string
+		got  error
+		want Matcher
+		out  string
+	}{
+		{"timeout", wrapped, TimeoutMethod(true), ""},
+		{"not timeout", netErr{}, TimeoutMethod(false), ""},
+		{"temporary", wrapped, TemporaryMethod(false), ""},
+		{"category error", timeoutErr{}, TemporaryMethod(true), ""},
+		{"wrong", wrapped, TimeoutMethod(false), sprintf(wrongMethod, "dial: network error", "Timeout", "true", "false")},
+		{"missing method", io.EOF, TimeoutMethod(true), sprintf(noMethod, io.EOF, "Timeout")},
+		{"joined", &multi{io.EOF, netErr{timeout: true}}, TimeoutMethod(true), ""},
+		{"first", fmt.Errorf("%w", &multi{netErr{}, timeoutErr{}}), TimeoutMethod(false), ""},
+		{"code", requestErr{404}, Method("Code", 404), ""},
+		{"any method", io.EOF, Method("Error", "EOF"), ""},
+		{"nil", nil, TimeoutMethod(true), missing},
+	} {
+		if s := Error(tt.got, tt.want); s != tt.out {
+			t.Errorf("%s: got %q, want %q", tt.name, s, tt.out)
+		}
+	}
+	if d := Describe(TimeoutMethod(true)); d != `has Timeout() returning "true"` {
+		t.Errorf("got description %q", d)
+	}
+	if err := Validate(Method("", 1)); err == nil || err.Error() != "want 0: Method name is empty" {
+		t.Errorf("got validation error %v", err)
+	}
+	if Matches(errors.New("x"), TimeoutMethod(false)) {
+		t.Errorf("Matches of an error without a Timeout method")
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"errors"
+	"reflect"
+	"unicode"
+)
+
+const (
+	looseAny    = "want %q matches any error"
+	looseMutant = "want %q is loose, it still matches when mutated to %q"
+	looseSwap   = "want %q is loose, it still matches when swapped for %q"
+)
+
+// mutantRune replaces a rune of a string want to make a mutant.
+const mutantRune = '�'
+
+// unrelated is an error no reasonable want expects.
+var unrelated = errors.New(string(mutantRune))
+
+// Tight returns the empty string if got matches want and want is tight,
+// otherwise it returns a string indicating the failure of the check or why
+// want is loose.  A want is loose if:
+//
+//   - it also matches an unrelated error, as Regexp(".*") does
+//   - it still matches got when perturbed, that is, when a single character
+//     of a string, Equal, Case, or CaseEqual want, or a single literal
+//     letter or digit of a Regexp want, is replaced
+//   - got also matches any of swaps, such as the other sentinels of the
+//     package under test, that is not want
+//
+// A loose want is an assertion that can never, or all but never, fail.
+// Tight is typically applied to each row of a table test in place of its
+// usual check:
+//
+//	err := Parse(tt.in)
+//	if s := check.Tight(err, tt.err, parse.ErrSyntax, parse.ErrRange); s != "" {
+//		t.Errorf("Parse(%q): %s", tt.in, s)
+//	}
+//
+// Other wants, such as Matchers, are not perturbed.
+func Tight(got error, want interface{}, swaps ...interface{}) string {
+	return defaults().tight(got, want, swaps)
+}
+
+// Tight is the same as the Tight function but uses the options of ck.
+func (ck *Checker) Tight(got error, want interface{}, swaps ...interface{}) Result {
+	ck.count()
+	return Result(ck.c.tight(got, want, swaps))
+}
+
+func (c *config) tight(got error, want interface{}, swaps []interface{}) string {
+	if c.outer() {
+		return c.run("Tight", got, func() string { return Describe(want) },
+			func(c *config) string { return c.tight(got, want, swaps) })
+	}
+	if s := c.checkError(got, want); s != "" || want == nil {
+		return s
+	}
+	q := *c
+	q.quiet = true
+	q.strict = false // a mutated Regexp need not compile
+	if q.checkError(unrelated, want) == "" {
+		return c.failf(looseAny, Describe(want))
+	}
+	for _, m := range mutants(want) {
+		if q.checkError(got, m) == "" {
+			return c.failf(looseMutant, Describe(want), Describe(m))
+		}
+	}
+	for _, swap := range swaps {
+		if !sameWant(swap, want) && q.checkError(got, swap) == "" {
+			return c.failf(looseSwap, Describe(want), Describe(swap))
+		}
+	}
+	return ""
+}
+
+// sameWant reports whether a and b are the same want.  Wants that are not
+// comparable, such as All, are compared as by reflect.DeepEqual.
+func sameWant(a, b interface{}) bool {
+	if t := reflect.TypeOf(a); t != nil && !t.Comparable() {
+		return reflect.DeepEqual(a, b)
+	}
+	return a == b
+}
+
+// mutants returns the perturbations of want described by Tight.
+func mutants(want interface{}) []interface{} {
+	var ms []interface{}
+	switch want := want.(type) {
+	case string:
+		for _, s := range mutateString(want) {
+			ms = append(ms, s)
+		}
+	case Equal:
+		for _, s := range mutateString(string(want)) {
+			ms = append(ms, Equal(s))
+		}
+	case Case:
+		for _, s := range mutateString(string(want)) {
+			ms = append(ms, Case(s))
+		}
+	case CaseEqual:
+		for _, s := range mutateString(string(want)) {
+			ms = append(ms, CaseEqual(s))
+		}
+	case Regexp:
+		for _, s := range mutateRegexp(string(want)) {
+			ms = append(ms, Regexp(s))
+		}
+	}
+	return ms
+}
+
+// mutateString returns the strings made by replacing each rune of s in turn.
+func mutateString(s string) []string {
+	return mutate(s, func([]rune, int) bool { return true })
+}
+
+// mutateRegexp returns the patterns made by replacing each literal letter or
+// digit of the pattern p in turn.  Replacing other runes, such as those of a
+// character class, may widen rather than perturb the pattern.
+func mutateRegexp(p string) []string {
+	inClass := false
+	return mutate(p, func(rs []rune, i int) bool {
+		escaped := i > 0 && rs[i-1] == '\\'
+		switch {
+		case escaped:
+			return false
+		case rs[i] == '[':
+			inClass = true
+		case rs[i] == ']':
+			inClass = false
+		}
+		return !inClass && (unicode.IsLetter(rs[i]) || unicode.IsDigit(rs[i]))
+	})
+}
+
+// mutate returns the strings made by replacing, in turn, each rune of s for
+// which replace, called with the runes of s and an index, returns true.
+func mutate(s string, replace func(rs []rune, i int) bool) []string {
+	var ss []string
+	rs := []rune(s)
+	for i, r := range rs {
+		if !replace(rs, i) {
+			continue
+		}
+		m := append([]rune(nil), rs...)
+		if r == mutantRune {
+			m[i] = 'x'
+		} else {
+			m[i] = mutantRune
+		}
+		ss = append(ss, string(m))
+	}
+	return ss
+}
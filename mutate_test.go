@@ -0,0 +1,85 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestTight(t *testing.T) {
+	setDefaults(t)
+	got := fmt.Errorf("open: %w", io.EOF)
+	for _, tt := range []struct {
+		name string
+		got  error
+		want interface{}
+		out  string
+	}{
+		{"nil", nil, nil, ""},
+		{"substring", got, "EOF", ""},
+		{"equal", got, Equal("open: EOF"), ""},
+		{"case", got, Case("OPEN"), ""},
+		{"regexp", got, Regexp("^open: [A-Z]+$"), ""},
+		{"sentinel", io.EOF, io.EOF, ""},
+		{"matcher", got, NonEmptyMessage(), sprintf(looseAny, "a non-empty message")},
+		{"no error", nil, "", ""},
+		{"failed", got, "close", sprintf(wrong, got, "close")},
+		{"any", got, true, sprintf(looseAny, Describe(true))},
+		{"class", got, Regexp(`^open: [A-Z]+\.?$`), ""},
+		{"dot star", got, Regexp(".*"), sprintf(looseAny, Describe(Regexp(".*")))},
+		{"optional", got, Regexp("open: (EOF)?"), sprintf(looseMutant, Describe(Regexp("open: (EOF)?")), Describe(Regexp("open: (�OF)?")))},
+	} {
+		if s := Tight(tt.got, tt.want); s != tt.out {
+			t.Errorf("%s: got %q, want %q", tt.name, s, tt.out)
+		}
+	}
+	errSyntax := errors.New("syntax error")
+	errRange := errors.New("out of range")
+	for _, tt := range []struct {
+		got  error
+		want interface{}
+		out  string
+	}{
+		{errSyntax, errSyntax, ""},
+		{fmt.Errorf("line 1: %w", errRange), "range", ""},
+		{errSyntax, "error", sprintf(looseSwap, Describe("error"), Describe(errSyntax))},
+	} {
+		if s := Tight(tt.got, tt.want, errSyntax, errRange, "range", All("quota")); s != tt.out {
+			t.Errorf("Tight(%v, %v) with swaps: got %q, want %q", tt.got, tt.want, s, tt.out)
+		}
+	}
+	setDefaults(t, Strict())
+	if s := Tight(got, Regexp("o(p)en")); s != "" {
+		t.Errorf("strict: got %q", s)
+	}
+}
+
+func TestMutants(t *testing.T) {
+	if ms := mutants(Equal("ab")); len(ms) != 2 || ms[0] != Equal("�b") || ms[1] != Equal("a�") {
+		t.Errorf("got mutants %q", ms)
+	}
+	if ms := mutants("a�"); len(ms) != 2 || ms[1] != "ax" {
+		t.Errorf("got mutants %q", ms)
+	}
+	if ms := mutants(Regexp(`a\d[b-c]{2}`)); len(ms) != 2 || ms[0] != Regexp(`�\d[b-c]{2}`) || ms[1] != Regexp(`a\d[b-c]{�}`) {
+		t.Errorf("got mutants %q", ms)
+	}
+	if ms := mutants(io.EOF); ms != nil {
+		t.Errorf("got mutants %q", ms)
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import "strings"
+
+// A Normalizer returns msg in a canonical form.  The Scrubbers, such as
+// ScrubNetwork, are Normalizers, as are FoldCase, TrimSpace, CollapseSpace,
+// and FirstLineOnly.  Normalizers let other test helpers canonicalize
+// messages exactly as the matchers of this package do.
+type Normalizer = Scrubber
+
+// Normalize returns msg with each of norms applied, in order.
+func Normalize(msg string, norms ...Normalizer) string {
+	for _, f := range norms {
+		msg = f(msg)
+	}
+	return msg
+}
+
+// Pipeline returns a Normalizer that applies each of norms, in order.
+func Pipeline(norms ...Normalizer) Normalizer {
+	return func(msg string) string { return Normalize(msg, norms...) }
+}
+
+// FoldCase returns msg in lower case, as compared by Case and CaseEqual using
+// the default Lowercase option.
+func FoldCase(msg string) string {
+	return defaults().toLower(msg)
+}
+
+// TrimSpace returns msg without leading and trailing white space.
+func TrimSpace(msg string) string {
+	return strings.TrimSpace(msg)
+}
+
+// CollapseSpace returns msg with each run of white space, including
+// newlines, replaced by a single space, and without leading and trailing
+// white space.
+func CollapseSpace(msg string) string {
+	return strings.Join(strings.Fields(msg), " ")
+}
+
+// FirstLineOnly returns the first line of msg, without its newline, as
+// compared by FirstLine.
+func FirstLineOnly(msg string) string {
+	if i := strings.IndexByte(msg, '\n'); i >= 0 {
+		return msg[:i]
+	}
+	return msg
+}
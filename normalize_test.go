@@ -0,0 +1,45 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	setDefaults(t)
+	msg := "  Dial 10.0.0.1:80:\tConnection   REFUSED \n\tat main.go:12"
+	for _, tt := range []struct {
+		norms []Normalizer
+		out   string
+	}{
+		{nil, msg},
+		{[]Normalizer{TrimSpace}, "Dial 10.0.0.1:80:\tConnection   REFUSED \n\tat main.go:12"},
+		{[]Normalizer{FirstLineOnly}, "  Dial 10.0.0.1:80:\tConnection   REFUSED "},
+		{[]Normalizer{FirstLineOnly, CollapseSpace}, "Dial 10.0.0.1:80: Connection REFUSED"},
+		{[]Normalizer{FirstLineOnly, CollapseSpace, FoldCase, ScrubNetwork}, "dial <ip>:<port>: connection refused"},
+		{[]Normalizer{Pipeline(FirstLineOnly, CollapseSpace), FoldCase}, "dial 10.0.0.1:80: connection refused"},
+		{[]Normalizer{CollapseSpace}, "Dial 10.0.0.1:80: Connection REFUSED at main.go:12"},
+	} {
+		if got := Normalize(msg, tt.norms...); got != tt.out {
+			t.Errorf("Normalize(%d normalizers): got %q, want %q", len(tt.norms), got, tt.out)
+		}
+	}
+	// Normalizers may be passed to Scrub.
+	if s := Error(errors.New(msg), Scrub(Equal("dial <ip>:<port>: connection refused"), FirstLineOnly, CollapseSpace, FoldCase, ScrubNetwork)); s != "" {
+		t.Errorf("Scrub: %s", s)
+	}
+}
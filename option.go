@@ -0,0 +1,108 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// An Option changes how a check is made or how its failure is reported.
+type Option func(*config)
+
+// config holds the settings used when making a check.
+type config struct {
+	quoting   Quoting
+	width     int
+	rendering Rendering
+	showCaret bool
+	codes     bool
+	strict    bool
+	noEmpty   bool
+	deadline  time.Duration
+	cache     *Cache
+	lower     *lowerer
+	recorder  *Recorder
+	verb      string
+	scope     string // the name of a Checker made by Child
+	test      namer  // the test of a Tester or Table row, if known
+	nearest   bool
+	verbose   bool
+
+	norms          *[]Normalizer // set by NormalizeMessages
+	ignoreWrapping bool
+	maxMessageLen  int
+	strictUnwrap   bool
+	formatter      *formatter
+	anyOrder       bool
+	categories     *CategoryStats
+	withStack      bool
+	hints          bool
+
+	attachments *attachments
+	reporters   *reporters
+	transforms  *transforms
+
+	// quiet causes all failures to be reported as quietFailure,
+	// without formatting them.
+	quiet bool
+}
+
+// settings holds the *config used by Error, IsError, and the other check
+// functions.  The config is never modified once stored, SetDefaults stores a
+// new one, so checks made by parallel tests read it without locking or
+// racing.
+var settings atomic.Value
+
+// setMu serializes calls to SetDefaults.
+var setMu sync.Mutex
+
+func init() {
+	settings.Store(&config{})
+}
+
+// defaults returns the current default configuration.  The returned config
+// must not be modified.
+func defaults() *config {
+	return settings.Load().(*config)
+}
+
+// SetDefaults applies opts to the package wide default configuration.
+// SetDefaults is normally called from TestMain or an init function, before
+// any checks are made, but it is safe to call while checks are being made.
+// Each check uses the defaults as they were when the check started.
+func SetDefaults(opts ...Option) {
+	setMu.Lock()
+	defer setMu.Unlock()
+	nc := *defaults()
+	for _, opt := range opts {
+		opt(&nc)
+	}
+	settings.Store(&nc)
+}
+
+// with returns c with opts applied.  c itself is returned, unchanged, if
+// there are no opts, otherwise a modified copy of c is returned.
+func (c *config) with(opts ...Option) *config {
+	if len(opts) == 0 {
+		return c
+	}
+	nc := *c
+	for _, opt := range opts {
+		opt(&nc)
+	}
+	return &nc
+}
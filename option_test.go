@@ -0,0 +1,99 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestWith(t *testing.T) {
+	c := &config{}
+	if got := c.with(); got != c {
+		t.Errorf("with no options returned a copy")
+	}
+	got := c.with(Codes())
+	if got == c {
+		t.Fatalf("with options did not return a copy")
+	}
+	if !got.codes || c.codes {
+		t.Errorf("got codes %t, original codes %t, want true, false", got.codes, c.codes)
+	}
+}
+
+// TestConcurrentDefaults makes checks from many goroutines while the defaults
+// are being changed.  It is most useful when run with -race.
+func TestConcurrentDefaults(t *testing.T) {
+	setDefaults(t)
+	err1 := errors.New("Err one")
+	plain := sprintf(wrong, "Err one", "two")
+	valid := map[string]bool{
+		plain:                             true,
+		"CHK-WRONG: " + plain:             true,
+		"got error `Err one`, want `two`": true,
+		"CHK-WRONG: got error `Err one`, want `two`": true,
+	}
+
+	const checkers = 8
+	const checks = 500
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	errs := make(chan string, checkers)
+	for i := 0; i < checkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < checks; j++ {
+				if s := Error(err1, "two"); !valid[s] {
+					errs <- s
+					return
+				}
+				if s := Error(err1, "one"); s != "" {
+					errs <- s
+					return
+				}
+			}
+		}()
+	}
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		for i := 0; ; i++ {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			switch i % 4 {
+			case 0:
+				SetDefaults(Codes())
+			case 1:
+				SetDefaults(Quote(QuoteBack))
+			case 2:
+				settings.Store(&config{})
+			case 3:
+				SetDefaults(Quote(QuoteGo))
+			}
+		}
+	}()
+	wg.Wait()
+	close(done)
+	<-stopped
+	close(errs)
+	for s := range errs {
+		t.Errorf("got unexpected failure %q", s)
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import "strings"
+
+type own struct {
+	want interface{}
+}
+
+// Own returns a Matcher that checks got against want, as by Error, using only
+// the own message of got, as returned by OwnMessage.  Own suits tests of a
+// single layer of wrapping, which should not assert the text of the layers
+// beneath it:
+//
+//	// err is "load config.yaml: open config.yaml: permission denied"
+//	check.Error(err, check.Own(check.Equal("load config.yaml")))
+//
+// As with Scrub, an error want will never be identical to got.
+func Own(want interface{}) Matcher {
+	return own{want: want}
+}
+
+func (o own) match(c *config, got error) string {
+	if got != nil {
+		got = &scrubbed{msg: OwnMessage(got), err: got}
+	}
+	return c.checkError(got, o.want)
+}
+
+// ownSeparators are the characters separating the own message of an error
+// from the message of an error it wraps.
+const ownSeparators = " \t\n:;,-"
+
+// OwnMessage returns the text the message of err adds to the messages of the
+// errors it directly wraps, through either form of Unwrap: the message of err
+// with each of their messages removed, along with the separators, such as
+// ": ", joining them.  For example, the own message of the error returned by
+//
+//	fmt.Errorf("open %s: %w, retrying", path, err)
+//
+// is "open config.yaml, retrying".  OwnMessage returns the empty string if err
+// is nil.
+func OwnMessage(err error) string {
+	if err == nil {
+		return ""
+	}
+	msg := message(err)
+	for _, w := range unwrapAll(err) {
+		wm := message(w)
+		i := strings.LastIndex(msg, wm)
+		if wm == "" || i < 0 {
+			continue
+		}
+		before := strings.TrimRight(msg[:i], ownSeparators)
+		after := msg[i+len(wm):]
+		if before == "" {
+			after = strings.TrimLeft(after, ownSeparators)
+		}
+		msg = before + after
+	}
+	return msg
+}
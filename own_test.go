@@ -0,0 +1,91 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+)
+
+// layerErr is an error whose message does not include that of the error it
+// wraps.
+type layerErr struct {
+	msg string
+	err error
+}
+
+func (e *layerErr) Error() string { return e.msg }
+func (e *layerErr) Unwrap() error { return e.err }
+
+func TestOwnMessage(t *testing.T) {
+	denied := errors.New("permission denied")
+	open := fmt.Errorf("open config.yaml: %w", denied)
+	for _, tt := range []struct {
+		err error
+		own string
+	}{
+		{nil, ""},
+		{io.EOF, "EOF"},
+		{open, "open config.yaml"},
+		{fmt.Errorf("load config.yaml: %w", open), "load config.yaml"},
+		{fmt.Errorf("open config.yaml: %w, retrying", denied), "open config.yaml, retrying"},
+		{fmt.Errorf("%w (while reading)", io.EOF), "(while reading)"},
+		{fmt.Errorf("%w", io.EOF), ""},
+		{fmt.Errorf("read failed: %v", io.EOF), "read failed: EOF"},
+		{&layerErr{msg: "read failed", err: io.EOF}, "read failed"},
+		{multi{io.EOF, denied}, ""},
+		{fmt.Errorf("%w and %w", io.EOF, denied), "and"},
+		{fmt.Errorf("EOF: %w", io.EOF), "EOF"},
+	} {
+		if own := OwnMessage(tt.err); own != tt.own {
+			t.Errorf("OwnMessage(%v): got %q, want %q", tt.err, own, tt.own)
+		}
+	}
+}
+
+func TestOwn(t *testing.T) {
+	setDefaults(t)
+	err := fmt.Errorf("load config.yaml: %w", fmt.Errorf("open config.yaml: %w", io.EOF))
+	for _, tt := range []struct {
+		got  error
+		want interface{}
+		out  string
+	}{
+		{err, Equal("load config.yaml"), ""},
+		{err, "load", ""},
+		{err, io.EOF, sprintf(wrong, "load config.yaml", io.EOF)},
+		{err, "open", sprintf(wrong, "load config.yaml", "open")},
+		{err, nil, sprintf(unexpected, "load config.yaml")},
+		{nil, nil, ""},
+		{nil, "load", sprintf(expected, "load")},
+		{io.EOF, Equal("EOF"), ""},
+	} {
+		if s := Error(tt.got, Own(tt.want)); s != tt.out {
+			t.Errorf("Own(%v) of %v: got %q, want %q", tt.want, tt.got, s, tt.out)
+		}
+	}
+	var perr *layerErr
+	if s := Error(&layerErr{msg: "x", err: io.EOF}, Own(&perr)); s != "" {
+		t.Errorf("Own of *T: %s", s)
+	}
+	if d := Describe(Own("load")); d != `contains "load", own message only` {
+		t.Errorf("got description %q", d)
+	}
+	if err := Validate(Own(1)); err == nil || err.Error() != "want 0: Own: unsupported type int" {
+		t.Errorf("got validation error %v", err)
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+const (
+	unexpectedPanic = "got unexpected panic %q"
+	wrongPanic      = "got panic %q, want panic %q"
+)
+
+// Panic returns the empty string if calling f panics with a value matched by
+// want, otherwise it returns a string indicating the failure.  The value is
+// matched as by Error: a value that is an error is checked as itself and any
+// other value as an error whose message is the value formatted by fmt.Sprint.
+// A want of true matches any panic and a want of false or nil matches only
+// no panic.  A want of a type not supported by Error, such as an int, matches
+// a value deeply equal to it:
+//
+//	check.Panic(func() { regexp.MustCompile("(") }, "missing closing )")
+//	check.Panic(func() { panic(42) }, 42)
+func Panic(f func(), want interface{}) string {
+	return defaults().panics(f, want)
+}
+
+// Panic is the same as the Panic function but uses the options of ck.
+func (ck *Checker) Panic(f func(), want interface{}) Result {
+	ck.count()
+	return Result(ck.c.panics(f, want))
+}
+
+// NoPanic returns the empty string if calling f does not panic, otherwise it
+// returns a string indicating the value f panicked with.
+func NoPanic(f func()) string {
+	return defaults().panics(f, nil)
+}
+
+// NoPanic is the same as the NoPanic function but uses the options of ck.
+func (ck *Checker) NoPanic(f func()) Result {
+	ck.count()
+	return Result(ck.c.panics(f, nil))
+}
+
+// panics implements Panic using the settings in c.
+func (c *config) panics(f func(), want interface{}) string {
+	if c.outer() {
+		name := "Panic"
+		if want == nil {
+			name = "NoPanic"
+		}
+		return c.run(name, nil, func() string { return Describe(want) },
+			func(c *config) string { return c.panics(f, want) })
+	}
+	p, panicked := recovered(f)
+	if !panicked {
+		switch want {
+		case nil, false:
+			return ""
+		}
+		return c.failc(CodeMissing, "did not panic, want panic: %q", description(Describe(want)))
+	}
+	switch want {
+	case nil, false:
+		return c.failf(unexpectedPanic, p)
+	}
+	if validate(want) != nil {
+		if !reflect.DeepEqual(p, want) {
+			return c.failf(wrongPanic, p, want)
+		}
+		return ""
+	}
+	err, ok := p.(error)
+	if !ok {
+		err = errors.New(fmt.Sprint(p))
+	}
+	s := c.checkError(err, want)
+	if s == "" || c.quiet {
+		return s
+	}
+	return prefixed("panic: ", s)
+}
+
+// recovered calls f and returns the value it panicked with, if any, and
+// whether it panicked, even with a nil value.
+func recovered(f func()) (p interface{}, panicked bool) {
+	panicked = true
+	defer func() {
+		if panicked {
+			p = recover()
+		}
+	}()
+	f()
+	panicked = false
+	return nil, false
+}
@@ -0,0 +1,67 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"io"
+	"regexp"
+	"testing"
+)
+
+func TestPanic(t *testing.T) {
+	setDefaults(t)
+	none := func() {}
+	eof := func() { panic(io.EOF) }
+	str := func() { panic("bad index") }
+	num := func() { panic(42) }
+	for _, tt := range []struct {
+		name string
+		f    func()
+		want interface{}
+		out  string
+	}{
+		{"none nil", none, nil, ""},
+		{"none false", none, false, ""},
+		{"none true", none, true, "did not panic, want panic: any error"},
+		{"none string", none, "x", `did not panic, want panic: contains "x"`},
+		{"any", eof, true, ""},
+		{"error", eof, io.EOF, ""},
+		{"wrong error", eof, io.ErrUnexpectedEOF, "panic: " + sprintf(wrong, io.EOF, io.ErrUnexpectedEOF)},
+		{"string", str, "index", ""},
+		{"equal", str, Equal("bad index"), ""},
+		{"wrong string", str, Equal("bad"), "panic: " + sprintf(wrong, "bad index", "bad")},
+		{"unexpected", str, nil, sprintf(unexpectedPanic, "bad index")},
+		{"unexpected false", num, false, sprintf(unexpectedPanic, "42")},
+		{"value", num, 42, ""},
+		{"wrong value", num, 43, sprintf(wrongPanic, "42", "43")},
+		{"value message", num, "42", ""},
+		{"regexp", func() { regexp.MustCompile("(") }, "missing closing )", ""},
+		{"nil panic", func() { panic(nil) }, true, ""},
+	} {
+		if s := Panic(tt.f, tt.want); s != tt.out {
+			t.Errorf("%s: got %q, want %q", tt.name, s, tt.out)
+		}
+	}
+	if s := NoPanic(func() {}); s != "" {
+		t.Errorf("NoPanic: got %q", s)
+	}
+	if s := NoPanic(eof); s != sprintf(unexpectedPanic, "EOF") {
+		t.Errorf("NoPanic: got %q", s)
+	}
+	setDefaults(t, Codes())
+	if s := Panic(str, Equal("bad")); s != "CHK-WRONG: panic: "+sprintf(wrong, "bad index", "bad") {
+		t.Errorf("with Codes got %q", s)
+	}
+}
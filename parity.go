@@ -0,0 +1,117 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import "errors"
+
+// An Equivalence returns the empty string if got and want are equivalent,
+// otherwise it returns a string indicating how they differ.
+type Equivalence func(got, want error) string
+
+// SameMessage is an Equivalence of errors with the same message.
+func SameMessage(got, want error) string {
+	return MessagesEqual(got, want)
+}
+
+// SameMessage is the same as the SameMessage function but uses the options of
+// ck.
+func (ck *Checker) SameMessage(got, want error) Result {
+	return ck.MessagesEqual(got, want)
+}
+
+// SameIs is an Equivalence of errors where one is the other, as determined by
+// errors.Is, such as os.ErrNotExist and an *fs.PathError wrapping it.
+func SameIs(got, want error) string {
+	return defaults().sameIs(got, want)
+}
+
+// SameIs is the same as the SameIs function but uses the options of ck.
+func (ck *Checker) SameIs(got, want error) Result {
+	ck.count()
+	return Result(ck.c.sameIs(got, want))
+}
+
+func (c *config) sameIs(got, want error) string {
+	if c.outer() {
+		return c.run("SameIs", got, func() string { return Describe(want) },
+			func(c *config) string { return c.sameIs(got, want) })
+	}
+	switch {
+	case got == nil && want == nil:
+		return ""
+	case got == nil:
+		return c.failf(expected, want)
+	case want == nil:
+		return c.failf(unexpected, got)
+	case errors.Is(got, want), errors.Is(want, got):
+		return ""
+	default:
+		return c.failf(wrong, got, want)
+	}
+}
+
+// SameCategory is an Equivalence of errors of the same Category, as
+// determined by Classify.
+func SameCategory(got, want error) string {
+	return defaults().sameCategory(got, want)
+}
+
+// SameCategory is the same as the SameCategory function but uses the options of
+// ck.
+func (ck *Checker) SameCategory(got, want error) Result {
+	ck.count()
+	return Result(ck.c.sameCategory(got, want))
+}
+
+func (c *config) sameCategory(got, want error) string {
+	if c.outer() {
+		return c.run("SameCategory", got, func() string { return Describe(want) },
+			func(c *config) string { return c.sameCategory(got, want) })
+	}
+	switch {
+	case got == nil && want == nil:
+		return ""
+	case got == nil:
+		return c.failf(expectedCategory, Classify(want))
+	case want == nil:
+		return c.failf(unexpected, got)
+	}
+	if gc, wc := Classify(got), Classify(want); gc != wc {
+		return c.failf(wrongCategory, got, gc, wc)
+	}
+	return ""
+}
+
+// Parity returns the empty string if calling op with candidate returns an
+// error equivalent, as determined by eq, to the error returned by calling op
+// with reference, otherwise it returns a string indicating the difference.
+// Parity keeps the errors of a fake, the candidate, honest with those of the
+// real implementation, the reference:
+//
+//	op := func(impl interface{}) error {
+//		_, err := impl.(Store).Get("missing")
+//		return err
+//	}
+//	if s := check.Parity(realStore, fakeStore, op, check.SameCategory); s != "" {
+//		t.Error(s)
+//	}
+func Parity(reference, candidate interface{}, op func(impl interface{}) error, eq Equivalence) string {
+	want := op(reference)
+	got := op(candidate)
+	if s := eq(got, want); s != "" {
+		return sprintf("%T differs from %T: %s", candidate, reference, s)
+	}
+	return ""
+}
@@ -0,0 +1,114 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+// fakeStore returns its error from Get.
+type fakeStore struct{ err error }
+
+func (s fakeStore) Get(string) (string, error) { return "", s.err }
+
+func TestParity(t *testing.T) {
+	setDefaults(t)
+	ref := goodStore{"present": "value"}
+	get := func(key string) func(interface{}) error {
+		return func(impl interface{}) error {
+			_, err := impl.(store).Get(key)
+			return err
+		}
+	}
+	pathErr := "get missing: file does not exist"
+	for _, tt := range []struct {
+		name string
+		fake error
+		key  string
+		eq   Equivalence
+		out  string
+	}{
+		{
+			name: "both nil",
+			key:  "present",
+			eq:   SameIs,
+		}, {
+			name: "is",
+			fake: os.ErrNotExist,
+			key:  "missing",
+			eq:   SameIs,
+		}, {
+			name: "is differs",
+			fake: os.ErrPermission,
+			key:  "missing",
+			eq:   SameIs,
+			out:  sprintf(wrong, "permission denied", pathErr),
+		}, {
+			name: "is unexpected",
+			fake: os.ErrNotExist,
+			key:  "present",
+			eq:   SameIs,
+			out:  sprintf(unexpected, "file does not exist"),
+		}, {
+			name: "is missing",
+			key:  "missing",
+			eq:   SameIs,
+			out:  sprintf(expected, pathErr),
+		}, {
+			name: "category",
+			fake: errors.New("404 not found"),
+			key:  "missing",
+			eq:   SameCategory,
+			out:  sprintf(wrongCategory, "404 not found", "Unknown", "NotFound"),
+		}, {
+			name: "category http",
+			fake: httpErr(404),
+			key:  "missing",
+			eq:   SameCategory,
+		}, {
+			name: "category missing",
+			key:  "missing",
+			eq:   SameCategory,
+			out:  sprintf(expectedCategory, "NotFound"),
+		}, {
+			name: "category unexpected",
+			fake: httpErr(404),
+			key:  "present",
+			eq:   SameCategory,
+			out:  sprintf(unexpected, "http 404"),
+		}, {
+			name: "message",
+			fake: errors.New(pathErr),
+			key:  "missing",
+			eq:   SameMessage,
+		}, {
+			name: "message differs",
+			fake: os.ErrNotExist,
+			key:  "missing",
+			eq:   SameMessage,
+			out:  sprintf(wrong, "file does not exist", pathErr),
+		},
+	} {
+		out := tt.out
+		if out != "" {
+			out = "check.fakeStore differs from check.goodStore: " + out
+		}
+		if s := Parity(ref, fakeStore{tt.fake}, get(tt.key), tt.eq); s != out {
+			t.Errorf("%s: got %q, want %q", tt.name, s, out)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+)
+
+type csvParse struct {
+	line, column int
+	err          error
+}
+
+// CSVParse returns a Matcher that matches an error wrapping a
+// *csv.ParseError reported at line and column whose Err is err, as determined
+// by errors.Is, such as csv.ErrQuote.  A line or column of 0, or a nil err,
+// matches any.  Asserting the fields avoids matching the message, whose
+// format has changed between versions of Go.
+func CSVParse(line, column int, err error) Matcher {
+	return csvParse{line: line, column: column, err: err}
+}
+
+const (
+	csvParseType  = "*csv.ParseError"
+	xmlSyntaxType = "*xml.SyntaxError"
+)
+
+func (m csvParse) match(c *config, got error) string {
+	if got == nil {
+		return c.failf(expectedType, csvParseType)
+	}
+	var pe *csv.ParseError
+	if !errors.As(got, &pe) {
+		return c.failf(wrongType, got, csvParseType)
+	}
+	switch {
+	case m.line != 0 && pe.Line != m.line:
+		return c.failc(CodeWrong, sprintf("got error %%q, want line %d (got %d)", m.line, pe.Line), got)
+	case m.column != 0 && pe.Column != m.column:
+		return c.failc(CodeWrong, sprintf("got error %%q, want column %d (got %d)", m.column, pe.Column), got)
+	case m.err != nil && !errors.Is(pe.Err, m.err):
+		return c.failf(wrong, got, m.err)
+	}
+	return ""
+}
+
+type xmlSyntax int
+
+// XMLSyntax returns a Matcher that matches an error wrapping an
+// *xml.SyntaxError reported at line.  A line of 0 matches any line.
+func XMLSyntax(line int) Matcher { return xmlSyntax(line) }
+
+func (m xmlSyntax) match(c *config, got error) string {
+	if got == nil {
+		return c.failf(expectedType, xmlSyntaxType)
+	}
+	var se *xml.SyntaxError
+	if !errors.As(got, &se) {
+		return c.failf(wrongType, got, xmlSyntaxType)
+	}
+	if m != 0 && se.Line != int(m) {
+		return c.failc(CodeWrong, sprintf("got error %%q, want line %d (got %d)", int(m), se.Line), got)
+	}
+	return ""
+}
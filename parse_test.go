@@ -0,0 +1,85 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestCSVParse(t *testing.T) {
+	setDefaults(t)
+	_, got := csv.NewReader(strings.NewReader("a,b\nc,\"d\"e\n")).ReadAll()
+	pe, ok := got.(*csv.ParseError)
+	if !ok {
+		t.Fatalf("csv returned %T", got)
+	}
+	for _, tt := range []struct {
+		line, column int
+		err          error
+		got          error
+		out          string
+	}{
+		{2, pe.Column, csv.ErrQuote, got, ""},
+		{0, 0, nil, fmt.Errorf("load: %w", got), ""},
+		{2, 0, nil, got, ""},
+		{1, 0, nil, got, sprintf("got error %q, want line 1 (got 2)", got)},
+		{2, pe.Column + 1, nil, got, sprintf("got error %q, want column %d (got %d)", got, pe.Column+1, pe.Column)},
+		{0, 0, csv.ErrFieldCount, got, sprintf(wrong, got, csv.ErrFieldCount)},
+		{0, 0, nil, io.EOF, sprintf(wrongType, "EOF", csvParseType)},
+		{0, 0, nil, nil, sprintf(expectedType, csvParseType)},
+	} {
+		if s := Error(tt.got, CSVParse(tt.line, tt.column, tt.err)); s != tt.out {
+			t.Errorf("CSVParse(%d, %d, %v) %v: got %q, want %q", tt.line, tt.column, tt.err, tt.got, s, tt.out)
+		}
+	}
+	if d := Describe(CSVParse(2, 4, csv.ErrQuote)); d != `a *csv.ParseError at line 2 column 4 of "extraneous or missing \" in quoted-field"` {
+		t.Errorf("got description %q", d)
+	}
+	if d := Describe(CSVParse(0, 0, nil)); d != "a *csv.ParseError" {
+		t.Errorf("got description %q", d)
+	}
+}
+
+func TestXMLSyntax(t *testing.T) {
+	setDefaults(t)
+	var v struct{}
+	got := xml.Unmarshal([]byte("<a>\n<b>\n</a>"), &v)
+	for _, tt := range []struct {
+		line int
+		got  error
+		out  string
+	}{
+		{3, got, ""},
+		{0, got, ""},
+		{1, got, sprintf("got error %q, want line 1 (got 3)", got)},
+		{1, io.EOF, sprintf(wrongType, "EOF", xmlSyntaxType)},
+		{1, nil, sprintf(expectedType, xmlSyntaxType)},
+	} {
+		if s := Error(tt.got, XMLSyntax(tt.line)); s != tt.out {
+			t.Errorf("XMLSyntax(%d) %v: got %q, want %q", tt.line, tt.got, s, tt.out)
+		}
+	}
+	if d := Describe(XMLSyntax(3)); d != "an *xml.SyntaxError at line 3" {
+		t.Errorf("got description %q", d)
+	}
+	if err := Validate(XMLSyntax(-1), CSVParse(1, -1, nil)); fmt.Sprint(err) != "want 0: XMLSyntax line -1 is negative\nwant 1: CSVParse line 1 or column -1 is negative" {
+		t.Errorf("got validation error %v", err)
+	}
+}
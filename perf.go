@@ -0,0 +1,99 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"testing"
+	"time"
+)
+
+// perfRuns is the number of times Allocs and MaxDuration run their function.
+const perfRuns = 100
+
+// Allocs returns the empty string if f makes, on average, no more than max
+// allocations per run, as measured by testing.AllocsPerRun, otherwise it
+// returns a failure with the number of allocations made, e.g.:
+//
+//	got 3 allocations per run, want at most 1
+//
+// Allocs permits allocation regressions to be checked by ordinary table
+// tests:
+//
+//	if s := check.Allocs(func() { buf.Format(tt.in) }, tt.allocs); s != "" {
+//		t.Errorf("%s: %s", tt.name, s)
+//	}
+func Allocs(f func(), max float64) string {
+	return defaults().allocs(f, max)
+}
+
+// Allocs is the same as the Allocs function but uses the options of ck.
+func (ck *Checker) Allocs(f func(), max float64) Result {
+	ck.count()
+	return Result(ck.c.allocs(f, max))
+}
+
+func (c *config) allocs(f func(), max float64) string {
+	if c.outer() {
+		return c.run("Allocs", nil, func() string { return sprintf("at most %v allocations per run", max) },
+			func(c *config) string { return c.allocs(f, max) })
+	}
+	n := testing.AllocsPerRun(perfRuns, f)
+	if n <= max {
+		return ""
+	}
+	return c.failc(CodeWrong, sprintf("got %v allocations per run, want at most %v", n, max))
+}
+
+// MaxDuration returns the empty string if the fastest of a number of runs of
+// f takes no more than d, otherwise it returns a failure with the time the
+// fastest run took, e.g.:
+//
+//	fastest run took 12.5ms, want at most 10ms
+//
+// f is run once before it is timed.  Using the fastest run prevents spurious
+// failures when a loaded machine slows some runs, but a test using
+// MaxDuration should still allow for machines slower than the one the test
+// was written on.  Within a testing/synctest bubble time does not advance
+// while f runs, so MaxDuration always succeeds.
+func MaxDuration(f func(), d time.Duration) string {
+	return defaults().maxDuration(f, d)
+}
+
+// MaxDuration is the same as the MaxDuration function but uses the options of
+// ck.
+func (ck *Checker) MaxDuration(f func(), d time.Duration) Result {
+	ck.count()
+	return Result(ck.c.maxDuration(f, d))
+}
+
+func (c *config) maxDuration(f func(), d time.Duration) string {
+	if c.outer() {
+		return c.run("MaxDuration", nil, func() string { return sprintf("at most %v", d) },
+			func(c *config) string { return c.maxDuration(f, d) })
+	}
+	f()
+	var fastest time.Duration
+	for i := 0; i < perfRuns; i++ {
+		start := time.Now()
+		f()
+		if took := time.Since(start); i == 0 || took < fastest {
+			fastest = took
+		}
+		if fastest <= d {
+			return ""
+		}
+	}
+	return c.failc(CodeWrong, sprintf("fastest run took %v, want at most %v", fastest, d))
+}
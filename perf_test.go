@@ -0,0 +1,60 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// allocSink keeps the allocations of TestAllocs from being optimized away.
+var allocSink []byte
+
+func TestAllocs(t *testing.T) {
+	setDefaults(t)
+	if s := Allocs(func() {}, 0); s != "" {
+		t.Errorf("no allocations: got %q", s)
+	}
+	alloc := func() { allocSink = make([]byte, 64) }
+	if s := Allocs(alloc, 1); s != "" {
+		t.Errorf("one allocation: got %q", s)
+	}
+	if s, want := Allocs(alloc, 0.5), "got 1 allocations per run, want at most 0.5"; s != want {
+		t.Errorf("too many allocations: got %q, want %q", s, want)
+	}
+	SetDefaults(Codes())
+	if s := Allocs(alloc, 0); !strings.HasPrefix(s, string(CodeWrong)+": ") {
+		t.Errorf("got %q without its code", s)
+	}
+}
+
+func TestMaxDuration(t *testing.T) {
+	setDefaults(t)
+	if s := MaxDuration(func() {}, time.Second); s != "" {
+		t.Errorf("fast: got %q", s)
+	}
+	runs := 0
+	s := MaxDuration(func() {
+		runs++
+		time.Sleep(time.Millisecond)
+	}, time.Microsecond)
+	if !strings.HasPrefix(s, "fastest run took ") || !strings.HasSuffix(s, ", want at most 1µs") {
+		t.Errorf("slow: got %q", s)
+	}
+	if runs != perfRuns+1 {
+		t.Errorf("slow: got %d runs, want %d", runs, perfRuns+1)
+	}
+}
@@ -0,0 +1,243 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type selector struct {
+	key   func() string
+	wants map[string]interface{}
+}
+
+// Select returns a Matcher that checks got, as by Error, against the want in
+// wants whose key is returned by key, or whose key is "default" if there is
+// none.  key is called each time a check is made, so the want may depend on
+// conditions such as build tags or feature flags:
+//
+//	check.Select(func() string { return os.Getenv("STORAGE") }, map[string]interface{}{
+//		"s3":      "NoSuchKey",
+//		"default": os.ErrNotExist,
+//	})
+//
+// ByOS and ByGoVersion are selectors for common conditions.
+func Select(key func() string, wants map[string]interface{}) Matcher {
+	return selector{key: key, wants: wants}
+}
+
+func (m selector) match(c *config, got error) string {
+	want, err := m.resolve()
+	if err != nil {
+		return c.code(CodeUnsupported) + err.Error()
+	}
+	return c.checkError(got, want)
+}
+
+// resolve returns the want of m for the current key.
+func (m selector) resolve() (interface{}, error) {
+	if m.key == nil {
+		return nil, fmt.Errorf("Select: nil key function")
+	}
+	key := m.key()
+	if want, ok := m.wants[key]; ok {
+		return want, nil
+	}
+	if want, ok := m.wants["default"]; ok {
+		return want, nil
+	}
+	return nil, fmt.Errorf("Select: no want for %q and no default", key)
+}
+
+// goos is the operating system running the tests, as by runtime.GOOS.  It is
+// a variable for testing.
+var goos = runtime.GOOS
+
+type byOS map[string]interface{}
+
+// ByOS returns a Matcher that checks got, as by Error, against the want in
+// wants whose key is the operating system running the test, as reported by
+// runtime.GOOS, or whose key is "default" if there is none:
+//
+//	check.ByOS(map[string]interface{}{
+//		"windows": "The system cannot find the file specified",
+//		"default": "no such file or directory",
+//	})
+//
+// The key "unix" matches the operating systems that are considered Unix by
+// the unix build constraint.
+func ByOS(wants map[string]interface{}) Matcher {
+	return byOS(wants)
+}
+
+func (m byOS) match(c *config, got error) string {
+	want, err := m.resolve()
+	if err != nil {
+		return c.code(CodeUnsupported) + err.Error()
+	}
+	return c.checkError(got, want)
+}
+
+// unixOS is the set of operating systems matched by the unix build
+// constraint.
+var unixOS = map[string]bool{
+	"aix": true, "android": true, "darwin": true, "dragonfly": true,
+	"freebsd": true, "hurd": true, "illumos": true, "ios": true,
+	"linux": true, "netbsd": true, "openbsd": true, "solaris": true,
+}
+
+// resolve returns the want of m for goos.
+func (m byOS) resolve() (interface{}, error) {
+	if want, ok := m[goos]; ok {
+		return want, nil
+	}
+	if want, ok := m["unix"]; ok && unixOS[goos] {
+		return want, nil
+	}
+	if want, ok := m["default"]; ok {
+		return want, nil
+	}
+	return nil, fmt.Errorf("ByOS: no want for %s and no default", goos)
+}
+
+// goVersion is the version of Go running the tests, as reported by
+// runtime.Version.  It is a variable for testing.
+var goVersion = runtime.Version()
+
+type byGoVersion map[string]interface{}
+
+// ByGoVersion returns a Matcher that checks got, as by Error, against the
+// want in wants whose key matches the version of Go running the test.  This
+// keeps tables working when the standard library changes a message:
+//
+//	check.ByGoVersion(map[string]interface{}{
+//		">=1.22": check.Equal("new message"),
+//		"<1.22":  check.Equal("old message"),
+//	})
+//
+// A key is a version, such as 1.21 or 1.21.3, optionally preceded by one of
+// the operators <, <=, >, >=, or ==, which is the default.  A version with
+// only a major and minor number matches all of its patch releases when
+// compared with ==.  Exactly one key must match; development versions of Go
+// are treated as newer than all releases.
+func ByGoVersion(wants map[string]interface{}) Matcher {
+	return byGoVersion(wants)
+}
+
+func (m byGoVersion) match(c *config, got error) string {
+	want, err := m.resolve()
+	if err != nil {
+		return c.code(CodeUnsupported) + err.Error()
+	}
+	return c.checkError(got, want)
+}
+
+// resolve returns the want of m for goVersion.
+func (m byGoVersion) resolve() (interface{}, error) {
+	cur, ok := parseGoVersion(strings.TrimPrefix(goVersion, "go"))
+	if !ok {
+		// A development version, e.g., "devel go1.23-abc123".
+		cur = []int{1 << 30}
+	}
+	var keys []string
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var found []string
+	for _, key := range keys {
+		matched, err := matchGoVersion(key, cur)
+		if err != nil {
+			return nil, err
+		}
+		if matched {
+			found = append(found, key)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("ByGoVersion: no key matches %s", goVersion)
+	case 1:
+		return m[found[0]], nil
+	default:
+		return nil, fmt.Errorf("ByGoVersion: keys %q all match %s", found, goVersion)
+	}
+}
+
+// matchGoVersion reports whether the version cur matches key.
+func matchGoVersion(key string, cur []int) (bool, error) {
+	op := "=="
+	for _, o := range []string{"<=", ">=", "==", "<", ">"} {
+		if strings.HasPrefix(key, o) {
+			op = o
+			break
+		}
+	}
+	v, ok := parseGoVersion(strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(key, op), "go")))
+	if !ok {
+		return false, fmt.Errorf("ByGoVersion: bad version key %q", key)
+	}
+	n := compareGoVersions(cur, v)
+	switch op {
+	case "<":
+		return n < 0, nil
+	case "<=":
+		return n <= 0, nil
+	case ">":
+		return n > 0, nil
+	case ">=":
+		return n >= 0, nil
+	}
+	return n == 0, nil
+}
+
+// parseGoVersion returns the numbers of the version v, such as 1.21.3.  Text
+// following the numbers, such as "rc1", is ignored.
+func parseGoVersion(v string) ([]int, bool) {
+	if i := strings.IndexFunc(v, func(r rune) bool { return (r < '0' || r > '9') && r != '.' }); i >= 0 {
+		v = v[:i]
+	}
+	var nums []int
+	for _, f := range strings.Split(v, ".") {
+		n, err := strconv.Atoi(f)
+		if err != nil {
+			return nil, false
+		}
+		nums = append(nums, n)
+	}
+	return nums, true
+}
+
+// compareGoVersions returns -1, 0, or 1 as a is less than, equal to, or
+// greater than b, comparing only the numbers present in b.
+func compareGoVersions(a, b []int) int {
+	for i, n := range b {
+		var m int
+		if i < len(a) {
+			m = a[i]
+		}
+		switch {
+		case m < n:
+			return -1
+		case m > n:
+			return 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,127 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestByGoVersion(t *testing.T) {
+	setDefaults(t)
+	defer func(v string) { goVersion = v }(goVersion)
+	got := errors.New("new message")
+	want := ByGoVersion(map[string]interface{}{
+		">=1.22": Equal("new message"),
+		"<1.22":  Equal("old message"),
+	})
+	for _, tt := range []struct {
+		version string
+		want    Matcher
+		out     string
+	}{
+		{"go1.22.0", want, ""},
+		{"go1.23.4", want, ""},
+		{"go1.21.9", want, sprintf(wrong, got, "old message")},
+		{"go1.22rc1", want, ""},
+		{"devel go1.23-abc123 Mon Jan 1 2024", want, ""},
+		{"go1.21.3", ByGoVersion(map[string]interface{}{"1.21": "new", "1.22": "old"}), ""},
+		{"go1.21.3", ByGoVersion(map[string]interface{}{"1.21.3": "new", ">1.21.3": "old"}), ""},
+		{"go1.21.3", ByGoVersion(map[string]interface{}{"<=go1.21": "new", ">1.21": "old"}), ""},
+		{"go1.20", ByGoVersion(map[string]interface{}{"1.21": "new"}), "ByGoVersion: no key matches go1.20"},
+		{"go1.21.3", ByGoVersion(map[string]interface{}{"1.21": "new", "<1.22": "new"}), `ByGoVersion: keys ["1.21" "<1.22"] all match go1.21.3`},
+		{"go1.21.3", ByGoVersion(map[string]interface{}{"~1.21": "new"}), `ByGoVersion: bad version key "~1.21"`},
+	} {
+		goVersion = tt.version
+		if s := Error(got, tt.want); s != tt.out {
+			t.Errorf("%s: got %q, want %q", tt.version, s, tt.out)
+		}
+	}
+	goVersion = "go1.21.0"
+	if d := Describe(want); d != `is "old message"` {
+		t.Errorf("got description %q", d)
+	}
+	if err := Validate(ByGoVersion(map[string]interface{}{"1.21": 1})); err == nil || err.Error() != "want 0: unsupported type int" {
+		t.Errorf("got validation error %v", err)
+	}
+}
+
+func TestByOS(t *testing.T) {
+	setDefaults(t)
+	defer func(os string) { goos = os }(goos)
+	got := errors.New("open x: no such file or directory")
+	want := ByOS(map[string]interface{}{
+		"windows": "cannot find the file",
+		"plan9":   "does not exist",
+		"default": "no such file",
+	})
+	for _, tt := range []struct {
+		goos string
+		want Matcher
+		out  string
+	}{
+		{"linux", want, ""},
+		{"windows", want, sprintf(wrong, got, "cannot find the file")},
+		{"plan9", want, sprintf(wrong, got, "does not exist")},
+		{"darwin", ByOS(map[string]interface{}{"unix": "no such", "default": "other"}), ""},
+		{"windows", ByOS(map[string]interface{}{"unix": "no such", "default": "other"}), sprintf(wrong, got, "other")},
+		{"windows", ByOS(map[string]interface{}{"linux": "no such"}), "ByOS: no want for windows and no default"},
+	} {
+		goos = tt.goos
+		if s := Error(got, tt.want); s != tt.out {
+			t.Errorf("%s: got %q, want %q", tt.goos, s, tt.out)
+		}
+	}
+	goos = "windows"
+	if d := Describe(want); d != `contains "cannot find the file"` {
+		t.Errorf("got description %q", d)
+	}
+	if err := Validate(ByOS(map[string]interface{}{"plan9": 1})); err == nil || err.Error() != "want 0: ByOS: no want for windows and no default" {
+		t.Errorf("got validation error %v", err)
+	}
+}
+
+func TestSelect(t *testing.T) {
+	setDefaults(t)
+	flag := "on"
+	key := func() string { return flag }
+	want := Select(key, map[string]interface{}{
+		"on":      "cached",
+		"default": Equal("miss"),
+	})
+	got := errors.New("miss")
+	for _, tt := range []struct {
+		flag string
+		want Matcher
+		out  string
+	}{
+		{"on", want, sprintf(wrong, got, "cached")},
+		{"off", want, ""},
+		{"off", Select(key, map[string]interface{}{"on": "x"}), `Select: no want for "off" and no default`},
+		{"off", Select(nil, nil), "Select: nil key function"},
+	} {
+		flag = tt.flag
+		if s := Error(got, tt.want); s != tt.out {
+			t.Errorf("%s: got %q, want %q", tt.flag, s, tt.out)
+		}
+	}
+	flag = "on"
+	if d := Describe(want); d != `contains "cached"` {
+		t.Errorf("got description %q", d)
+	}
+	if err := Validate(Select(key, map[string]interface{}{"on": 1})); err == nil || err.Error() != "want 0: unsupported type int" {
+		t.Errorf("got validation error %v", err)
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var rowType = reflect.TypeOf(Row{})
+
+// Product returns the rows built by calling build with each combination of
+// one value from each of dims, the cartesian product of dims.  Build must be
+// a function that takes one argument per dimension and returns a Row.  Each
+// of dims must be a slice whose elements are assignable to the corresponding
+// argument of build.  The last dimension varies fastest.  A row built without
+// a Name is named after its values, separated by commas.
+//
+//	rows := check.Product(func(input string, mode int) check.Row {
+//		return check.Row{
+//			Test: func(*check.T) error { return parse(input, mode) },
+//			Want: wants[input],
+//		}
+//	}, []string{"", "{", "[1,]"}, []int{Strict, Lenient})
+//
+// Product panics if build or dims are not as described.
+func Product(build interface{}, dims ...interface{}) []Row {
+	f := reflect.ValueOf(build)
+	ft := f.Type()
+	if ft.Kind() != reflect.Func || ft.NumIn() != len(dims) || ft.NumOut() != 1 || ft.Out(0) != rowType {
+		panic(fmt.Sprintf("check.Product: build is %T, want a func of %d arguments returning check.Row", build, len(dims)))
+	}
+	slices := make([]reflect.Value, len(dims))
+	for i, dim := range dims {
+		v := reflect.ValueOf(dim)
+		if v.Kind() != reflect.Slice || !v.Type().Elem().AssignableTo(ft.In(i)) {
+			panic(fmt.Sprintf("check.Product: dimension %d is %T, want []%v", i, dim, ft.In(i)))
+		}
+		slices[i] = v
+	}
+	var rows []Row
+	args := make([]reflect.Value, len(dims))
+	var product func(i int)
+	product = func(i int) {
+		if i == len(dims) {
+			row := f.Call(args)[0].Interface().(Row)
+			if row.Name == "" {
+				names := make([]string, len(args))
+				for j, arg := range args {
+					names[j] = fmt.Sprint(arg.Interface())
+				}
+				row.Name = strings.Join(names, ",")
+			}
+			rows = append(rows, row)
+			return
+		}
+		for j := 0; j < slices[i].Len(); j++ {
+			args[i] = slices[i].Index(j)
+			product(i + 1)
+		}
+	}
+	product(0)
+	return rows
+}
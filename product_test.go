@@ -0,0 +1,108 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestProduct(t *testing.T) {
+	rows := Product(func(input string, strict bool) Row {
+		row := Row{
+			Test: func(*T) error {
+				if strict && input == "" {
+					return errors.New("empty input")
+				}
+				return nil
+			},
+		}
+		if strict && input == "" {
+			row.Want = "empty"
+		}
+		if input == "x" && !strict {
+			row.Name = "lenient x"
+		}
+		return row
+	}, []string{"", "x"}, []bool{true, false})
+
+	var names []string
+	for _, r := range rows {
+		names = append(names, r.Name)
+	}
+	if got, want := strings.Join(names, " "), ",true ,false x,true lenient x"; got != want {
+		t.Errorf("got names %q, want %q", got, want)
+	}
+	Run(t, rows...)
+
+	if rows := Product(func() Row { return Row{} }); len(rows) != 1 {
+		t.Errorf("no dimensions: got %d rows, want 1", len(rows))
+	}
+	if rows := Product(func(int) Row { return Row{} }, []int{}); len(rows) != 0 {
+		t.Errorf("empty dimension: got %d rows, want 0", len(rows))
+	}
+}
+
+func TestProductPanics(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		build interface{}
+		dims  []interface{}
+		want  string
+	}{
+		{
+			name:  "not func",
+			build: 1,
+			want:  "build is int",
+		}, {
+			name:  "wrong arity",
+			build: func(int) Row { return Row{} },
+			want:  "build is func(int) check.Row, want a func of 0 arguments",
+		}, {
+			name:  "wrong result",
+			build: func(int) error { return nil },
+			dims:  []interface{}{[]int{1}},
+			want:  "returning check.Row",
+		}, {
+			name:  "not slice",
+			build: func(int) Row { return Row{} },
+			dims:  []interface{}{1},
+			want:  "dimension 0 is int, want []int",
+		}, {
+			name:  "wrong element",
+			build: func(int) Row { return Row{} },
+			dims:  []interface{}{[]string{"a"}},
+			want:  "dimension 0 is []string, want []int",
+		},
+	} {
+		msg := panicked(func() { Product(tt.build, tt.dims...) })
+		if !strings.Contains(msg, tt.want) {
+			t.Errorf("%s: got panic %q, want %q", tt.name, msg, tt.want)
+		}
+	}
+}
+
+// panicked calls f and returns the value it panicked with, as a string, or
+// "" if it did not panic.
+func panicked(f func()) (msg string) {
+	defer func() {
+		if r := recover(); r != nil {
+			msg = sprintf("%v", r)
+		}
+	}()
+	f()
+	return ""
+}
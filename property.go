@@ -0,0 +1,84 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import "strings"
+
+// Counterexample returns the empty string if s, the failure of a check made
+// in a property test, is the empty string, otherwise it returns s together
+// with the inputs that produced it in a single block, e.g.:
+//
+//	counterexample:
+//		s = "\x00"
+//		n = 0x1
+//	failure:
+//		got unexpected error "invalid byte"
+//
+// inputs are pairs of the names and values of the inputs.  Values are
+// formatted as by %#v.  With the shrinking of pgregory.net/rapid the inputs
+// are those of the minimal counterexample:
+//
+//	rapid.Check(t, func(t *rapid.T) {
+//		s := rapid.String().Draw(t, "s")
+//		_, err := Parse(Quote(s))
+//		check.Require(t, check.Counterexample(check.NoError(err), "s", s))
+//	})
+func Counterexample(s string, inputs ...interface{}) string {
+	if s == "" {
+		return ""
+	}
+	// A Code of s is moved to the start of the block.
+	var code string
+	if loc := codePrefix.FindStringIndex(s); loc != nil && loc[0] == 0 {
+		code, s = s[:loc[1]], s[loc[1]:]
+	}
+	var b strings.Builder
+	b.WriteString(code)
+	b.WriteString("counterexample:")
+	for i := 0; i < len(inputs); i += 2 {
+		v := "<missing>"
+		if i+1 < len(inputs) {
+			v = sprintf("%#v", inputs[i+1])
+		}
+		b.WriteString(sprintf("\n\t%v = %s", inputs[i], v))
+	}
+	b.WriteString("\nfailure:\n")
+	b.WriteString(indent(s))
+	return b.String()
+}
+
+// A Fataler is a test that can be stopped with a failure, such as a
+// testing.TB or the *rapid.T of the property testing package
+// pgregory.net/rapid.
+type Fataler interface {
+	Helper()
+	Fatal(args ...interface{})
+}
+
+// Require stops t with the failure s, the result of a check, if s is not the
+// empty string.  Require permits checks to be made in the properties of
+// property testing packages, whose tests are not a testing.TB:
+//
+//	rapid.Check(t, func(t *rapid.T) {
+//		s := rapid.String().Draw(t, "s")
+//		_, err := Parse(Quote(s))
+//		check.Require(t, check.NoError(err))
+//	})
+func Require(t Fataler, s string) {
+	if s != "" {
+		t.Helper()
+		t.Fatal(s)
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCounterexample(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		s      string
+		inputs []interface{}
+		out    string
+	}{
+		{"pass", "", []interface{}{"s", "x"}, ""},
+		{"inputs", "got unexpected error \"short\"", []interface{}{"s", "x", "n", uint8(1)},
+			"counterexample:\n\ts = \"x\"\n\tn = 0x1\nfailure:\n\tgot unexpected error \"short\""},
+		{"no inputs", "boom", nil, "counterexample:\nfailure:\n\tboom"},
+		{"odd", "boom", []interface{}{"s"}, "counterexample:\n\ts = <missing>\nfailure:\n\tboom"},
+		{"code", "CHK-WRONG: got error \"a\"\nand more", []interface{}{"n", 2},
+			"CHK-WRONG: counterexample:\n\tn = 2\nfailure:\n\tgot error \"a\"\n\tand more"},
+	} {
+		if s := Counterexample(tt.s, tt.inputs...); s != tt.out {
+			t.Errorf("%s: got %q, want %q", tt.name, s, tt.out)
+		}
+	}
+}
+
+// fatalT is a Fataler that records its failure.
+type fatalT struct{ failure string }
+
+func (t *fatalT) Helper()                   {}
+func (t *fatalT) Fatal(args ...interface{}) { t.failure = fmt.Sprint(args...) }
+
+func TestRequire(t *testing.T) {
+	ft := &fatalT{}
+	Require(ft, "")
+	if ft.failure != "" {
+		t.Errorf("Require of a pass failed with %q", ft.failure)
+	}
+	Require(ft, "boom")
+	if ft.failure != "boom" {
+		t.Errorf("got failure %q, want boom", ft.failure)
+	}
+	var _ Fataler = t
+}
@@ -0,0 +1,147 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// A Quarantine is a list of cases whose failures are logged as warnings
+// rather than reported as errors, until an expiry date.  A Quarantine is used
+// to manage flaky cases without skipping them, and without forgetting them.
+type Quarantine struct {
+	expires map[string]time.Time
+}
+
+// quarantineDate is the format of the expiry dates in a quarantine file.
+const quarantineDate = "2006-01-02"
+
+// ParseQuarantine returns the Quarantine read from r.  Each line names the
+// full name of a case, as returned by t.Name() (e.g., "TestOpen/no_such_file"),
+// followed by the date, in YYYY-MM-DD format, through which it is
+// quarantined.  The rest of the line, typically a reason or a bug, is
+// ignored.  Blank lines and lines starting with # are also ignored:
+//
+//	# flaky on loaded machines
+//	TestDial/timeout  2020-12-31  see issue 42
+func ParseQuarantine(r io.Reader) (*Quarantine, error) {
+	q := &Quarantine{expires: map[string]time.Time{}}
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("line %d: missing expiry date", n)
+		}
+		date, err := time.Parse(quarantineDate, fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: bad expiry date %q", n, fields[1])
+		}
+		// The case is quarantined through the end of the date.
+		q.expires[fields[0]] = date.AddDate(0, 0, 1)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// LoadQuarantine returns the Quarantine read from the file path, as described
+// by ParseQuarantine.
+func LoadQuarantine(path string) (*Quarantine, error) {
+	fd, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer fd.Close()
+	q, err := ParseQuarantine(fd)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return q, nil
+}
+
+// lookup returns the expiry of name and whether name is quarantined.  It is
+// safe to call lookup on a nil Quarantine.
+func (q *Quarantine) lookup(name string) (expires time.Time, ok bool) {
+	if q == nil {
+		return time.Time{}, false
+	}
+	expires, ok = q.expires[name]
+	return expires, ok
+}
+
+// quarantineFlag is the -check.quarantine flag, which is only registered in
+// test binaries.
+var quarantineFlag = new(string)
+
+func init() {
+	if testBinary() {
+		flag.StringVar(quarantineFlag, "check.quarantine", "", "quarantine file for check.Table rows without a Quarantine")
+	}
+}
+
+var flagQuarantine struct {
+	once sync.Once
+	q    *Quarantine
+	err  error
+}
+
+// quarantine returns the Quarantine of tb, or, if tb does not have one, the
+// Quarantine named by the -check.quarantine flag, if any.
+func (tb *Table) quarantine() (*Quarantine, error) {
+	if tb.Quarantine != nil || *quarantineFlag == "" {
+		return tb.Quarantine, nil
+	}
+	fq := &flagQuarantine
+	fq.once.Do(func() { fq.q, fq.err = LoadQuarantine(*quarantineFlag) })
+	return fq.q, fq.err
+}
+
+// quarantined reports the failure s, if any, of the case named name to t,
+// applying q.  A quarantined failure is logged rather than reported as an
+// error.
+func quarantined(t testing.TB, q *Quarantine, name, s string, now time.Time) {
+	t.Helper()
+	expires, ok := q.lookup(name)
+	switch {
+	case !ok && s != "":
+		t.Error(s)
+	case !ok:
+	case now.After(expires) || now.Equal(expires):
+		day := expires.AddDate(0, 0, -1).Format(quarantineDate)
+		if s != "" {
+			t.Errorf("%s\n(quarantine expired %s)", s, day)
+		} else {
+			t.Logf("quarantine expired %s; remove it from the quarantine", day)
+		}
+	case s != "":
+		t.Logf("quarantined until %s, failure ignored: %s",
+			expires.AddDate(0, 0, -1).Format(quarantineDate), s)
+	default:
+		t.Logf("quarantined case passed; consider removing it from the quarantine")
+	}
+}
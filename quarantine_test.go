@@ -0,0 +1,147 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeTB records the errors and logs of a test.  Cleanup functions are
+// passed to cleanup.
+type fakeTB struct {
+	testing.TB
+	errors  []string
+	logs    []string
+	cleanup func(func())
+}
+
+func (t *fakeTB) Helper()                   {}
+func (t *fakeTB) Error(args ...interface{}) { t.errors = append(t.errors, fmt.Sprint(args...)) }
+func (t *fakeTB) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, sprintf(format, args...))
+}
+func (t *fakeTB) Logf(format string, args ...interface{}) {
+	t.logs = append(t.logs, sprintf(format, args...))
+}
+func (t *fakeTB) Cleanup(f func()) { t.cleanup(f) }
+
+const quarantineFile = `
+# flaky
+TestDial/timeout   2020-06-30  issue 42
+TestDial/refused   2020-06-01
+`
+
+func TestParseQuarantine(t *testing.T) {
+	q, err := ParseQuarantine(strings.NewReader(quarantineFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := q.lookup("TestDial/timeout"); !ok || !got.Equal(time.Date(2020, 7, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("timeout: got %v, %t", got, ok)
+	}
+	if _, ok := q.lookup("TestDial/other"); ok {
+		t.Errorf("other is quarantined")
+	}
+	if _, ok := (*Quarantine)(nil).lookup("TestDial/timeout"); ok {
+		t.Errorf("nil Quarantine quarantined a case")
+	}
+
+	for _, tt := range []struct {
+		in  string
+		err string
+	}{
+		{"TestX\n", "line 1: missing expiry date"},
+		{"\nTestX 2020/01/01\n", `line 2: bad expiry date "2020/01/01"`},
+	} {
+		if _, err := ParseQuarantine(strings.NewReader(tt.in)); fmt.Sprint(err) != tt.err {
+			t.Errorf("%q: got error %v, want %s", tt.in, err, tt.err)
+		}
+	}
+}
+
+func TestLoadQuarantine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "quarantine")
+	if err := ioutil.WriteFile(path, []byte("TestX 2020-01-01\nbad\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadQuarantine(path); err == nil || err.Error() != path+": line 2: missing expiry date" {
+		t.Errorf("got error %v", err)
+	}
+	if _, err := LoadQuarantine(path + ".missing"); err == nil {
+		t.Errorf("missing file did not fail")
+	}
+}
+
+func TestQuarantined(t *testing.T) {
+	q, err := ParseQuarantine(strings.NewReader(quarantineFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2020, 6, 15, 12, 0, 0, 0, time.UTC)
+	for _, tt := range []struct {
+		name   string
+		s      string
+		errors []string
+		logs   []string
+	}{
+		{name: "TestDial/other"},
+		{
+			name:   "TestDial/other",
+			s:      "failed",
+			errors: []string{"failed"},
+		}, {
+			name: "TestDial/timeout",
+			s:    "failed",
+			logs: []string{"quarantined until 2020-06-30, failure ignored: failed"},
+		}, {
+			name: "TestDial/timeout",
+			logs: []string{"quarantined case passed; consider removing it from the quarantine"},
+		}, {
+			name:   "TestDial/refused",
+			s:      "failed",
+			errors: []string{"failed\n(quarantine expired 2020-06-01)"},
+		}, {
+			name: "TestDial/refused",
+			logs: []string{"quarantine expired 2020-06-01; remove it from the quarantine"},
+		},
+	} {
+		var ft fakeTB
+		quarantined(&ft, q, tt.name, tt.s, now)
+		if fmt.Sprint(ft.errors) != fmt.Sprint(tt.errors) || fmt.Sprint(ft.logs) != fmt.Sprint(tt.logs) {
+			t.Errorf("%s %q: got errors %q and logs %q, want %q and %q", tt.name, tt.s, ft.errors, ft.logs, tt.errors, tt.logs)
+		}
+	}
+}
+
+func TestTableQuarantine(t *testing.T) {
+	expires := time.Now().AddDate(0, 0, 7).Format(quarantineDate)
+	q, err := ParseQuarantine(strings.NewReader(t.Name() + "/flaky " + expires))
+	if err != nil {
+		t.Fatal(err)
+	}
+	(&Table{
+		Quarantine: q,
+		Rows: []Row{{
+			Name: "flaky",
+			Test: func(*T) error { return io.EOF },
+		}},
+	}).Run(t)
+}
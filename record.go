@@ -0,0 +1,173 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// A Recorder records the distinct messages of the errors checked with the
+// Record option.  A Recorder is safe for concurrent use.
+type Recorder struct {
+	mu   sync.Mutex
+	msgs map[string]int
+}
+
+// NewRecorder returns a new, empty, Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{msgs: map[string]int{}}
+}
+
+// Record returns an Option that records the message of each non-nil error
+// checked in r, whether or not the check passes.  Recording the errors of an
+// entire run shows the full surface of error text produced by the code under
+// test, e.g., for a UX or localization review:
+//
+//	var recorder = check.NewRecorder()
+//
+//	func TestMain(m *testing.M) {
+//		check.SetDefaults(check.Record(recorder))
+//		code := m.Run()
+//		if err := recorder.WriteFile("testdata/errors.txt"); err != nil {
+//			fmt.Fprintln(os.Stderr, err)
+//			code = 1
+//		}
+//		os.Exit(code)
+//	}
+func Record(r *Recorder) Option {
+	return func(c *config) { c.recorder = r }
+}
+
+// add records msg in r.
+func (r *Recorder) add(msg string) {
+	r.mu.Lock()
+	r.msgs[msg]++
+	r.mu.Unlock()
+}
+
+// Messages returns the distinct messages recorded in r, sorted.
+func (r *Recorder) Messages() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	msgs := make([]string, 0, len(r.msgs))
+	for msg := range r.msgs {
+		msgs = append(msgs, msg)
+	}
+	sort.Strings(msgs)
+	return msgs
+}
+
+// Count returns the number of times msg was recorded in r.
+func (r *Recorder) Count(msg string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.msgs[msg]
+}
+
+// WriteFile writes the messages recorded in r to the file at path, sorted,
+// one per line.  Each message is written as a quoted Go string so messages
+// containing newlines or trailing spaces remain readable and unambiguous.
+func (r *Recorder) WriteFile(path string) error {
+	return ioutil.WriteFile(path, formatMessages(r.Messages()), 0644)
+}
+
+// formatMessages returns msgs in the format written by WriteFile.
+func formatMessages(msgs []string) []byte {
+	var b bytes.Buffer
+	for _, msg := range msgs {
+		b.WriteString(strconv.Quote(msg))
+		b.WriteByte('\n')
+	}
+	return b.Bytes()
+}
+
+// parseMessages returns the messages in data, as written by WriteFile.  Blank
+// lines are ignored.
+func parseMessages(data []byte) ([]string, error) {
+	var msgs []string
+	for i, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line == "" {
+			continue
+		}
+		msg, err := strconv.Unquote(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: malformed message %s", i+1, line)
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs, nil
+}
+
+// updateFlag is the -check.update flag, which is only registered in test
+// binaries.
+var updateFlag = new(bool)
+
+func init() {
+	if testBinary() {
+		flag.BoolVar(updateFlag, "check.update", false, "update check baseline, wants, and golden files rather than failing")
+	}
+}
+
+// Baseline compares the messages recorded in r with the baseline file at
+// path, as written by WriteFile, and returns an error listing the recorded
+// messages missing from the baseline.  Messages in the baseline that were not
+// recorded are not an error; a run may not exercise every test.  A missing
+// baseline file is treated as empty.  Baseline catches accidental changes to
+// error text across an entire suite:
+//
+//	code := m.Run()
+//	if err := recorder.Baseline("testdata/errors.txt"); err != nil {
+//		fmt.Fprintln(os.Stderr, err)
+//		code = 1
+//	}
+//
+// When the -check.update flag is set the baseline file is instead replaced by
+// the messages recorded in r, so the update should be made by running the
+// entire suite.
+func (r *Recorder) Baseline(path string) error {
+	if *updateFlag {
+		return r.WriteFile(path)
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	baseline, err := parseMessages(data)
+	if err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+	known := map[string]bool{}
+	for _, msg := range baseline {
+		known[msg] = true
+	}
+	var added []string
+	for _, msg := range r.Messages() {
+		if !known[msg] {
+			added = append(added, "\t"+strconv.Quote(msg))
+		}
+	}
+	if added == nil {
+		return nil
+	}
+	return fmt.Errorf("%s: %d new error messages (run with -check.update to accept them):\n%s", path, len(added), strings.Join(added, "\n"))
+}
@@ -0,0 +1,131 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecord(t *testing.T) {
+	r := NewRecorder()
+	setDefaults(t, Record(r))
+	Error(io.EOF, "EOF")
+	Error(io.EOF, "other")
+	Error(nil, nil)
+	Is(errors.New("line 1\nline 2"), io.EOF)
+	NoError(fmt.Errorf("wrapped: %w", io.EOF))
+	HasError(errors.New("a"), "a")
+	NewChecker().Error(errors.New("z"), nil)
+
+	want := []string{"EOF", "a", "line 1\nline 2", "wrapped: EOF", "z"}
+	if got := r.Messages(); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("got messages %q, want %q", got, want)
+	}
+	if n := r.Count("EOF"); n != 2 {
+		t.Errorf("EOF recorded %d times, want 2", n)
+	}
+
+	path := filepath.Join(t.TempDir(), "errors.txt")
+	if err := r.WriteFile(path); err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := `"EOF"
+"a"
+"line 1\nline 2"
+"wrapped: EOF"
+"z"
+`
+	if string(data) != file {
+		t.Errorf("got file:\n%s\nwant:\n%s", data, file)
+	}
+}
+
+func TestRecordOption(t *testing.T) {
+	setDefaults(t)
+	r := NewRecorder()
+	ck := NewChecker(Record(r))
+	ck.Error(io.EOF, io.EOF)
+	Error(io.ErrUnexpectedEOF, nil)
+	if got := r.Messages(); fmt.Sprint(got) != "[EOF]" {
+		t.Errorf("got messages %q, want [EOF]", got)
+	}
+}
+
+func TestParseMessages(t *testing.T) {
+	msgs, err := parseMessages([]byte("\"a\"\n\n  \"b\\nc\"\n"))
+	if err != nil || fmt.Sprint(msgs) != fmt.Sprint([]string{"a", "b\nc"}) {
+		t.Errorf("got %q, %v", msgs, err)
+	}
+	if _, err := parseMessages([]byte("\"a\"\nb\n")); fmt.Sprint(err) != "line 2: malformed message b" {
+		t.Errorf("got error %v", err)
+	}
+}
+
+func TestBaseline(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "errors.txt")
+	r := NewRecorder()
+	r.add("old")
+	r.add("new\nline")
+	r.add("newer")
+
+	if err := ioutil.WriteFile(path, []byte("\"old\"\n\"gone\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	want := path + `: 2 new error messages (run with -check.update to accept them):
+	"new\nline"
+	"newer"`
+	if err := r.Baseline(path); fmt.Sprint(err) != want {
+		t.Errorf("got error:\n%v\nwant:\n%s", err, want)
+	}
+	missing := filepath.Join(dir, "missing.txt")
+	if err := r.Baseline(missing); err == nil || !strings.Contains(err.Error(), ": 3 new error messages") {
+		t.Errorf("missing baseline: got error %v", err)
+	}
+
+	*updateFlag = true
+	defer func() { *updateFlag = false }()
+	if err := r.Baseline(path); err != nil {
+		t.Fatal(err)
+	}
+	*updateFlag = false
+	if err := r.Baseline(path); err != nil {
+		t.Errorf("after update: %v", err)
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "gone") {
+		t.Errorf("update kept a message that was not recorded:\n%s", data)
+	}
+
+	if err := ioutil.WriteFile(path, []byte("bad\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Baseline(path); fmt.Sprint(err) != path+": line 1: malformed message bad" {
+		t.Errorf("got error %v", err)
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"regexp"
+	"strings"
+)
+
+// redactedMask replaces secrets in failures so the failure does not itself
+// leak them.
+const redactedMask = "***"
+
+// Redacted returns the empty string if none of secrets appear in the message
+// of got or of any error it wraps, otherwise it returns a string indicating
+// where each secret was found.  Secrets are identified by their position in
+// secrets, starting at 1, and are masked in the returned string.  Empty
+// secrets are ignored.
+func Redacted(got error, secrets ...string) string {
+	return defaults().redacted(got, secrets)
+}
+
+// Redacted is the same as the Redacted function but uses the options of ck.
+func (ck *Checker) Redacted(got error, secrets ...string) Result {
+	ck.count()
+	return Result(ck.c.redacted(got, secrets))
+}
+
+func (c *config) redacted(got error, secrets []string) string {
+	if c.outer() {
+		return c.run("Redacted", got, nil,
+			func(c *config) string { return c.redacted(got, secrets) })
+	}
+	mask := func(msg string) string {
+		for _, secret := range secrets {
+			if secret != "" {
+				msg = strings.Replace(msg, secret, redactedMask, -1)
+			}
+		}
+		return msg
+	}
+	var leaks []string
+	walk(got, func(err error) bool {
+		msg := err.Error()
+		for i, secret := range secrets {
+			if secret != "" && strings.Contains(msg, secret) {
+				format := sprintf("got error %%q (%T) containing secret %d", err, i+1)
+				leaks = append(leaks, c.failc(CodeWrong, format, mask(msg)))
+			}
+		}
+		return true
+	})
+	return strings.Join(leaks, "\n")
+}
+
+// piiDetectors are the patterns of sensitive data searched for by NoPII.
+var piiDetectors = []struct {
+	name  string
+	re    *regexp.Regexp
+	valid func(string) bool
+}{
+	{
+		name: "email address",
+		re:   regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`),
+	}, {
+		name:  "card number",
+		re:    regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`),
+		valid: luhn,
+	}, {
+		name: "bearer token",
+		re:   regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9._~+/=-]{8,}`),
+	}, {
+		name: "JSON web token",
+		re:   regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`),
+	},
+}
+
+// NoPII returns the empty string if neither the message of got nor of any
+// error it wraps contains recognizable sensitive data, otherwise it returns a
+// string indicating what was found, and where.  NoPII detects email
+// addresses, payment card numbers (13 to 19 digits, optionally separated by
+// spaces or dashes, that pass the Luhn check), bearer tokens, and JSON web
+// tokens.  Detected data is masked in the returned string.
+func NoPII(got error) string {
+	return defaults().noPII(got)
+}
+
+// NoPII is the same as the NoPII function but uses the options of ck.
+func (ck *Checker) NoPII(got error) Result {
+	ck.count()
+	return Result(ck.c.noPII(got))
+}
+
+func (c *config) noPII(got error) string {
+	if c.outer() {
+		return c.run("NoPII", got, nil,
+			func(c *config) string { return c.noPII(got) })
+	}
+	var leaks []string
+	walk(got, func(err error) bool {
+		msg := err.Error()
+		var found []string
+		for _, d := range piiDetectors {
+			matched := false
+			masked := d.re.ReplaceAllStringFunc(msg, func(s string) string {
+				if d.valid != nil && !d.valid(s) {
+					return s
+				}
+				matched = true
+				return redactedMask
+			})
+			if matched {
+				found = append(found, d.name)
+				msg = masked
+			}
+		}
+		if len(found) > 0 {
+			format := sprintf("got error %%q (%T) containing %s", err, strings.Join(found, ", "))
+			leaks = append(leaks, c.failc(CodeWrong, format, msg))
+		}
+		return true
+	})
+	return strings.Join(leaks, "\n")
+}
+
+// luhn reports whether the digits in s pass the Luhn checksum.  Characters
+// other than digits are ignored.
+func luhn(s string) bool {
+	sum, double := 0, false
+	for i := len(s) - 1; i >= 0; i-- {
+		d := int(s[i] - '0')
+		if d < 0 || d > 9 {
+			continue
+		}
+		if double {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
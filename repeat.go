@@ -0,0 +1,121 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import "strings"
+
+// Budget calls f n times and returns the empty string if at most allowed of
+// the calls returned an error and each error returned matches want, as by
+// Error.  Otherwise it returns a string indicating the errors.  Want is
+// normally a value describing the acceptable failure, such as true (any
+// error) or a sentinel error.
+//
+//	// At most 2 of 100 requests may fail, and only with a timeout.
+//	if s := check.Budget(100, 2, doRequest, ErrTimeout); s != "" {
+//		t.Error(s)
+//	}
+func Budget(n, allowed int, f func() error, want interface{}) string {
+	return defaults().budget(n, allowed, f, want)
+}
+
+// Budget is the same as the Budget function but uses the options of ck.
+func (ck *Checker) Budget(n, allowed int, f func() error, want interface{}) Result {
+	ck.count()
+	return Result(ck.c.budget(n, allowed, f, want))
+}
+
+func (c *config) budget(n, allowed int, f func() error, want interface{}) string {
+	if c.outer() {
+		return c.run("Budget", nil, func() string { return Describe(want) },
+			func(c *config) string { return c.budget(n, allowed, f, want) })
+	}
+	var failures []string
+	var last error
+	failed := 0
+	for i := 0; i < n; i++ {
+		err := f()
+		if err == nil {
+			continue
+		}
+		failed++
+		last = err
+		if s := c.checkError(err, want); s != "" {
+			failures = append(failures, sprintf("call %d: %s", i+1, s))
+		}
+	}
+	if failed > allowed {
+		format := sprintf("%d of %d calls failed, want at most %d, last error %%q", failed, n, allowed)
+		failures = append([]string{c.failc(CodeUnexpected, format, last)}, failures...)
+	}
+	return strings.Join(failures, "\n")
+}
+
+// Stable calls f n times and returns the empty string if the error returned
+// by each call matches want, as by Error, otherwise it returns a string
+// indicating each call that did not.  Calls are numbered from 1.  Stable
+// catches errors that depend on state left by earlier calls.
+func Stable(f func() error, n int, want interface{}) string {
+	return defaults().stable(f, n, want, false)
+}
+
+// Stable is the same as the Stable function but uses the options of ck.
+func (ck *Checker) Stable(f func() error, n int, want interface{}) Result {
+	ck.count()
+	return Result(ck.c.stable(f, n, want, false))
+}
+
+// StableMessage is like Stable but also requires each call to return an error
+// with the same message as the first call whose error matched want, catching
+// messages that include nondeterministic text such as map iteration order or
+// addresses.
+func StableMessage(f func() error, n int, want interface{}) string {
+	return defaults().stable(f, n, want, true)
+}
+
+// StableMessage is the same as the StableMessage function but uses the options
+// of ck.
+func (ck *Checker) StableMessage(f func() error, n int, want interface{}) Result {
+	ck.count()
+	return Result(ck.c.stable(f, n, want, true))
+}
+
+func (c *config) stable(f func() error, n int, want interface{}, sameMessage bool) string {
+	if c.outer() {
+		name := "Stable"
+		if sameMessage {
+			name = "StableMessage"
+		}
+		return c.run(name, nil, func() string { return Describe(want) },
+			func(c *config) string { return c.stable(f, n, want, sameMessage) })
+	}
+	var failures []string
+	var first error
+	matched := false
+	for i := 0; i < n; i++ {
+		err := f()
+		s := c.checkError(err, want)
+		if s == "" && sameMessage {
+			if matched {
+				s = c.messagesEqual(err, first)
+			} else {
+				first, matched = err, true
+			}
+		}
+		if s != "" {
+			failures = append(failures, sprintf("call %d: %s", i+1, s))
+		}
+	}
+	return strings.Join(failures, "\n")
+}
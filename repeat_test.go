@@ -0,0 +1,145 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"errors"
+	"testing"
+)
+
+// failEvery returns a function that returns err on every nth call and nil
+// otherwise.
+func failEvery(n int, err error) func() error {
+	i := 0
+	return func() error {
+		i++
+		if i%n == 0 {
+			return err
+		}
+		return nil
+	}
+}
+
+func TestBudget(t *testing.T) {
+	errTimeout := errors.New("timeout")
+	errOther := errors.New("other")
+	for _, tt := range []struct {
+		name    string
+		n       int
+		allowed int
+		f       func() error
+		want    interface{}
+		out     string
+	}{
+		{
+			name:    "no failures",
+			n:       10,
+			allowed: 0,
+			f:       func() error { return nil },
+			want:    true,
+		}, {
+			name:    "within budget",
+			n:       10,
+			allowed: 2,
+			f:       failEvery(5, errTimeout),
+			want:    errTimeout,
+		}, {
+			name:    "over budget",
+			n:       10,
+			allowed: 1,
+			f:       failEvery(5, errTimeout),
+			want:    errTimeout,
+			out:     `2 of 10 calls failed, want at most 1, last error "timeout"`,
+		}, {
+			name:    "wrong error",
+			n:       10,
+			allowed: 2,
+			f:       failEvery(5, errOther),
+			want:    errTimeout,
+			out:     "call 5: got error \"other\", want \"timeout\"\ncall 10: got error \"other\", want \"timeout\"",
+		},
+	} {
+		if s := Budget(tt.n, tt.allowed, tt.f, tt.want); s != tt.out {
+			t.Errorf("%s: got %q, want %q", tt.name, s, tt.out)
+		}
+	}
+}
+
+// sequence returns a function that returns each of errs in turn, repeating
+// the last.
+func sequence(errs ...error) func() error {
+	i := 0
+	return func() error {
+		err := errs[i]
+		if i < len(errs)-1 {
+			i++
+		}
+		return err
+	}
+}
+
+func TestStable(t *testing.T) {
+	setDefaults(t)
+	a1, a2, b := errors.New("conflict on a"), errors.New("conflict on a"), errors.New("conflict on b")
+	for _, tt := range []struct {
+		name   string
+		f      func() error
+		n      int
+		want   interface{}
+		stable string
+	}{
+		{name: "none", f: sequence(nil), n: 0},
+		{name: "nil", f: sequence(nil), n: 3},
+		{name: "same", f: sequence(a1, a2), n: 3, want: "conflict"},
+		{name: "different messages", f: sequence(a1, b), n: 3, want: "conflict"},
+		{
+			name:   "state dependent",
+			f:      sequence(nil, a1),
+			n:      2,
+			want:   nil,
+			stable: "call 2: " + sprintf(unexpected, "conflict on a"),
+		},
+	} {
+		if s := Stable(tt.f, tt.n, tt.want); s != tt.stable {
+			t.Errorf("%s: Stable got %q, want %q", tt.name, s, tt.stable)
+		}
+	}
+	for _, tt := range []struct {
+		name string
+		f    func() error
+		n    int
+		want interface{}
+		out  string
+	}{
+		{name: "same", f: sequence(a1, a2), n: 3, want: "conflict"},
+		{
+			name: "different messages",
+			f:    sequence(a1, b),
+			n:    3,
+			want: "conflict",
+			out:  "call 2: " + sprintf(wrong, "conflict on b", "conflict on a") + "\ncall 3: " + sprintf(wrong, "conflict on b", "conflict on a"),
+		}, {
+			name: "first nil",
+			f:    sequence(nil, a1),
+			n:    2,
+			want: true,
+			out:  "call 1: " + sprintf(missing),
+		},
+	} {
+		if s := StableMessage(tt.f, tt.n, tt.want); s != tt.out {
+			t.Errorf("%s: StableMessage got %q, want %q", tt.name, s, tt.out)
+		}
+	}
+}
@@ -0,0 +1,276 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// A Failure describes a failed check.  Failures are passed to Reporters.
+type Failure struct {
+	Time        time.Time    `json:"time"`
+	Test        string       `json:"test,omitempty"`   // the test of a Tester or Table row, if known
+	Scope       string       `json:"scope,omitempty"`  // the name of the Checker, as by Child
+	Caller      string       `json:"caller,omitempty"` // file:line of the check
+	Check       string       `json:"check"`            // e.g., "Error" or "Is"
+	Code        Code         `json:"code,omitempty"`
+	Got         string       `json:"got,omitempty"`  // the message of the error checked
+	Want        string       `json:"want,omitempty"` // as returned by Describe
+	Message     string       `json:"message"`        // the failure as returned by the check
+	Attachments []Attachment `json:"attachments,omitempty"`
+}
+
+// testName returns the name of t, or the empty string if t is nil.
+func testName(t namer) string {
+	if t == nil {
+		return ""
+	}
+	return t.Name()
+}
+
+// A Reporter is passed each failure of the checks made with the Report option.
+// A Reporter must be safe for concurrent use.
+type Reporter interface {
+	Report(f Failure)
+}
+
+// reporters is an immutable list of Reporters.
+type reporters struct {
+	r    Reporter
+	next *reporters
+}
+
+// Report returns an Option that passes the failures of checks to r, in
+// addition to returning them.  Report may be used more than once to add
+// several Reporters.
+func Report(r Reporter) Option {
+	return func(c *config) {
+		c.reporters = &reporters{r: r, next: c.reporters}
+	}
+}
+
+// A namer is a test, such as a testing.TB, that has a name.
+type namer interface {
+	Name() string
+}
+
+// inTest returns an Option that names t as the test of the checks made, as
+// reported in the Test field of their Failures.  The name is only taken from
+// t when a failure is reported.
+func inTest(t namer) Option {
+	return func(c *config) {
+		c.test = t
+	}
+}
+
+// outer reports whether c has options that apply to a check as a whole
+// and must be applied by run.
+func (c *config) outer() bool {
+	return c.deadline > 0 || c.attachments != nil || c.reporters != nil || c.recorder != nil || c.scope != "" || c.verbose || c.withStack
+}
+
+// run returns the result of calling check after applying the options of c
+// that apply to a check as a whole: Deadline, Attach, Report, and Record, and
+// the scope of a Checker made by Child, Verbose, and WithStack.
+// check is passed a copy of c without these options so they are not applied
+// again by checks made on its behalf.  name is the name of the check, got is
+// the error checked, and want returns the description of what was wanted.
+func (c *config) run(name string, got error, want func() string, check func(*config) string) string {
+	nc := *c
+	nc.deadline = 0
+	nc.attachments = nil
+	nc.reporters = nil
+	nc.recorder = nil
+	nc.scope = ""
+	nc.verbose = false
+	nc.withStack = false
+	if c.recorder != nil && got != nil {
+		c.recorder.add(message(got))
+	}
+	if c.reporters != nil {
+		// Reporters are passed the Code of the failure.
+		nc.codes = true
+	}
+	var s string
+	if c.deadline > 0 {
+		s = nc.bounded(c.deadline, check)
+	} else {
+		s = check(&nc)
+	}
+	if s == "" || c.quiet {
+		return s
+	}
+	var code Code
+	if c.reporters != nil {
+		code = Code(codePrefix.FindString(s))
+		if code != "" {
+			code = code[:len(code)-2]
+		}
+		if !c.codes {
+			s = codePrefix.ReplaceAllString(s, "")
+		}
+	}
+	if c.verbose && got != nil && name != "NoError" {
+		// NoError always includes the chain.
+		s += chain(got)
+	}
+	if c.withStack && got != nil {
+		s += origin(got)
+	}
+	s = c.scoped(s)
+	s = c.attach(s)
+	if c.reporters != nil {
+		f := Failure{
+			Time:        time.Now(),
+			Caller:      caller(),
+			Test:        testName(c.test),
+			Scope:       c.scope,
+			Check:       name,
+			Code:        code,
+			Want:        describeWant(want),
+			Message:     s,
+			Attachments: c.attachments.list(),
+		}
+		if got != nil {
+			f.Got = message(got)
+		}
+		for r := c.reporters; r != nil; r = r.next {
+			r.r.Report(f)
+		}
+	}
+	return s
+}
+
+// scoped returns the failure s prefixed with the scope of c, if any.
+func (c *config) scoped(s string) string {
+	if c.scope == "" {
+		return s
+	}
+	return prefixed(c.scope+": ", s)
+}
+
+// describeWant returns the description returned by want, or the empty string
+// if want is nil, as it is for checks that have no single want.
+func describeWant(want func() string) string {
+	if want == nil {
+		return ""
+	}
+	return want()
+}
+
+// prefixed returns the failure s with prefix added.  The prefix follows the
+// Code of s, if any, so the Code still starts the failure.
+func prefixed(prefix, s string) string {
+	code := ""
+	if loc := codePrefix.FindStringIndex(s); loc != nil && loc[0] == 0 {
+		code, s = s[:loc[1]], s[loc[1]:]
+	}
+	return code + prefix + s
+}
+
+// codePrefix matches the prefixes added by the Codes option.
+var codePrefix = regexp.MustCompile(`(?m)^CHK-[A-Z]+: `)
+
+type pkgMarker struct{}
+
+// pkgPrefix is the prefix of the names of the functions in this package.
+var pkgPrefix = reflect.TypeOf(pkgMarker{}).PkgPath() + "."
+
+// caller returns the file:line of the first caller outside of this package,
+// not counting its tests.
+func caller() string {
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	for {
+		f, more := frames.Next()
+		if !strings.HasPrefix(f.Function, pkgPrefix) || strings.HasSuffix(f.File, "_test.go") {
+			return fmt.Sprintf("%s:%d", f.File, f.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+// ReportEnv is the environment variable that, if set, names a file that all
+// failures are appended to by an NDJSON Reporter.  Because the file is
+// appended to, many test binaries, such as those of all the packages of a
+// repository, may share it.
+const ReportEnv = "CHECK_REPORT_FILE"
+
+func init() {
+	if path := os.Getenv(ReportEnv); path != "" {
+		SetDefaults(Report(NDJSON(path)))
+	}
+}
+
+// An ndjson is a Reporter that appends failures to a file.
+type ndjson struct {
+	path string
+	mu   sync.Mutex
+	err  error // the first error writing to path
+}
+
+// NDJSON returns a Reporter that appends each failure, encoded as a JSON
+// object on a single line, to the file at path, creating it if needed.  Each
+// failure is appended with a single write so that many processes may safely
+// append to the same file.  Errors writing the file are reported once on
+// standard error.
+func NDJSON(path string) Reporter {
+	return &ndjson{path: path}
+}
+
+func (r *ndjson) Report(f Failure) {
+	data, err := json.Marshal(f)
+	if err == nil {
+		data = append(data, '\n')
+		err = r.write(data)
+	}
+	if err != nil {
+		r.fail(err)
+	}
+}
+
+// fail reports err, if it is the first error writing r, on standard error.
+func (r *ndjson) fail(err error) {
+	r.mu.Lock()
+	if r.err == nil {
+		r.err = err
+		fmt.Fprintf(os.Stderr, "check: reporting failure: %v\n", err)
+	}
+	r.mu.Unlock()
+}
+
+func (r *ndjson) write(data []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	fd, err := os.OpenFile(r.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := fd.Write(data); err != nil {
+		fd.Close()
+		return err
+	}
+	return fd.Close()
+}
@@ -0,0 +1,248 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// collector is a Reporter that collects failures.
+type collector struct {
+	mu       sync.Mutex
+	failures []Failure
+}
+
+func (c *collector) Report(f Failure) {
+	c.mu.Lock()
+	c.failures = append(c.failures, f)
+	c.mu.Unlock()
+}
+
+func TestReport(t *testing.T) {
+	setDefaults(t)
+	var col collector
+	ck := NewChecker(Report(&col), Attach("dump", "state"))
+	if s := ck.Error(io.EOF, "EOF"); s != "" {
+		t.Fatalf("got %q", s)
+	}
+	if len(col.failures) != 0 {
+		t.Fatalf("passing check reported %v", col.failures)
+	}
+	s := ck.Error(io.EOF, Equal("other")).String()
+	if want := sprintf(wrong, "EOF", "other") + "\n--- dump ---\nstate"; s != want {
+		t.Errorf("got %q, want %q", s, want)
+	}
+	ck.With(Codes()).HasError(nil, "a", "b")
+	ck.Is(io.EOF, io.ErrUnexpectedEOF)
+	if len(col.failures) != 3 {
+		t.Fatalf("got %d failures, want 3", len(col.failures))
+	}
+	f := col.failures[0]
+	if time.Since(f.Time) > time.Minute {
+		t.Errorf("got time %v", f.Time)
+	}
+	if !strings.Contains(f.Caller, "report_test.go:") {
+		t.Errorf("got caller %q", f.Caller)
+	}
+	f.Time, f.Caller = time.Time{}, ""
+	want := Failure{
+		Check:       "Error",
+		Code:        CodeWrong,
+		Got:         "EOF",
+		Want:        `is "other"`,
+		Message:     s,
+		Attachments: []Attachment{{Name: "dump", Content: "state"}},
+	}
+	if !jsonEqual(f, want) {
+		t.Errorf("got failure %+v, want %+v", f, want)
+	}
+	f = col.failures[1]
+	if f.Check != "HasError" || f.Code != CodeMissing || f.Got != "" || f.Want != `contains "a" AND contains "b"` {
+		t.Errorf("got failure %+v", f)
+	}
+	if !strings.HasPrefix(f.Message, "CHK-MISSING: ") {
+		t.Errorf("with Codes got message %q", f.Message)
+	}
+	if f := col.failures[2]; f.Check != "Is" || f.Want != `is the error "unexpected EOF" (*errors.errorString)` {
+		t.Errorf("got failure %+v", f)
+	}
+}
+
+// namedTB is a fakeTB with a name.
+type namedTB struct {
+	fakeTB
+	name string
+}
+
+func (t *namedTB) Name() string { return t.name }
+
+func TestReportTest(t *testing.T) {
+	var col collector
+	setDefaults(t, Report(&col))
+	Error(io.EOF, "other")
+	tc := New(&namedTB{name: "TestOpen/missing"})
+	tc.Error(io.EOF, "other")
+	tb := &Table{}
+	tb.once(&T{TB: &namedTB{name: "TestOpen/row"}}, Row{Test: func(*T) error { return io.EOF }})
+	var tests []string
+	for _, f := range col.failures {
+		tests = append(tests, f.Test)
+	}
+	if want := []string{"", "TestOpen/missing", "TestOpen/row"}; fmt.Sprint(tests) != fmt.Sprint(want) {
+		t.Errorf("got tests %q, want %q", tests, want)
+	}
+}
+
+// reportedChecks holds a failing call of each check that reports by name.
+// Checks built on other checks, such as ErrorCase or SameMessage, report as
+// the check they are built on.
+var reportedChecks = []struct {
+	name string
+	f    func() string
+}{
+	{"Error", func() string { return Error(io.EOF, "other") }},
+	{"NoError", func() string { return NoError(io.EOF) }},
+	{"HasError", func() string { return HasError(nil, "a") }},
+	{"Is", func() string { return Is(io.EOF, io.ErrUnexpectedEOF) }},
+	{"MessagesEqual", func() string { return MessagesEqual(io.EOF, io.ErrUnexpectedEOF) }},
+	{"Errors", func() string { return Errors([]error{io.EOF}, []interface{}{"other"}) }},
+	{"NotIsError", func() string { return NotIsError(io.EOF, io.EOF) }},
+	{"WrapDepth", func() string { return WrapDepth(io.EOF, 1) }},
+	{"Chain", func() string { return Chain(io.EOF, "a", io.EOF) }},
+	{"Tight", func() string { return Tight(io.EOF, Regexp(".*")) }},
+	{"Batch", func() string { return Batch(nil, FirstFailure, "a") }},
+	{"CLI", func() string {
+		return CLI(func([]string, io.Writer) error { return nil }, nil, "a", nil)
+	}},
+	{"Concurrently", func() string {
+		return Concurrently(1, func(int) error { return io.EOF }, Outcome{nil, 1})
+	}},
+	{"Context", func() string { return Context(context.Background(), "a") }},
+	{"ContextCause", func() string { return ContextCause(context.Background(), "a") }},
+	{"Covers", func() string { return Covers(nil, io.EOF) }},
+	{"Deferred", func() string { return Deferred(func() error { return nil }, "a") }},
+	{"ErrorFrom", func() string {
+		ch := make(chan error, 1)
+		ch <- nil
+		return ErrorFrom(ch, time.Second, "a")
+	}},
+	{"Silent", func() string {
+		ch := make(chan error, 1)
+		ch <- io.EOF
+		return Silent(ch, time.Millisecond)
+	}},
+	{"Eventually", func() string {
+		return Eventually(func() error { return nil }, "a", time.Millisecond, time.Millisecond)
+	}},
+	{"IsSymmetric", func() string { return IsSymmetric(fmt.Errorf("x: %w", io.EOF), io.EOF) }},
+	{"IsConsistent", func() string { return IsConsistent(nilIsErr{}) }},
+	{"SameIs", func() string { return SameIs(io.EOF, io.ErrUnexpectedEOF) }},
+	{"SameCategory", func() string { return SameCategory(io.EOF, os.ErrNotExist) }},
+	{"FormatConsistent", func() string { return FormatConsistent(&driftErr{v: "a", s: "b"}) }},
+	{"CustomIs", func() string { return CustomIs(io.EOF, []error{io.ErrUnexpectedEOF}, nil) }},
+	{"IsPartition", func() string { return IsPartition([]error{io.EOF}, []error{io.EOF}) }},
+	{"JoinedErrors", func() string { return JoinedErrors(io.EOF, io.ErrUnexpectedEOF) }},
+	{"OnlyJoinedErrors", func() string { return OnlyJoinedErrors(io.EOF, io.ErrUnexpectedEOF) }},
+	{"JSONShape", func() string { return JSONShape(io.EOF, nil, `{"a":1}`) }},
+	{"Panic", func() string { return Panic(func() {}, "a") }},
+	{"NoPanic", func() string { return NoPanic(func() { panic("a") }) }},
+	{"Redacted", func() string { return Redacted(errors.New("key secret"), "secret") }},
+	{"NoPII", func() string { return NoPII(errors.New("mail bob@example.com")) }},
+	{"Budget", func() string { return Budget(1, 0, func() error { return io.EOF }, true) }},
+	{"Stable", func() string { return Stable(func() error { return io.EOF }, 1, "a") }},
+	{"StableMessage", func() string { return StableMessage(func() error { return io.EOF }, 1, "a") }},
+	{"Retryable", func() string { return Retryable(io.EOF, true) }},
+	{"Value", func() string { return Value(1, 2) }},
+	{"IsNotExist", func() string { return IsNotExist(io.EOF) }},
+	{"IsExist", func() string { return IsExist(io.EOF) }},
+	{"IsPermission", func() string { return IsPermission(io.EOF) }},
+	{"IsTimeout", func() string { return IsTimeout(io.EOF) }},
+	{"Allocs", func() string { return Allocs(func() { allocSink = make([]byte, 64) }, 0) }},
+	{"MaxDuration", func() string { return MaxDuration(func() { time.Sleep(time.Millisecond) }, time.Microsecond) }},
+	{"Warnings", func() string { return Warnings(nil, io.EOF) }},
+	{"Contains", func() string { return (&Logs{}).Contains("a") }},
+}
+
+func TestReportEveryCheck(t *testing.T) {
+	for _, tt := range reportedChecks {
+		var col collector
+		setDefaults(t, Report(&col))
+		if s := tt.f(); s == "" {
+			t.Errorf("%s: did not fail", tt.name)
+			continue
+		}
+		switch {
+		case len(col.failures) != 1:
+			t.Errorf("%s: got %d failures, want 1", tt.name, len(col.failures))
+		case col.failures[0].Check != tt.name:
+			t.Errorf("%s: got check %q", tt.name, col.failures[0].Check)
+		}
+	}
+}
+
+func jsonEqual(a, b interface{}) bool {
+	ja, _ := json.Marshal(a)
+	jb, _ := json.Marshal(b)
+	return string(ja) == string(jb)
+}
+
+func TestNDJSON(t *testing.T) {
+	setDefaults(t)
+	path := filepath.Join(t.TempDir(), "failures.ndjson")
+	r := NDJSON(path)
+	ck := NewChecker(Report(r))
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ck.Error(io.EOF, "other")
+			NewChecker(Report(NDJSON(path))).NoError(io.EOF)
+		}()
+	}
+	wg.Wait()
+
+	fd, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fd.Close()
+	counts := map[string]int{}
+	scanner := bufio.NewScanner(fd)
+	for scanner.Scan() {
+		var f Failure
+		if err := json.Unmarshal(scanner.Bytes(), &f); err != nil {
+			t.Fatalf("line %q: %v", scanner.Text(), err)
+		}
+		counts[f.Check]++
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if counts["Error"] != 10 || counts["NoError"] != 10 || len(counts) != 2 {
+		t.Errorf("got counts %v, want 10 each of Error and NoError", counts)
+	}
+}
@@ -0,0 +1,31 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+// A Result is the result of a check made by a Checker: the empty string if
+// the check passed, otherwise a string describing why it failed.  A check
+// whose Result is ignored checks nothing.  The checkvet analyzer, in
+// github.com/pborman/check/checkvet, reports calls whose Result is discarded,
+// including calls of functions and interface methods outside this package
+// that return a Result.
+//
+//check:mustuse
+type Result string
+
+// Failed reports whether r is a failure.
+func (r Result) Failed() bool { return r != "" }
+
+// String returns r as a string.
+func (r Result) String() string { return string(r) }
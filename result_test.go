@@ -0,0 +1,31 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"io"
+	"testing"
+)
+
+func TestResult(t *testing.T) {
+	ck := NewChecker()
+	if r := ck.Error(io.EOF, io.EOF); r.Failed() || r.String() != "" {
+		t.Errorf("passing check: got %q, failed %t", r, r.Failed())
+	}
+	r := ck.Error(io.EOF, "other")
+	if want := sprintf(wrong, "EOF", "other"); !r.Failed() || r.String() != want {
+		t.Errorf("failing check: got %q, failed %t, want %q", r, r.Failed(), want)
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+// Retryable returns the empty string if whether got is retryable matches
+// want, otherwise it returns a string indicating the error.  A nil error is
+// not retryable.
+//
+// The first error in the chain of got, as walked by errors.Is, that follows
+// one of these conventions determines whether got is retryable:
+//
+//	Retryable() bool      retryable if it returns true
+//	Temporary() bool      retryable if it returns true
+//	GRPCStatus()          retryable if the code is Unavailable,
+//	                      ResourceExhausted, or Aborted
+//	StatusCode() int      retryable if a 5xx or 429 HTTP status
+//	HTTPStatusCode() int  retryable if a 5xx or 429 HTTP status
+//
+// An error that follows none of the conventions is not retryable.
+func Retryable(got error, want bool) string {
+	return defaults().retryable(got, want)
+}
+
+// Retryable is the same as the Retryable function but uses the options of ck.
+func (ck *Checker) Retryable(got error, want bool) Result {
+	ck.count()
+	return Result(ck.c.retryable(got, want))
+}
+
+func (c *config) retryable(got error, want bool) string {
+	if c.outer() {
+		return c.run("Retryable", got, func() string { return sprintf("retryable %t", want) },
+			func(c *config) string { return c.retryable(got, want) })
+	}
+	if got == nil {
+		if want {
+			return c.failf(missing)
+		}
+		return ""
+	}
+	retry, why := retryable(got)
+	switch {
+	case retry == want:
+		return ""
+	case want && why == "":
+		return c.failf(notRetryable, got)
+	case want:
+		return c.failf(notRetryable, got) + " (" + why + ")"
+	default:
+		return c.failf(isRetryable, got) + " (" + why + ")"
+	}
+}
+
+const (
+	isRetryable  = "got retryable error %q, want not retryable"
+	notRetryable = "got non-retryable error %q, want retryable"
+)
+
+// retryable reports whether err is retryable, as described by Retryable, and
+// the reason, if any, it is or is not.
+func retryable(err error) (retry bool, why string) {
+	walk(err, func(err error) bool {
+		switch e := err.(type) {
+		case interface{ Retryable() bool }:
+			retry, why = e.Retryable(), sprintf("Retryable() returned %t", e.Retryable())
+			return false
+		case interface{ Temporary() bool }:
+			retry, why = e.Temporary(), sprintf("Temporary() returned %t", e.Temporary())
+			return false
+		}
+		if code, ok := grpcCode(err); ok {
+			switch code {
+			case grpcUnavailable, grpcResourceExhausted, grpcAborted:
+				retry = true
+			}
+			why = sprintf("gRPC code %d", code)
+			return false
+		}
+		status, ok := httpStatus(err)
+		if !ok {
+			return true
+		}
+		retry = status >= 500 && status <= 599 || status == 429
+		why = sprintf("HTTP status %d", status)
+		return false
+	})
+	return retry, why
+}
+
+// httpStatus returns the HTTP status code of err, if it has a StatusCode or
+// HTTPStatusCode method.
+func httpStatus(err error) (int, bool) {
+	switch e := err.(type) {
+	case interface{ StatusCode() int }:
+		return e.StatusCode(), true
+	case interface{ HTTPStatusCode() int }:
+		return e.HTTPStatusCode(), true
+	}
+	return 0, false
+}
@@ -0,0 +1,128 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+type retryErr bool
+
+func (e retryErr) Error() string   { return "retry" }
+func (e retryErr) Retryable() bool { return bool(e) }
+
+type tempErr bool
+
+func (e tempErr) Error() string   { return "temp" }
+func (e tempErr) Temporary() bool { return bool(e) }
+
+type httpErr int
+
+func (e httpErr) Error() string   { return fmt.Sprintf("http %d", int(e)) }
+func (e httpErr) StatusCode() int { return int(e) }
+
+// grpcStatus mimics *status.Status from google.golang.org/grpc/status.
+type grpcStatus struct {
+	code uint32
+	msg  string
+}
+
+func (s *grpcStatus) Code() uint32 {
+	if s == nil {
+		return 0
+	}
+	return s.code
+}
+func (s *grpcStatus) Message() string {
+	if s == nil {
+		return ""
+	}
+	return s.msg
+}
+
+type grpcErr struct{ s *grpcStatus }
+
+func (e grpcErr) Error() string           { return "rpc error: " + e.s.Message() }
+func (e grpcErr) GRPCStatus() *grpcStatus { return e.s }
+
+func TestRetryable(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		got  error
+		want bool
+		out  string
+	}{
+		{name: "nil"},
+		{
+			name: "nil want retryable",
+			want: true,
+			out:  "did not get expected error",
+		}, {
+			name: "plain",
+			got:  errors.New("plain"),
+			out:  "",
+		}, {
+			name: "plain want retryable",
+			got:  errors.New("plain"),
+			want: true,
+			out:  `got non-retryable error "plain", want retryable`,
+		}, {
+			name: "retryable",
+			got:  fmt.Errorf("wrapped: %w", retryErr(true)),
+			want: true,
+		}, {
+			name: "retryable false",
+			got:  retryErr(false),
+			want: true,
+			out:  `got non-retryable error "retry", want retryable (Retryable() returned false)`,
+		}, {
+			name: "temporary",
+			got:  tempErr(true),
+			out:  `got retryable error "temp", want not retryable (Temporary() returned true)`,
+		}, {
+			name: "http 503",
+			got:  httpErr(503),
+			want: true,
+		}, {
+			name: "http 429",
+			got:  httpErr(429),
+			want: true,
+		}, {
+			name: "http 404",
+			got:  httpErr(404),
+		}, {
+			name: "grpc unavailable",
+			got:  grpcErr{&grpcStatus{code: grpcUnavailable, msg: "down"}},
+			want: true,
+		}, {
+			name: "grpc not found",
+			got:  grpcErr{&grpcStatus{code: 5, msg: "missing"}},
+			want: true,
+			out:  `got non-retryable error "rpc error: missing", want retryable (gRPC code 5)`,
+		}, {
+			name: "grpc nil status",
+			got:  grpcErr{},
+		}, {
+			name: "outermost wins",
+			got:  fmt.Errorf("%w", tempErr(false)),
+		},
+	} {
+		if s := Retryable(tt.got, tt.want); s != tt.out {
+			t.Errorf("%s: got %q, want %q", tt.name, s, tt.out)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// A Codec is an encoding used by RoundTrip.
+type Codec int
+
+const (
+	// GobCodec encodes the error as an error interface value with
+	// encoding/gob, as done when sending errors between processes.  The
+	// concrete type of the error is registered with gob.Register.
+	GobCodec = Codec(iota)
+
+	// JSONCodec encodes the error with encoding/json and decodes it into a
+	// new value of the same concrete type.
+	JSONCodec
+)
+
+func (c Codec) String() string {
+	switch c {
+	case GobCodec:
+		return "gob"
+	case JSONCodec:
+		return "json"
+	}
+	return sprintf("Codec(%d)", int(c))
+}
+
+const (
+	decodeError   = "decoding error %q failed: %q"
+	changedType   = "got error of type %q after round trip, want %q"
+	changedString = "got error %q after round trip, want %q"
+	changedCode   = "got code %q after round trip, want %q"
+)
+
+// RoundTrip returns the empty string if got survives being encoded and then
+// decoded with codec, otherwise it returns a string indicating each failure,
+// one per line.  To survive, the decoded error must be of the same type as got
+// and have the same message and code.  The code is the result of the Code
+// method of the error, if it has one.
+func RoundTrip(got error, codec Codec) string {
+	return defaults().roundTrip(got, codec)
+}
+
+// RoundTrip is the same as the RoundTrip function but uses the options of ck.
+func (ck *Checker) RoundTrip(got error, codec Codec) Result {
+	ck.count()
+	return Result(ck.c.roundTrip(got, codec))
+}
+
+func (c *config) roundTrip(got error, codec Codec) string {
+	if c.outer() {
+		return c.run("RoundTrip", got, nil,
+			func(c *config) string { return c.roundTrip(got, codec) })
+	}
+	if got == nil {
+		return c.failf(missing)
+	}
+	var decoded error
+	var err error
+	switch codec {
+	case GobCodec:
+		decoded, err = gobRoundTrip(got)
+	case JSONCodec:
+		decoded, err = jsonRoundTrip(got)
+	default:
+		return c.failc(CodeUnsupported, "unsupported codec %q", codec)
+	}
+	if err != nil {
+		return c.failf(decodeError, got, err)
+	}
+	var failures []string
+	if gt, dt := reflect.TypeOf(got), reflect.TypeOf(decoded); gt != dt {
+		failures = append(failures, c.failf(changedType, dt, gt))
+	}
+	if g, d := message(got), message(decoded); g != d {
+		failures = append(failures, c.failf(changedString, d, g)+c.caret(d, g, false))
+	}
+	gc, gok := call(got, "Code")
+	dc, dok := call(decoded, "Code")
+	if gok && (!dok || !reflect.DeepEqual(gc, dc)) {
+		failures = append(failures, c.failf(changedCode, fmt.Sprint(dc), fmt.Sprint(gc)))
+	}
+	return strings.Join(failures, "\n")
+}
+
+// envelope holds the error sent by gobRoundTrip so it is encoded as an
+// interface value.
+type envelope struct {
+	Err error
+}
+
+// gobRoundTrip returns err after encoding and decoding it with encoding/gob.
+func gobRoundTrip(err error) (_ error, rerr error) {
+	defer func() {
+		// gob.Register panics if the type of err conflicts with
+		// a type already registered.
+		if p := recover(); p != nil {
+			rerr = fmt.Errorf("%v", p)
+		}
+	}()
+	gob.Register(err)
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(&envelope{Err: err}); err != nil {
+		return nil, err
+	}
+	var e envelope
+	if err := gob.NewDecoder(&buf).Decode(&e); err != nil {
+		return nil, err
+	}
+	return e.Err, nil
+}
+
+// jsonRoundTrip returns err after encoding it with encoding/json and decoding
+// it into a new value of the same type as err.
+func jsonRoundTrip(err error) (error, error) {
+	data, jerr := json.Marshal(err)
+	if jerr != nil {
+		return nil, jerr
+	}
+	t := reflect.TypeOf(err)
+	if t.Kind() == reflect.Ptr {
+		v := reflect.New(t.Elem())
+		if err := json.Unmarshal(data, v.Interface()); err != nil {
+			return nil, err
+		}
+		return v.Interface().(error), nil
+	}
+	v := reflect.New(t)
+	if err := json.Unmarshal(data, v.Interface()); err != nil {
+		return nil, err
+	}
+	return v.Elem().Interface().(error), nil
+}
@@ -0,0 +1,95 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"errors"
+	"testing"
+)
+
+type jobErr struct {
+	Msg  string
+	Num  int
+	Lost int `json:"-"`
+}
+
+func (e *jobErr) Error() string { return e.Msg }
+func (e *jobErr) Code() int     { return e.Num + e.Lost }
+
+type valueErr struct{ Msg string }
+
+func (e valueErr) Error() string { return e.Msg }
+
+type hiddenMsg struct {
+	msg string
+	Num int
+}
+
+func (e hiddenMsg) Error() string { return e.msg }
+
+func TestRoundTrip(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		got   error
+		codec Codec
+		out   string
+	}{
+		{
+			name: "nil",
+			out:  "did not get expected error",
+		}, {
+			name: "gob",
+			got:  &jobErr{Msg: "job failed", Num: 3, Lost: 4},
+		}, {
+			name:  "json",
+			got:   &jobErr{Msg: "job failed", Num: 3},
+			codec: JSONCodec,
+		}, {
+			name: "gob value",
+			got:  valueErr{"value"},
+		}, {
+			name:  "json value",
+			got:   valueErr{"value"},
+			codec: JSONCodec,
+		}, {
+			name:  "json lost code",
+			got:   &jobErr{Msg: "job failed", Num: 3, Lost: 4},
+			codec: JSONCodec,
+			out:   sprintf(changedCode, "3", "7"),
+		}, {
+			name: "gob no exported fields",
+			got:  errors.New("plain"),
+			out:  sprintf(decodeError, "plain", "gob: type errors.errorString has no exported fields"),
+		}, {
+			name:  "json no exported fields",
+			got:   errors.New("plain"),
+			codec: JSONCodec,
+			out:   sprintf(changedString, "", "plain"),
+		}, {
+			name: "gob lost message",
+			got:  hiddenMsg{msg: "hidden", Num: 1},
+			out:  sprintf(changedString, "", "hidden"),
+		}, {
+			name:  "unsupported",
+			got:   valueErr{"value"},
+			codec: Codec(42),
+			out:   `unsupported codec "Codec(42)"`,
+		},
+	} {
+		if s := RoundTrip(tt.got, tt.codec); s != tt.out {
+			t.Errorf("%s: got %q, want %q", tt.name, s, tt.out)
+		}
+	}
+}
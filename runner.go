@@ -0,0 +1,468 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// A Row is a single case of a table driven test.  The error returned by
+// calling Test is checked against Want, as by Error.
+type Row struct {
+	Name string         // name of the subtest running the case (see Run)
+	Test func(*T) error // function under test
+	Want interface{}    // expected error, as by Error
+
+	// Before, if not nil, is called before Test.  If it returns an error
+	// the row fails and Test is not called.
+	Before func(*T) error
+
+	// After, if not nil, is called after Test, even if Test or Before
+	// failed.  Its error is checked against AfterWant, as by Error.
+	After     func(*T) error
+	AfterWant interface{}
+
+	// Focus causes only the rows of the table with Focus set to be run.
+	// Focus is meant for iterating on a few rows of a large table; a
+	// table with a focused row always fails so the focus is not
+	// committed.
+	Focus bool
+
+	// Timeout, if not 0, is how long Test may run.  A row that times out
+	// fails with a "case timed out after D" failure, where D is Timeout,
+	// and the Context of its T is canceled.  Test is run in its own
+	// goroutine, which is abandoned if it does not return.
+	Timeout time.Duration
+}
+
+// A T is passed to the Test function of a Row.  It provides the testing.TB
+// of the subtest running the case along with trace support.  Test should not
+// call FailNow (or Fatal) on T.
+type T struct {
+	testing.TB
+	ctx     context.Context
+	verbose bool
+
+	mu    sync.Mutex
+	trace []string
+}
+
+// Context returns the context of the case.  While Test is running it is
+// canceled if the row times out, in which case it remains canceled.
+func (t *T) Context() context.Context {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.ctx
+}
+
+// setContext sets the context returned by t.Context.
+func (t *T) setContext(ctx context.Context) {
+	t.mu.Lock()
+	t.ctx = ctx
+	t.mu.Unlock()
+}
+
+// Verbose reports whether the case is being replayed in verbose mode, in
+// which case its trace output is recorded.
+func (t *T) Verbose() bool { return t.verbose }
+
+// Tracef records a line of trace output, formatted as by fmt.Sprintf.  Trace
+// output is discarded unless t is Verbose.
+func (t *T) Tracef(format string, v ...interface{}) {
+	if t.verbose {
+		t.mu.Lock()
+		t.trace = append(t.trace, sprintf(format, v...))
+		t.mu.Unlock()
+	}
+}
+
+// A Table is a table driven test.
+type Table struct {
+	Rows []Row
+
+	// Filter, if not empty, is a regular expression.  Only rows whose
+	// names match Filter are run.  Filter is in addition to the -run flag
+	// of go test.
+	Filter string
+
+	// Shuffle causes the rows to be run in a random order, to find rows
+	// that depend on the rows before them.  The order is determined by
+	// Seed, or, if Seed is 0, by a seed based on the current time.  The
+	// seed is logged and may be replayed with the -check.seed flag, which
+	// overrides Seed.
+	Shuffle bool
+	Seed    int64
+
+	// Replay causes a failing case to be run a second time, in verbose
+	// mode, with its trace output and result appended to its failure.
+	Replay bool
+
+	// Before and After, if not nil, are called before the first row and
+	// after the last row is run.  If Before returns an error no rows are
+	// run.  The error returned by After is checked against AfterWant, as
+	// by Error.
+	Before func(testing.TB) error
+	After  func(testing.TB) error
+
+	// BeforeEach and AfterEach, if not nil, are called before and after
+	// each row, outside of the row's own Before and After.  If BeforeEach
+	// returns an error the row fails and is not run.  The error returned
+	// by AfterEach is checked against AfterWant, as by Error.
+	BeforeEach func(*T) error
+	AfterEach  func(*T) error
+
+	// AfterWant is the expected error of After and AfterEach.  The nil
+	// default expects no error.
+	AfterWant interface{}
+
+	// Quarantine, if not nil, lists cases whose failures are logged
+	// rather than reported as errors.  If Quarantine is nil the file named
+	// by the -check.quarantine flag, if any, is used.
+	Quarantine *Quarantine
+}
+
+// Run runs each of rows as a subtest of t.
+func Run(t *testing.T, rows ...Row) {
+	t.Helper()
+	(&Table{Rows: rows}).Run(t)
+}
+
+// Run runs each row of tb as a subtest of t.  A row without a Name is named
+// after its Want, e.g., "contains_unknown_type" or "is_io.EOF".  Names are
+// made unique by adding a suffix, such as "_2", to repeated names.  The
+// failures of a row passed to Reporters are of the subtest of the row.
+func (tb *Table) Run(t *testing.T) {
+	t.Helper()
+	rows, focused, err := tb.selected()
+	if err != nil {
+		t.Fatal(err)
+	}
+	q, err := tb.quarantine()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if focused != nil {
+		defer func() {
+			t.Helper()
+			t.Errorf("rows %q have Focus set; remove it before committing", focused)
+		}()
+	}
+	if tb.Shuffle {
+		seed := tb.seed()
+		shuffle(rows, seed)
+		t.Logf("shuffled rows with seed %d (replay with -check.seed=%d)", seed, seed)
+	}
+	c := defaults().with(inTest(t))
+	if tb.Before != nil {
+		if s := c.checkError(tb.Before(t), nil); s != "" {
+			t.Fatalf("before: %s", s)
+		}
+	}
+	if tb.After != nil {
+		defer func() {
+			t.Helper()
+			if s := c.checkError(tb.After(t), tb.AfterWant); s != "" {
+				t.Errorf("after: %s", s)
+			}
+		}()
+	}
+	for _, row := range rows {
+		row := row
+		t.Run(row.Name, func(t *testing.T) {
+			t.Helper()
+			quarantined(t, q, t.Name(), tb.check(t, row), time.Now())
+		})
+	}
+}
+
+// selected returns the rows of tb to run, as selected by tb.Filter and the
+// Focus field of the rows, and the names of the focused rows, if any.  The
+// rows returned have their Name set to the name of their subtest, as
+// described by Run.  Rows are named in the order of the table, so neither a
+// Filter nor a shuffle changes the names, or the suffixes of repeated names,
+// of the rows.
+func (tb *Table) selected() (rows []Row, focused []string, err error) {
+	var re *regexp.Regexp
+	if tb.Filter != "" {
+		if re, err = regexp.Compile(tb.Filter); err != nil {
+			return nil, nil, fmt.Errorf("bad Filter: %v", err)
+		}
+	}
+	all := append([]Row(nil), tb.Rows...)
+	for i, name := range rowNames(tb.Rows) {
+		all[i].Name = name
+	}
+	for _, c := range all {
+		if c.Focus {
+			focused = append(focused, c.Name)
+		}
+	}
+	for _, c := range all {
+		if (re == nil || re.MatchString(c.Name)) && (focused == nil || c.Focus) {
+			rows = append(rows, c)
+		}
+	}
+	return rows, focused, nil
+}
+
+// maxNameLen is the maximum length, in runes, of a name made by wantName.
+const maxNameLen = 40
+
+// rowNames returns the subtest names of rows, as described by Table.Run.
+func rowNames(rows []Row) []string {
+	names := make([]string, len(rows))
+	for i, c := range rows {
+		names[i] = c.Name
+		if names[i] == "" {
+			names[i] = wantName(c.Want)
+		}
+	}
+	return uniqueNames(names)
+}
+
+// uniqueNames returns names, modified in place, with a suffix, such as "_2",
+// added to repeated names.
+func uniqueNames(names []string) []string {
+	seen := map[string]int{}
+	for i, name := range names {
+		if n := seen[name]; n > 0 {
+			seen[name]++
+			names[i] = sprintf("%s_%d", name, n+1)
+		} else {
+			seen[name] = 1
+		}
+	}
+	return names
+}
+
+// sentinelNames are the names of well known sentinel errors used in names.
+var sentinelNames = map[error]string{
+	io.EOF:                   "io.EOF",
+	io.ErrUnexpectedEOF:      "io.ErrUnexpectedEOF",
+	io.ErrClosedPipe:         "io.ErrClosedPipe",
+	io.ErrShortWrite:         "io.ErrShortWrite",
+	context.Canceled:         "context.Canceled",
+	context.DeadlineExceeded: "context.DeadlineExceeded",
+	os.ErrNotExist:           "os.ErrNotExist",
+	os.ErrExist:              "os.ErrExist",
+	os.ErrPermission:         "os.ErrPermission",
+	os.ErrClosed:             "os.ErrClosed",
+	os.ErrInvalid:            "os.ErrInvalid",
+}
+
+// wantName returns a subtest name describing want.
+func wantName(want interface{}) string {
+	var name string
+	switch w := want.(type) {
+	case nil:
+		name = "no_error"
+	case bool:
+		name = "no_error"
+		if w {
+			name = "any_error"
+		}
+	case string:
+		name = "contains_" + w
+		if w == "" {
+			name = "no_error"
+		}
+	case Equal:
+		name = "equals_" + string(w)
+	case Case:
+		name = "contains_case_" + string(w)
+	case CaseEqual:
+		name = "equals_case_" + string(w)
+	case Regexp:
+		name = "matches_" + string(w)
+	case Category:
+		name = "category_" + string(w)
+	case presence:
+		return wantName(bool(w))
+	case Matcher, CustomMatcher:
+		name = sprintf("matches_%T", w)
+	case func(error) bool, func(error) string:
+		name = "satisfies_predicate"
+	case error:
+		// Looping rather than indexing the map avoids a panic when
+		// w's dynamic type is not comparable.
+		for err, n := range sentinelNames {
+			if w == err {
+				return "is_" + n
+			}
+		}
+		name = "is_" + w.Error()
+	default:
+		if v := reflect.ValueOf(want); v.Kind() == reflect.Ptr {
+			name = "as_" + v.Type().Elem().String()
+		} else {
+			name = sprintf("want_%T", want)
+		}
+	}
+	name = strings.Join(strings.Fields(name), "_")
+	if r := []rune(name); len(r) > maxNameLen {
+		name = string(r[:maxNameLen])
+	}
+	return name
+}
+
+// seedFlag is the -check.seed flag, which is only registered in test
+// binaries.
+var seedFlag = new(int64)
+
+func init() {
+	if testBinary() {
+		flag.Int64Var(seedFlag, "check.seed", 0, "seed for shuffling check.Table rows (0 uses Table.Seed)")
+	}
+}
+
+// seed returns the seed to shuffle the rows of tb with.
+func (tb *Table) seed() int64 {
+	switch {
+	case *seedFlag != 0:
+		return *seedFlag
+	case tb.Seed != 0:
+		return tb.Seed
+	default:
+		return time.Now().UnixNano()
+	}
+}
+
+// shuffle shuffles rows in place using seed.
+func shuffle(rows []Row, seed int64) {
+	rand.New(rand.NewSource(seed)).Shuffle(len(rows), func(i, j int) {
+		rows[i], rows[j] = rows[j], rows[i]
+	})
+}
+
+// check runs c and returns the empty string if it passed, otherwise it
+// returns a string indicating the failure.
+func (tb *Table) check(t testing.TB, c Row) string {
+	s := tb.once(&T{TB: t}, c)
+	if s == "" || !tb.Replay {
+		return s
+	}
+	tt := &T{TB: t, verbose: true}
+	replay := tb.once(tt, c)
+	tt.mu.Lock()
+	trace := tt.trace
+	tt.mu.Unlock()
+	var b strings.Builder
+	b.WriteString(s)
+	b.WriteString("\nreplay:")
+	for _, line := range trace {
+		b.WriteString("\n")
+		b.WriteString(indent(line))
+	}
+	if replay == "" {
+		b.WriteString("\n\treplay passed; the case may not be deterministic")
+	} else if replay != s {
+		b.WriteString("\n")
+		b.WriteString(indent(replay))
+	}
+	return b.String()
+}
+
+// once runs c a single time with t, including the before and after hooks,
+// and returns the empty string if it passed, otherwise a string indicating
+// each failure.  The checks are made as checks of the test t.
+func (tb *Table) once(t *T, c Row) string {
+	cfg := defaults().with(inTest(t))
+	var failures []string
+	fail := func(prefix, s string) bool {
+		if s == "" {
+			return false
+		}
+		failures = append(failures, prefix+s)
+		return true
+	}
+	t.setContext(context.Background())
+	before := func(f func(*T) error) bool {
+		return f != nil && fail("before: ", cfg.checkError(f(t), nil))
+	}
+	if !before(tb.BeforeEach) && !before(c.Before) {
+		fail("", c.run(cfg, t))
+	}
+	if c.After != nil {
+		fail("after: ", cfg.checkError(c.After(t), c.AfterWant))
+	}
+	if tb.AfterEach != nil {
+		fail("after: ", cfg.checkError(tb.AfterEach(t), tb.AfterWant))
+	}
+	return strings.Join(failures, "\n")
+}
+
+// run calls the Test function of c with t and returns the result of checking
+// its error against c.Want using the settings in cfg.
+func (c Row) run(cfg *config, t *T) string {
+	ctx, cancel := context.WithCancel(context.Background())
+	if c.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+	}
+	defer cancel()
+	t.setContext(ctx)
+	if c.Timeout <= 0 {
+		defer t.setContext(context.Background())
+		return cfg.checkError(c.Test(t), c.Want)
+	}
+	done := make(chan error, 1)
+	go func() { done <- c.Test(t) }()
+	select {
+	case err := <-done:
+		t.setContext(context.Background())
+		return cfg.checkError(err, c.Want)
+	case <-ctx.Done():
+		t.setContext(context.Background())
+		if ctx.Err() != context.DeadlineExceeded {
+			return cfg.failc(CodeUnexpected, "case canceled: %q", ctx.Err())
+		}
+		return cfg.failc(CodeTimeout, sprintf("case timed out after %v", c.Timeout))
+	}
+}
+
+// structName returns the Name field of row, a struct or pointer to a struct,
+// if it has a Name field of type string.
+func structName(row interface{}) string {
+	v := structOf(row)
+	if !v.IsValid() {
+		return ""
+	}
+	f := v.FieldByName("Name")
+	if !f.IsValid() || f.Kind() != reflect.String {
+		return ""
+	}
+	return f.String()
+}
+
+// checkRow returns the failure of a row of RunTable whose function returned
+// got and err.  got is compared to wantVal, as by Value, only if err is nil,
+// as wanted by wantErr, and checkVal is true.  The checks use the settings in
+// c.
+func checkRow(c *config, got interface{}, err error, wantErr, wantVal interface{}, checkVal bool) string {
+	if s := c.checkError(err, wantErr); s != "" || err != nil || !checkVal {
+		return s
+	}
+	return c.value(got, wantVal)
+}
@@ -0,0 +1,512 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	ran := 0
+	Run(t, Row{
+		Name: "no error",
+		Test: func(t *T) error { ran++; return nil },
+	}, Row{
+		Name: "eof",
+		Test: func(t *T) error { ran++; return io.EOF },
+		Want: io.EOF,
+	})
+	if ran != 2 {
+		t.Errorf("ran %d cases, want 2", ran)
+	}
+}
+
+func TestReplay(t *testing.T) {
+	calls := 0
+	for _, tt := range []struct {
+		name   string
+		replay bool
+		c      Row
+		out    string
+	}{
+		{
+			name:   "pass",
+			replay: true,
+			c: Row{
+				Test: func(t *T) error {
+					t.Tracef("trace")
+					return nil
+				},
+			},
+		}, {
+			name: "no replay",
+			c: Row{
+				Test: func(t *T) error {
+					t.Tracef("trace")
+					return io.EOF
+				},
+			},
+			out: `got unexpected error "EOF"`,
+		}, {
+			name:   "replay",
+			replay: true,
+			c: Row{
+				Test: func(t *T) error {
+					t.Tracef("verbose %t", t.Verbose())
+					t.Tracef("step two")
+					return io.EOF
+				},
+			},
+			out: "got unexpected error \"EOF\"\nreplay:\n\tverbose true\n\tstep two",
+		}, {
+			name:   "flaky",
+			replay: true,
+			c: Row{
+				Test: func(t *T) error {
+					calls++
+					if calls%2 == 1 {
+						return errors.New("flaky")
+					}
+					return nil
+				},
+			},
+			out: "got unexpected error \"flaky\"\nreplay:\n\treplay passed; the case may not be deterministic",
+		}, {
+			name:   "different",
+			replay: true,
+			c: Row{
+				Test: func(t *T) error {
+					calls++
+					return errors.New(sprintf("call %d", calls))
+				},
+			},
+			out: "got unexpected error \"call 3\"\nreplay:\n\tgot unexpected error \"call 4\"",
+		},
+	} {
+		tb := &Table{Replay: tt.replay}
+		if s := tb.check(t, tt.c); s != tt.out {
+			t.Errorf("%s: got %q, want %q", tt.name, s, tt.out)
+		}
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	tb := &Table{}
+	for _, tt := range []struct {
+		name string
+		c    Row
+		out  string
+	}{
+		{
+			name: "fast",
+			c: Row{
+				Timeout: time.Minute,
+				Test:    func(t *T) error { return io.EOF },
+				Want:    io.EOF,
+			},
+		}, {
+			name: "slow",
+			c: Row{
+				Timeout: time.Millisecond,
+				Test: func(t *T) error {
+					<-t.Context().Done()
+					time.Sleep(time.Millisecond)
+					return nil
+				},
+			},
+			out: "case timed out after 1ms",
+		}, {
+			name: "no timeout",
+			c: Row{
+				Test: func(t *T) error {
+					if t.Context().Err() != nil {
+						return t.Context().Err()
+					}
+					return nil
+				},
+			},
+		},
+	} {
+		if s := tb.check(t, tt.c); s != tt.out {
+			t.Errorf("%s: got %q, want %q", tt.name, s, tt.out)
+		}
+	}
+	setDefaults(t, Codes())
+	tt := &T{TB: t}
+	slow := Row{
+		Timeout: time.Millisecond,
+		Test: func(t *T) error {
+			time.Sleep(time.Second)
+			return nil
+		},
+	}
+	if s := slow.run(defaults(), tt); s != "CHK-TIMEOUT: case timed out after 1ms" {
+		t.Errorf("with Codes got %q", s)
+	}
+	if err := tt.Context().Err(); err != nil {
+		t.Errorf("after timing out the context is %v", err)
+	}
+}
+
+func TestHooks(t *testing.T) {
+	errSetup := errors.New("setup failed")
+	errTeardown := errors.New("teardown failed")
+	var calls []string
+	record := func(name string, err error) func(*T) error {
+		return func(*T) error {
+			calls = append(calls, name)
+			return err
+		}
+	}
+	for _, tt := range []struct {
+		name  string
+		tb    Table
+		c     Row
+		calls string
+		out   string
+	}{
+		{
+			name: "order",
+			tb: Table{
+				BeforeEach: record("beforeEach", nil),
+				AfterEach:  record("afterEach", nil),
+			},
+			c: Row{
+				Before: record("before", nil),
+				Test:   record("test", nil),
+				After:  record("after", nil),
+			},
+			calls: "beforeEach before test after afterEach",
+		}, {
+			name: "before each fails",
+			tb: Table{
+				BeforeEach: record("beforeEach", errSetup),
+				AfterEach:  record("afterEach", nil),
+			},
+			c: Row{
+				Before: record("before", nil),
+				Test:   record("test", nil),
+				After:  record("after", nil),
+			},
+			calls: "beforeEach after afterEach",
+			out:   `before: got unexpected error "setup failed"`,
+		}, {
+			name: "before fails",
+			c: Row{
+				Before: record("before", errSetup),
+				Test:   record("test", nil),
+			},
+			calls: "before",
+			out:   `before: got unexpected error "setup failed"`,
+		}, {
+			name: "after fails",
+			tb: Table{
+				AfterEach: record("afterEach", errTeardown),
+			},
+			c: Row{
+				Test:  record("test", io.EOF),
+				After: record("after", errTeardown),
+			},
+			calls: "test after afterEach",
+			out: "got unexpected error \"EOF\"\n" +
+				"after: got unexpected error \"teardown failed\"\n" +
+				"after: got unexpected error \"teardown failed\"",
+		}, {
+			name: "after wants",
+			tb: Table{
+				AfterEach: record("afterEach", errTeardown),
+				AfterWant: "teardown",
+			},
+			c: Row{
+				Test:      record("test", nil),
+				After:     record("after", io.EOF),
+				AfterWant: io.EOF,
+			},
+			calls: "test after afterEach",
+		},
+	} {
+		calls = nil
+		if s := tt.tb.check(t, tt.c); s != tt.out {
+			t.Errorf("%s: got %q, want %q", tt.name, s, tt.out)
+		}
+		if s := strings.Join(calls, " "); s != tt.calls {
+			t.Errorf("%s: called %q, want %q", tt.name, s, tt.calls)
+		}
+	}
+}
+
+func TestRunHooks(t *testing.T) {
+	var calls []string
+	t.Run("table", func(t *testing.T) {
+		(&Table{
+			Before: func(testing.TB) error {
+				calls = append(calls, "before")
+				return nil
+			},
+			After: func(testing.TB) error {
+				calls = append(calls, "after")
+				return io.EOF
+			},
+			AfterWant: io.EOF,
+			Rows: []Row{{
+				Name: "row",
+				Test: func(t *T) error {
+					calls = append(calls, "row")
+					if t.Context() == nil {
+						return errors.New("no context")
+					}
+					return nil
+				},
+			}},
+		}).Run(t)
+	})
+	if s := strings.Join(calls, " "); s != "before row after" {
+		t.Errorf("called %q, want %q", s, "before row after")
+	}
+}
+
+func TestSelected(t *testing.T) {
+	names := func(rows []Row) string {
+		var s []string
+		for _, r := range rows {
+			s = append(s, r.Name)
+		}
+		return strings.Join(s, " ")
+	}
+	rows := []Row{{Name: "open"}, {Name: "close"}, {Name: "open_dir"}}
+	focus := []Row{{Name: "open"}, {Name: "close", Focus: true}, {Name: "open_dir", Focus: true}}
+	for _, tt := range []struct {
+		name    string
+		tb      Table
+		out     string
+		focused string
+		err     interface{}
+	}{
+		{
+			name: "all",
+			tb:   Table{Rows: rows},
+			out:  "open close open_dir",
+		}, {
+			name: "filter",
+			tb:   Table{Rows: rows, Filter: "^open"},
+			out:  "open open_dir",
+		}, {
+			name:    "focus",
+			tb:      Table{Rows: focus},
+			out:     "close open_dir",
+			focused: "close open_dir",
+		}, {
+			name:    "focus filter",
+			tb:      Table{Rows: focus, Filter: "open"},
+			out:     "open_dir",
+			focused: "close open_dir",
+		}, {
+			name: "unnamed",
+			tb:   Table{Rows: []Row{{Want: "EOF"}, {Want: io.EOF}, {Want: "EOF"}}, Filter: "contains"},
+			out:  "contains_EOF contains_EOF_2",
+		}, {
+			name:    "unnamed focus",
+			tb:      Table{Rows: []Row{{Want: "EOF"}, {Want: io.EOF}, {Want: "EOF", Focus: true}}, Filter: "contains"},
+			out:     "contains_EOF_2",
+			focused: "contains_EOF_2",
+		}, {
+			name: "bad filter",
+			tb:   Table{Rows: rows, Filter: "("},
+			err:  "bad Filter",
+		},
+	} {
+		got, focused, err := tt.tb.selected()
+		if s := Error(err, tt.err); s != "" {
+			t.Errorf("%s: %s", tt.name, s)
+			continue
+		}
+		if s := names(got); s != tt.out {
+			t.Errorf("%s: got rows %q, want %q", tt.name, s, tt.out)
+		}
+		if s := strings.Join(focused, " "); s != tt.focused {
+			t.Errorf("%s: got focused %q, want %q", tt.name, s, tt.focused)
+		}
+	}
+}
+
+func TestShuffle(t *testing.T) {
+	rows := func() []Row {
+		var rows []Row
+		for i := 0; i < 20; i++ {
+			rows = append(rows, Row{Name: sprintf("%d", i)})
+		}
+		return rows
+	}
+	order := func(rows []Row) string {
+		var s []string
+		for _, r := range rows {
+			s = append(s, r.Name)
+		}
+		return strings.Join(s, ",")
+	}
+	a, b, c := rows(), rows(), rows()
+	shuffle(a, 1)
+	shuffle(b, 1)
+	shuffle(c, 2)
+	if order(a) != order(b) {
+		t.Errorf("same seed gave %s and %s", order(a), order(b))
+	}
+	if order(a) == order(c) {
+		t.Errorf("different seeds gave the same order %s", order(a))
+	}
+	if order(a) == order(rows()) {
+		t.Errorf("rows were not shuffled")
+	}
+	seen := map[string]bool{}
+	for _, r := range a {
+		seen[r.Name] = true
+	}
+	if len(seen) != 20 {
+		t.Errorf("shuffled rows are not a permutation: %s", order(a))
+	}
+
+	if s := (&Table{Seed: 42}).seed(); s != 42 {
+		t.Errorf("got seed %d, want 42", s)
+	}
+	*seedFlag = 7
+	defer func() { *seedFlag = 0 }()
+	if s := (&Table{Seed: 42}).seed(); s != 7 {
+		t.Errorf("got seed %d, want 7 from -check.seed", s)
+	}
+}
+
+func TestShuffledNames(t *testing.T) {
+	// names maps each row to the name of the subtest that ran it.
+	run := func(shuffle bool, seed int64) map[int]string {
+		names := map[int]string{}
+		var rows []Row
+		for i := 0; i < 4; i++ {
+			i := i
+			rows = append(rows, Row{
+				Test: func(t *T) error {
+					name := t.Name()
+					names[i] = name[strings.LastIndex(name, "/")+1:]
+					return io.EOF
+				},
+				Want: "EOF",
+			})
+		}
+		t.Run("table", func(t *testing.T) {
+			(&Table{Rows: rows, Shuffle: shuffle, Seed: seed}).Run(t)
+		})
+		return names
+	}
+	want := run(false, 0)
+	for _, seed := range []int64{1, 3} {
+		if got := run(true, seed); !reflect.DeepEqual(got, want) {
+			t.Errorf("seed %d: got names %v, want %v", seed, got, want)
+		}
+	}
+}
+
+func TestRowNames(t *testing.T) {
+	var et *errtype
+	rows := []Row{
+		{Name: "named"},
+		{},
+		{Want: false},
+		{Want: true},
+		{Want: "unknown type"},
+		{Want: "unknown type"},
+		{Want: Equal("bad  value")},
+		{Want: Case("Bad")},
+		{Want: CaseEqual("BAD")},
+		{Want: io.EOF},
+		{Want: errors.New("my error")},
+		{Want: MaxLen(3)},
+		{Want: &et},
+		{Want: 1},
+		{Want: "a very long expected error message that goes on and on"},
+		{Name: "no_error"},
+	}
+	want := []string{
+		"named",
+		"no_error",
+		"no_error_2",
+		"any_error",
+		"contains_unknown_type",
+		"contains_unknown_type_2",
+		"equals_bad_value",
+		"contains_case_Bad",
+		"equals_case_BAD",
+		"is_io.EOF",
+		"is_my_error",
+		"matches_check.maxLen",
+		"as_*check.errtype",
+		"want_int",
+		"contains_a_very_long_expected_error_mess",
+		"no_error_3",
+	}
+	got := rowNames(rows)
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("row %d: got name %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestStructName(t *testing.T) {
+	type named struct{ Name string }
+	type number struct{ Name int }
+	for _, tt := range []struct {
+		row  interface{}
+		name string
+	}{
+		{named{"a"}, "a"},
+		{&named{"b"}, "b"},
+		{(*named)(nil), ""},
+		{number{1}, ""},
+		{struct{ In string }{"x"}, ""},
+		{"string", ""},
+	} {
+		if name := structName(tt.row); name != tt.name {
+			t.Errorf("%#v: got name %q, want %q", tt.row, name, tt.name)
+		}
+	}
+}
+
+func TestCheckRow(t *testing.T) {
+	setDefaults(t)
+	for _, tt := range []struct {
+		name     string
+		got      interface{}
+		err      error
+		wantErr  interface{}
+		wantVal  interface{}
+		checkVal bool
+		out      string
+	}{
+		{"value", 1, nil, nil, 1, true, ""},
+		{"wrong value", 2, nil, nil, 1, true, Value(2, 1)},
+		{"unchecked value", 2, nil, nil, 1, false, ""},
+		{"error", nil, io.EOF, io.EOF, 1, true, ""},
+		{"unexpected error", 1, io.EOF, nil, 1, true, Error(io.EOF, nil)},
+		{"missing error", 1, nil, io.EOF, 1, true, Error(nil, io.EOF)},
+	} {
+		if s := checkRow(defaults(), tt.got, tt.err, tt.wantErr, tt.wantVal, tt.checkVal); s != tt.out {
+			t.Errorf("%s: got %q, want %q", tt.name, s, tt.out)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.18
+// +build go1.18
+
+package check
+
+import "testing"
+
+// RunTable runs a subtest of t for each of rows, checking the error returned
+// by calling f with the row against the want selected from the row by
+// wantErr, as by Error, and, if f returns no error, the value it returns
+// against the want selected by wantVal, as by Value.  A nil wantErr wants no
+// error and a nil wantVal does not check the values:
+//
+//	type row struct {
+//		Name string
+//		In   string
+//		Out  int
+//		Err  interface{}
+//	}
+//	check.RunTable(t, []row{
+//		{Name: "one", In: "1", Out: 1},
+//		{Name: "empty", Err: "invalid syntax"},
+//	}, func(tt row) (interface{}, error) {
+//		return strconv.Atoi(tt.In)
+//	}, func(tt row) interface{} { return tt.Err },
+//		func(tt row) interface{} { return tt.Out })
+//
+// Subtests are named by the Name field of the row, if it is a struct with a
+// string Name field, otherwise they are named after the wanted error, as by
+// Table.Run.
+func RunTable[R any](t *testing.T, rows []R, f func(R) (interface{}, error), wantErr, wantVal func(R) interface{}) {
+	t.Helper()
+	names := make([]string, len(rows))
+	for i, row := range rows {
+		names[i] = structName(row)
+		if names[i] == "" {
+			var want interface{}
+			if wantErr != nil {
+				want = wantErr(row)
+			}
+			names[i] = wantName(want)
+		}
+	}
+	uniqueNames(names)
+	for i, row := range rows {
+		row := row
+		t.Run(names[i], func(t *testing.T) {
+			t.Helper()
+			var want, val interface{}
+			if wantErr != nil {
+				want = wantErr(row)
+			}
+			if wantVal != nil {
+				val = wantVal(row)
+			}
+			got, err := f(row)
+			if s := checkRow(defaults().with(inTest(t)), got, err, want, val, wantVal != nil); s != "" {
+				t.Error(s)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.18
+// +build go1.18
+
+package check
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestRunTable(t *testing.T) {
+	type row struct {
+		Name string
+		In   string
+		Out  int
+		Err  interface{}
+	}
+	calls := 0
+	RunTable(t, []row{
+		{Name: "one", In: "1", Out: 1},
+		{Name: "one", In: "01", Out: 1},
+		{Name: "empty", Err: "invalid syntax"},
+		{Name: "big", In: "1e99", Err: "invalid syntax"},
+	}, func(tt row) (interface{}, error) {
+		return strconv.Atoi(tt.In)
+	}, func(tt row) interface{} { return tt.Err }, func(tt row) interface{} { return tt.Out })
+
+	RunTable(t, []error{nil, strconv.ErrRange}, func(want error) (interface{}, error) {
+		calls++
+		return nil, want
+	}, func(want error) interface{} { return want }, nil)
+	if calls != 2 {
+		t.Errorf("called f %d times, want 2", calls)
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"errors"
+	"sync"
+)
+
+// saves holds the errors saved by Save.
+var saves struct {
+	mu   sync.Mutex
+	errs map[string]error
+}
+
+// Save saves err, which may be nil, as key, replacing any error already saved
+// as key, so a later step of a test may check that the same error resurfaces
+// with Saved.  Save returns err.  Keys are shared by all tests; a test run in
+// parallel with others should use keys that include its name:
+//
+//	key := t.Name() + "/lookup"
+//	_, err := db.Get("bob")
+//	check.Save(key, err)
+//	...
+//	_, err = cache.Get("bob")
+//	check.Error(err, check.Saved(key))
+func Save(key string, err error) error {
+	saves.mu.Lock()
+	defer saves.mu.Unlock()
+	if saves.errs == nil {
+		saves.errs = map[string]error{}
+	}
+	saves.errs[key] = err
+	return err
+}
+
+// lookupSaved returns the error saved as key and whether one was saved.
+func lookupSaved(key string) (error, bool) {
+	saves.mu.Lock()
+	defer saves.mu.Unlock()
+	err, ok := saves.errs[key]
+	return err, ok
+}
+
+type saved struct{ key string }
+
+// Saved returns a Matcher that matches the error saved as key by Save, or an
+// error that wraps it, as by errors.Is.  If nil was saved as key, Saved
+// matches no error.  The saved error is looked up when the check is made, so
+// Saved may be used in a table built before the error is saved.  A check of
+// a key with no saved error fails.
+func Saved(key string) Matcher {
+	return saved{key: key}
+}
+
+const notSaved = "no error saved as %q"
+
+func (m saved) match(c *config, got error) string {
+	want, ok := lookupSaved(m.key)
+	switch {
+	case !ok:
+		return c.failf(notSaved, m.key)
+	case want == nil:
+		return c.checkError(got, nil)
+	case got == nil:
+		return c.failc(CodeMissing, "did not get expected error, want %q", description(m.Describe()))
+	case !errors.Is(got, want):
+		return c.failc(CodeWrong, "got error %q, want %q", got, description(m.Describe()))
+	}
+	return ""
+}
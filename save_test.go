@@ -0,0 +1,63 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestSaved(t *testing.T) {
+	setDefaults(t)
+	key := t.Name() + "/"
+	first := errors.New("no such user")
+	if err := Save(key+"first", first); err != first {
+		t.Errorf("Save returned %v", err)
+	}
+	Save(key+"none", nil)
+	Save(key+"replaced", io.EOF)
+	Save(key+"replaced", first)
+	for _, tt := range []struct {
+		name string
+		got  error
+		key  string
+		out  string
+	}{
+		{"same", first, "first", ""},
+		{"wrapped", fmt.Errorf("cache: %w", first), "first", ""},
+		{"replaced", first, "replaced", ""},
+		{"none", nil, "none", ""},
+		{"other", errors.New("no such user"), "first",
+			sprintf(`got error "no such user", want is the error saved as %q`, key+"first")},
+		{"nil", nil, "first", sprintf(`did not get expected error, want is the error saved as %q`, key+"first")},
+		{"unexpected", io.EOF, "none", sprintf(unexpected, io.EOF)},
+		{"unsaved", io.EOF, "unsaved", sprintf(notSaved, key+"unsaved")},
+	} {
+		if s := Error(tt.got, Saved(key+tt.key)); s != tt.out {
+			t.Errorf("%s: got %q, want %q", tt.name, s, tt.out)
+		}
+	}
+	// A table may be built before the error is saved.
+	want := Saved(key + "later")
+	Save(key+"later", io.EOF)
+	if s := Error(io.EOF, want); s != "" {
+		t.Errorf("later: %s", s)
+	}
+	if s := NewChecker(Codes()).Error(io.EOF, Saved(key+"unsaved")); s != Result("CHK-UNSUPPORTED: "+sprintf(notSaved, key+"unsaved")) {
+		t.Errorf("Codes: got %q", s)
+	}
+}
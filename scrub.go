@@ -0,0 +1,218 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"net"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// A Scrubber returns msg with the text that varies between runs, such as
+// addresses and ports, replaced with placeholders.
+type Scrubber func(msg string) string
+
+type scrub struct {
+	want      interface{}
+	scrubbers []Scrubber
+}
+
+// Scrub returns a Matcher that checks got against want, as by Error, after
+// applying scrubbers, in order, to the message of got.  If want is a string,
+// Equal, Case, or CaseEqual it is scrubbed as well, so it may be written
+// either with placeholders or with the literal text seen in a single run.
+//
+// The scrubbed error wraps got so IsError style checks of want still work,
+// but an error want will never be identical to it.
+func Scrub(want interface{}, scrubbers ...Scrubber) Matcher {
+	return scrub{want: want, scrubbers: scrubbers}
+}
+
+func (s scrub) match(c *config, got error) string {
+	want := s.want
+	switch w := want.(type) {
+	case string:
+		want = s.apply(w)
+	case Equal:
+		want = Equal(s.apply(string(w)))
+	case Case:
+		want = Case(s.apply(string(w)))
+	case CaseEqual:
+		want = CaseEqual(s.apply(string(w)))
+	}
+	if got != nil {
+		got = &scrubbed{msg: s.apply(got.Error()), err: got}
+	}
+	return c.checkError(got, want)
+}
+
+type firstLine struct {
+	want interface{}
+}
+
+// FirstLine returns a Matcher that checks got against want, as by Error,
+// using only the first line of the message of got.  This suits errors that
+// follow a stable headline with a stack trace or other details.  If want is a
+// string, Equal, Case, or CaseEqual only its first line is used as well.  As
+// with Scrub, an error want will never be identical to got.
+func FirstLine(want interface{}) Matcher {
+	return firstLine{want: want}
+}
+
+func (f firstLine) match(c *config, got error) string {
+	return scrub{want: f.want, scrubbers: []Scrubber{FirstLineOnly}}.match(c, got)
+}
+
+// apply returns msg after applying each of the scrubbers in s.
+func (s scrub) apply(msg string) string {
+	return Normalize(msg, s.scrubbers...)
+}
+
+// A scrubbed error is an error with a scrubbed message.
+type scrubbed struct {
+	msg string
+	err error
+}
+
+func (e *scrubbed) Error() string { return e.msg }
+func (e *scrubbed) Unwrap() error { return e.err }
+
+// Placeholders used by the Scrubbers in this package.
+const (
+	PlaceholderIP   = "<ip>"
+	PlaceholderPort = "<port>"
+	PlaceholderHost = "<host>"
+	PlaceholderUser = "<user>"
+	PlaceholderHome = "<home>"
+)
+
+var (
+	bracketedIP = regexp.MustCompile(`\[[0-9A-Fa-f:.]+(%[-\w.]+)?\]`)
+	ipv6Word    = regexp.MustCompile(`[\w:.]*:[\w:.]*:[\w:.]*`)
+	ipv4Addr    = regexp.MustCompile(`\b\d{1,3}(\.\d{1,3}){3}\b`)
+	hostPort    = regexp.MustCompile(`(` + PlaceholderIP + `|\blocalhost):\d{1,5}\b`)
+)
+
+// ScrubNetwork is a Scrubber that replaces IPv4 and IPv6 addresses with
+// PlaceholderIP and the port numbers following them, or following localhost,
+// with PlaceholderPort.  Only whole words that parse as addresses are
+// replaced, so names such as std::string are left alone.  A bracketed IPv6
+// address, such as [::1], is replaced including its brackets:
+//
+//	dial tcp [::1]:54321: connect: connection refused
+//	dial tcp 127.0.0.1:54321: connect: connection refused
+//
+// both become
+//
+//	dial tcp <ip>:<port>: connect: connection refused
+func ScrubNetwork(msg string) string {
+	msg = bracketedIP.ReplaceAllStringFunc(msg, func(s string) string {
+		ip := strings.Trim(s, "[]")
+		if i := strings.Index(ip, "%"); i >= 0 {
+			ip = ip[:i]
+		}
+		if net.ParseIP(ip) == nil {
+			return s
+		}
+		return PlaceholderIP
+	})
+	msg = ipv6Word.ReplaceAllStringFunc(msg, scrubIPv6)
+	msg = ipv4Addr.ReplaceAllStringFunc(msg, func(s string) string {
+		if net.ParseIP(s) == nil {
+			return s
+		}
+		return PlaceholderIP
+	})
+	return hostPort.ReplaceAllString(msg, "$1:"+PlaceholderPort)
+}
+
+// scrubIPv6 returns word, a whole word of letters, digits, colons, and dots,
+// with PlaceholderIP in place of the IPv6 address it is, less any trailing
+// colons and dots, such as the colon of "dial ::1: refused".  A word must
+// contain a decimal digit to be an address, so identifiers such as a::b and
+// std::string are left alone.
+func scrubIPv6(word string) string {
+	ip := strings.TrimRight(word, ":.")
+	if !strings.ContainsAny(ip, "0123456789") || net.ParseIP(ip) == nil {
+		return word
+	}
+	return PlaceholderIP + word[len(ip):]
+}
+
+// ScrubHost is a Scrubber that replaces the local hostname, as returned by
+// os.Hostname, with PlaceholderHost.  Only whole words are replaced.
+func ScrubHost(msg string) string {
+	host, err := os.Hostname()
+	if err != nil {
+		return msg
+	}
+	return replaceWord(msg, host, PlaceholderHost)
+}
+
+// ScrubUser is a Scrubber that replaces the name of the current user, as
+// found in $USER (or $USERNAME), with PlaceholderUser.  Only whole words are
+// replaced.
+func ScrubUser(msg string) string {
+	name := os.Getenv("USER")
+	if name == "" {
+		name = os.Getenv("USERNAME")
+	}
+	return replaceWord(msg, name, PlaceholderUser)
+}
+
+// ScrubHome is a Scrubber that replaces the current user's home directory, as
+// returned by os.UserHomeDir, with PlaceholderHome, e.g., /home/alice/.config
+// becomes <home>/.config.  The home directory is only replaced where it is
+// followed by a separator or the end of a path, so /home/alice/x is not
+// changed when the home directory is /home/al.  A home directory of the root,
+// as is common in containers, is never replaced.
+func ScrubHome(msg string) string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return msg
+	}
+	home = strings.TrimRight(home, `/\`)
+	if home == "" || strings.HasSuffix(home, ":") {
+		// The root directory, such as / or C:\.
+		return msg
+	}
+	re := regexp.MustCompile(regexp.QuoteMeta(home) + `($|[^\w.-])`)
+	return re.ReplaceAllString(msg, PlaceholderHome+"${1}")
+}
+
+// ScrubLocal is a Scrubber that applies ScrubHome, ScrubUser, and ScrubHost,
+// in that order.
+func ScrubLocal(msg string) string {
+	return ScrubHost(ScrubUser(ScrubHome(msg)))
+}
+
+// replaceWord returns msg with each whole word occurrence of word replaced by
+// placeholder.  Nothing is replaced if word is empty.
+func replaceWord(msg, word, placeholder string) string {
+	if word == "" {
+		return msg
+	}
+	re := regexp.MustCompile(`(^|[^\w.-])` + regexp.QuoteMeta(word) + `($|[^\w-])`)
+	// The trailing context of one match may be the leading context of the
+	// next, so replace until nothing changes.
+	for {
+		next := re.ReplaceAllString(msg, "${1}"+placeholder+"${2}")
+		if next == msg {
+			return msg
+		}
+		msg = next
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+)
+
+func TestScrubNetwork(t *testing.T) {
+	for _, tt := range []struct {
+		in, out string
+	}{
+		{"", ""},
+		{"no addresses here", "no addresses here"},
+		{"dial tcp 127.0.0.1:54321: connect: connection refused", "dial tcp <ip>:<port>: connect: connection refused"},
+		{"dial tcp [::1]:54321: connect: connection refused", "dial tcp <ip>:<port>: connect: connection refused"},
+		{"dial tcp [fe80::1%eth0]:80: timeout", "dial tcp <ip>:<port>: timeout"},
+		{"read udp 10.0.0.1:53->8.8.8.8:53: i/o timeout", "read udp <ip>:<port>-><ip>:<port>: i/o timeout"},
+		{"lookup 2001:db8::68 failed", "lookup <ip> failed"},
+		{"mapped ::ffff:192.0.2.1 address", "mapped <ip> address"},
+		{"dial tcp localhost:8080: refused", "dial tcp localhost:<port>: refused"},
+		{"at 12:30:45 in main.go:12", "at 12:30:45 in main.go:12"},
+		{"version 1.2.3.4567", "version 1.2.3.4567"},
+		{"not an address 999.1.1.1", "not an address 999.1.1.1"},
+		{"cannot convert std::string to int", "cannot convert std::string to int"},
+		{"a::b", "a::b"},
+		{"abc::def", "abc::def"},
+		{"no method named foo in std::io::Error", "no method named foo in std::io::Error"},
+		{"in crate::v2::parse at src/lib.rs:10:5", "in crate::v2::parse at src/lib.rs:10:5"},
+		{"thrown from ns::Widget::resize(int)", "thrown from ns::Widget::resize(int)"},
+		{"dial ::1: connection refused", "dial <ip>: connection refused"},
+		{"peer fe80::1.", "peer <ip>."},
+	} {
+		if out := ScrubNetwork(tt.in); out != tt.out {
+			t.Errorf("ScrubNetwork(%q) got %q, want %q", tt.in, out, tt.out)
+		}
+	}
+}
+
+func TestScrub(t *testing.T) {
+	err := fmt.Errorf("dial tcp 127.0.0.1:54321: %w", io.EOF)
+	for _, tt := range []struct {
+		name string
+		got  error
+		want interface{}
+		out  string
+	}{
+		{
+			name: "placeholders",
+			got:  err,
+			want: Scrub("dial tcp <ip>:<port>", ScrubNetwork),
+		}, {
+			name: "literal",
+			got:  err,
+			want: Scrub(Equal("dial tcp [::1]:80: EOF"), ScrubNetwork),
+		}, {
+			name: "case",
+			got:  err,
+			want: Scrub(Case("DIAL TCP 10.1.1.1:1"), ScrubNetwork),
+		}, {
+			name: "case equal",
+			got:  err,
+			want: Scrub(CaseEqual("DIAL TCP 10.1.1.1:1: eof"), ScrubNetwork),
+		}, {
+			name: "wrong",
+			got:  err,
+			want: Scrub(Equal("dial udp <ip>:<port>: EOF"), ScrubNetwork),
+			out:  `got error "dial tcp <ip>:<port>: EOF", want "dial udp <ip>:<port>: EOF"`,
+		}, {
+			name: "unscrubbed",
+			got:  err,
+			want: Scrub("127.0.0.1:54322"),
+			out:  `got error "dial tcp 127.0.0.1:54321: EOF", want "127.0.0.1:54322"`,
+		}, {
+			name: "missing",
+			want: Scrub("dial tcp", ScrubNetwork),
+			out:  `did not get expected error "dial tcp"`,
+		}, {
+			name: "no error",
+			want: Scrub(nil, ScrubNetwork),
+		}, {
+			name: "bool",
+			got:  err,
+			want: Scrub(true, ScrubNetwork),
+		},
+	} {
+		if s := Error(tt.got, tt.want); s != tt.out {
+			t.Errorf("%s: got %q, want %q", tt.name, s, tt.out)
+		}
+	}
+	if !errors.Is(&scrubbed{msg: "x", err: err}, io.EOF) {
+		t.Errorf("scrubbed error does not wrap io.EOF")
+	}
+}
+
+func TestScrubLocal(t *testing.T) {
+	setenv(t, "HOME", "/home/alice")
+	setenv(t, "USER", "alice")
+	host, err := os.Hostname()
+	if err != nil {
+		t.Skipf("no hostname: %v", err)
+	}
+	for _, tt := range []struct {
+		f       Scrubber
+		in, out string
+	}{
+		{ScrubHome, "open /home/alice/.config: denied", "open <home>/.config: denied"},
+		{ScrubHome, "open /home/alicex/.config: denied", "open /home/alicex/.config: denied"},
+		{ScrubHome, "open /home/alice.bak: denied", "open /home/alice.bak: denied"},
+		{ScrubHome, "cd /home/alice", "cd <home>"},
+		{ScrubHome, `"/home/alice" "/home/alice\x"`, `"<home>" "<home>\x"`},
+		{ScrubUser, "user alice: unknown", "user <user>: unknown"},
+		{ScrubUser, "alice alice", "<user> <user>"},
+		{ScrubUser, "user alicex: unknown", "user alicex: unknown"},
+		{ScrubUser, "user malice: unknown", "user malice: unknown"},
+		{ScrubHost, "lookup " + host + ": no such host", "lookup <host>: no such host"},
+		{ScrubHost, "lookup " + host + "x: no such host", "lookup " + host + "x: no such host"},
+		{ScrubLocal, "alice@" + host + ":/home/alice/x", "<user>@<host>:<home>/x"},
+	} {
+		if out := tt.f(tt.in); out != tt.out {
+			t.Errorf("got %q, want %q", out, tt.out)
+		}
+	}
+	setenv(t, "HOME", "/")
+	if out := ScrubLocal("open /etc/x"); out != "open /etc/x" {
+		t.Errorf("HOME=/: got %q", out)
+	}
+	setenv(t, "USER", "")
+	setenv(t, "USERNAME", "")
+	if out := ScrubUser("user alice"); out != "user alice" {
+		t.Errorf("ScrubUser without a user got %q", out)
+	}
+}
+
+func TestFirstLine(t *testing.T) {
+	setDefaults(t)
+	got := errors.New("open failed: permission denied\ngoroutine 1 [running]:\nmain.main()")
+	for _, tt := range []struct {
+		got  error
+		want interface{}
+		out  string
+	}{
+		{got, Equal("open failed: permission denied"), ""},
+		{got, Equal("open failed: permission denied\nstack differs"), ""},
+		{got, "permission", ""},
+		{got, "goroutine", sprintf(wrong, "open failed: permission denied", "goroutine")},
+		{got, Equal("open failed"), sprintf(wrong, "open failed: permission denied", "open failed")},
+		{got, CaseEqual("OPEN FAILED: PERMISSION DENIED"), ""},
+		{errors.New("one line"), Equal("one line"), ""},
+		{got, true, ""},
+		{nil, nil, ""},
+		{nil, "open", sprintf(expected, "open")},
+		{fmt.Errorf("wrapped: %w\nmore", &os.PathError{Op: "open", Path: "x", Err: io.EOF}), new(*os.PathError), ""},
+	} {
+		if s := Error(tt.got, FirstLine(tt.want)); s != tt.out {
+			t.Errorf("Error(%q, FirstLine(%v)): got %q, want %q", tt.got, tt.want, s, tt.out)
+		}
+	}
+	if d := Describe(FirstLine(Equal("x"))); d != `is "x", first line only` {
+		t.Errorf("got description %q", d)
+	}
+	if err := Validate(FirstLine(3)); err == nil || err.Error() != "want 0: FirstLine: unsupported type int" {
+		t.Errorf("got validation error %v", err)
+	}
+}
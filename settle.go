@@ -0,0 +1,31 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import "time"
+
+// after returns a channel that is closed once d has passed, and a function
+// that stops it.  Within a testing/synctest bubble the channel is only closed
+// once the other goroutines of the bubble are blocked, so that a goroutine
+// finishing at the same virtual instant is seen to finish before the time
+// runs out.
+func after(d time.Duration) (<-chan struct{}, func()) {
+	ch := make(chan struct{})
+	t := time.AfterFunc(d, func() {
+		settle()
+		close(ch)
+	})
+	return ch, func() { t.Stop() }
+}
@@ -0,0 +1,28 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.25
+// +build go1.25
+
+package check
+
+import "testing/synctest"
+
+// settle waits for the other goroutines of the testing/synctest bubble of the
+// caller to block.  It returns immediately if the caller is not in a bubble.
+func settle() {
+	// synctest.Wait panics when not called from within a bubble.
+	defer func() { recover() }()
+	synctest.Wait()
+}
@@ -0,0 +1,153 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.21
+// +build go1.21
+
+package check
+
+import (
+	"log/slog"
+	"strings"
+)
+
+type attrs []slog.Attr
+
+// Attrs returns a Matcher that matches an error carrying each of the
+// attributes in want, such as slog.String("user", "alice"), compared with
+// slog.Value.Equal.  Attributes not in want are ignored.  The attributes of an
+// error are those returned by its Attrs() []slog.Attr method or, if it is a
+// slog.LogValuer, those of the group its LogValue method returns.  The errors
+// got wraps are consulted as well, with the attributes of outer errors taking
+// precedence.  Attributes in groups are named by their path, e.g., a want of
+//
+//	slog.Group("req", slog.Int("id", 7))
+//
+// matches an error with the attribute "req.id" of 7.
+func Attrs(want ...slog.Attr) Matcher {
+	return attrs(want)
+}
+
+const (
+	missingAttr = "got error %q, want attribute %q"
+	wrongAttr   = "got error %q, want %q for attribute %q (got %q)"
+)
+
+func init() {
+	formatCodes[missingAttr] = CodeWrong
+	formatCodes[wrongAttr] = CodeWrong
+}
+
+func (a attrs) match(c *config, got error) string {
+	if got == nil {
+		return c.failf(missing)
+	}
+	have := errorAttrs(got)
+	for _, w := range flattenAttrs("", a, nil) {
+		v, ok := have[w.Key]
+		switch {
+		case !ok:
+			return c.failf(missingAttr, got, w.Key)
+		case !v.Equal(w.Value):
+			return c.failf(wrongAttr, got, w.Value, w.Key, v)
+		}
+	}
+	return ""
+}
+
+func (a attrs) Describe() string {
+	flat := flattenAttrs("", a, nil)
+	pairs := make([]string, len(flat))
+	for i, w := range flat {
+		pairs[i] = w.String()
+	}
+	return "an error with attributes " + strings.Join(pairs, " ")
+}
+
+// errorAttrs returns the attributes of err and the errors it wraps, keyed by
+// their flattened names.  The first error in the chain to have an attribute
+// determines its value.
+func errorAttrs(err error) map[string]slog.Value {
+	have := map[string]slog.Value{}
+	walk(err, func(err error) bool {
+		var list []slog.Attr
+		switch e := err.(type) {
+		case interface{ Attrs() []slog.Attr }:
+			list = e.Attrs()
+		case slog.LogValuer:
+			if v := e.LogValue().Resolve(); v.Kind() == slog.KindGroup {
+				list = v.Group()
+			}
+		}
+		for _, a := range flattenAttrs("", list, nil) {
+			if _, ok := have[a.Key]; !ok {
+				have[a.Key] = a.Value
+			}
+		}
+		return true
+	})
+	return have
+}
+
+// flattenAttrs appends list to flat, resolved and with the attributes of
+// groups replaced by their members, named prefix.group.key.
+func flattenAttrs(prefix string, list []slog.Attr, flat []slog.Attr) []slog.Attr {
+	for _, a := range list {
+		a.Value = a.Value.Resolve()
+		key := a.Key
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if a.Value.Kind() == slog.KindGroup {
+			// A group with an empty key is inlined, as by slog.
+			if a.Key == "" {
+				key = prefix
+			}
+			flat = flattenAttrs(key, a.Value.Group(), flat)
+			continue
+		}
+		flat = append(flat, slog.Attr{Key: key, Value: a.Value})
+	}
+	return flat
+}
+
+// CaptureSlog returns the output logged by the default logger of log/slog
+// while f runs, as by slog.Error, along with that of the standard logger of
+// the log package, which slog then handles.  Each record is captured as a
+// line of text, as written by slog.TextHandler, without its time, e.g.:
+//
+//	level=WARN msg="retrying request" attempt=2 err="connection refused"
+//
+// Records of all levels, including Debug, are captured.  The default slog
+// logger and the standard logger are restored when f returns.
+func CaptureSlog(f func()) *Logs {
+	l := &Logs{}
+	restore := saveLog()
+	prev := slog.Default()
+	defer func() {
+		slog.SetDefault(prev)
+		restore()
+	}()
+	slog.SetDefault(slog.New(slog.NewTextHandler(l, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey && len(groups) == 0 {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})))
+	f()
+	return l
+}
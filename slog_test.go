@@ -0,0 +1,102 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.21
+// +build go1.21
+
+package check
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"log/slog"
+	"reflect"
+	"testing"
+)
+
+// attrErr is an error with attributes.
+type attrErr struct {
+	msg   string
+	attrs []slog.Attr
+	err   error
+}
+
+func (e *attrErr) Error() string      { return e.msg }
+func (e *attrErr) Attrs() []slog.Attr { return e.attrs }
+func (e *attrErr) Unwrap() error      { return e.err }
+
+// valuerErr is an error that is a slog.LogValuer.
+type valuerErr struct{ user string }
+
+func (e valuerErr) Error() string { return "denied" }
+func (e valuerErr) LogValue() slog.Value {
+	return slog.GroupValue(slog.String("user", e.user), slog.Group("req", slog.Int("id", 7)))
+}
+
+func TestAttrs(t *testing.T) {
+	setDefaults(t)
+	inner := &attrErr{msg: "inner", attrs: []slog.Attr{slog.Int("code", 1), slog.String("op", "read")}}
+	outer := &attrErr{msg: "outer", attrs: []slog.Attr{slog.Int("code", 2)}, err: inner}
+	for _, tt := range []struct {
+		got  error
+		want []slog.Attr
+		out  string
+	}{
+		{outer, nil, ""},
+		{outer, []slog.Attr{slog.Int("code", 2), slog.String("op", "read")}, ""},
+		{fmt.Errorf("wrapped: %w", outer), []slog.Attr{slog.Int("code", 2)}, ""},
+		{outer, []slog.Attr{slog.Int("code", 1)}, sprintf(wrongAttr, "outer", "1", "code", "2")},
+		{outer, []slog.Attr{slog.Int64("code", 2)}, ""},
+		{outer, []slog.Attr{slog.String("code", "2")}, sprintf(wrongAttr, "outer", "2", "code", "2")},
+		{outer, []slog.Attr{slog.Bool("retry", true)}, sprintf(missingAttr, "outer", "retry")},
+		{errors.New("plain"), []slog.Attr{slog.Int("code", 1)}, sprintf(missingAttr, "plain", "code")},
+		{nil, []slog.Attr{slog.Int("code", 1)}, sprintf(missing)},
+		{valuerErr{"alice"}, []slog.Attr{slog.String("user", "alice"), slog.Int("req.id", 7)}, ""},
+		{valuerErr{"alice"}, []slog.Attr{slog.Group("req", slog.Int("id", 7))}, ""},
+		{valuerErr{"alice"}, []slog.Attr{slog.Group("", slog.String("user", "alice"))}, ""},
+	} {
+		if s := Error(tt.got, Attrs(tt.want...)); s != tt.out {
+			t.Errorf("%v %v: got %q, want %q", tt.got, tt.want, s, tt.out)
+		}
+	}
+	if d := Describe(Attrs(slog.Int("code", 2), slog.Group("req", slog.String("id", "x")))); d != "an error with attributes code=2 req.id=x" {
+		t.Errorf("got description %q", d)
+	}
+}
+
+func TestCaptureSlog(t *testing.T) {
+	setDefaults(t)
+	prev := slog.Default()
+	defer saveLog()()
+	logs := CaptureSlog(func() {
+		slog.Debug("starting")
+		slog.Warn("retrying request", "attempt", 2, "err", "connection refused")
+		log.Print("legacy")
+	})
+	want := []string{
+		"level=DEBUG msg=starting",
+		`level=WARN msg="retrying request" attempt=2 err="connection refused"`,
+		"level=INFO msg=legacy",
+	}
+	if got := logs.Lines(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got lines %q, want %q", got, want)
+	}
+	if s := logs.Contains(Regexp(`level=WARN .*attempt=2`)); s != "" {
+		t.Error(s)
+	}
+	if slog.Default() != prev {
+		t.Errorf("default slog logger not restored")
+	}
+}
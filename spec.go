@@ -0,0 +1,143 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// A SpecParser returns the want described by arg, the text of a spec
+// following its prefix, or an error if arg is malformed.
+type SpecParser func(arg string) (interface{}, error)
+
+// builtinSpecs are the prefixes understood by ParseSpec without registration.
+var builtinSpecs = map[string]SpecParser{
+	"equal:":     func(arg string) (interface{}, error) { return Equal(arg), nil },
+	"case:":      func(arg string) (interface{}, error) { return Case(arg), nil },
+	"caseequal:": func(arg string) (interface{}, error) { return CaseEqual(arg), nil },
+	"contains:":  func(arg string) (interface{}, error) { return arg, nil },
+	"category:":  func(arg string) (interface{}, error) { return Category(arg), nil },
+	"regexp:": func(arg string) (interface{}, error) {
+		if _, err := compileRegexp(arg); err != nil {
+			return nil, err
+		}
+		return Regexp(arg), nil
+	},
+}
+
+var specRegistry struct {
+	mu      sync.RWMutex
+	parsers map[string]SpecParser
+}
+
+// RegisterSpec registers parse as the parser of specs starting with prefix,
+// such as "grpc-code:", permitting ParseSpec, and the data files decoded with
+// it, to construct wants of packages this package does not import.
+// Registrations are normally made by the init function of the package
+// defining the Matcher:
+//
+//	func init() {
+//		check.RegisterSpec("grpc-code:", func(arg string) (interface{}, error) {
+//			return grpccheck.ParseCode(arg)
+//		})
+//	}
+//
+// RegisterSpec panics if prefix is empty or is already registered.
+func RegisterSpec(prefix string, parse SpecParser) {
+	if prefix == "" {
+		panic("check: RegisterSpec with an empty prefix")
+	}
+	if parse == nil {
+		panic("check: RegisterSpec of " + prefix + " with a nil parser")
+	}
+	specRegistry.mu.Lock()
+	defer specRegistry.mu.Unlock()
+	if _, ok := builtinSpecs[prefix]; ok {
+		panic("check: RegisterSpec of built in prefix " + prefix)
+	}
+	if _, ok := specRegistry.parsers[prefix]; ok {
+		panic("check: RegisterSpec called twice for " + prefix)
+	}
+	if specRegistry.parsers == nil {
+		specRegistry.parsers = map[string]SpecParser{}
+	}
+	specRegistry.parsers[prefix] = parse
+}
+
+// ParseSpec returns the want described by spec, a string such as might be
+// found in a data file driving a table test.  A spec that starts with a
+// registered or built in prefix is parsed by the parser of the longest such
+// prefix.  The built in prefixes are:
+//
+//	equal:MSG      Equal(MSG)
+//	case:MSG       Case(MSG)
+//	caseequal:MSG  CaseEqual(MSG)
+//	contains:MSG   MSG, matching messages containing MSG
+//	regexp:PAT     Regexp(PAT)
+//	category:NAME  Category(NAME)
+//
+// The empty spec is nil, no error, and any other spec is a string.
+func ParseSpec(spec string) (interface{}, error) {
+	if spec == "" {
+		return nil, nil
+	}
+	prefix, parse := lookupSpec(spec)
+	if parse == nil {
+		return spec, nil
+	}
+	want, err := parse(spec[len(prefix):])
+	if err != nil {
+		return nil, fmt.Errorf("spec %q: %v", spec, err)
+	}
+	return want, nil
+}
+
+// lookupSpec returns the longest prefix of spec with a parser, and its
+// parser, or a nil parser if there is none.
+func lookupSpec(spec string) (string, SpecParser) {
+	var prefix string
+	var parse SpecParser
+	consider := func(parsers map[string]SpecParser) {
+		for p, f := range parsers {
+			if len(p) > len(prefix) && strings.HasPrefix(spec, p) {
+				prefix, parse = p, f
+			}
+		}
+	}
+	consider(builtinSpecs)
+	specRegistry.mu.RLock()
+	consider(specRegistry.parsers)
+	specRegistry.mu.RUnlock()
+	return prefix, parse
+}
+
+// SpecPrefixes returns the sorted list of registered and built in spec
+// prefixes.
+func SpecPrefixes() []string {
+	specRegistry.mu.RLock()
+	defer specRegistry.mu.RUnlock()
+	prefixes := make([]string, 0, len(builtinSpecs)+len(specRegistry.parsers))
+	for p := range builtinSpecs {
+		prefixes = append(prefixes, p)
+	}
+	for p := range specRegistry.parsers {
+		prefixes = append(prefixes, p)
+	}
+	sort.Strings(prefixes)
+	return prefixes
+}
@@ -0,0 +1,108 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func saveSpecs(t *testing.T) {
+	specRegistry.mu.Lock()
+	saved := specRegistry.parsers
+	specRegistry.parsers = nil
+	specRegistry.mu.Unlock()
+	t.Cleanup(func() {
+		specRegistry.mu.Lock()
+		specRegistry.parsers = saved
+		specRegistry.mu.Unlock()
+	})
+}
+
+func TestParseSpec(t *testing.T) {
+	saveSpecs(t)
+	RegisterSpec("grpc-code:", func(arg string) (interface{}, error) {
+		n, err := strconv.Atoi(arg)
+		if err != nil {
+			return nil, errors.New("not a code")
+		}
+		return Status(n, nil), nil
+	})
+	RegisterSpec("equal:ci:", func(arg string) (interface{}, error) { return CaseEqual(arg), nil })
+
+	for _, tt := range []struct {
+		spec string
+		want interface{}
+		err  string
+	}{
+		{spec: "", want: nil},
+		{spec: "not found", want: "not found"},
+		{spec: "contains:equal:", want: "equal:"},
+		{spec: "equal:not found", want: Equal("not found")},
+		{spec: "case:Not", want: Case("Not")},
+		{spec: "caseequal:NOT FOUND", want: CaseEqual("NOT FOUND")},
+		{spec: "regexp:^not", want: Regexp("^not")},
+		{spec: "regexp:(", err: `spec "regexp:(": error parsing regexp: missing closing ): ` + "`(`"},
+		{spec: "category:NotFound", want: NotFound},
+		{spec: "grpc-code:5", want: Status(5, nil)},
+		{spec: "grpc-code:five", err: `spec "grpc-code:five": not a code`},
+		{spec: "equal:ci:Not Found", want: CaseEqual("Not Found")},
+	} {
+		want, err := ParseSpec(tt.spec)
+		if fmt.Sprint(err) != tt.err && !(err == nil && tt.err == "") {
+			t.Errorf("ParseSpec(%q): got error %v, want %s", tt.spec, err, tt.err)
+			continue
+		}
+		if !reflect.DeepEqual(want, tt.want) {
+			t.Errorf("ParseSpec(%q): got %#v, want %#v", tt.spec, want, tt.want)
+		}
+	}
+	if got := strings.Join(SpecPrefixes(), " "); got != "case: caseequal: category: contains: equal: equal:ci: grpc-code: regexp:" {
+		t.Errorf("got prefixes %s", got)
+	}
+	want, _ := ParseSpec("grpc-code:5")
+	if s := Error(grpcErr{&grpcStatus{code: 5, msg: "gone"}}, want); s != "" {
+		t.Error(s)
+	}
+}
+
+func TestRegisterSpecPanics(t *testing.T) {
+	saveSpecs(t)
+	parse := func(arg string) (interface{}, error) { return arg, nil }
+	RegisterSpec("x:", parse)
+	for _, tt := range []struct {
+		prefix string
+		parse  SpecParser
+		out    string
+	}{
+		{"", parse, "check: RegisterSpec with an empty prefix"},
+		{"y:", nil, "check: RegisterSpec of y: with a nil parser"},
+		{"equal:", parse, "check: RegisterSpec of built in prefix equal:"},
+		{"x:", parse, "check: RegisterSpec called twice for x:"},
+	} {
+		func() {
+			defer func() {
+				if p := recover(); p != tt.out {
+					t.Errorf("RegisterSpec(%q): got panic %v, want %s", tt.prefix, p, tt.out)
+				}
+			}()
+			RegisterSpec(tt.prefix, tt.parse)
+		}()
+	}
+}
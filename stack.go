@@ -0,0 +1,118 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"reflect"
+	"strings"
+)
+
+type hasStack bool
+
+const (
+	noStack       = "got error %q without a stack trace"
+	unwantedStack = "got error %q with a stack trace, want none"
+)
+
+// stackMethods are the names of the methods that return the stack trace of an
+// error, as the program counters returned by runtime.Callers.
+var stackMethods = []string{"StackTrace", "Callers"}
+
+// HasStack returns a Matcher that matches an error whose chain includes an
+// error carrying a stack trace, if want is true, or that includes no such
+// error, if want is false.  An error carries a stack trace if it has a
+// StackTrace or Callers method returning a non-empty slice of program
+// counters, as returned by runtime.Callers, such as the StackTrace method of
+// the errors of github.com/pkg/errors.
+func HasStack(want bool) Matcher {
+	return hasStack(want)
+}
+
+// WithStack returns an Option that appends the origin of the error checked,
+// the stack trace carried by the innermost error of its chain that carries
+// one, as described by HasStack, to each failure, e.g.:
+//
+//	got error "dial: connection refused", want "timeout"
+//		origin:
+//			example.com/db.dial /src/db/dial.go:42
+//			example.com/db.Open /src/db/db.go:17
+//
+// Nothing is appended if no error in the chain carries a stack trace.
+func WithStack() Option {
+	return func(c *config) { c.withStack = true }
+}
+
+// stackOf returns the program counters of the stack trace carried by the
+// innermost error of the chain of err that carries one, or nil.
+func stackOf(err error) []uintptr {
+	var pcs []uintptr
+	walk(err, func(err error) bool {
+		if p := callers(err); p != nil {
+			pcs = p
+		}
+		return true
+	})
+	return pcs
+}
+
+// callers returns the program counters of the stack trace carried by err,
+// not considering the errors it wraps, or nil.
+func callers(err error) []uintptr {
+	for _, name := range stackMethods {
+		v, ok := call(err, name)
+		if !ok {
+			continue
+		}
+		rv := reflect.ValueOf(v)
+		if rv.Kind() != reflect.Slice || rv.Type().Elem().Kind() != reflect.Uintptr || rv.Len() == 0 {
+			continue
+		}
+		pcs := make([]uintptr, rv.Len())
+		for i := range pcs {
+			pcs[i] = uintptr(rv.Index(i).Uint())
+		}
+		return pcs
+	}
+	return nil
+}
+
+// origin returns the origin of err, as appended to failures by WithStack, or
+// the empty string.
+func origin(err error) string {
+	pcs := stackOf(err)
+	if pcs == nil {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("\n\torigin:")
+	for _, line := range frameLines(pcs) {
+		b.WriteString("\n\t\t")
+		b.WriteString(line)
+	}
+	return b.String()
+}
+
+func (m hasStack) match(c *config, got error) string {
+	if got == nil {
+		return c.failf(missing)
+	}
+	switch has := stackOf(got) != nil; {
+	case bool(m) && !has:
+		return c.failf(noStack, got)
+	case !bool(m) && has:
+		return c.failf(unwantedStack, got)
+	}
+	return ""
+}
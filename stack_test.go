@@ -0,0 +1,108 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// tracedErr is an error carrying a stack trace from runtime.Callers.
+type tracedErr struct {
+	msg string
+	pcs []uintptr
+}
+
+func newTraced(msg string) *tracedErr {
+	pcs := make([]uintptr, 32)
+	return &tracedErr{msg: msg, pcs: pcs[:runtime.Callers(2, pcs)]}
+}
+
+func (e *tracedErr) Error() string      { return e.msg }
+func (e *tracedErr) Callers() []uintptr { return e.pcs }
+
+// frame and frameStack are as the Frame and StackTrace of
+// github.com/pkg/errors.
+type frame uintptr
+type frameStack []frame
+
+// pkgErr is an error with a StackTrace method as in github.com/pkg/errors.
+type pkgErr struct{ stack frameStack }
+
+func (e pkgErr) Error() string          { return "pkg error" }
+func (e pkgErr) StackTrace() frameStack { return e.stack }
+
+func TestHasStack(t *testing.T) {
+	setDefaults(t)
+	traced := newTraced("boom")
+	pkg := pkgErr{frameStack{frame(traced.pcs[0])}}
+	for _, tt := range []struct {
+		name string
+		got  error
+		want Matcher
+		out  string
+	}{
+		{"traced", traced, HasStack(true), ""},
+		{"wrapped", fmt.Errorf("run: %w", traced), HasStack(true), ""},
+		{"pkg", pkg, HasStack(true), ""},
+		{"joined", &multi{io.EOF, pkg}, HasStack(true), ""},
+		{"no stack", io.EOF, HasStack(false), ""},
+		{"empty stack", pkgErr{}, HasStack(false), ""},
+		{"missing stack", io.EOF, HasStack(true), sprintf(noStack, io.EOF)},
+		{"unwanted stack", traced, HasStack(false), sprintf(unwantedStack, traced)},
+		{"nil", nil, HasStack(false), missing},
+	} {
+		if s := Error(tt.got, tt.want); s != tt.out {
+			t.Errorf("%s: got %q, want %q", tt.name, s, tt.out)
+		}
+	}
+	if d := Describe(HasStack(false)); d != "has no stack trace" {
+		t.Errorf("got description %q", d)
+	}
+}
+
+func TestWithStack(t *testing.T) {
+	setDefaults(t)
+	ck := NewChecker(WithStack())
+	traced := newTraced("boom")
+	s := ck.Error(fmt.Errorf("run: %w", traced), "timeout").String()
+	prefix := Error(fmt.Errorf("run: %w", traced), "timeout") + "\n\torigin:\n\t\tgithub.com/pborman/check.TestWithStack "
+	if !strings.HasPrefix(s, prefix) || !strings.Contains(s, "stack_test.go:") {
+		t.Errorf("got %q, want prefix %q", s, prefix)
+	}
+	// The origin is the innermost stack.
+	inner := newTraced("inner")
+	outer := &tracedErr{msg: "outer", pcs: []uintptr{outerPC()}}
+	s = ck.Error(&multi{outer, inner}, "timeout").String()
+	if strings.Contains(s, "outerPC") || !strings.Contains(s, "TestWithStack") {
+		t.Errorf("innermost: got %q", s)
+	}
+	if s := ck.Error(io.EOF, "timeout").String(); s != Error(io.EOF, "timeout") {
+		t.Errorf("no stack: got %q", s)
+	}
+	if s := ck.Error(traced, "boom").String(); s != "" {
+		t.Errorf("pass: got %q", s)
+	}
+}
+
+// outerPC returns a program counter within itself.
+func outerPC() uintptr {
+	pcs := make([]uintptr, 1)
+	runtime.Callers(1, pcs)
+	return pcs[0]
+}
@@ -0,0 +1,157 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// A CategoryCount is the number of checks made against a Category want, and
+// how many of them failed.
+type CategoryCount struct {
+	Ran    int `json:"ran"`
+	Failed int `json:"failed"`
+}
+
+// CategoryStats counts the checks made against each Category want, such as
+// NotFound or Timeout, showing which classes of errors a test suite actually
+// exercises.  The zero value is ready to use.  A CategoryStats is safe for
+// concurrent use.
+type CategoryStats struct {
+	mu     sync.Mutex
+	counts map[Category]CategoryCount
+}
+
+// CountCategories returns an Option that counts, in s, each check made
+// against a Category want and whether it failed.  Checks made by Matches, and
+// the other checks that only report whether an error matches, are not
+// counted.  CountCategories is normally set for a whole run with SetDefaults
+// in TestMain, with the counts exported by Export once the tests complete:
+//
+//	var stats check.CategoryStats
+//
+//	func TestMain(m *testing.M) {
+//		check.SetDefaults(check.CountCategories(&stats))
+//		code := m.Run()
+//		stats.Export(nil)
+//		os.Exit(code)
+//	}
+func CountCategories(s *CategoryStats) Option {
+	return func(c *config) {
+		c.categories = s
+	}
+}
+
+// add counts a check against cat.
+func (s *CategoryStats) add(cat Category, failed bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.counts == nil {
+		s.counts = map[Category]CategoryCount{}
+	}
+	n := s.counts[cat]
+	n.Ran++
+	if failed {
+		n.Failed++
+	}
+	s.counts[cat] = n
+}
+
+// Counts returns a copy of the counts of s by Category.
+func (s *CategoryStats) Counts() map[Category]CategoryCount {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	counts := make(map[Category]CategoryCount, len(s.counts))
+	for cat, n := range s.counts {
+		counts[cat] = n
+	}
+	return counts
+}
+
+// String returns the counts of s as a human readable table.
+func (s *CategoryStats) String() string {
+	return formatCategories(s.Counts())
+}
+
+// A StatsReporter is a Reporter that is also passed the counts of a
+// CategoryStats by Export.
+type StatsReporter interface {
+	Reporter
+	ReportStats(counts map[Category]CategoryCount)
+}
+
+// Export passes the counts of s to r, if r is a StatsReporter, or, if r is
+// nil, to each Reporter of the defaults, as set by SetDefaults or the
+// CHECK_REPORT_FILE environment variable, that is a StatsReporter.  The NDJSON
+// Reporter appends the counts to its file, where they are summed by
+// ReadReport and MergeReports.
+func (s *CategoryStats) Export(r Reporter) {
+	counts := s.Counts()
+	if r != nil {
+		if sr, ok := r.(StatsReporter); ok {
+			sr.ReportStats(counts)
+		}
+		return
+	}
+	for rs := defaults().reporters; rs != nil; rs = rs.next {
+		if sr, ok := rs.r.(StatsReporter); ok {
+			sr.ReportStats(counts)
+		}
+	}
+}
+
+// statsRecord is the line written by the NDJSON Reporter for the counts of a
+// CategoryStats.
+type statsRecord struct {
+	Time       time.Time                  `json:"time"`
+	Categories map[Category]CategoryCount `json:"categories"`
+}
+
+func (r *ndjson) ReportStats(counts map[Category]CategoryCount) {
+	data, err := json.Marshal(statsRecord{Time: time.Now(), Categories: counts})
+	if err == nil {
+		data = append(data, '\n')
+		err = r.write(data)
+	}
+	if err != nil {
+		r.fail(err)
+	}
+}
+
+// formatCategories returns counts as a human readable table, the most
+// exercised categories first.
+func formatCategories(counts map[Category]CategoryCount) string {
+	cats := make([]Category, 0, len(counts))
+	for cat := range counts {
+		cats = append(cats, cat)
+	}
+	sort.Slice(cats, func(i, j int) bool {
+		if counts[cats[i]].Ran != counts[cats[j]].Ran {
+			return counts[cats[i]].Ran > counts[cats[j]].Ran
+		}
+		return cats[i] < cats[j]
+	})
+	var b strings.Builder
+	b.WriteString("categories:")
+	for _, cat := range cats {
+		fmt.Fprintf(&b, "\n\t%d ran\t%d failed\t%s", counts[cat].Ran, counts[cat].Failed, cat)
+	}
+	return b.String()
+}
@@ -0,0 +1,77 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCategoryStats(t *testing.T) {
+	setDefaults(t)
+	var stats CategoryStats
+	ck := NewChecker(CountCategories(&stats))
+	ck.Error(os.ErrNotExist, NotFound)
+	ck.Error(io.EOF, NotFound)
+	ck.Error(nil, NotFound)
+	ck.Error(context.DeadlineExceeded, Timeout)
+	ck.Error(io.EOF, "EOF")
+	ck.Error(io.EOF, Any(Timeout, "EOF"))
+	if !Matches(context.Canceled, Canceled) {
+		t.Errorf("Matches failed")
+	}
+	want := map[Category]CategoryCount{
+		NotFound: {Ran: 3, Failed: 2},
+		Timeout:  {Ran: 2, Failed: 1},
+	}
+	if got := stats.Counts(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got counts %v, want %v", got, want)
+	}
+	if got, want := stats.String(), "categories:\n\t3 ran\t2 failed\tNotFound\n\t2 ran\t1 failed\tTimeout"; got != want {
+		t.Errorf("got String %q, want %q", got, want)
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "report.ndjson")
+	ck = NewChecker(Report(NDJSON(path)), CountCategories(&stats))
+	ck.Error(io.EOF, Timeout)
+	stats.Export(NDJSON(path))
+	SetDefaults(Report(NDJSON(path)))
+	stats.Export(nil)
+	stats.Export(&collector{}) // not a StatsReporter
+
+	s, err := MergeReports(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Failures != 1 {
+		t.Errorf("got %d failures, want 1", s.Failures)
+	}
+	want = map[Category]CategoryCount{
+		NotFound: {Ran: 6, Failed: 4},
+		Timeout:  {Ran: 6, Failed: 4},
+	}
+	if !reflect.DeepEqual(s.Categories, want) {
+		t.Errorf("got summary counts %v, want %v", s.Categories, want)
+	}
+	if str := s.String(); !strings.HasSuffix(str, "\ncategories:\n\t6 ran\t4 failed\tNotFound\n\t6 ran\t4 failed\tTimeout") {
+		t.Errorf("got String:\n%s", str)
+	}
+}
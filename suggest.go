@@ -0,0 +1,72 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"reflect"
+	"strconv"
+)
+
+// Suggest returns the Go expression of the most specific reasonable want for
+// got, to paste into a new table row when writing a table from observed
+// errors.  In order of preference the suggestion is a well known sentinel
+// wrapped by got, such as io.EOF, the first exported type in the chain of got,
+// as by errors.As, such as new(*fs.PathError), or the message of got, such as
+// check.Equal("bad request").  The suggestion for a nil got is nil.
+func Suggest(got error) string {
+	if got == nil {
+		return "nil"
+	}
+	var sentinel string
+	walk(got, func(err error) bool {
+		for s, name := range sentinelNames {
+			if err == s {
+				sentinel = name
+				return false
+			}
+		}
+		return true
+	})
+	if sentinel != "" {
+		return sentinel
+	}
+	var typ string
+	walk(got, func(err error) bool {
+		if suggestible(reflect.TypeOf(err)) {
+			typ = sprintf("new(%T)", err)
+			return false
+		}
+		return true
+	})
+	if typ != "" {
+		return typ
+	}
+	return "check.Equal(" + strconv.Quote(message(got)) + ")"
+}
+
+// suggestible reports whether t is a type Suggest can suggest.  Unexported
+// types cannot be named by the test and the types of the errors and fmt
+// packages are too general to be useful.
+func suggestible(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.PkgPath() {
+	case "", "errors", "fmt":
+		return false
+	}
+	name := t.Name()
+	return name != "" && name[0] >= 'A' && name[0] <= 'Z'
+}
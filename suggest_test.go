@@ -0,0 +1,53 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"testing"
+)
+
+// ExportedErr is an exported error type.
+type ExportedErr struct{ msg string }
+
+func (e *ExportedErr) Error() string { return e.msg }
+
+func TestSuggest(t *testing.T) {
+	pathErr := &os.PathError{Op: "open", Path: "/x", Err: errors.New("broken")}
+	for _, tt := range []struct {
+		got  error
+		want string
+	}{
+		{nil, "nil"},
+		{io.EOF, "io.EOF"},
+		{fmt.Errorf("read: %w", io.ErrUnexpectedEOF), "io.ErrUnexpectedEOF"},
+		{&os.PathError{Op: "open", Path: "/x", Err: os.ErrNotExist}, "os.ErrNotExist"},
+		{pathErr, "new(*fs.PathError)"},
+		{fmt.Errorf("config: %w", pathErr), "new(*fs.PathError)"},
+		{&net.OpError{Op: "dial", Err: &ExportedErr{"refused"}}, "new(*net.OpError)"},
+		{fmt.Errorf("x: %w", &ExportedErr{"y"}), "new(*check.ExportedErr)"},
+		{errors.New(`bad "request"`), `check.Equal("bad \"request\"")`},
+		{fmt.Errorf("wrapped: %w", errors.New("base")), `check.Equal("wrapped: base")`},
+		{&scrubbed{msg: "unexported"}, `check.Equal("unexported")`},
+	} {
+		if got := Suggest(tt.got); got != tt.want {
+			t.Errorf("Suggest(%v) got %s, want %s", tt.got, got, tt.want)
+		}
+	}
+}
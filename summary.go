@@ -0,0 +1,206 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// A Summary counts the failures in one or more reports written by the NDJSON
+// Reporter.  Summaries of the reports of the shards of a sharded test run are
+// combined with Merge.  The zero value is ready to use.
+type Summary struct {
+	Reports  int            `json:"reports"`  // the number of reports summarized
+	Failures int            `json:"failures"` // the total number of failures
+	Checks   map[string]int `json:"checks"`   // failures by check, e.g., "Error"
+	Codes    map[Code]int   `json:"codes"`    // failures by Code, if known
+	Callers  map[string]int `json:"callers"`  // failures by file:line of the check
+	Scopes   map[string]int `json:"scopes,omitempty"`
+
+	// Categories are the counts exported by CategoryStats, if any.
+	Categories map[Category]CategoryCount `json:"categories,omitempty"`
+}
+
+// NewSummary returns an empty Summary.
+func NewSummary() *Summary {
+	return &Summary{
+		Checks:  map[string]int{},
+		Codes:   map[Code]int{},
+		Callers: map[string]int{},
+		Scopes:  map[string]int{},
+
+		Categories: map[Category]CategoryCount{},
+	}
+}
+
+// init makes the maps of s that are nil, such as those of a zero Summary or of
+// one decoded from JSON that omits them.
+func (s *Summary) init() {
+	if s.Checks == nil {
+		s.Checks = map[string]int{}
+	}
+	if s.Codes == nil {
+		s.Codes = map[Code]int{}
+	}
+	if s.Callers == nil {
+		s.Callers = map[string]int{}
+	}
+	if s.Scopes == nil {
+		s.Scopes = map[string]int{}
+	}
+	if s.Categories == nil {
+		s.Categories = map[Category]CategoryCount{}
+	}
+}
+
+// Add counts the failure f.
+func (s *Summary) Add(f Failure) {
+	s.init()
+	s.Failures++
+	s.Checks[f.Check]++
+	if f.Code != "" {
+		s.Codes[f.Code]++
+	}
+	if f.Caller != "" {
+		s.Callers[f.Caller]++
+	}
+	if f.Scope != "" {
+		s.Scopes[f.Scope]++
+	}
+}
+
+// Merge adds the counts of o to s.
+func (s *Summary) Merge(o *Summary) {
+	s.init()
+	s.Reports += o.Reports
+	s.Failures += o.Failures
+	for k, n := range o.Checks {
+		s.Checks[k] += n
+	}
+	for k, n := range o.Codes {
+		s.Codes[k] += n
+	}
+	for k, n := range o.Callers {
+		s.Callers[k] += n
+	}
+	for k, n := range o.Scopes {
+		s.Scopes[k] += n
+	}
+	s.addCategories(o.Categories)
+}
+
+// addCategories adds counts to the category counts of s.
+func (s *Summary) addCategories(counts map[Category]CategoryCount) {
+	s.init()
+	for cat, n := range counts {
+		sn := s.Categories[cat]
+		sn.Ran += n.Ran
+		sn.Failed += n.Failed
+		s.Categories[cat] = sn
+	}
+}
+
+// ReadReport returns a Summary of the report, as written by the NDJSON
+// Reporter, read from r.  The category counts exported to the report by
+// CategoryStats are summed.  Blank lines are ignored.
+func ReadReport(r io.Reader) (*Summary, error) {
+	s := NewSummary()
+	s.Reports = 1
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<24)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var record struct {
+			Failure
+			Categories map[Category]CategoryCount `json:"categories"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+		if record.Categories != nil {
+			s.addCategories(record.Categories)
+			continue
+		}
+		s.Add(record.Failure)
+	}
+	return s, scanner.Err()
+}
+
+// MergeReports returns a Summary of the reports, as written by the NDJSON
+// Reporter, in the files named by paths, such as the reports of each shard of
+// a test run.
+func MergeReports(paths ...string) (*Summary, error) {
+	s := NewSummary()
+	for _, path := range paths {
+		fd, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		rs, err := ReadReport(fd)
+		fd.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		s.Merge(rs)
+	}
+	return s, nil
+}
+
+// String returns s as a human readable table.
+func (s *Summary) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d failures in %d reports", s.Failures, s.Reports)
+	section := func(name string, counts map[string]int) {
+		if len(counts) == 0 {
+			return
+		}
+		keys := make([]string, 0, len(counts))
+		for k := range counts {
+			keys = append(keys, k)
+		}
+		// Most failures first.
+		sort.Slice(keys, func(i, j int) bool {
+			if counts[keys[i]] != counts[keys[j]] {
+				return counts[keys[i]] > counts[keys[j]]
+			}
+			return keys[i] < keys[j]
+		})
+		fmt.Fprintf(&b, "\n%s:", name)
+		for _, k := range keys {
+			fmt.Fprintf(&b, "\n\t%d\t%s", counts[k], k)
+		}
+	}
+	codes := map[string]int{}
+	for k, n := range s.Codes {
+		codes[string(k)] = n
+	}
+	section("checks", s.Checks)
+	section("codes", codes)
+	section("scopes", s.Scopes)
+	section("callers", s.Callers)
+	if len(s.Categories) > 0 {
+		b.WriteString("\n")
+		b.WriteString(formatCategories(s.Categories))
+	}
+	return b.String()
+}
@@ -0,0 +1,93 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMergeReports(t *testing.T) {
+	setDefaults(t)
+	dir := t.TempDir()
+	shard1 := filepath.Join(dir, "shard1.ndjson")
+	shard2 := filepath.Join(dir, "shard2.ndjson")
+
+	ck := NewChecker(Report(NDJSON(shard1)))
+	ck.Error(io.EOF, "x")
+	ck.Error(io.EOF, "y")
+	ck.Child("db").NoError(io.EOF)
+	ck = NewChecker(Report(NDJSON(shard2)))
+	ck.Is(io.EOF, io.ErrUnexpectedEOF)
+
+	s, err := MergeReports(shard1, shard2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Reports != 2 || s.Failures != 4 {
+		t.Errorf("got %d failures in %d reports, want 4 in 2", s.Failures, s.Reports)
+	}
+	if s.Checks["Error"] != 2 || s.Checks["NoError"] != 1 || s.Checks["Is"] != 1 {
+		t.Errorf("got checks %v", s.Checks)
+	}
+	if s.Codes[CodeWrong] != 3 || s.Codes[CodeUnexpected] != 1 {
+		t.Errorf("got codes %v", s.Codes)
+	}
+	if s.Scopes["db"] != 1 || len(s.Callers) != 4 {
+		t.Errorf("got scopes %v and callers %v", s.Scopes, s.Callers)
+	}
+	str := s.String()
+	if !strings.HasPrefix(str, "4 failures in 2 reports\nchecks:\n\t2\tError\n\t1\tIs\n\t1\tNoError\ncodes:\n\t3\tCHK-WRONG\n\t1\tCHK-UNEXPECTED\nscopes:\n\t1\tdb\ncallers:\n\t1\t") {
+		t.Errorf("got String:\n%s", str)
+	}
+
+	bad := filepath.Join(dir, "bad.ndjson")
+	if err := ioutil.WriteFile(bad, []byte("\n{}\nnot json\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := MergeReports(shard1, bad); err == nil || !strings.HasPrefix(err.Error(), bad+": line 3: ") {
+		t.Errorf("got error %v", err)
+	}
+	if _, err := MergeReports(filepath.Join(dir, "missing")); err == nil {
+		t.Errorf("missing report did not fail")
+	}
+}
+
+func TestZeroSummary(t *testing.T) {
+	var s Summary
+	s.Add(Failure{Check: "Error", Code: CodeWrong, Scope: "db"})
+	if s.Failures != 1 || s.Checks["Error"] != 1 || s.Codes[CodeWrong] != 1 || s.Scopes["db"] != 1 {
+		t.Errorf("Add: got %+v", s)
+	}
+
+	var decoded Summary
+	if err := json.Unmarshal([]byte(`{"reports":1,"failures":0,"checks":{},"codes":{},"callers":{}}`), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	decoded.Add(Failure{Check: "Is", Scope: "api"})
+	decoded.Merge(&Summary{Reports: 1, Categories: map[Category]CategoryCount{"timeout": {Ran: 2, Failed: 1}}})
+	if decoded.Reports != 2 || decoded.Scopes["api"] != 1 || decoded.Categories["timeout"].Failed != 1 {
+		t.Errorf("decoded: got %+v", decoded)
+	}
+	var merged Summary
+	merged.Merge(&s)
+	if merged.Failures != 1 || merged.Checks["Error"] != 1 {
+		t.Errorf("Merge: got %+v", merged)
+	}
+}
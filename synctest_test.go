@@ -0,0 +1,82 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.25
+// +build go1.25
+
+package check
+
+import (
+	"context"
+	"io"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+// slowErr is an error whose Error method takes an hour.
+type slowErr struct{}
+
+func (slowErr) Error() string {
+	time.Sleep(time.Hour)
+	return "slow"
+}
+
+func TestSynctest(t *testing.T) {
+	setDefaults(t)
+	start := time.Now()
+	synctest.Test(t, func(t *testing.T) {
+		if s := PropagatesWithin(context.Background(), func(ctx context.Context) error {
+			<-ctx.Done()
+			time.Sleep(time.Hour - time.Second)
+			return ctx.Err()
+		}, time.Hour); s != "" {
+			t.Errorf("Propagates: %s", s)
+		}
+		if s := Expires(context.Background(), func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}, time.Hour, 0); s != "" {
+			t.Errorf("Expires: %s", s)
+		}
+		if s := NewChecker(Deadline(time.Minute)).Error(slowErr{}, "slow"); s != Result(timedOut) {
+			t.Errorf("Deadline: got %q", s)
+		}
+		errc := make(chan error)
+		go func() {
+			time.Sleep(time.Hour)
+			errc <- context.Canceled
+		}()
+		if s := Silent(errc, time.Hour-time.Second); s != "" {
+			t.Errorf("Silent: %s", s)
+		}
+		if s := ErrorFrom(errc, time.Second, context.Canceled); s != "" {
+			t.Errorf("ErrorFrom: %s", s)
+		}
+		start := time.Now()
+		if s := Eventually(func() error {
+			if time.Since(start) < time.Hour {
+				return io.EOF
+			}
+			return nil
+		}, nil, 2*time.Hour, time.Minute); s != "" {
+			t.Errorf("Eventually: %s", s)
+		}
+		// Let the abandoned check finish so the bubble can complete.
+		time.Sleep(time.Hour)
+	})
+	if d := time.Since(start); d > time.Minute {
+		t.Errorf("checks took %v of real time", d)
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"context"
+	"errors"
+	"os"
+	"syscall"
+)
+
+type syscallError struct {
+	name  string
+	errno syscall.Errno
+}
+
+// Syscall returns a Matcher that matches an error wrapping an
+// *os.SyscallError for the system call name, such as "connect" or "read",
+// whose error is errno, as determined by errors.Is.  An empty name matches
+// any system call and an errno of 0 matches any error.  The messages of these
+// errors vary by platform but their fields do not:
+//
+//	check.Error(err, check.Syscall("connect", syscall.ECONNREFUSED))
+func Syscall(name string, errno syscall.Errno) Matcher {
+	return syscallError{name: name, errno: errno}
+}
+
+const (
+	wrongSyscall = "got error %q, want system call %q (got %q)"
+	wrongErrno   = "got error %q, want errno %q (got %q)"
+)
+
+const syscallErrorType = "*os.SyscallError"
+
+func (m syscallError) match(c *config, got error) string {
+	if got == nil {
+		return c.failf(expectedType, syscallErrorType)
+	}
+	var se *os.SyscallError
+	if !errors.As(got, &se) {
+		return c.failf(wrongType, got, syscallErrorType)
+	}
+	if m.name != "" && se.Syscall != m.name {
+		return c.failf(wrongSyscall, got, m.name, se.Syscall)
+	}
+	if m.errno != 0 && !errors.Is(se.Err, m.errno) {
+		return c.failf(wrongErrno, got, m.errno, se.Err)
+	}
+	return ""
+}
+
+type errnoError syscall.Errno
+
+// Errno returns a Matcher that matches an error that is errno, as determined
+// by errors.Is, such as an *fs.PathError or *os.SyscallError wrapping errno:
+//
+//	check.Error(err, check.Errno(syscall.ENOENT))
+func Errno(errno syscall.Errno) Matcher {
+	return errnoError(errno)
+}
+
+const wrongErrnoOnly = "got error %q, want errno %q"
+
+func (m errnoError) match(c *config, got error) string {
+	switch {
+	case got == nil:
+		return c.failf(expected, syscall.Errno(m))
+	case !errors.Is(got, syscall.Errno(m)):
+		var errno syscall.Errno
+		if errors.As(got, &errno) {
+			return c.failf(wrongErrno, got, syscall.Errno(m), errno)
+		}
+		return c.failf(wrongErrnoOnly, got, syscall.Errno(m))
+	}
+	return ""
+}
+
+// A classification is the kind of error checked for by IsNotExist, IsExist,
+// IsPermission, or IsTimeout.
+type classification struct {
+	name string           // the name of the check, e.g., "IsExist"
+	what string           // e.g., "a permission error"
+	is   func(error) bool // reports whether an error is of the kind
+}
+
+var (
+	notExistError = classification{"IsNotExist", "a not-exist error (os.ErrNotExist)", func(err error) bool {
+		return errors.Is(err, os.ErrNotExist)
+	}}
+	existError = classification{"IsExist", "an already-exists error (os.ErrExist)", func(err error) bool {
+		return errors.Is(err, os.ErrExist)
+	}}
+	permissionError = classification{"IsPermission", "a permission error (os.ErrPermission)", func(err error) bool {
+		return errors.Is(err, os.ErrPermission)
+	}}
+	timeoutError = classification{"IsTimeout", "a timeout error", func(err error) bool {
+		if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, os.ErrDeadlineExceeded) {
+			return true
+		}
+		return !walk(err, func(err error) bool { return !os.IsTimeout(err) })
+	}}
+)
+
+// IsNotExist returns the empty string if got is os.ErrNotExist, as determined
+// by errors.Is, otherwise it returns a string indicating the error.
+func IsNotExist(got error) string {
+	return defaults().classified(got, notExistError)
+}
+
+// IsNotExist is the same as the IsNotExist function but uses the options of ck.
+func (ck *Checker) IsNotExist(got error) Result {
+	ck.count()
+	return Result(ck.c.classified(got, notExistError))
+}
+
+// IsExist returns the empty string if got is os.ErrExist, as determined by
+// errors.Is, otherwise it returns a string indicating the error.
+func IsExist(got error) string {
+	return defaults().classified(got, existError)
+}
+
+// IsExist is the same as the IsExist function but uses the options of ck.
+func (ck *Checker) IsExist(got error) Result {
+	ck.count()
+	return Result(ck.c.classified(got, existError))
+}
+
+// IsPermission returns the empty string if got is os.ErrPermission, as
+// determined by errors.Is, otherwise it returns a string indicating the error.
+func IsPermission(got error) string {
+	return defaults().classified(got, permissionError)
+}
+
+// IsPermission is the same as the IsPermission function but uses the options of
+// ck.
+func (ck *Checker) IsPermission(got error) Result {
+	ck.count()
+	return Result(ck.c.classified(got, permissionError))
+}
+
+// IsTimeout returns the empty string if got is a timeout, otherwise it returns
+// a string indicating the error.  An error is a timeout if it, or an error it
+// wraps, has a Timeout method that returns true, as checked by os.IsTimeout,
+// or is context.DeadlineExceeded or os.ErrDeadlineExceeded.
+func IsTimeout(got error) string {
+	return defaults().classified(got, timeoutError)
+}
+
+// IsTimeout is the same as the IsTimeout function but uses the options of ck.
+func (ck *Checker) IsTimeout(got error) Result {
+	ck.count()
+	return Result(ck.c.classified(got, timeoutError))
+}
+
+// classified returns the empty string if got is of the kind k, otherwise a
+// failure saying got is not k.what, such as "a permission error".
+func (c *config) classified(got error, k classification) string {
+	if c.outer() {
+		return c.run(k.name, got, func() string { return k.what },
+			func(c *config) string { return c.classified(got, k) })
+	}
+	switch {
+	case got == nil:
+		return c.failc(CodeMissing, "did not get expected error, want %q", description(k.what))
+	case !k.is(got):
+		return c.failc(CodeWrong, "got error %q, want %q", got, description(k.what))
+	}
+	return ""
+}
@@ -0,0 +1,111 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+)
+
+func TestSyscall(t *testing.T) {
+	setDefaults(t)
+	refused := &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}
+	msg := refused.Error()
+	for _, tt := range []struct {
+		name  string
+		errno syscall.Errno
+		got   error
+		out   string
+	}{
+		{"connect", syscall.ECONNREFUSED, refused, ""},
+		{"", syscall.ECONNREFUSED, refused, ""},
+		{"connect", 0, refused, ""},
+		{"", 0, refused, ""},
+		{"read", syscall.ECONNREFUSED, refused, sprintf(wrongSyscall, msg, "read", "connect")},
+		{"connect", syscall.ETIMEDOUT, refused, sprintf(wrongErrno, msg, syscall.ETIMEDOUT, syscall.ECONNREFUSED)},
+		{"connect", 0, io.EOF, sprintf(wrongType, "EOF", syscallErrorType)},
+		{"connect", 0, nil, sprintf(expectedType, syscallErrorType)},
+	} {
+		if s := Error(tt.got, Syscall(tt.name, tt.errno)); s != tt.out {
+			t.Errorf("Syscall(%q, %v) %v: got %q, want %q", tt.name, tt.errno, tt.got, s, tt.out)
+		}
+	}
+	for _, tt := range []struct {
+		m    Matcher
+		want string
+	}{
+		{Syscall("", 0), "an *os.SyscallError"},
+		{Syscall("read", syscall.EPIPE), `an *os.SyscallError from "read" with errno "broken pipe"`},
+	} {
+		if d := Describe(tt.m); d != tt.want {
+			t.Errorf("got description %q, want %q", d, tt.want)
+		}
+	}
+}
+
+func TestErrno(t *testing.T) {
+	setDefaults(t)
+	perr := &os.PathError{Op: "open", Path: "x", Err: syscall.ENOENT}
+	for _, tt := range []struct {
+		got  error
+		want syscall.Errno
+		out  string
+	}{
+		{perr, syscall.ENOENT, ""},
+		{fmt.Errorf("load: %w", perr), syscall.ENOENT, ""},
+		{perr, syscall.EACCES, sprintf(wrongErrno, perr, syscall.EACCES, syscall.ENOENT)},
+		{io.EOF, syscall.EACCES, sprintf(wrongErrnoOnly, io.EOF, syscall.EACCES)},
+		{nil, syscall.EACCES, sprintf(expected, syscall.EACCES)},
+	} {
+		if s := Error(tt.got, Errno(tt.want)); s != tt.out {
+			t.Errorf("Errno(%v) %v: got %q, want %q", tt.want, tt.got, s, tt.out)
+		}
+	}
+	if d := Describe(Errno(syscall.EACCES)); d != sprintf("errno %q", syscall.EACCES.Error()) {
+		t.Errorf("got description %q", d)
+	}
+}
+
+func TestClassified(t *testing.T) {
+	notExist := &os.PathError{Op: "open", Path: "x", Err: os.ErrNotExist}
+	for _, tt := range []struct {
+		name  string
+		check func(error) string
+		got   error
+		out   string
+	}{
+		{"not exist", IsNotExist, notExist, ""},
+		{"not exist errno", IsNotExist, &os.PathError{Op: "open", Path: "x", Err: syscall.ENOENT}, ""},
+		{"not exist wrong", IsNotExist, io.EOF, `got error "EOF", want a not-exist error (os.ErrNotExist)`},
+		{"not exist nil", IsNotExist, nil, "did not get expected error, want a not-exist error (os.ErrNotExist)"},
+		{"exist", IsExist, fmt.Errorf("mkdir: %w", os.ErrExist), ""},
+		{"exist wrong", IsExist, notExist, `got error "open x: file does not exist", want an already-exists error (os.ErrExist)`},
+		{"permission", IsPermission, os.ErrPermission, ""},
+		{"permission wrong", IsPermission, io.EOF, `got error "EOF", want a permission error (os.ErrPermission)`},
+		{"timeout", IsTimeout, fmt.Errorf("read: %w", timeoutErr{}), ""},
+		{"timeout deadline", IsTimeout, fmt.Errorf("get: %w", context.DeadlineExceeded), ""},
+		{"timeout os", IsTimeout, os.ErrDeadlineExceeded, ""},
+		{"timeout wrong", IsTimeout, io.EOF, `got error "EOF", want a timeout error`},
+	} {
+		if s := tt.check(tt.got); s != tt.out {
+			t.Errorf("%s: got %q, want %q", tt.name, s, tt.out)
+		}
+	}
+}
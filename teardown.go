@@ -0,0 +1,59 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"io"
+	"testing"
+)
+
+// CleanupError registers f to be called when t and its subtests complete, as
+// by t.Cleanup, and its error to be checked against want, as by Error.  A
+// failure is reported with t.Error, prefixed with "cleanup: ".  Errors
+// returned by teardown are otherwise commonly ignored:
+//
+//	check.CleanupError(t, func() error { return os.RemoveAll(dir) }, nil)
+//
+// Checks may be made during cleanup, or in deferred functions, like any
+// other; CleanupError only removes the boilerplate.
+func CleanupError(t testing.TB, f func() error, want interface{}) {
+	t.Helper()
+	t.Cleanup(func() {
+		t.Helper()
+		if s := Error(f(), want); s != "" {
+			t.Error("cleanup: " + s)
+		}
+	})
+}
+
+// Close registers c to be closed when t and its subtests complete, as by
+// t.Cleanup, and the error returned by Close to be checked against want, as
+// by Error.  A failure is reported with t.Error, prefixed with the type of c,
+// e.g., "close *os.File: ".  Close replaces
+//
+//	defer f.Close()
+//
+// which silently discards the error, with
+//
+//	check.Close(t, f, nil)
+func Close(t testing.TB, c io.Closer, want interface{}) {
+	t.Helper()
+	t.Cleanup(func() {
+		t.Helper()
+		if s := Error(c.Close(), want); s != "" {
+			t.Error(sprintf("close %T: %s", c, s))
+		}
+	})
+}
@@ -0,0 +1,117 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestCleanupError(t *testing.T) {
+	setDefaults(t)
+	for _, tt := range []struct {
+		err    error
+		want   interface{}
+		errors []string
+	}{
+		{nil, nil, nil},
+		{io.EOF, io.EOF, nil},
+		{io.EOF, nil, []string{"cleanup: " + sprintf(unexpected, "EOF")}},
+		{nil, "closed", []string{"cleanup: " + sprintf(expected, "closed")}},
+	} {
+		var cleanups []func()
+		ft := &fakeTB{cleanup: func(f func()) { cleanups = append(cleanups, f) }}
+		called := false
+		CleanupError(ft, func() error { called = true; return tt.err }, tt.want)
+		if called {
+			t.Fatalf("f called before cleanup")
+		}
+		for _, f := range cleanups {
+			f()
+		}
+		if !called {
+			t.Errorf("f not called during cleanup")
+		}
+		if fmt.Sprint(ft.errors) != fmt.Sprint(tt.errors) {
+			t.Errorf("%v %v: got errors %q, want %q", tt.err, tt.want, ft.errors, tt.errors)
+		}
+	}
+}
+
+// TestChecksDuringCleanup makes checks, which must pass, from both a Cleanup
+// function and a deferred function.
+func TestChecksDuringCleanup(t *testing.T) {
+	ck := NewChecker()
+	ck.Expect(t, 3)
+	t.Cleanup(func() {
+		if s := ck.Error(io.EOF, io.EOF); s != "" {
+			t.Errorf("in cleanup: %s", s)
+		}
+	})
+	CleanupError(t, func() error { return nil }, nil)
+	t.Run("sub", func(t *testing.T) {
+		defer func() {
+			if s := ck.NoError(nil); s != "" {
+				t.Errorf("in defer: %s", s)
+			}
+		}()
+		t.Cleanup(func() {
+			if s := ck.Is(fmt.Errorf("x: %w", io.EOF), io.EOF); s != "" {
+				t.Errorf("in subtest cleanup: %s", s)
+			}
+		})
+	})
+}
+
+// closer is an io.Closer that returns err.
+type closer struct {
+	err    error
+	closed bool
+}
+
+func (c *closer) Close() error {
+	c.closed = true
+	return c.err
+}
+
+func TestClose(t *testing.T) {
+	setDefaults(t)
+	for _, tt := range []struct {
+		err    error
+		want   interface{}
+		errors []string
+	}{
+		{nil, nil, nil},
+		{io.ErrClosedPipe, io.ErrClosedPipe, nil},
+		{io.ErrShortWrite, nil, []string{"close *check.closer: " + sprintf(unexpected, "short write")}},
+		{nil, true, []string{"close *check.closer: " + sprintf(missing)}},
+	} {
+		var cleanup func()
+		ft := &fakeTB{cleanup: func(f func()) { cleanup = f }}
+		c := &closer{err: tt.err}
+		Close(ft, c, tt.want)
+		if c.closed {
+			t.Fatalf("closed before cleanup")
+		}
+		cleanup()
+		if !c.closed {
+			t.Errorf("not closed during cleanup")
+		}
+		if fmt.Sprint(ft.errors) != fmt.Sprint(tt.errors) {
+			t.Errorf("%v %v: got errors %q, want %q", tt.err, tt.want, ft.errors, tt.errors)
+		}
+	}
+}
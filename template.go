@@ -0,0 +1,64 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+type templateExec struct {
+	name, mention string
+}
+
+// TemplateExec returns a Matcher that matches an error wrapping a
+// template.ExecError, as returned by executing a text/template or
+// html/template, from the template name that mentions mention, such as a
+// node ("<.User.Name>") or a field ("Name").  An empty name matches any
+// template.  The mention is looked for in the message with its
+// "template: name:line:col: " prefix removed, as the position reported has
+// changed between versions of Go.
+func TemplateExec(name, mention string) Matcher {
+	return templateExec{name: name, mention: mention}
+}
+
+const (
+	templateExecType = "template.ExecError"
+	wrongTemplate    = "got error %q, want template %q (got %q)"
+	missingMention   = "got error %q, want a mention of %q"
+)
+
+// templatePrefix matches the "template: name:line:col: " prefix of template
+// errors.  The line and column are optional.
+var templatePrefix = regexp.MustCompile(`^template: [^:]*(:\d+)*: `)
+
+func (m templateExec) match(c *config, got error) string {
+	if got == nil {
+		return c.failf(expectedType, templateExecType)
+	}
+	var ee template.ExecError
+	if !errors.As(got, &ee) {
+		return c.failf(wrongType, got, templateExecType)
+	}
+	if m.name != "" && ee.Name != m.name {
+		return c.failf(wrongTemplate, got, m.name, ee.Name)
+	}
+	if !strings.Contains(templatePrefix.ReplaceAllString(message(ee.Err), ""), m.mention) {
+		return c.failf(missingMention, got, m.mention)
+	}
+	return ""
+}
@@ -0,0 +1,68 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"io/ioutil"
+	"testing"
+	"text/template"
+)
+
+func TestTemplateExec(t *testing.T) {
+	setDefaults(t)
+	data := struct{ User struct{ Name string } }{}
+	got := template.Must(template.New("greet").Parse("Hello\n{{.User.Email}}")).Execute(ioutil.Discard, data)
+	htmlGot := htmltemplate.Must(htmltemplate.New("page").Parse("<p>{{.Missing}}</p>")).Execute(ioutil.Discard, data)
+	for _, tt := range []struct {
+		name, mention string
+		got           error
+		out           string
+	}{
+		{"greet", "<.User.Email>", got, ""},
+		{"greet", "Email", got, ""},
+		{"", "Email", fmt.Errorf("render: %w", got), ""},
+		{"page", "Missing", htmlGot, ""},
+		{"greet", "2:", got, sprintf(missingMention, got, "2:")},
+		{"other", "Email", got, sprintf(wrongTemplate, got, "other", "greet")},
+		{"greet", "Phone", got, sprintf(missingMention, got, "Phone")},
+		{"greet", "", io.EOF, sprintf(wrongType, "EOF", templateExecType)},
+		{"greet", "", nil, sprintf(expectedType, templateExecType)},
+	} {
+		if s := Error(tt.got, TemplateExec(tt.name, tt.mention)); s != tt.out {
+			t.Errorf("TemplateExec(%q, %q) %v: got %q, want %q", tt.name, tt.mention, tt.got, s, tt.out)
+		}
+	}
+	if d := Describe(TemplateExec("greet", "Email")); d != `a template.ExecError from template "greet" mentioning "Email"` {
+		t.Errorf("got description %q", d)
+	}
+}
+
+func TestTemplatePrefix(t *testing.T) {
+	for _, tt := range []struct {
+		in, out string
+	}{
+		{`template: greet:2:2: executing "greet" at <.User.Email>: x`, `executing "greet" at <.User.Email>: x`},
+		{`template: greet:2: x`, `x`},
+		{`template: greet: x`, `x`},
+		{`other: x`, `other: x`},
+	} {
+		if got := templatePrefix.ReplaceAllString(tt.in, ""); got != tt.out {
+			t.Errorf("%q: got %q, want %q", tt.in, got, tt.out)
+		}
+	}
+}
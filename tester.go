@@ -0,0 +1,124 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import "testing"
+
+// A Tester makes checks with a Checker and reports their failures to a test.
+// Each check reports whether it passed.  A failure is reported with t.Error,
+// or, by the Must methods, with t.Fatal:
+//
+//	c := check.New(t)
+//	c.Error(err, "not found")
+//	c.MustNoError(f.Close())
+//
+// A Tester is safe for concurrent use, but the Must methods must only be
+// called from the goroutine running the test.
+type Tester struct {
+	t  testing.TB
+	ck *Checker
+}
+
+// New returns a Tester that reports to t the failures of checks made with
+// the current defaults, as set by SetDefaults, with opts applied.  Failures
+// passed to Reporters are of the test t.
+func New(t testing.TB, opts ...Option) *Tester {
+	return &Tester{t: t, ck: NewChecker(append([]Option{inTest(t)}, opts...)...)}
+}
+
+// With returns a Tester that reports to the same test as tc using the
+// options of tc with opts applied.  tc is not changed.
+func (tc *Tester) With(opts ...Option) *Tester {
+	return &Tester{t: tc.t, ck: tc.ck.With(opts...)}
+}
+
+// Checker returns the Checker used by tc.
+func (tc *Tester) Checker() *Checker { return tc.ck }
+
+// report reports the failure r, if any, with t.Error, or t.Fatal if fatal is
+// set, and reports whether r passed.
+func (tc *Tester) report(r Result, fatal bool) bool {
+	tc.t.Helper()
+	switch {
+	case !r.Failed():
+		return true
+	case fatal:
+		tc.t.Fatal(r.String())
+	default:
+		tc.t.Error(r.String())
+	}
+	return false
+}
+
+// Error checks got against want, as by Error.
+func (tc *Tester) Error(got error, want interface{}) bool {
+	tc.t.Helper()
+	return tc.report(tc.ck.Error(got, want), false)
+}
+
+// MustError is like Error but stops the test on failure.
+func (tc *Tester) MustError(got error, want interface{}) {
+	tc.t.Helper()
+	tc.report(tc.ck.Error(got, want), true)
+}
+
+// Is checks that got is want, as by Is.
+func (tc *Tester) Is(got, want error) bool {
+	tc.t.Helper()
+	return tc.report(tc.ck.Is(got, want), false)
+}
+
+// IsError is the same as Is.
+func (tc *Tester) IsError(got, want error) bool {
+	tc.t.Helper()
+	return tc.report(tc.ck.Is(got, want), false)
+}
+
+// MustIs is like Is but stops the test on failure.
+func (tc *Tester) MustIs(got, want error) {
+	tc.t.Helper()
+	tc.report(tc.ck.Is(got, want), true)
+}
+
+// NoError checks that got is nil, as by NoError.
+func (tc *Tester) NoError(got error) bool {
+	tc.t.Helper()
+	return tc.report(tc.ck.NoError(got), false)
+}
+
+// MustNoError is like NoError but stops the test on failure.
+func (tc *Tester) MustNoError(got error) {
+	tc.t.Helper()
+	tc.report(tc.ck.NoError(got), true)
+}
+
+// HasError checks got against each of classifiers, as by HasError.
+func (tc *Tester) HasError(got error, classifiers ...interface{}) bool {
+	tc.t.Helper()
+	return tc.report(tc.ck.HasError(got, classifiers...), false)
+}
+
+// MustHasError is like HasError but stops the test on failure.
+func (tc *Tester) MustHasError(got error, classifiers ...interface{}) {
+	tc.t.Helper()
+	tc.report(tc.ck.HasError(got, classifiers...), true)
+}
+
+// MessagesEqual checks that got and want have the same message, as by
+// MessagesEqual.
+func (tc *Tester) MessagesEqual(got, want error) bool {
+	tc.t.Helper()
+	return tc.report(tc.ck.MessagesEqual(got, want), false)
+}
@@ -0,0 +1,68 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"fmt"
+	"io"
+	"testing"
+)
+
+// fatalTB is a fakeTB that also records calls to Fatal.
+type fatalTB struct {
+	fakeTB
+	fatals []string
+}
+
+func (t *fatalTB) Fatal(args ...interface{}) { t.fatals = append(t.fatals, fmt.Sprint(args...)) }
+
+func TestTester(t *testing.T) {
+	setDefaults(t)
+	var ft fatalTB
+	c := New(&ft)
+	if !c.Error(io.EOF, "EOF") || !c.Is(io.EOF, io.EOF) || !c.IsError(nil, nil) ||
+		!c.NoError(nil) || !c.HasError(io.EOF, "E", "OF") || !c.MessagesEqual(io.EOF, io.EOF) {
+		t.Errorf("passing check failed")
+	}
+	if len(ft.errors) != 0 || len(ft.fatals) != 0 {
+		t.Fatalf("passing checks reported errors %q and fatals %q", ft.errors, ft.fatals)
+	}
+	if c.Error(io.EOF, "x") {
+		t.Errorf("failing check passed")
+	}
+	c.With(Codes()).Is(nil, io.EOF)
+	c.MustError(io.EOF, "x")
+	c.MustNoError(io.EOF)
+	c.MustIs(nil, nil)
+	c.MustHasError(nil)
+	want := []string{
+		sprintf(wrong, "EOF", "x"),
+		"CHK-MISSING: " + sprintf(expected, "EOF"),
+	}
+	if fmt.Sprint(ft.errors) != fmt.Sprint(want) {
+		t.Errorf("got errors %q, want %q", ft.errors, want)
+	}
+	wantFatals := []string{
+		sprintf(wrong, "EOF", "x"),
+		NoError(io.EOF),
+		missing,
+	}
+	if fmt.Sprint(ft.fatals) != fmt.Sprint(wantFatals) {
+		t.Errorf("got fatals %q, want %q", ft.fatals, wantFatals)
+	}
+	if c.Checker() == nil {
+		t.Errorf("Checker returned nil")
+	}
+}
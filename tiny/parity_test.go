@@ -0,0 +1,66 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !tinygo
+// +build !tinygo
+
+package tiny
+
+import (
+	"go/build"
+	"testing"
+
+	"github.com/pborman/check"
+)
+
+// checkWant returns want converted to the equivalent want of package check.
+func checkWant(want interface{}) interface{} {
+	switch want := want.(type) {
+	case Case:
+		return check.Case(want)
+	case Equal:
+		return check.Equal(want)
+	case CaseEqual:
+		return check.CaseEqual(want)
+	}
+	return want
+}
+
+func TestParity(t *testing.T) {
+	for _, got := range errs {
+		for _, want := range wants {
+			if s, cs := Error(got, want), check.Error(got, checkWant(want)); s != cs {
+				t.Errorf("Error(%v, %#v): got %q, check got %q", got, want, s, cs)
+			}
+			if werr, ok := want.(error); ok || want == nil {
+				if s, cs := Is(got, werr), check.Is(got, werr); s != cs {
+					t.Errorf("Is(%v, %v): got %q, check got %q", got, werr, s, cs)
+				}
+			}
+		}
+	}
+}
+
+func TestImports(t *testing.T) {
+	pkg, err := build.ImportDir(".", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, imp := range pkg.Imports {
+		switch imp {
+		case "fmt", "reflect":
+			t.Errorf("package tiny imports %s", imp)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tiny is a minimal version of package check for constrained
+// environments, such as TinyGo and WASM test harnesses on embedded targets.
+// It provides the core checks of package check, with the same failure
+// messages, but does not depend on fmt or reflect, keeping binaries small.
+//
+// Options, Matchers, and the other extensions of package check are not
+// supported.  An unsupported want fails as its type cannot be named without
+// reflect.
+package tiny
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// Type Equal is a string that an error must match exactly.
+type Equal string
+
+// Type Case is a string that must be contained in the error case insensitive.
+type Case string
+
+// Type CaseEqual is a string that error must case insensitive match exactly.
+type CaseEqual string
+
+func unexpected(got error) string {
+	return "got unexpected error " + strconv.Quote(got.Error())
+}
+
+func expected(want string) string {
+	return "did not get expected error " + strconv.Quote(want)
+}
+
+func wrong(got error, want string) string {
+	return "got error " + strconv.Quote(got.Error()) + ", want " + strconv.Quote(want)
+}
+
+const missing = "did not get expected error"
+
+// Error compares error got to interface want returning an empty string if they
+// match or an error string if they are different.  The type of want determines
+// how the check is made.
+//
+//	error:     got must be exactly want
+//	bool:      check for existance of error
+//	string:    check if got.Error() contains want
+//	Case:      check if got.Error() contains want, case insensitive
+//	Equal:     check if got.Error() is want
+//	CaseEqual: check if got.Error() is want, case insensitive
+func Error(got error, want interface{}) string {
+	var w string
+	var match func(msg string) bool
+	switch want := want.(type) {
+	case nil:
+		if got == nil {
+			return ""
+		}
+		return unexpected(got)
+	case bool:
+		switch want {
+		case (got != nil):
+			return ""
+		case true:
+			return missing
+		default:
+			return unexpected(got)
+		}
+	case error:
+		switch {
+		case got == nil:
+			return expected(want.Error())
+		case want != got:
+			return wrong(got, want.Error())
+		default:
+			return ""
+		}
+	case string:
+		w = want
+		match = func(msg string) bool { return strings.Contains(msg, want) }
+	case Case:
+		w = string(want)
+		match = func(msg string) bool {
+			return strings.Contains(strings.ToLower(msg), strings.ToLower(w))
+		}
+	case Equal:
+		w = string(want)
+		match = func(msg string) bool { return msg == w }
+	case CaseEqual:
+		w = string(want)
+		match = func(msg string) bool { return strings.ToLower(msg) == strings.ToLower(w) }
+	default:
+		return "Check does not support this type"
+	}
+	switch {
+	case got == nil && w == "":
+		return ""
+	case got == nil:
+		return expected(w)
+	case w == "":
+		return unexpected(got)
+	case !match(got.Error()):
+		return wrong(got, w)
+	default:
+		return ""
+	}
+}
+
+// Is returns the empty string if want is or is wrapped in got, as determined
+// by errors.Is, otherwise it returns a string indicating the error.
+func Is(got, want error) string {
+	switch {
+	case got == nil && want == nil:
+		return ""
+	case got == nil:
+		return expected(want.Error())
+	case want == nil:
+		return unexpected(got)
+	case !errors.Is(got, want):
+		return wrong(got, want.Error())
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tiny
+
+import (
+	"errors"
+	"io"
+	"testing"
+)
+
+// wants are the wants checked against each error in errs.
+var wants = []interface{}{
+	nil, true, false, io.EOF, io.ErrUnexpectedEOF,
+	"", "EOF", "eof", "unexpected",
+	Case(""), Case("eof"), Case("other"),
+	Equal(""), Equal("EOF"), Equal("eof"),
+	CaseEqual(""), CaseEqual("eof"), CaseEqual("unexpected"),
+}
+
+var errs = []error{nil, io.EOF, io.ErrUnexpectedEOF, errors.New("wrapped EOF")}
+
+func TestError(t *testing.T) {
+	for _, tt := range []struct {
+		got  error
+		want interface{}
+		out  string
+	}{
+		{got: nil, want: nil},
+		{got: io.EOF, want: true},
+		{got: nil, want: true, out: "did not get expected error"},
+		{got: io.EOF, want: false, out: `got unexpected error "EOF"`},
+		{got: io.EOF, want: io.EOF},
+		{got: io.EOF, want: io.ErrUnexpectedEOF, out: `got error "EOF", want "unexpected EOF"`},
+		{got: nil, want: io.EOF, out: `did not get expected error "EOF"`},
+		{got: io.EOF, want: "EOF"},
+		{got: io.EOF, want: "eof", out: `got error "EOF", want "eof"`},
+		{got: io.EOF, want: Case("eof")},
+		{got: io.EOF, want: Equal("EO"), out: `got error "EOF", want "EO"`},
+		{got: io.EOF, want: CaseEqual("eof")},
+		{got: nil, want: CaseEqual("")},
+		{got: io.EOF, want: Equal(""), out: `got unexpected error "EOF"`},
+		{got: io.EOF, want: 1, out: "Check does not support this type"},
+	} {
+		if s := Error(tt.got, tt.want); s != tt.out {
+			t.Errorf("Error(%v, %v): got %q, want %q", tt.got, tt.want, s, tt.out)
+		}
+	}
+}
+
+func TestIs(t *testing.T) {
+	wrapped := wrapErr{io.EOF}
+	for _, tt := range []struct {
+		got, want error
+		out       string
+	}{
+		{},
+		{got: wrapped, want: io.EOF},
+		{got: nil, want: io.EOF, out: `did not get expected error "EOF"`},
+		{got: io.EOF, want: nil, out: `got unexpected error "EOF"`},
+		{got: io.EOF, want: io.ErrUnexpectedEOF, out: `got error "EOF", want "unexpected EOF"`},
+	} {
+		if s := Is(tt.got, tt.want); s != tt.out {
+			t.Errorf("Is(%v, %v): got %q, want %q", tt.got, tt.want, s, tt.out)
+		}
+	}
+}
+
+type wrapErr struct{ err error }
+
+func (e wrapErr) Error() string { return "wrapped " + e.err.Error() }
+func (e wrapErr) Unwrap() error { return e.err }
@@ -0,0 +1,54 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+// A Transform returns the want to use in place of want, such as want wrapped
+// in a Matcher returned by Scrub or FirstLine.
+type Transform func(want interface{}) interface{}
+
+// transforms is an immutable list of Transforms, most recent first.
+type transforms struct {
+	f    Transform
+	next *transforms
+}
+
+// Transforms returns an Option that applies fs, in order, to the want of each
+// check made by Error or HasError.  This lets a Checker, or the defaults,
+// configure normalization once rather than at each check:
+//
+//	ck := check.NewChecker(check.Transforms(
+//		func(want interface{}) interface{} { return check.FirstLine(want) },
+//		func(want interface{}) interface{} { return check.Scrub(want, check.ScrubHome) },
+//	))
+//
+// Transforms may be used more than once; later Transforms are applied after
+// earlier ones.  The want passed to the Matchers returned by the Transforms
+// is not transformed again.
+func Transforms(fs ...Transform) Option {
+	return func(c *config) {
+		for _, f := range fs {
+			c.transforms = &transforms{f: f, next: c.transforms}
+		}
+	}
+}
+
+// apply returns want with each Transform in t applied, in the order they
+// were added.
+func (t *transforms) apply(want interface{}) interface{} {
+	if t == nil {
+		return want
+	}
+	return t.f(t.next.apply(want))
+}
@@ -0,0 +1,60 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestTransforms(t *testing.T) {
+	setDefaults(t)
+	firstLine := func(want interface{}) interface{} { return FirstLine(want) }
+	var order []string
+	tag := func(name string) Transform {
+		return func(want interface{}) interface{} {
+			order = append(order, name)
+			return want
+		}
+	}
+	ck := NewChecker(Transforms(tag("a"), firstLine), Transforms(tag("b")))
+	got := errors.New("bad input\n\tat main.go:12")
+	if s := ck.Error(got, Equal("bad input")); s != "" {
+		t.Errorf("got %q", s)
+	}
+	if strings.Join(order, ",") != "a,b" {
+		t.Errorf("transforms applied in order %q, want a,b", order)
+	}
+	if s := ck.Error(got, Equal("bad")); s != Result(sprintf(wrong, "bad input", "bad")) {
+		t.Errorf("got %q", s)
+	}
+	if s := ck.HasError(got, "bad", "input"); s != "" {
+		t.Errorf("HasError: got %q", s)
+	}
+	if s := ck.Error(nil, nil); s != "" {
+		t.Errorf("nil: got %q", s)
+	}
+	if s := NewChecker().Error(got, Equal("bad input")); s == "" {
+		t.Errorf("Checker without Transforms passed")
+	}
+	if s := Error(got, Equal("bad input")); s == "" {
+		t.Errorf("Error without Transforms passed")
+	}
+	setDefaults(t, Transforms(firstLine))
+	if s := Error(got, Equal("bad input")); s != "" {
+		t.Errorf("Error with default Transforms: got %q", s)
+	}
+}
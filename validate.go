@@ -0,0 +1,330 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// A validator is a Matcher that can report whether it is well formed.
+type validator interface {
+	validate() error
+}
+
+// Validate returns an error describing each of wants that Error would not
+// accept, or would not be able to match anything with, otherwise it returns
+// nil.  Wants are identified by their index in wants.  Validate is meant to
+// be called when a table is built, so a bad want is reported once, clearly,
+// rather than as a confusing failure of each row using it.
+//
+//	if err := check.Validate(wants...); err != nil {
+//		t.Fatal(err)
+//	}
+//
+// An empty Regexp, as any empty message want, wants no error and is valid.
+// NoEmptyWants rejects empty wants when the checks are made.
+func Validate(wants ...interface{}) error {
+	var msgs []string
+	for i, want := range wants {
+		if err := validate(want); err != nil {
+			msgs = append(msgs, fmt.Sprintf("want %d: %v", i, err))
+		}
+	}
+	if msgs == nil {
+		return nil
+	}
+	return errors.New(strings.Join(msgs, "\n"))
+}
+
+// Strict returns an Option that causes a check made with an invalid want, one
+// rejected by Validate, to panic rather than return a failure.  In a large
+// table an unsupported want otherwise reads like any other failure.
+func Strict() Option {
+	return func(c *config) { c.strict = true }
+}
+
+// NoEmptyWants returns an Option that rejects empty string, Equal, Case,
+// CaseEqual, and Regexp wants, failing the check whatever the error.  An empty
+// want means no error is wanted, which is easily written by mistake, e.g., by
+// a table row missing its message.  With NoEmptyWants, nil or None must be
+// used to want no error.  With Strict, an empty want panics.
+func NoEmptyWants() Option {
+	return func(c *config) { c.noEmpty = true }
+}
+
+// emptyWant returns the failure of the empty want, or the empty string if
+// want is not empty.
+func emptyWant(want interface{}) string {
+	switch w := want.(type) {
+	case string:
+		if w == "" {
+			return "empty string want, use nil or None to want no error"
+		}
+	case Equal, Case, CaseEqual, Regexp:
+		if reflect.ValueOf(w).String() == "" {
+			return sprintf("empty %T want, use nil or None to want no error", w)
+		}
+	}
+	return ""
+}
+
+// validate returns an error if want is not a valid want for Error.
+func validate(want interface{}) error {
+	switch w := want.(type) {
+	case validator:
+		return w.validate()
+	case Regexp:
+		if _, err := compileRegexp(string(w)); err != nil {
+			return fmt.Errorf("Regexp: %v", err)
+		}
+		return nil
+	case func(error) bool:
+		if w == nil {
+			return errors.New("nil func(error) bool")
+		}
+		return nil
+	case func(error) string:
+		if w == nil {
+			return errors.New("nil func(error) string")
+		}
+		return nil
+	case Matcher, CustomMatcher, nil, bool, string, Equal, Case, CaseEqual, error:
+		return nil
+	}
+	if asTarget(want) != nil {
+		return nil
+	}
+	return fmt.Errorf("unsupported type %T", want)
+}
+
+func (s similar) validate() error {
+	if s.threshold < 0 || s.threshold > 1 {
+		return fmt.Errorf("Similar threshold %v not between 0 and 1", s.threshold)
+	}
+	return nil
+}
+
+func (w words) validate() error {
+	if w.tolerance < 0 {
+		return fmt.Errorf("Words tolerance %d is negative", w.tolerance)
+	}
+	return nil
+}
+
+func (m maxLen) validate() error {
+	if m < 0 {
+		return fmt.Errorf("MaxLen %d is negative", m)
+	}
+	return nil
+}
+
+func (s scrub) validate() error {
+	for i, f := range s.scrubbers {
+		if f == nil {
+			return fmt.Errorf("Scrub scrubber %d is nil", i)
+		}
+	}
+	if err := validate(s.want); err != nil {
+		return fmt.Errorf("Scrub: %v", err)
+	}
+	return nil
+}
+
+func (f firstLine) validate() error {
+	if err := validate(f.want); err != nil {
+		return fmt.Errorf("FirstLine: %v", err)
+	}
+	return nil
+}
+
+func (o own) validate() error {
+	if err := validate(o.want); err != nil {
+		return fmt.Errorf("Own: %v", err)
+	}
+	return nil
+}
+
+func (d durations) validate() error {
+	if d.tolerance < 0 {
+		return fmt.Errorf("Durations tolerance %v is negative", d.tolerance)
+	}
+	if err := validate(d.want); err != nil {
+		return fmt.Errorf("Durations: %v", err)
+	}
+	return nil
+}
+
+func (m wantsEqual) validate() error {
+	if m.key == "" || strings.ContainsAny(m.key, " \t\r\n") {
+		return fmt.Errorf("Wants key %q is empty or contains white space", m.key)
+	}
+	return nil
+}
+
+func (m flateCorrupt) validate() error {
+	if m.min < 0 || m.max < m.min {
+		return fmt.Errorf("FlateCorrupt range %d to %d is invalid", m.min, m.max)
+	}
+	return nil
+}
+
+func (m csvParse) validate() error {
+	if m.line < 0 || m.column < 0 {
+		return fmt.Errorf("CSVParse line %d or column %d is negative", m.line, m.column)
+	}
+	return nil
+}
+
+func (m xmlSyntax) validate() error {
+	if m < 0 {
+		return fmt.Errorf("XMLSyntax line %d is negative", int(m))
+	}
+	return nil
+}
+
+func (m all) validate() error {
+	return validateEach("All", m)
+}
+
+func (m anyOf) validate() error {
+	return validateEach("Any", m)
+}
+
+func (m not) validate() error {
+	if err := validate(m.want); err != nil {
+		return fmt.Errorf("Not: %v", err)
+	}
+	return nil
+}
+
+// validateEach returns an error describing the first of wants, the wants of
+// the Matcher returned by name, that is not valid.
+func validateEach(name string, wants []interface{}) error {
+	for i, want := range wants {
+		if err := validate(want); err != nil {
+			return fmt.Errorf("%s want %d: %v", name, i, err)
+		}
+	}
+	return nil
+}
+
+func (b bind) validate() error {
+	switch b.want.(type) {
+	case string, Equal, Case, CaseEqual, Regexp:
+	default:
+		return fmt.Errorf("Bind: unsupported type %T", b.want)
+	}
+	for i, arg := range b.args {
+		if v := reflect.ValueOf(arg); v.Kind() == reflect.Ptr && v.IsNil() {
+			return fmt.Errorf("Bind arg %d is a nil %T", i, arg)
+		}
+	}
+	return validate(b.bound())
+}
+
+func (m byGoVersion) validate() error {
+	want, err := m.resolve()
+	if err != nil {
+		return err
+	}
+	return validate(want)
+}
+
+func (m byOS) validate() error {
+	want, err := m.resolve()
+	if err != nil {
+		return err
+	}
+	return validate(want)
+}
+
+func (m selector) validate() error {
+	want, err := m.resolve()
+	if err != nil {
+		return err
+	}
+	return validate(want)
+}
+
+func (m status) validate() error {
+	if m.code == ^uint32(0) {
+		return errors.New("Status code is not an integer")
+	}
+	if m.msg == nil {
+		return nil
+	}
+	if err := validate(m.msg); err != nil {
+		return fmt.Errorf("Status: %v", err)
+	}
+	return nil
+}
+
+func (m jsonMessage) validate() error {
+	var v interface{}
+	if err := json.Unmarshal([]byte(m.want), &v); err != nil {
+		return fmt.Errorf("JSON: invalid want: %v", err)
+	}
+	return nil
+}
+
+func (m ExitStatus) validate() error {
+	if m < 0 {
+		return fmt.Errorf("ExitStatus %d is negative", int(m))
+	}
+	return nil
+}
+
+func (m errCode) validate() error {
+	if m.code == nil {
+		return errors.New("ErrCode code is nil")
+	}
+	return nil
+}
+
+func (m method) validate() error {
+	if m.name == "" {
+		return errors.New("Method name is empty")
+	}
+	return nil
+}
+
+func (m fields) validate() error {
+	if !structOf(m.want).IsValid() || !reflect.TypeOf(m.want).Implements(errorType) {
+		return fmt.Errorf("Fields: unsupported type %T", m.want)
+	}
+	return nil
+}
+
+func (m ContainsAll) validate() error { return validateFragments("ContainsAll", m) }
+
+func (m ContainsAny) validate() error { return validateFragments("ContainsAny", m) }
+
+// validateFragments returns an error if the fragments of the Matcher name are
+// missing or empty.
+func validateFragments(name string, fragments []string) error {
+	if len(fragments) == 0 {
+		return fmt.Errorf("%s has no fragments", name)
+	}
+	for i, f := range fragments {
+		if f == "" {
+			return fmt.Errorf("%s fragment %d is empty", name, i)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"io"
+	"testing"
+	"time"
+)
+
+func TestValidate(t *testing.T) {
+	var et *errtype
+	for _, tt := range []struct {
+		name  string
+		wants []interface{}
+		err   interface{}
+	}{
+		{
+			name: "none",
+		}, {
+			name: "good",
+			wants: []interface{}{
+				nil, true, "x", Equal("x"), Case("x"), CaseEqual("x"),
+				io.EOF, &et, Similar("x", 0.5), Words("x", 0), MaxLen(1),
+				SingleLine(), Scrub("x", ScrubNetwork), Durations("1s", time.Second),
+			},
+		}, {
+			name:  "unsupported",
+			wants: []interface{}{nil, 1, (*struct{})(nil)},
+			err:   Equal("want 1: unsupported type int\nwant 2: unsupported type *struct {}"),
+		}, {
+			name:  "similar",
+			wants: []interface{}{Similar("x", 1.5)},
+			err:   Equal("want 0: Similar threshold 1.5 not between 0 and 1"),
+		}, {
+			name:  "words",
+			wants: []interface{}{Words("x", -1)},
+			err:   Equal("want 0: Words tolerance -1 is negative"),
+		}, {
+			name:  "maxlen",
+			wants: []interface{}{MaxLen(-1)},
+			err:   Equal("want 0: MaxLen -1 is negative"),
+		}, {
+			name:  "scrub",
+			wants: []interface{}{Scrub("x", nil), Scrub(1.0)},
+			err:   Equal("want 0: Scrub scrubber 0 is nil\nwant 1: Scrub: unsupported type float64"),
+		}, {
+			name:  "durations",
+			wants: []interface{}{Durations("x", -time.Second), Durations(1.0, 0)},
+			err:   Equal("want 0: Durations tolerance -1s is negative\nwant 1: Durations: unsupported type float64"),
+		}, {
+			name:  "regexp",
+			wants: []interface{}{Regexp("^x$"), Regexp("(")},
+			err:   Equal("want 1: Regexp: error parsing regexp: missing closing ): `(`"),
+		}, {
+			name:  "empty regexp",
+			wants: []interface{}{Regexp(""), Regexp(".")},
+		},
+	} {
+		if s := Error(Validate(tt.wants...), tt.err); s != "" {
+			t.Errorf("%s: %s", tt.name, s)
+		}
+	}
+}
+
+func TestStrict(t *testing.T) {
+	setDefaults(t, Strict())
+	if msg := panicked(func() { Error(io.EOF, 1) }); msg != "check: unsupported type int" {
+		t.Errorf("unsupported: got panic %q", msg)
+	}
+	if msg := panicked(func() { Error(io.EOF, MaxLen(-1)) }); msg != "check: MaxLen -1 is negative" {
+		t.Errorf("invalid: got panic %q", msg)
+	}
+	if msg := panicked(func() { Error(io.EOF, io.EOF) }); msg != "" {
+		t.Errorf("valid: got panic %q", msg)
+	}
+	// An empty Regexp wants no error, as it does without Strict.
+	if msg := panicked(func() {
+		if s := Error(nil, Regexp("")); s != "" {
+			t.Errorf("empty Regexp: got %q", s)
+		}
+	}); msg != "" {
+		t.Errorf("empty Regexp: got panic %q", msg)
+	}
+	if err := Validate(Regexp("")); err != nil {
+		t.Errorf("Validate of empty Regexp: %v", err)
+	}
+}
+
+func TestNoEmptyWants(t *testing.T) {
+	setDefaults(t, NoEmptyWants())
+	for _, tt := range []struct {
+		got  error
+		want interface{}
+		out  string
+	}{
+		{nil, nil, ""},
+		{io.EOF, "EOF", ""},
+		{io.EOF, "", "empty string want, use nil or None to want no error"},
+		{nil, "", "empty string want, use nil or None to want no error"},
+		{io.EOF, Case(""), "empty check.Case want, use nil or None to want no error"},
+		{nil, Equal(""), "empty check.Equal want, use nil or None to want no error"},
+		{io.EOF, CaseEqual(""), "empty check.CaseEqual want, use nil or None to want no error"},
+		{io.EOF, Regexp(""), "empty check.Regexp want, use nil or None to want no error"},
+		{io.EOF, Any("", "EOF"), ""},
+		{io.EOF, Not(""), ""},
+	} {
+		if s := Error(tt.got, tt.want); s != tt.out {
+			t.Errorf("Error(%v, %#v): got %q, want %q", tt.got, tt.want, s, tt.out)
+		}
+	}
+	setDefaults(t, NoEmptyWants(), Codes())
+	if s := Error(nil, ""); s != "CHK-UNSUPPORTED: empty string want, use nil or None to want no error" {
+		t.Errorf("got %q", s)
+	}
+	setDefaults(t, NoEmptyWants(), Strict())
+	if msg := panicked(func() { Error(nil, Case("")) }); msg != "check: empty check.Case want, use nil or None to want no error" {
+		t.Errorf("got panic %q", msg)
+	}
+}
@@ -0,0 +1,270 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxDiffs is the maximum number of differences reported by Value.
+const maxDiffs = 20
+
+// Value returns the empty string if got and want are deeply equal, as by
+// reflect.DeepEqual, otherwise it returns a string listing their differences.
+// Each difference is reported with its path, e.g.:
+//
+//	got value differs from want:
+//		.Name: got "bob", want "alice"
+//		.Tags[1]: got "b", want "c"
+//		.Attrs["id"]: missing, want 7
+//
+// Multi-line strings are compared line by line, with removed lines marked
+// with - and added lines with +.  At most 20 differences are reported.
+func Value(got, want interface{}, opts ...Option) string {
+	return defaults().with(opts...).value(got, want)
+}
+
+// Value is the same as the Value function but uses the options of ck.
+func (ck *Checker) Value(got, want interface{}, opts ...Option) Result {
+	ck.count()
+	return Result(ck.c.with(opts...).value(got, want))
+}
+
+// value implements Value using the settings in c.
+func (c *config) value(got, want interface{}) string {
+	if c.outer() {
+		return c.run("Value", nil, nil,
+			func(c *config) string { return c.value(got, want) })
+	}
+	if reflect.DeepEqual(got, want) {
+		return ""
+	}
+	if c.quiet {
+		return quietFailure
+	}
+	d := differ{visited: map[visit]bool{}}
+	d.diff("", reflect.ValueOf(got), reflect.ValueOf(want))
+	if len(d.diffs) == 0 {
+		// The values differ in a way the differ does not look into, such
+		// as non-nil funcs.
+		d.diffs = []string{sprintf("got %s, want %s", formatValue(reflect.ValueOf(got)), formatValue(reflect.ValueOf(want)))}
+	}
+	if len(d.diffs) > maxDiffs {
+		n := len(d.diffs) - maxDiffs
+		d.diffs = append(d.diffs[:maxDiffs], sprintf("... and %d more differences", n))
+	}
+	var b strings.Builder
+	b.WriteString(c.code(CodeWrong))
+	b.WriteString("got value differs from want:")
+	for _, s := range d.diffs {
+		b.WriteString("\n")
+		b.WriteString(indent(s))
+	}
+	return b.String()
+}
+
+// A visit is a pair of pointers already compared by a differ.
+type visit struct {
+	got, want uintptr
+	typ       reflect.Type
+}
+
+// A differ collects the differences between two values.
+type differ struct {
+	diffs   []string
+	visited map[visit]bool
+}
+
+// addf adds a difference at path.
+func (d *differ) addf(path, format string, args ...interface{}) {
+	if path == "" || path[0] == '[' {
+		path = "value" + path
+	}
+	d.diffs = append(d.diffs, path+": "+sprintf(format, args...))
+}
+
+// diff adds the differences between got and want, found at path.
+func (d *differ) diff(path string, got, want reflect.Value) {
+	switch {
+	case !got.IsValid() || !want.IsValid():
+		if got.IsValid() != want.IsValid() {
+			d.addf(path, "got %s, want %s", formatValue(got), formatValue(want))
+		}
+		return
+	case got.Type() != want.Type():
+		d.addf(path, "got type %v, want type %v", got.Type(), want.Type())
+		return
+	}
+	switch got.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if got.IsNil() || want.IsNil() {
+			if got.IsNil() != want.IsNil() {
+				d.addf(path, "got %s, want %s", formatValue(got), formatValue(want))
+			}
+			return
+		}
+		if got.Kind() == reflect.Ptr {
+			v := visit{got.Pointer(), want.Pointer(), got.Type()}
+			if d.visited[v] {
+				return
+			}
+			d.visited[v] = true
+		}
+		d.diff(path, got.Elem(), want.Elem())
+	case reflect.Struct:
+		for i := 0; i < got.NumField(); i++ {
+			d.diff(path+"."+got.Type().Field(i).Name, got.Field(i), want.Field(i))
+		}
+	case reflect.Slice, reflect.Array:
+		if got.Kind() == reflect.Slice && got.IsNil() != want.IsNil() {
+			d.addf(path, "got %s, want %s", formatValue(got), formatValue(want))
+			return
+		}
+		n := got.Len()
+		if want.Len() < n {
+			n = want.Len()
+		}
+		for i := 0; i < n; i++ {
+			d.diff(sprintf("%s[%d]", path, i), got.Index(i), want.Index(i))
+		}
+		for i := n; i < got.Len(); i++ {
+			d.addf(sprintf("%s[%d]", path, i), "got %s, want nothing", formatValue(got.Index(i)))
+		}
+		for i := n; i < want.Len(); i++ {
+			d.addf(sprintf("%s[%d]", path, i), "missing, want %s", formatValue(want.Index(i)))
+		}
+	case reflect.Map:
+		if got.IsNil() != want.IsNil() {
+			d.addf(path, "got %s, want %s", formatValue(got), formatValue(want))
+			return
+		}
+		for _, k := range sortedKeys(got, want) {
+			p := sprintf("%s[%s]", path, formatValue(k))
+			gv, wv := got.MapIndex(k), want.MapIndex(k)
+			switch {
+			case !wv.IsValid():
+				d.addf(p, "got %s, want nothing", formatValue(gv))
+			case !gv.IsValid():
+				d.addf(p, "missing, want %s", formatValue(wv))
+			default:
+				d.diff(p, gv, wv)
+			}
+		}
+	case reflect.String:
+		g, w := got.String(), want.String()
+		if g == w {
+			return
+		}
+		if !strings.Contains(g, "\n") && !strings.Contains(w, "\n") {
+			d.addf(path, "got %s, want %s", strconv.Quote(g), strconv.Quote(w))
+			return
+		}
+		d.addf(path, "lines differ (-got +want):\n%s", indent(strings.Join(lineDiff(strings.Split(g, "\n"), strings.Split(w, "\n")), "\n")))
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		if got.Pointer() != want.Pointer() {
+			d.addf(path, "got %s, want %s", formatValue(got), formatValue(want))
+		}
+	default:
+		if g, w := formatValue(got), formatValue(want); g != w {
+			d.addf(path, "got %s, want %s", g, w)
+		} else if got.CanInterface() && !reflect.DeepEqual(got.Interface(), want.Interface()) {
+			// E.g., NaN.
+			d.addf(path, "got %s, want %s", g, w)
+		}
+	}
+}
+
+// sortedKeys returns the keys of the maps a and b, without duplicates,
+// sorted by their formatted values.
+func sortedKeys(a, b reflect.Value) []reflect.Value {
+	seen := map[string]bool{}
+	var keys []reflect.Value
+	for _, m := range []reflect.Value{a, b} {
+		for _, k := range m.MapKeys() {
+			if s := formatValue(k); !seen[s] {
+				seen[s] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return formatValue(keys[i]) < formatValue(keys[j]) })
+	return keys
+}
+
+// formatValue returns v formatted for a difference.  Strings are quoted.
+func formatValue(v reflect.Value) string {
+	switch {
+	case !v.IsValid():
+		return "nil"
+	case v.Kind() == reflect.String:
+		return strconv.Quote(v.String())
+	case (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface || v.Kind() == reflect.Map || v.Kind() == reflect.Slice) && v.IsNil():
+		return sprintf("%v(nil)", v.Type())
+	case v.Kind() == reflect.Func || v.Kind() == reflect.Chan:
+		return sprintf("%v(%#x)", v.Type(), v.Pointer())
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// lineDiff returns the lines of got and want, each prefixed by "-" if only
+// in got, "+" if only in want, or " " if in both, using a longest common
+// subsequence.
+func lineDiff(got, want []string) []string {
+	// lcs[i][j] is the length of the longest common subsequence of got[i:]
+	// and want[j:].
+	lcs := make([][]int, len(got)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(want)+1)
+	}
+	for i := len(got) - 1; i >= 0; i-- {
+		for j := len(want) - 1; j >= 0; j-- {
+			switch {
+			case got[i] == want[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	var lines []string
+	i, j := 0, 0
+	for i < len(got) && j < len(want) {
+		switch {
+		case got[i] == want[j]:
+			lines = append(lines, " "+got[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, "-"+got[i])
+			i++
+		default:
+			lines = append(lines, "+"+want[j])
+			j++
+		}
+	}
+	for ; i < len(got); i++ {
+		lines = append(lines, "-"+got[i])
+	}
+	for ; j < len(want); j++ {
+		lines = append(lines, "+"+want[j])
+	}
+	return lines
+}
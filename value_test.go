@@ -0,0 +1,118 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+type person struct {
+	Name  string
+	Age   int
+	Tags  []string
+	Attrs map[string]int
+	Boss  *person
+	notes string
+}
+
+func TestValue(t *testing.T) {
+	setDefaults(t)
+	alice := person{Name: "alice", Age: 30, Tags: []string{"a", "b"}, Attrs: map[string]int{"id": 7}}
+	bob := person{Name: "bob", Age: 30, Tags: []string{"a", "c", "d"}, Attrs: map[string]int{"x": 1}, notes: "n"}
+	cyclic := &person{Name: "c"}
+	cyclic.Boss = cyclic
+	cyclic2 := &person{Name: "c"}
+	cyclic2.Boss = cyclic2
+	for _, tt := range []struct {
+		name      string
+		got, want interface{}
+		diffs     []string
+	}{
+		{name: "equal", got: alice, want: alice},
+		{name: "nil", got: nil, want: nil},
+		{name: "int", got: 1, want: 2, diffs: []string{"value: got 1, want 2"}},
+		{name: "types", got: 1, want: "1", diffs: []string{"value: got type int, want type string"}},
+		{name: "nil want", got: 1, want: nil, diffs: []string{"value: got 1, want nil"}},
+		{name: "string", got: "a", want: "b", diffs: []string{`value: got "a", want "b"`}},
+		{
+			name: "struct",
+			got:  bob,
+			want: alice,
+			diffs: []string{
+				`.Name: got "bob", want "alice"`,
+				`.Tags[1]: got "c", want "b"`,
+				`.Tags[2]: got "d", want nothing`,
+				`.Attrs["id"]: missing, want 7`,
+				`.Attrs["x"]: got 1, want nothing`,
+				`.notes: got "n", want ""`,
+			},
+		},
+		{
+			name:  "pointer",
+			got:   &person{Boss: &alice},
+			want:  &person{},
+			diffs: []string{".Boss: got &{alice 30 [a b] map[id:7] <nil> }, want *check.person(nil)"},
+		},
+		{name: "cyclic", got: cyclic, want: cyclic2},
+		{
+			name:  "lines",
+			got:   "one\ntwo\nthree",
+			want:  "one\n2\nthree\nfour",
+			diffs: []string{"value: lines differ (-got +want):\n\t one\n\t-two\n\t+2\n\t three\n\t+four"},
+		},
+		{name: "nil slice", got: []int(nil), want: []int{}, diffs: []string{"value: got []int(nil), want []"}},
+		{name: "NaN", got: math.NaN(), want: math.NaN(), diffs: []string{"value: got NaN, want NaN"}},
+	} {
+		out := ""
+		if tt.diffs != nil {
+			out = "got value differs from want:"
+			for _, d := range tt.diffs {
+				out += "\n" + indent(d)
+			}
+		}
+		if s := Value(tt.got, tt.want); s != out {
+			t.Errorf("%s: got:\n%s\nwant:\n%s", tt.name, s, out)
+		}
+	}
+	if s := Value(1, 2, Codes()); s != "CHK-WRONG: got value differs from want:\n\tvalue: got 1, want 2" {
+		t.Errorf("with Codes got %q", s)
+	}
+	got, want := make([]int, 30), make([]int, 30)
+	for i := range got {
+		got[i] = i
+	}
+	s := Value(got, want)
+	if n := strings.Count(s, "\n"); n != maxDiffs+1 || !strings.HasSuffix(s, fmt.Sprintf("... and %d more differences", 29-maxDiffs)) {
+		t.Errorf("got %d lines:\n%s", n, s)
+	}
+}
+
+func TestLineDiff(t *testing.T) {
+	for _, tt := range []struct {
+		got, want, out string
+	}{
+		{"a b c", "a b c", " a  b  c"},
+		{"a b c", "a c", " a -b  c"},
+		{"a c", "a b c", " a +b  c"},
+		{"a", "b", "-a +b"},
+	} {
+		if out := strings.Join(lineDiff(strings.Fields(tt.got), strings.Fields(tt.want)), " "); out != tt.out {
+			t.Errorf("lineDiff(%q, %q): got %q, want %q", tt.got, tt.want, out, tt.out)
+		}
+	}
+}
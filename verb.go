@@ -0,0 +1,97 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import "strings"
+
+// Verb returns an Option that causes the message of an error to be the error
+// as formatted with verb, such as "%+v" or "%#v", rather than the result of
+// its Error method.  Verb permits testing the verbose renderings of error
+// types that implement fmt.Formatter:
+//
+//	check.NewChecker(check.Verb("%+v")).Error(err, "main.go:12")
+//
+// Verb applies to the messages checked by Error, HasError, and MessagesEqual.
+// Checks of the identity or type of an error are not changed.  The verb "%v"
+// is the same as the default unless the error implements fmt.Formatter.
+func Verb(verb string) Option {
+	return func(c *config) { c.verb = verb }
+}
+
+// formatted returns err as formatted with the Verb of c.  An error returned
+// by formatted wraps err, so checks of its identity and type still apply to
+// err.  err is returned unchanged if c has no Verb, err is nil, or err was
+// returned by formatted.
+func (c *config) formatted(err error) error {
+	if c.verb == "" || err == nil {
+		return err
+	}
+	if _, ok := err.(*formattedError); ok {
+		return err
+	}
+	return &formattedError{msg: sprintf(c.verb, err), err: err}
+}
+
+// A formattedError is an error whose message is the formatted form of the
+// error it wraps.
+type formattedError struct {
+	msg string
+	err error
+}
+
+func (e *formattedError) Error() string { return e.msg }
+func (e *formattedError) Unwrap() error { return e.err }
+
+const inconsistentVerb = "got %q formatting error with %q, want %q"
+
+// FormatConsistent returns the empty string if got formats the same with the
+// verbs "%v" and "%s" as its Error method returns, otherwise it returns a
+// string indicating each verb that differs, one per line.  FormatConsistent
+// catches error types whose fmt.Formatter has drifted from their Error
+// method:
+//
+//	if s := check.FormatConsistent(&QueryError{Query: "q", Err: io.EOF}); s != "" {
+//		t.Error(s)
+//	}
+//
+// The "%+v" and "%#v" verbs, which are expected to differ, are not checked.
+func FormatConsistent(got error) string {
+	return defaults().formatConsistent(got)
+}
+
+// FormatConsistent is the same as the FormatConsistent function but uses the
+// options of ck.
+func (ck *Checker) FormatConsistent(got error) Result {
+	ck.count()
+	return Result(ck.c.formatConsistent(got))
+}
+
+func (c *config) formatConsistent(got error) string {
+	if c.outer() {
+		return c.run("FormatConsistent", got, nil,
+			func(c *config) string { return c.formatConsistent(got) })
+	}
+	if got == nil {
+		return c.failf(missing)
+	}
+	msg := message(got)
+	var failures []string
+	for _, verb := range []string{"%v", "%s"} {
+		if s := sprintf(verb, got); s != msg {
+			failures = append(failures, c.failf(inconsistentVerb, s, verb, msg)+c.caret(s, msg, false))
+		}
+	}
+	return strings.Join(failures, "\n")
+}
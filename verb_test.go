@@ -0,0 +1,115 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+)
+
+// stackErr is an error whose %+v form includes a stack.
+type stackErr struct{ msg string }
+
+func (e *stackErr) Error() string { return e.msg }
+
+func (e *stackErr) Format(f fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && f.Flag('+'):
+		fmt.Fprintf(f, "%s\n\tmain.go:12", e.msg)
+	case verb == 'v' && f.Flag('#'):
+		fmt.Fprintf(f, "&stackErr{%q}", e.msg)
+	default:
+		fmt.Fprint(f, e.msg)
+	}
+}
+
+func TestVerb(t *testing.T) {
+	setDefaults(t)
+	err := &stackErr{"boom"}
+	wrapped := fmt.Errorf("run: %w", err)
+	var target *stackErr
+	for _, tt := range []struct {
+		verb string
+		got  error
+		want interface{}
+		out  string
+	}{
+		{"", err, Equal("boom"), ""},
+		{"%v", err, Equal("boom"), ""},
+		{"%+v", err, "main.go:12", ""},
+		{"%+v", err, Equal("boom"), defaults().failf(wrong, "boom\n\tmain.go:12", "boom")},
+		{"%#v", err, Equal(`&stackErr{"boom"}`), ""},
+		{"%+v", err, err, ""},
+		{"%+v", wrapped, err, sprintf(wrong, "run: boom", "boom")},
+		{"%+v", wrapped, &target, ""},
+		{"%+v", wrapped, SingleLine(), ""},
+		{"%+v", err, SingleLine(), defaults().failf(multiLine, "boom\n\tmain.go:12")},
+		{"%+v", nil, nil, ""},
+		{"%+v", io.EOF, "EOF", ""},
+		{"%q", io.EOF, Equal(`"EOF"`), ""},
+	} {
+		if s := NewChecker(Verb(tt.verb)).Error(tt.got, tt.want); string(s) != tt.out {
+			t.Errorf("%s %v %v: got %q, want %q", tt.verb, tt.got, tt.want, s, tt.out)
+		}
+	}
+	ck := NewChecker(Verb("%+v"))
+	if s := ck.MessagesEqual(err, &stackErr{"boom"}); s != "" {
+		t.Errorf("MessagesEqual: %s", s)
+	}
+	if s := ck.MessagesEqual(err, errors.New("boom")); s == "" {
+		t.Errorf("MessagesEqual of different renderings passed")
+	}
+	if s := ck.HasError(err, "main.go", Equal("boom\n\tmain.go:12")); s != "" {
+		t.Errorf("HasError: %s", s)
+	}
+}
+
+// driftErr is an error whose Format method has drifted from its Error method.
+type driftErr struct{ v, s string }
+
+func (e *driftErr) Error() string { return "boom" }
+
+func (e *driftErr) Format(f fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		fmt.Fprint(f, e.v)
+	case 's':
+		fmt.Fprint(f, e.s)
+	}
+}
+
+func TestFormatConsistent(t *testing.T) {
+	setDefaults(t)
+	for _, tt := range []struct {
+		got error
+		out string
+	}{
+		{io.EOF, ""},
+		{&stackErr{"boom"}, ""},
+		{fmt.Errorf("run: %w", &stackErr{"boom"}), ""},
+		{&driftErr{"boom", "boom"}, ""},
+		{nil, sprintf(missing)},
+		{&driftErr{"boom (3)", "boom"}, sprintf(inconsistentVerb, "boom (3)", "%v", "boom")},
+		{&driftErr{"boom", ""}, sprintf(inconsistentVerb, "", "%s", "boom")},
+		{&driftErr{"", ""}, sprintf(inconsistentVerb, "", "%v", "boom") + "\n" +
+			sprintf(inconsistentVerb, "", "%s", "boom")},
+	} {
+		if s := FormatConsistent(tt.got); s != tt.out {
+			t.Errorf("FormatConsistent(%#v): got %q, want %q", tt.got, s, tt.out)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// A Wants is a file of expected error messages, keyed by name, such as the
+// name of the subtest checking the error.  Keeping long or frequently changed
+// messages in a Wants file, rather than in table literals, permits them to be
+// updated all at once with the -check.update flag.  A Wants is safe for
+// concurrent use.
+//
+// Each line of the file is a key, which contains no spaces, followed by a
+// space and the message as a quoted Go string:
+//
+//	TestParse/empty "parse: unexpected end of input"
+type Wants struct {
+	path string
+
+	mu      sync.Mutex
+	wants   map[string]string
+	changes map[string]*string // new messages, nil if removed
+}
+
+// LoadWants returns the Wants in the file at path, calling t.Fatal if it
+// cannot be read.  A missing file is treated as empty.  When t completes, if
+// the -check.update flag is set and a check using the Wants failed, the file
+// is rewritten with the messages of the errors actually checked and the
+// changes are logged with t.
+func LoadWants(t testing.TB, path string) *Wants {
+	t.Helper()
+	data, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		t.Fatal(err)
+	}
+	wants, err := parseWants(data)
+	if err != nil {
+		t.Fatalf("%s: %v", path, err)
+	}
+	w := &Wants{path: path, wants: wants}
+	t.Cleanup(func() {
+		summary, err := w.update()
+		if err != nil {
+			t.Error(err)
+		} else if summary != "" {
+			t.Logf("updated %s:\n%s", path, summary)
+		}
+	})
+	return w
+}
+
+// parseWants returns the wants in data, in the format described by Wants.
+func parseWants(data []byte) (map[string]string, error) {
+	wants := map[string]string{}
+	for i, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line == "" {
+			continue
+		}
+		x := strings.Index(line, " ")
+		if x < 0 {
+			return nil, fmt.Errorf("line %d: missing message", i+1)
+		}
+		msg, err := strconv.Unquote(strings.TrimSpace(line[x:]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: malformed message %s", i+1, strings.TrimSpace(line[x:]))
+		}
+		wants[line[:x]] = msg
+	}
+	return wants, nil
+}
+
+// Equal returns a Matcher that matches an error whose message is the message
+// stored under key, or, if there is no message stored under key, no error.
+// When the -check.update flag is set a mismatch is not a failure, rather the
+// message of the error checked replaces the stored message.
+func (w *Wants) Equal(key string) Matcher {
+	return wantsEqual{w: w, key: key}
+}
+
+type wantsEqual struct {
+	w   *Wants
+	key string
+}
+
+func (m wantsEqual) match(c *config, got error) string {
+	var want interface{}
+	if msg, ok := m.w.lookup(m.key); ok {
+		want = Equal(msg)
+	}
+	s := c.checkError(got, want)
+	if s == "" || !*updateFlag {
+		return s
+	}
+	var msg *string
+	if got != nil {
+		s := message(got)
+		msg = &s
+	}
+	m.w.set(m.key, msg)
+	return ""
+}
+
+// lookup returns the message stored under key, if any.
+func (w *Wants) lookup(key string) (string, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	msg, ok := w.wants[key]
+	return msg, ok
+}
+
+// set records that the message stored under key is to become msg, or is to
+// be removed if msg is nil.
+func (w *Wants) set(key string, msg *string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.changes == nil {
+		w.changes = map[string]*string{}
+	}
+	w.changes[key] = msg
+}
+
+// update applies the changes recorded by set, if any, to the file of w and
+// returns a summary of them, one line per change.
+func (w *Wants) update() (string, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.changes) == 0 {
+		return "", nil
+	}
+	var summary []string
+	for key, msg := range w.changes {
+		old, ok := w.wants[key]
+		switch {
+		case msg == nil:
+			delete(w.wants, key)
+			summary = append(summary, sprintf("\t%s: removed %q", key, old))
+		case ok:
+			w.wants[key] = *msg
+			summary = append(summary, sprintf("\t%s: %q -> %q", key, old, *msg))
+		default:
+			w.wants[key] = *msg
+			summary = append(summary, sprintf("\t%s: added %q", key, *msg))
+		}
+	}
+	w.changes = nil
+	sort.Strings(summary)
+	keys := make([]string, 0, len(w.wants))
+	for key := range w.wants {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var b bytes.Buffer
+	for _, key := range keys {
+		fmt.Fprintf(&b, "%s %s\n", key, strconv.Quote(w.wants[key]))
+	}
+	if err := ioutil.WriteFile(w.path, b.Bytes(), 0644); err != nil {
+		return "", err
+	}
+	return strings.Join(summary, "\n"), nil
+}
@@ -0,0 +1,120 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseWants(t *testing.T) {
+	wants, err := parseWants([]byte("a \"x\"\n\nb   \"y\\nz\"\n"))
+	if err != nil || fmt.Sprint(wants) != fmt.Sprint(map[string]string{"a": "x", "b": "y\nz"}) {
+		t.Errorf("got %q, %v", wants, err)
+	}
+	for _, tt := range []struct {
+		in, err string
+	}{
+		{"a\n", "line 1: missing message"},
+		{"a \"x\"\nb x\n", "line 2: malformed message x"},
+	} {
+		if _, err := parseWants([]byte(tt.in)); fmt.Sprint(err) != tt.err {
+			t.Errorf("%q: got error %v, want %s", tt.in, err, tt.err)
+		}
+	}
+}
+
+// loadWants returns the Wants in the file at path, loaded with a fakeTB, and
+// a function that completes the fakeTB.
+func loadWants(t *testing.T, path string) (*Wants, *fakeTB, func()) {
+	var cleanup func()
+	ft := &fakeTB{cleanup: func(f func()) { cleanup = f }}
+	return LoadWants(ft, path), ft, func() { cleanup() }
+}
+
+func TestWants(t *testing.T) {
+	setDefaults(t)
+	path := filepath.Join(t.TempDir(), "wants.txt")
+	if err := ioutil.WriteFile(path, []byte("eof \"EOF\"\ngone \"old\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	w, ft, done := loadWants(t, path)
+	for _, tt := range []struct {
+		key  string
+		got  error
+		want string
+	}{
+		{"eof", io.EOF, ""},
+		{"eof", io.ErrUnexpectedEOF, sprintf(wrong, "unexpected EOF", "EOF")},
+		{"missing", nil, ""},
+		{"missing", io.EOF, sprintf(unexpected, "EOF")},
+	} {
+		if s := Error(tt.got, w.Equal(tt.key)); s != tt.want {
+			t.Errorf("%s %v: got %q, want %q", tt.key, tt.got, s, tt.want)
+		}
+	}
+	if d := Describe(w.Equal("eof")); d != `is "EOF"` {
+		t.Errorf("got description %q", d)
+	}
+	if err := Validate(w.Equal("a b"), w.Equal("")); fmt.Sprint(err) != `want 0: Wants key "a b" is empty or contains white space
+want 1: Wants key "" is empty or contains white space` {
+		t.Errorf("got validation error %v", err)
+	}
+
+	*updateFlag = true
+	defer func() { *updateFlag = false }()
+	for _, tt := range []struct {
+		key string
+		got error
+	}{
+		{"eof", io.ErrUnexpectedEOF},
+		{"gone", nil},
+		{"new", errors.New("new\nmessage")},
+	} {
+		if s := Error(tt.got, w.Equal(tt.key)); s != "" {
+			t.Errorf("update %s: got %q", tt.key, s)
+		}
+	}
+	done()
+	if ft.errors != nil {
+		t.Errorf("got errors %q", ft.errors)
+	}
+	summary := []string{"updated " + path + `:
+	eof: "EOF" -> "unexpected EOF"
+	gone: removed "old"
+	new: added "new\nmessage"`}
+	if fmt.Sprint(ft.logs) != fmt.Sprint(summary) {
+		t.Errorf("got logs:\n%s\nwant:\n%s", ft.logs, summary)
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "eof \"unexpected EOF\"\nnew \"new\\nmessage\"\n"; string(data) != want {
+		t.Errorf("got file:\n%s\nwant:\n%s", data, want)
+	}
+
+	// Without changes the file is left alone.
+	*updateFlag = false
+	_, ft, done = loadWants(t, path)
+	done()
+	if ft.logs != nil || ft.errors != nil {
+		t.Errorf("unchanged: got logs %q and errors %q", ft.logs, ft.errors)
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import "strings"
+
+// Warnings returns the empty string if err is nil and warnings, the advisory
+// errors of an API that returns both warnings and an error, match wants, one
+// for one and in order, as by Error.  Otherwise it returns a string indicating
+// each difference.  Warnings are numbered from 0 in failures.
+//
+//	cfg, warnings, err := config.Load(path)
+//	if s := check.Warnings(warnings, err, "deprecated key", "unknown key"); s != "" {
+//		t.Error(s)
+//	}
+func Warnings(warnings []error, err error, wants ...interface{}) string {
+	return defaults().warnings(warnings, err, wants)
+}
+
+// Warnings is the same as the Warnings function but uses the options of ck.
+func (ck *Checker) Warnings(warnings []error, err error, wants ...interface{}) Result {
+	ck.count()
+	return Result(ck.c.warnings(warnings, err, wants))
+}
+
+func (c *config) warnings(warnings []error, err error, wants []interface{}) string {
+	if c.outer() {
+		return c.run("Warnings", err, func() string { return describeAll(wants) },
+			func(c *config) string { return c.warnings(warnings, err, wants) })
+	}
+	var failures []string
+	if s := c.checkError(err, nil); s != "" {
+		failures = append(failures, "error: "+s)
+	}
+	if len(warnings) != len(wants) {
+		failures = append(failures, c.failc(CodeWrong, sprintf("got %d warnings, want %d", len(warnings), len(wants))))
+	}
+	for i, w := range warnings {
+		var s string
+		if i < len(wants) {
+			s = c.checkError(w, wants[i])
+		} else {
+			s = c.failf(unexpected, w)
+		}
+		if s != "" {
+			failures = append(failures, sprintf("warning %d: %s", i, s))
+		}
+	}
+	for i := len(warnings); i < len(wants); i++ {
+		if s := c.checkError(nil, wants[i]); s != "" {
+			failures = append(failures, sprintf("warning %d: %s", i, s))
+		}
+	}
+	return strings.Join(failures, "\n")
+}
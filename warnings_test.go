@@ -0,0 +1,64 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestWarnings(t *testing.T) {
+	setDefaults(t)
+	deprecated := errors.New("deprecated key: port")
+	unknown := errors.New("unknown key: colour")
+	for _, tt := range []struct {
+		name     string
+		warnings []error
+		err      error
+		wants    []interface{}
+		out      string
+	}{
+		{name: "none"},
+		{name: "match", warnings: []error{deprecated, unknown}, wants: []interface{}{"deprecated", "unknown"}},
+		{
+			name:     "error",
+			warnings: []error{deprecated},
+			err:      io.EOF,
+			wants:    []interface{}{"deprecated"},
+			out:      "error: " + sprintf(unexpected, "EOF"),
+		}, {
+			name:     "order",
+			warnings: []error{unknown, deprecated},
+			wants:    []interface{}{"deprecated", "unknown"},
+			out: "warning 0: " + sprintf(wrong, "unknown key: colour", "deprecated") +
+				"\nwarning 1: " + sprintf(wrong, "deprecated key: port", "unknown"),
+		}, {
+			name:     "extra",
+			warnings: []error{deprecated, unknown},
+			wants:    []interface{}{"deprecated"},
+			out:      "got 2 warnings, want 1\nwarning 1: " + sprintf(unexpected, "unknown key: colour"),
+		}, {
+			name:     "missing",
+			warnings: []error{deprecated},
+			wants:    []interface{}{"deprecated", "unknown"},
+			out:      "got 1 warnings, want 2\nwarning 1: " + sprintf(expected, "unknown"),
+		},
+	} {
+		if s := Warnings(tt.warnings, tt.err, tt.wants...); s != tt.out {
+			t.Errorf("%s: got:\n%s\nwant:\n%s", tt.name, s, tt.out)
+		}
+	}
+}
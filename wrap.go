@@ -0,0 +1,127 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import "strings"
+
+// WrapDepth returns the empty string if got wraps exactly want errors, one
+// inside the other, through Unwrap() error methods, otherwise it returns a
+// failure that includes the chain of got.  An error that wraps nothing has a
+// depth of 0, and fmt.Errorf("open config: %w", err) adds 1 to the depth of
+// err.  A joined error, one with an Unwrap() []error method, ends the chain.
+// WrapDepth enforces wrapping conventions, such as each package adding one
+// layer of context:
+//
+//	if s := check.WrapDepth(err, 2); s != "" {
+//		t.Error(s)
+//	}
+func WrapDepth(got error, want int) string {
+	return defaults().wrapDepth(got, want)
+}
+
+// WrapDepth is the same as the WrapDepth function but uses the options of ck.
+func (ck *Checker) WrapDepth(got error, want int) Result {
+	ck.count()
+	return Result(ck.c.wrapDepth(got, want))
+}
+
+// wrapDepth implements WrapDepth using the settings in c.
+func (c *config) wrapDepth(got error, want int) string {
+	if c.outer() {
+		return c.run("WrapDepth", got, func() string { return sprintf("wrapped %d times", want) },
+			func(c *config) string { return c.wrapDepth(got, want) })
+	}
+	if got == nil {
+		return c.failf(missing)
+	}
+	depth := len(links(got)) - 1
+	if depth == want {
+		return ""
+	}
+	if c.quiet {
+		return quietFailure
+	}
+	return c.failc(CodeWrong, sprintf("got error %%q wrapped %d times, want %d", depth, want), got) + chain(got)
+}
+
+// Chain returns the empty string if each successive link of the chain of got,
+// got and then each error it wraps through an Unwrap() error method, is
+// matched by the want at the same index in wants, as by Error, and the chain
+// has as many links as there are wants.  Otherwise it returns a string
+// indicating each failure, one per line.  A joined error ends the chain, as
+// for WrapDepth:
+//
+//	err := fmt.Errorf("load: %w", fmt.Errorf("open config: %w", os.ErrNotExist))
+//	if s := check.Chain(err, "load", "open config", os.ErrNotExist); s != "" {
+//		t.Error(s)
+//	}
+func Chain(got error, wants ...interface{}) string {
+	return defaults().chain(got, wants)
+}
+
+// Chain is the same as the Chain function but uses the options of ck.
+func (ck *Checker) Chain(got error, wants ...interface{}) Result {
+	ck.count()
+	return Result(ck.c.chain(got, wants))
+}
+
+// chain implements Chain using the settings in c.
+func (c *config) chain(got error, wants []interface{}) string {
+	if c.outer() {
+		return c.run("Chain", got, func() string { return describeAll(wants) },
+			func(c *config) string { return c.chain(got, wants) })
+	}
+	if got == nil {
+		if len(wants) == 0 {
+			return ""
+		}
+		return c.failf(missing)
+	}
+	errs := links(got)
+	var failures []string
+	if len(errs) != len(wants) {
+		failures = append(failures, c.failc(CodeWrong, sprintf("got %d links in the chain of %%q, want %d", len(errs), len(wants)), got))
+	}
+	for i := 0; i < len(errs) && i < len(wants); i++ {
+		if s := c.checkError(errs[i], wants[i]); s != "" {
+			failures = append(failures, prefixed(sprintf("link %d: ", i), s))
+		}
+	}
+	if len(failures) == 0 {
+		return ""
+	}
+	if c.quiet {
+		return quietFailure
+	}
+	return strings.Join(failures, "\n")
+}
+
+// links returns err and each error it wraps through an Unwrap() error
+// method, outermost first.
+func links(err error) []error {
+	var errs []error
+	for err != nil {
+		errs = append(errs, err)
+		if _, ok := err.(interface{ Unwrap() error }); !ok {
+			break
+		}
+		wrapped := unwrapAll(err)
+		if len(wrapped) == 0 {
+			break
+		}
+		err = wrapped[0]
+	}
+	return errs
+}
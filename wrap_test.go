@@ -0,0 +1,83 @@
+// Copyright 2020 Paul Borman
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package check
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"testing"
+)
+
+func TestWrapDepth(t *testing.T) {
+	setDefaults(t)
+	once := fmt.Errorf("read: %w", io.EOF)
+	twice := fmt.Errorf("load: %w", once)
+	joined := fmt.Errorf("load: %w", &multi{once, io.EOF})
+	for _, tt := range []struct {
+		name string
+		got  error
+		want int
+		out  string
+	}{
+		{"unwrapped", io.EOF, 0, ""},
+		{"once", once, 1, ""},
+		{"twice", twice, 2, ""},
+		{"joined", joined, 1, ""},
+		{"too deep", twice, 1, defaults().failc(CodeWrong, "got error %q wrapped 2 times, want 1", twice) + chain(twice)},
+		{"too shallow", io.EOF, 1, defaults().failc(CodeWrong, "got error %q wrapped 0 times, want 1", io.EOF) + chain(io.EOF)},
+		{"nil", nil, 0, missing},
+	} {
+		if s := WrapDepth(tt.got, tt.want); s != tt.out {
+			t.Errorf("%s: got %q, want %q", tt.name, s, tt.out)
+		}
+	}
+	if s := NewChecker().WrapDepth(once, 1); s != "" {
+		t.Errorf("Checker: got %q", s)
+	}
+}
+
+func TestChain(t *testing.T) {
+	setDefaults(t)
+	open := fmt.Errorf("open config: %w", os.ErrNotExist)
+	load := fmt.Errorf("load: %w", open)
+	for _, tt := range []struct {
+		name  string
+		got   error
+		wants []interface{}
+		out   string
+	}{
+		{"match", load, []interface{}{"load", Equal("open config: file does not exist"), os.ErrNotExist}, ""},
+		{"categories", load, []interface{}{NotFound, NotFound, NotFound}, ""},
+		{"nil", nil, nil, ""},
+		{"missing", nil, []interface{}{"load"}, missing},
+		{"wrong link", load, []interface{}{"load", "read", os.ErrNotExist},
+			"link 1: " + Error(open, "read")},
+		{"too short", load, []interface{}{"load", "open"},
+			sprintf(`got 3 links in the chain of %q, want 2`, load)},
+		{"too long", open, []interface{}{"open", os.ErrNotExist, "extra"},
+			sprintf(`got 2 links in the chain of %q, want 3`, open)},
+		{"several", open, []interface{}{"read"},
+			sprintf(`got 2 links in the chain of %q, want 1`, open) + "\nlink 0: " + Error(open, "read")},
+	} {
+		if s := Chain(tt.got, tt.wants...); s != tt.out {
+			t.Errorf("%s: got %q, want %q", tt.name, s, tt.out)
+		}
+	}
+	s := NewChecker(Codes()).Chain(load, "load", "read", os.ErrNotExist).String()
+	if want := string(CodeWrong) + ": link 1: " + Error(open, "read"); s != want {
+		t.Errorf("Codes: got %q, want %q", s, want)
+	}
+}